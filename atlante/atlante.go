@@ -1,7 +1,9 @@
 package atlante
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -24,6 +26,7 @@ import (
 	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
 	"github.com/go-spatial/atlante/mbgl/bounds"
 	"github.com/go-spatial/atlante/svg2pdf"
+	"github.com/go-spatial/atlante/svg2pdf/geopdf"
 	"github.com/prometheus/common/log"
 )
 
@@ -33,6 +36,67 @@ type GridTemplateContext struct {
 	Width  float64
 	Height float64
 	Args   *tplArgs
+
+	// Provider is the grid provider the Grid came from, used to look up the neighbors
+	Provider  grids.Provider
+	neighbors *grids.Neighbors
+
+	// Page is the page number of the grid in an atlas, and Pages the
+	// number of pages in the atlas. Both are zero if the grid is not part
+	// of an atlas, or page numbers were not requested.
+	Page  uint
+	Pages uint
+
+	// PageSize is the paper size and orientation the grid is rendered on
+	PageSize PageSize
+	// PrintBoxes are the media, bleed, trim and live regions of the page; Width and
+	// Height are the size of the media
+	PrintBoxes
+}
+
+// viewport returns the georeferenced region of the page for the map frame, if the
+// image was placed on the page.
+func (grctx *GridTemplateContext) viewport() (geopdf.Viewport, bool) {
+	if grctx == nil {
+		return geopdf.Viewport{}, false
+	}
+	frame := grctx.Image.Frame()
+	if frame == nil || frame.Width <= 0 || frame.Height <= 0 {
+		return geopdf.Viewport{}, false
+	}
+	return geopdf.Viewport{
+		Name: grctx.Grid.GetMdgid().AsString(),
+		// pdf user space has the origin at the bottom left
+		BBox: [4]float64{
+			frame.X,
+			grctx.Height - (frame.Y + frame.Height),
+			frame.X + frame.Width,
+			grctx.Height - frame.Y,
+		},
+		SW: grctx.Grid.SW(),
+		NE: grctx.Grid.NE(),
+		// the image is always rendered in web mercator
+		EPSG: 3857,
+	}, true
+}
+
+// Neighbors returns the sheets adjoining the grid, for the adjoining sheet index.
+// A direction will be nil if there is no sheet in that direction.
+//
+//	{{ with .Neighbors.N }}{{ .Sheet }} {{ .MDGID }}{{ end }}
+func (grctx *GridTemplateContext) Neighbors() (*grids.Neighbors, error) {
+	if grctx.neighbors != nil {
+		return grctx.neighbors, nil
+	}
+	if grctx.Provider == nil {
+		return &grids.Neighbors{}, nil
+	}
+	ns, err := grids.NeighborsFor(grctx.Provider, grctx.Grid)
+	if err != nil {
+		return nil, err
+	}
+	grctx.neighbors = ns
+	return ns, nil
 }
 
 func (grctx *GridTemplateContext) SetWidthHeight(w float64, h float64) string {
@@ -62,6 +126,23 @@ func (grctx GridTemplateContext) DPI() uint {
 	return dpi
 }
 
+// HasFootprint returns weather the grid has a footprint that is not the bounds of the grid
+func (grctx GridTemplateContext) HasFootprint() bool { return grctx.Grid.HasFootprint() }
+
+// FootprintPath returns the footprint of the grid as svg path data, scaled so that the bounds
+// of the grid fill the pixel box x1,y1 (top left) to x2,y2 (bottom right). This can be used to
+// mask or clip the area outside of the footprint of the sheet. Holes are
+// included, so the path should be used with a fill-rule of evenodd.
+//
+//	<clipPath id="footprint"><path clip-rule="evenodd" d="{{ .FootprintPath 0 0 800 600 }}"/></clipPath>
+func (grctx GridTemplateContext) FootprintPath(x1, y1, x2, y2 float64) (string, error) {
+	footprint, err := grctx.Grid.Footprint()
+	if err != nil {
+		return "", err
+	}
+	return tplSVGPath(footprint, grctx.Grid.Hull(), bounds.ESPG3857, x1, y1, x2, y2), nil
+}
+
 func (grctx GridTemplateContext) DrawBars(gridSize int, pxlBox PixelBox, lblRows, lblCols []int, lblMeterOffset int) (string, error) {
 
 	log.Infof("Draw Bars called: ground measure: %v", pxlBox.GroundPixel)
@@ -115,6 +196,10 @@ type GeneratedFiles struct {
 	IMG string
 	SVG string
 	PDF string
+	// TIF is only generated if the sheet has GeoTIFF set
+	TIF string
+	// Poster is only generated if a poster paper size is set on the sheet or job
+	Poster string
 }
 
 // NewGeneratedFilesFromTpl will generate the three filesnames we need based on a filename template
@@ -126,9 +211,11 @@ func NewGeneratedFilesFromTpl(fnTemplate *filenameTemplate, sheetName string, gr
 	}
 
 	return &GeneratedFiles{
-		IMG: fn("png"),
-		SVG: fn("svg"),
-		PDF: fn("pdf"),
+		IMG:    fn("png"),
+		SVG:    fn("svg"),
+		PDF:    fn("pdf"),
+		TIF:    fn("tif"),
+		Poster: fn("poster.pdf"),
 	}
 }
 
@@ -172,36 +259,119 @@ func (ft filenameTemplate) Filename(sheetName string, grid *grids.Cell, wd strin
 // GeneratePDF will generate the PDF based on the sheet, and grid
 func GeneratePDF(ctx context.Context, sheet *Sheet, grid *grids.Cell, filenames *GeneratedFiles) error {
 
-	var (
-		style string
-	)
 	if grid == nil {
 		return ErrNilGrid
 	}
 
-	useCached := false
-	if val, ok := os.LookupEnv("ATLANTE_USED_CACHED_IMAGES"); ok {
-		useCached, _ = strconv.ParseBool(val)
-		log.Infof("ATLANTE_USED_CACHED_IMAGES=%t", useCached)
+	useCached := useCachedImages()
+
+	Emit(ctx, field.Started{})
+
+	// TODO(gdey): use MdgID once we move to partial templates system
+	// grp := grid.MdgID.String(), an empty group is current directory
+	grp := ""
+
+	assetsWriter, multiWriter, err := sheetWriters(ctx, sheet, grp)
+	if err != nil {
+		return err
 	}
 
-	sheet.Emit(field.Started{})
+	log.Infoln("filenames: ", filenames.IMG, filenames.SVG, filenames.PDF)
 
-	if grid.MetaData != nil {
-		style = grid.MetaData["styleLocation"]
-		if style == "" {
-			s, _ := sheet.Styles.For(grid.MetaData["styleName"])
-			style = s.Location
+	gtc, err := renderSVG(ctx, sheet, grid, filenames, multiWriter, useCached, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	//TODO(gdey): here we should change directories to the working directory.
+	// This is needed to generate the PDF. It might make sense to do this
+	// as the first thing we do when entering this function. Atlante object
+	// does have a working directory parameter for this.
+
+	//TODO(gdey): 2028 and 2607 are file sizes for the size of the
+	// page we are generating. This should really be configuration
+	// options in the sheet. Using standard name like A0.
+	// see: https://www.belightsoft.com/products/resources/paper-sizes-and-formats-explained
+	// Though the 2028 and 2606 don't quite match up.
+
+	pdffn := assetsWriter.Path(filenames.PDF)
+	svgfn := assetsWriter.Path(filenames.SVG)
+
+	Emit(ctx, field.Processing{
+		Description: fmt.Sprintf("generate file: %v ", filenames.PDF),
+	})
+
+	log.Infof("pdf %v,%v", gtc.Width, gtc.Height)
+	converter, err := sheet.pdfConverter()
+	if err != nil {
+		EmitError(ctx, "generate pdf failed", err)
+		return err
+	}
+	if err = converter.GeneratePDF(svgfn, pdffn, gtc.Width, gtc.Height); err != nil {
+		log.Warnf("error generating pdf: %v", err)
+		EmitError(ctx, "generate pdf failed", err)
+		return err
+	}
+	if err = setPrintBoxes(ctx, sheet, pdffn, gtc); err != nil {
+		return err
+	}
+	if sheet.GeoPDF {
+		if err = georeference(ctx, sheet, pdffn, gtc); err != nil {
+			return err
 		}
-	} else {
-		s, _ := sheet.Styles.For("")
-		style = s.Location
+	}
+	if err = writePDFMetadata(ctx, sheet, pdffn, newPDFMetadataContext(sheet, grid)); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		EmitError(ctx, "generate pdf canceled", ctx.Err())
+		return ctx.Err()
 	}
 
-	// TODO(gdey): use MdgID once we move to partial templates system
-	// grp := grid.MdgID.String(), an empty group is current directory
-	grp := ""
+	if err = copyToFilestore(ctx, sheet, assetsWriter, multiWriter, filenames.PDF); err != nil {
+		return err
+	}
+
+	posterOpts, err := sheet.Poster.ForGrid(grid)
+	if err != nil {
+		EmitError(ctx, "poster options failed", err)
+		return err
+	}
+	if posterOpts.PaperSize != "" {
+		if err = GeneratePoster(ctx, sheet, gtc, posterOpts, assetsWriter, multiWriter, filenames); err != nil {
+			return err
+		}
+	}
+	Emit(ctx, field.Completed{})
+	return nil
+}
+
+// GenerateSVG fills out the sheet template for the grid into the svg file, without generating
+// the pdf. The svg and the map image are written to dir; the filestore of the sheet is not
+// used. The returned context has the size of the page in points.
+func GenerateSVG(ctx context.Context, sheet *Sheet, grid *grids.Cell, filenames *GeneratedFiles, dir string) (*GridTemplateContext, error) {
+	if grid == nil {
+		return nil, ErrNilGrid
+	}
+	multiWriter := fsmulti.FileWriter{
+		Writers: []filestore.FileWriter{fsfile.Writer{Base: dir, Intermediate: true}},
+	}
+	return renderSVG(ctx, sheet, grid, filenames, multiWriter, false, 0, 0)
+}
 
+// useCachedImages returns weather the ATLANTE_USED_CACHED_IMAGES env is set to true
+func useCachedImages() bool {
+	useCached := false
+	if val, ok := os.LookupEnv("ATLANTE_USED_CACHED_IMAGES"); ok {
+		useCached, _ = strconv.ParseBool(val)
+		log.Infof("ATLANTE_USED_CACHED_IMAGES=%t", useCached)
+	}
+	return useCached
+}
+
+// sheetWriters returns the writer for the intermediate assets, and a writer that writes to the
+// assets writer and the filestore of the sheet, if there is one.
+func sheetWriters(ctx context.Context, sheet *Sheet, grp string) (fsfile.Writer, fsmulti.FileWriter, error) {
 	assetsWriter := fsfile.Writer{Base: grp, Intermediate: true}
 
 	multiWriter := fsmulti.FileWriter{
@@ -212,18 +382,32 @@ func GeneratePDF(ctx context.Context, sheet *Sheet, grid *grids.Cell, filenames
 		shWriter, err := sheet.Filestore.FileWriter(grp)
 		if err != nil {
 			err = fmt.Errorf("failed to create sheet filestore writer: %v", err)
-			sheet.EmitError("internal error", err)
-			return err
+			EmitError(ctx, "internal error", err)
+			return assetsWriter, multiWriter, err
 		}
 		if shWriter != nil {
 			multiWriter.Writers = append(multiWriter.Writers, shWriter)
 		}
 	}
+	return assetsWriter, multiWriter, nil
+}
 
-	sheet.FuncFilestoreWriter = multiWriter
-	sheet.UseCached = useCached
+// renderSVG fills out the sheet template for the grid into the svg file, returning the
+// width and height of the page in points. page and pages are the page number and
+// number of pages when the grid is part of an atlas.
+func renderSVG(ctx context.Context, sheet *Sheet, grid *grids.Cell, filenames *GeneratedFiles, multiWriter fsmulti.FileWriter, useCached bool, page, pages uint) (*GridTemplateContext, error) {
+	var style string
 
-	log.Infoln("filenames: ", filenames.IMG, filenames.SVG, filenames.PDF)
+	if grid.MetaData != nil {
+		style = grid.MetaData["styleLocation"]
+		if style == "" {
+			s, _ := sheet.Styles.For(grid.MetaData["styleName"])
+			style = s.Location
+		}
+	} else {
+		s, _ := sheet.Styles.For("")
+		style = s.Location
+	}
 
 	/*
 		TODO(gdey): Keeping this for now. Not sure if we need this, or if the
@@ -241,16 +425,17 @@ func GeneratePDF(ctx context.Context, sheet *Sheet, grid *grids.Cell, filenames
 			UseCached:      useCached,
 		},
 		StartGenerationCallback: func() {
-			sheet.Emit(field.Processing{
+			Emit(ctx, field.Processing{
 				Description: fmt.Sprintf("intermediate file: %v", filenames.IMG),
 			})
 		},
 		FailGenerationCallback: func(err error) {
-			sheet.EmitError(
+			EmitError(ctx,
 				fmt.Sprintf("failed to generate intermediate file: %v", filenames.IMG),
 				err,
 			)
 		},
+		Renderer:   sheet.Renderer,
 		DPI:        sheet.DPI,
 		Grid:       grid,
 		Projection: bounds.ESPG3857,
@@ -262,87 +447,174 @@ func GeneratePDF(ctx context.Context, sheet *Sheet, grid *grids.Cell, filenames
 		img.Close()
 	}()
 
-	sheet.Emit(field.Processing{
+	Emit(ctx, field.Processing{
 		Description: fmt.Sprintf("intermediate file: %v ", filenames.SVG),
 	})
 	file, err := multiWriter.Writer(filenames.SVG, true)
-	defer file.Close()
 	if err != nil {
-		sheet.EmitError("failed to copy files", err)
-		return err
+		EmitError(ctx, "failed to copy files", err)
+		return nil, err
 	}
+	defer file.Close()
 	if ctx.Err() != nil {
-		sheet.EmitError("generate pdf canceled", ctx.Err())
-		return ctx.Err()
+		EmitError(ctx, "generate pdf canceled", ctx.Err())
+		return nil, ctx.Err()
 	}
 
-	widthpts, heightpts := float64(sheet.WidthInPoints(72)), float64(sheet.HeightInPoints(72))
+	pageSize, err := sheet.PageSizeFor(grid)
+	if err != nil {
+		EmitError(ctx, "failed to get page size", err)
+		return nil, err
+	}
+	boxes := sheet.printBoxes(pageSize)
 	log.Infof("Got cell metadata: %v", grid.MetaData)
 	gtc := &GridTemplateContext{
 		Image:  &img,
 		Grid:   grid,
-		Width:  widthpts,
-		Height: heightpts,
+		Width:  boxes.Media.Width,
+		Height: boxes.Media.Height,
 		Args:   NewTplArgsFromMapStringString(grid.MetaData),
+
+		Provider:   sheet.Provider,
+		Page:       page,
+		Pages:      pages,
+		PageSize:   pageSize,
+		PrintBoxes: boxes,
 	}
 	// Fill out template
-	err = sheet.Execute(file, gtc)
+	var svg bytes.Buffer
+	err = sheet.execute(ctx, &svg, gtc, multiWriter, useCached)
 	if err != nil {
-		sheet.EmitError("template processing failure", err)
+		if img.renderErr != nil && !errors.As(err, new(ErrRender)) {
+			// the template failed because the map image could not be rendered
+			err = ErrRender{Err: err}
+		}
+		EmitError(ctx, "template processing failure", err)
 		log.Warnf("error trying to fillout sheet template")
-		return err
+		return nil, err
 	}
+	out := svg.Bytes()
+	if sheet.CropMarks {
+		if out, err = addCropMarks(out, boxes); err != nil {
+			EmitError(ctx, "failed to add crop marks", err)
+			return nil, err
+		}
+	}
+	if _, err = file.Write(out); err != nil {
+		EmitError(ctx, "failed to write svg", err)
+		return nil, err
+	}
+	if sheet.GeoTIFF {
+		if err = writeGeoTIFF(ctx, sheet, &img, multiWriter, filenames.TIF); err != nil {
+			return nil, err
+		}
+	}
+	return gtc, nil
+}
 
-	//TODO(gdey): here we should change directories to the working directory.
-	// This is needed to generate the PDF. It might make sense to do this
-	// as the first thing we do when entering this function. Atlante object
-	// does have a working directory parameter for this.
-
-	//TODO(gdey): 2028 and 2607 are file sizes for the size of the
-	// page we are generating. This should really be configuration
-	// options in the sheet. Using standard name like A0.
-	// see: https://www.belightsoft.com/products/resources/paper-sizes-and-formats-explained
-	// Though the 2028 and 2606 don't quite match up.
-
-	pdffn := assetsWriter.Path(filenames.PDF)
-	svgfn := assetsWriter.Path(filenames.SVG)
-
-	sheet.Emit(field.Processing{
-		Description: fmt.Sprintf("generate file: %v ", filenames.PDF),
+// writeGeoTIFF writes the map image as a GeoTIFF to the assets and the filestore of the sheet
+func writeGeoTIFF(ctx context.Context, sheet *Sheet, img *Img, multiWriter fsmulti.FileWriter, filename string) error {
+	Emit(ctx, field.Processing{
+		Description: fmt.Sprintf("generate file: %v ", filename),
 	})
-
-	log.Infof("pdf %v,%v", gtc.Width, gtc.Height)
-	if err = svg2pdf.GeneratePDF(svgfn, pdffn, gtc.Width, gtc.Height); err != nil {
-		log.Warnf("error generating pdf: %v", err)
-		sheet.EmitError("generate pdf failed", err)
+	file, err := multiWriter.Writer(filename, false)
+	if err != nil {
+		EmitError(ctx, "failed to copy files", err)
 		return err
 	}
-	if ctx.Err() != nil {
-		sheet.EmitError("generate pdf canceled", ctx.Err())
-		return ctx.Err()
+	defer file.Close()
+	if err = img.WriteGeoTIFF(file); err != nil {
+		log.Warnf("error generating geotiff: %v", err)
+		EmitError(ctx, "generate geotiff failed", err)
+		return err
 	}
+	return nil
+}
 
-	if len(multiWriter.Writers) > 1 {
-		// Don't want the assets writer
-		wrts, err := multiWriter.Writers[1].Writer(filenames.PDF, false)
-		if err != nil {
-			sheet.EmitError("generate pdf failed", err)
-			return err
-		}
-		// nil writer move on.
-		if wrts == nil {
-			return nil
+// georeference adds the viewports of the map frames to the pages of the pdf. Pages with a nil
+// context, or where the template did not place the image, are not georeferenced.
+func georeference(ctx context.Context, sheet *Sheet, pdffn string, pages ...*GridTemplateContext) error {
+	var (
+		viewports = make([][]geopdf.Viewport, len(pages))
+		found     bool
+	)
+	for i, gtc := range pages {
+		if vp, ok := gtc.viewport(); ok {
+			viewports[i] = []geopdf.Viewport{vp}
+			found = true
 		}
-		defer wrts.Close()
-		// Copy the pdf over
-		pdffile, err := os.Open(pdffn)
-		if err != nil {
-			sheet.EmitError("generate pdf failed", err)
-			return err
+	}
+	if !found {
+		log.Warnf("sheet %v: geopdf requested but the template did not place the image, use .Image.Place", sheet.Name)
+		return nil
+	}
+	Emit(ctx, field.Processing{
+		Description: fmt.Sprintf("georeference file: %v ", filepath.Base(pdffn)),
+	})
+	if err := geopdf.Georeference(pdffn, viewports); err != nil {
+		log.Warnf("error georeferencing pdf: %v", err)
+		EmitError(ctx, "georeference pdf failed", err)
+		return err
+	}
+	return nil
+}
+
+// setPrintBoxes sets the bleed and trim boxes of the pages of the pdf. Pages with a nil
+// context, or without a bleed or crop marks, are left as they are.
+func setPrintBoxes(ctx context.Context, sheet *Sheet, pdffn string, pages ...*GridTemplateContext) error {
+	var (
+		boxes = make([]svg2pdf.PageBoxes, len(pages))
+		found bool
+	)
+	for i, gtc := range pages {
+		if gtc != nil && gtc.HasBleed() {
+			boxes[i] = gtc.PageBoxes()
+			found = true
 		}
-		io.Copy(wrts, pdffile)
 	}
-	sheet.Emit(field.Completed{})
+	if !found {
+		return nil
+	}
+	if err := svg2pdf.SetPageBoxes(pdffn, boxes); err != nil {
+		log.Warnf("error setting pdf page boxes: %v", err)
+		EmitError(ctx, "set pdf trim box failed", err)
+		return err
+	}
+	return nil
+}
+
+// copyToFilestore copies the generated file from the assets to the filestore of the sheet
+func copyToFilestore(ctx context.Context, sheet *Sheet, assetsWriter fsfile.Writer, multiWriter fsmulti.FileWriter, filename string) error {
+	if len(multiWriter.Writers) <= 1 {
+		return nil
+	}
+	// Don't want the assets writer
+	wrts, err := multiWriter.Writers[1].Writer(filename, false)
+	if err != nil {
+		EmitError(ctx, "generate pdf failed", err)
+		return err
+	}
+	// nil writer move on.
+	if wrts == nil {
+		return nil
+	}
+	// Copy the pdf over
+	pdffile, err := os.Open(assetsWriter.Path(filename))
+	if err != nil {
+		wrts.Close()
+		EmitError(ctx, "generate pdf failed", err)
+		return err
+	}
+	defer pdffile.Close()
+	_, err = io.Copy(wrts, pdffile)
+	// the filestores are done writing once they are closed
+	if cerr := wrts.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		EmitError(ctx, "failed to copy file to file stores", err)
+		return err
+	}
 	return nil
 }
 
@@ -351,7 +623,6 @@ type Atlante struct {
 	sLock         sync.RWMutex
 	sheets        map[string]*Sheet
 	Notifier      notifiers.Provider
-	JobID         string
 }
 
 func (a *Atlante) Shutdown() {}
@@ -374,26 +645,58 @@ func (a *Atlante) FilenamesForCell(sheetName string, cell *grids.Cell) *Generate
 	return NewGeneratedFilesFromTpl(filenameGenerator, sheetName, cell, a.workDirectory)
 }
 
+// emitResult emits the final status of the job to the emitter of ctx. A job the queue
+// cancelled is reported as cancelled instead of failed; a job whose context was cancelled
+// otherwise, such as by its worker stopping or losing the job, emits nothing as the queue
+// runs it again.
+func (a *Atlante) emitResult(ctx context.Context, err error) {
+	emitter := EmitterFrom(ctx)
+	if emitter == nil {
+		return
+	}
+	if err != nil && ctx.Err() == context.Canceled {
+		if !jobStopped(ctx) {
+			emitter.Emit(field.Cancelled{})
+		}
+		return
+	}
+	if err != nil {
+		emitter.Emit(field.Failed{Error: err})
+	} else {
+		emitter.Emit(field.Completed{})
+	}
+}
+
 func (a *Atlante) generatePDF(ctx context.Context, sheet *Sheet, grid *grids.Cell, filenameTemplate string) (*GeneratedFiles, error) {
 	filenames, err := a.filenamesForCell(sheet.Name, grid, filenameTemplate)
 	if err != nil {
 		return nil, err
 	}
-	if a.Notifier != nil && a.JobID != "" {
-		sheet.Emitter, err = a.Notifier.NewEmitter(a.JobID)
-		if err != nil {
-			sheet.Emitter = nil
-			log.Warnf("Failed to init emitter: %v", err)
-		}
-	}
 	err = GeneratePDF(ctx, sheet, grid, filenames)
-	if sheet.Emitter != nil {
-		if err != nil {
-			sheet.Emitter.Emit(field.Failed{Error: err})
-		} else {
-			sheet.Emitter.Emit(field.Completed{})
+	a.emitResult(ctx, err)
+	return filenames, err
+}
+
+// generateAtlas generates the multi-page pdf for the pages of the job. The metadata of
+// the job (style, etc...) is applied to each of the pages.
+func (a *Atlante) generateAtlas(ctx context.Context, sheet *Sheet, job *Job, filenameTemplate string) (*GeneratedFiles, error) {
+	filenames, err := a.filenamesForCell(sheet.Name, job.Cell, filenameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range job.Pages {
+		if page.MetaData == nil {
+			page.MetaData = make(map[string]string, len(job.MetaData))
+		}
+		for k, v := range job.MetaData {
+			if k == "filename" {
+				continue
+			}
+			page.MetaData[k] = v
 		}
 	}
+	err = GenerateAtlas(ctx, sheet, job.Pages, AtlasOptionsFromMetaData(job.MetaData), filenames)
+	a.emitResult(ctx, err)
 	return filenames, err
 }
 
@@ -402,17 +705,11 @@ func (a *Atlante) GeneratePDFLatLng(ctx context.Context, sheetName string, style
 		return nil, ErrNilAtlanteObject
 	}
 
-	if len(a.sheets) == 0 {
-		return nil, ErrNoSheets
-	}
-
-	a.sLock.RLock()
-	provider, ok := a.sheets[sheetName]
-	if !ok || provider == nil {
-		a.sLock.Unlock()
-		return nil, fmt.Errorf("atlante sheet (%v) not found", sheetName)
+	provider, err := a.acquireSheet(sheetName)
+	if err != nil {
+		return nil, err
 	}
-	a.sLock.Unlock()
+	defer provider.release()
 
 	cell, err := provider.CellForLatLng(lat, lng, uint(srid))
 	if err != nil {
@@ -433,12 +730,22 @@ func (a *Atlante) GeneratePDFLatLng(ctx context.Context, sheetName string, style
 
 }
 
+// GeneratePDFJob generates the pdf, or atlas, of the job; the statuses of the job are
+// emitted to the emitter of ctx, or a new emitter for the job if ctx does not carry one
 func (a *Atlante) GeneratePDFJob(ctx context.Context, job Job, filenameTemplate string) (*GeneratedFiles, error) {
+	// the statuses of the job go to its own emitter, as jobs may run at the same time
+	return a.generatePDFJob(a.jobContext(ctx, job), job, filenameTemplate)
+}
+
+// generatePDFJob generates the pdf, or atlas, of the job emitting the statuses to the
+// emitter of ctx
+func (a *Atlante) generatePDFJob(ctx context.Context, job Job, filenameTemplate string) (*GeneratedFiles, error) {
 	cell := job.Cell
-	sheet, err := a.SheetFor(job.SheetName)
+	sheet, err := a.acquireSheet(job.SheetName)
 	if err != nil {
 		return nil, err
 	}
+	defer sheet.release()
 	if cell.MetaData == nil {
 		cell.MetaData = make(map[string]string, len(job.MetaData))
 	}
@@ -446,16 +753,19 @@ func (a *Atlante) GeneratePDFJob(ctx context.Context, job Job, filenameTemplate
 	for k, v := range job.MetaData {
 		cell.MetaData[k] = v
 	}
-	a.JobID = job.MetaData["job_id"]
+	if job.IsAtlas() {
+		return a.generateAtlas(ctx, sheet, &job, filenameTemplate)
+	}
 	return a.generatePDF(ctx, sheet, cell, filenameTemplate)
 }
 
 func (a *Atlante) GeneratePDFMDGID(ctx context.Context, sheetName string, styleName string, mdgID *grids.MDGID, filenameTemplate string) (*GeneratedFiles, error) {
 
-	sheet, err := a.SheetFor(sheetName)
+	sheet, err := a.acquireSheet(sheetName)
 	if err != nil {
 		return nil, err
 	}
+	defer sheet.release()
 
 	cell, err := sheet.CellForMDGID(mdgID)
 	if err != nil {
@@ -470,10 +780,11 @@ func (a *Atlante) GeneratePDFMDGID(ctx context.Context, sheetName string, styleN
 }
 
 func (a *Atlante) GeneratePDFBounds(ctx context.Context, sheetName string, styleName string, bounds geom.Extent, srid uint, filenameTemplate string) (*GeneratedFiles, error) {
-	sheet, err := a.SheetFor(sheetName)
+	sheet, err := a.acquireSheet(sheetName)
 	if err != nil {
 		return nil, err
 	}
+	defer sheet.release()
 
 	cell, err := sheet.CellForBounds(bounds, srid)
 	if err != nil {
@@ -0,0 +1,389 @@
+package atlante
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/go-spatial/atlante/svg2pdf"
+	"github.com/go-spatial/geom"
+	"github.com/prometheus/common/log"
+)
+
+const (
+	// AtlasMDGID is the mdgid of the synthetic cell that describes an atlas job
+	AtlasMDGID = "atlas"
+
+	// MetaDataKeyAtlasTitle is the job metadata key for the title of the atlas
+	MetaDataKeyAtlasTitle = "atlas_title"
+	// MetaDataKeyAtlasCover is the job metadata key for weather to add a cover page
+	MetaDataKeyAtlasCover = "atlas_cover"
+	// MetaDataKeyAtlasIndex is the job metadata key for weather to add an index of the pages
+	MetaDataKeyAtlasIndex = "atlas_index"
+	// MetaDataKeyAtlasPageNumbers is the job metadata key for weather the pages should be numbered
+	MetaDataKeyAtlasPageNumbers = "atlas_page_numbers"
+)
+
+// AtlasOptions are the options for the front matter of an atlas
+type AtlasOptions struct {
+	Title       string
+	Cover       bool
+	Index       bool
+	PageNumbers bool
+}
+
+// AtlasOptionsFromMetaData returns the atlas options from the job metadata
+func AtlasOptionsFromMetaData(md map[string]string) AtlasOptions {
+	b := func(key string) bool {
+		v, _ := strconv.ParseBool(md[key])
+		return v
+	}
+	return AtlasOptions{
+		Title:       md[MetaDataKeyAtlasTitle],
+		Cover:       b(MetaDataKeyAtlasCover),
+		Index:       b(MetaDataKeyAtlasIndex),
+		PageNumbers: b(MetaDataKeyAtlasPageNumbers),
+	}
+}
+
+// MetaData sets the options into the given job metadata
+func (opts AtlasOptions) MetaData(md map[string]string) map[string]string {
+	if md == nil {
+		md = make(map[string]string, 4)
+	}
+	if opts.Title != "" {
+		md[MetaDataKeyAtlasTitle] = opts.Title
+	}
+	md[MetaDataKeyAtlasCover] = strconv.FormatBool(opts.Cover)
+	md[MetaDataKeyAtlasIndex] = strconv.FormatBool(opts.Index)
+	md[MetaDataKeyAtlasPageNumbers] = strconv.FormatBool(opts.PageNumbers)
+	return md
+}
+
+// NewAtlasJob returns a new job that will render each of the pages into a single pdf.
+// The cell of the job is a synthetic cell covering all the pages, and is used for
+// the filenames and job lookup.
+func NewAtlasJob(sheet string, pages []*grids.Cell, opts AtlasOptions, metadata map[string]string) *Job {
+	jb := NewJob(sheet, NewAtlasCell(sheet, pages, opts.Title), opts.MetaData(metadata))
+	jb.Pages = pages
+	return jb
+}
+
+// NewAtlasCell returns a cell covering all of the given pages. The filename metadata
+// is a hash of the mdgids of the pages, so the same set of pages will generate
+// the same filenames.
+func NewAtlasCell(sheet string, pages []*grids.Cell, title string) *grids.Cell {
+	var (
+		ext    *geom.Extent
+		hash   = sha1.New()
+		series string
+	)
+	for i, page := range pages {
+		if i == 0 {
+			ext = geom.NewExtent(page.SW(), page.NE())
+			series = page.GetSeries()
+		} else {
+			ext.AddPoints(page.SW(), page.NE())
+		}
+		fmt.Fprintln(hash, page.GetMdgid().AsString())
+	}
+	if ext == nil {
+		ext = new(geom.Extent)
+	}
+	if title == "" {
+		title = fmt.Sprintf("%v atlas", sheet)
+	}
+	filename := fmt.Sprintf("%v_%x", AtlasMDGID, hash.Sum(nil)[:6])
+	return grids.NewCell(
+		AtlasMDGID,
+		[2]float64{ext.MinY(), ext.MinX()},
+		[2]float64{ext.MaxY(), ext.MaxX()},
+		"", "", nil, nil, time.Now(), "",
+		title,
+		series,
+		[2]string{}, [2]string{},
+		map[string]string{"filename": filename},
+	)
+}
+
+// IsAtlas returns weather the job is for a multi-page atlas
+func (j *Job) IsAtlas() bool { return len(j.GetPages()) != 0 }
+
+// AtlasPage is an entry in the index of an atlas
+type AtlasPage struct {
+	// Number is the page number of the page in the atlas
+	Number uint
+	*grids.Cell
+}
+
+// MDGID returns the mdgid of the page
+func (ap AtlasPage) MDGID() string { return ap.GetMdgid().AsString() }
+
+// AtlasTemplateContext is the context the cover and index templates are executed with
+type AtlasTemplateContext struct {
+	Title     string
+	SheetName string
+	Date      time.Time
+	// Pages are the map pages of the atlas, for the index page it is only the
+	// entries that are on that index page.
+	Pages []AtlasPage
+	// IndexPage is the number of the index page, starting at 1
+	IndexPage  uint
+	IndexPages uint
+	// Width and Height of the page in points
+	Width  float64
+	Height float64
+}
+
+// Rows returns the y position, in points, of each of the entries of the index page
+func (atc AtlasTemplateContext) Rows() []float64 {
+	rows := make([]float64, len(atc.Pages))
+	for i := range rows {
+		rows[i] = atlasIndexTop + float64(i+1)*atlasIndexLineHeight
+	}
+	return rows
+}
+
+const (
+	// size, in points, of the parts of the index page
+	atlasIndexMargin     = 36
+	atlasIndexTop        = atlasIndexMargin + 60
+	atlasIndexLineHeight = 14
+)
+
+// atlasIndexRows returns the number of entries that will fit on an index page
+// for a page of the given height in points
+func atlasIndexRows(height float64) int {
+	rows := int((height - atlasIndexTop - atlasIndexMargin) / atlasIndexLineHeight)
+	if rows < 1 {
+		return 1
+	}
+	return rows
+}
+
+var atlasFuncs = template.FuncMap{
+	"escape": template.HTMLEscapeString,
+}
+
+var atlasCoverTemplate = template.Must(template.New("cover").Funcs(atlasFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}pt" height="{{.Height}}pt" viewBox="0 0 {{.Width}} {{.Height}}">
+  <rect x="0" y="0" width="{{.Width}}" height="{{.Height}}" fill="white"/>
+  <text x="50%" y="40%" text-anchor="middle" font-family="sans-serif" font-size="48">{{escape .Title}}</text>
+  <text x="50%" y="48%" text-anchor="middle" font-family="sans-serif" font-size="24">{{escape .SheetName}}</text>
+  <text x="50%" y="54%" text-anchor="middle" font-family="sans-serif" font-size="18">{{len .Pages}} sheets</text>
+  <text x="50%" y="90%" text-anchor="middle" font-family="sans-serif" font-size="14">{{.Date.Format "2 January 2006"}}</text>
+</svg>
+`))
+
+var atlasIndexTemplate = template.Must(template.New("index").Funcs(atlasFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}pt" height="{{.Height}}pt" viewBox="0 0 {{.Width}} {{.Height}}">
+  <rect x="0" y="0" width="{{.Width}}" height="{{.Height}}" fill="white"/>
+  <text x="36" y="60" font-family="sans-serif" font-size="24">{{escape .Title}} — Index{{if gt .IndexPages 1}} ({{.IndexPage}} of {{.IndexPages}}){{end}}</text>
+  <g font-family="sans-serif" font-size="10">
+    <text x="36" y="96" font-weight="bold">Page</text>
+    <text x="96" y="96" font-weight="bold">Sheet</text>
+    <text x="216" y="96" font-weight="bold">MDGID</text>
+    <text x="336" y="96" font-weight="bold">Series</text>
+{{- $rows := .Rows }}
+{{- range $i, $page := .Pages }}
+    <text x="36" y="{{index $rows $i}}">{{if $page.Number}}{{$page.Number}}{{end}}</text>
+    <text x="96" y="{{index $rows $i}}">{{escape $page.SheetNumber}}</text>
+    <text x="216" y="{{index $rows $i}}">{{escape $page.MDGID}}</text>
+    <text x="336" y="{{index $rows $i}}">{{escape $page.GetSeries}}</text>
+{{- end }}
+  </g>
+</svg>
+`))
+
+// atlasPageFilenames returns the filenames for the given page of the atlas
+func atlasPageFilenames(filenames *GeneratedFiles, name string) *GeneratedFiles {
+	fn := func(base string, ext string) string {
+		return strings.TrimSuffix(base, filepath.Ext(base)) + "_" + name + "." + ext
+	}
+	return &GeneratedFiles{
+		IMG: fn(filenames.IMG, "png"),
+		SVG: fn(filenames.SVG, "svg"),
+		PDF: fn(filenames.PDF, "pdf"),
+		TIF: fn(filenames.TIF, "tif"),
+	}
+}
+
+// GenerateAtlas will generate a single PDF with a page for each of the given cells, optionally
+// preceded by a cover page and an index of the pages. The progress of each page is emitted.
+func GenerateAtlas(ctx context.Context, sheet *Sheet, pages []*grids.Cell, opts AtlasOptions, filenames *GeneratedFiles) error {
+	if len(pages) == 0 {
+		return ErrNoAtlasPages
+	}
+
+	useCached := useCachedImages()
+
+	Emit(ctx, field.Started{})
+
+	assetsWriter, multiWriter, err := sheetWriters(ctx, sheet, "")
+	if err != nil {
+		return err
+	}
+
+	widthpts, heightpts := float64(sheet.WidthInPoints(72)), float64(sheet.HeightInPoints(72))
+	title := opts.Title
+	if title == "" {
+		title = fmt.Sprintf("%v atlas", sheet.Name)
+	}
+
+	var (
+		front      uint
+		indexPages uint
+		rows       = atlasIndexRows(heightpts)
+	)
+	if opts.Cover {
+		front++
+	}
+	if opts.Index {
+		indexPages = uint(math.Ceil(float64(len(pages)) / float64(rows)))
+		front += indexPages
+	}
+	total := front + uint(len(pages))
+
+	var (
+		pdfPages = make([]svg2pdf.Page, 0, total)
+		// the template context of each page, nil for the front pages
+		contexts = make([]*GridTemplateContext, 0, total)
+		entries  = make([]AtlasPage, len(pages))
+		number   = func(i uint) uint {
+			if !opts.PageNumbers {
+				return 0
+			}
+			return i
+		}
+	)
+
+	log.Infoln("atlas filenames: ", filenames.SVG, filenames.PDF)
+
+	writeFrontPage := func(name string, tpl *template.Template, atc AtlasTemplateContext) error {
+		fn := atlasPageFilenames(filenames, name).SVG
+		file, err := multiWriter.Writer(fn, true)
+		if err != nil {
+			EmitError(ctx, "failed to copy files", err)
+			return err
+		}
+		defer file.Close()
+		if err = tpl.Execute(file, atc); err != nil {
+			EmitError(ctx, "template processing failure", err)
+			return err
+		}
+		pdfPages = append(pdfPages, svg2pdf.Page{
+			Filename: assetsWriter.Path(fn),
+			Width:    widthpts,
+			Height:   heightpts,
+		})
+		contexts = append(contexts, nil)
+		return nil
+	}
+
+	for i, page := range pages {
+		entries[i] = AtlasPage{
+			Number: number(front + uint(i) + 1),
+			Cell:   page,
+		}
+	}
+
+	atc := AtlasTemplateContext{
+		Title:     title,
+		SheetName: sheet.Name,
+		Date:      time.Now(),
+		Pages:     entries,
+		Width:     widthpts,
+		Height:    heightpts,
+	}
+
+	if opts.Cover {
+		Emit(ctx, field.Processing{
+			Description: fmt.Sprintf("page %d of %d: cover", 1, total),
+		})
+		if err = writeFrontPage("cover", atlasCoverTemplate, atc); err != nil {
+			return err
+		}
+	}
+
+	for i := uint(0); i < indexPages; i++ {
+		start, end := int(i)*rows, int(i+1)*rows
+		if end > len(entries) {
+			end = len(entries)
+		}
+		iatc := atc
+		iatc.Pages = entries[start:end]
+		iatc.IndexPage, iatc.IndexPages = i+1, indexPages
+		Emit(ctx, field.Processing{
+			Description: fmt.Sprintf("page %d of %d: index", uint(len(pdfPages))+1, total),
+		})
+		if err = writeFrontPage(fmt.Sprintf("index%03d", i+1), atlasIndexTemplate, iatc); err != nil {
+			return err
+		}
+	}
+
+	for i, page := range pages {
+		pageNumber := front + uint(i) + 1
+		mdgid := page.GetMdgid().AsString()
+		Emit(ctx, field.Processing{
+			Description: fmt.Sprintf("page %d of %d: %v", pageNumber, total, mdgid),
+		})
+		pageFilenames := atlasPageFilenames(filenames, fmt.Sprintf("p%03d", pageNumber))
+		gtc, err := renderSVG(ctx, sheet, page, pageFilenames, multiWriter, useCached, number(pageNumber), number(total))
+		if err != nil {
+			return err
+		}
+		pdfPages = append(pdfPages, svg2pdf.Page{
+			Filename: assetsWriter.Path(pageFilenames.SVG),
+			Width:    gtc.Width,
+			Height:   gtc.Height,
+		})
+		contexts = append(contexts, gtc)
+	}
+
+	Emit(ctx, field.Processing{
+		Description: fmt.Sprintf("generate file: %v ", filenames.PDF),
+	})
+
+	converter, err := sheet.pdfConverter()
+	if err != nil {
+		EmitError(ctx, "generate pdf failed", err)
+		return err
+	}
+	pdffn := assetsWriter.Path(filenames.PDF)
+	if err = converter.GenerateMultiPagePDF(pdfPages, pdffn); err != nil {
+		log.Warnf("error generating pdf: %v", err)
+		EmitError(ctx, "generate pdf failed", err)
+		return err
+	}
+	if err = setPrintBoxes(ctx, sheet, pdffn, contexts...); err != nil {
+		return err
+	}
+	if sheet.GeoPDF {
+		if err = georeference(ctx, sheet, pdffn, contexts...); err != nil {
+			return err
+		}
+	}
+	mdc := newPDFMetadataContext(sheet, pages[0])
+	mdc.MDGID, mdc.Atlas, mdc.Pages = "", title, total
+	if err = writePDFMetadata(ctx, sheet, pdffn, mdc); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		EmitError(ctx, "generate pdf canceled", ctx.Err())
+		return ctx.Err()
+	}
+
+	if err = copyToFilestore(ctx, sheet, assetsWriter, multiWriter, filenames.PDF); err != nil {
+		return err
+	}
+	Emit(ctx, field.Completed{})
+	return nil
+}
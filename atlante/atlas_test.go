@@ -0,0 +1,105 @@
+package atlante
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+)
+
+func TestAtlasOptionsMetaData(t *testing.T) {
+	type tcase struct {
+		opts AtlasOptions
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%+v", tc.opts), func(t *testing.T) {
+			md := tc.opts.MetaData(map[string]string{"styleName": "topo"})
+			if md["styleName"] != "topo" {
+				t.Errorf("styleName, expected topo got %v", md["styleName"])
+			}
+			got := AtlasOptionsFromMetaData(md)
+			if got != tc.opts {
+				t.Errorf("options, expected %+v got %+v", tc.opts, got)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{},
+		{opts: AtlasOptions{Title: "San Diego", Cover: true}},
+		{opts: AtlasOptions{Index: true, PageNumbers: true}},
+		{opts: AtlasOptions{Title: "all", Cover: true, Index: true, PageNumbers: true}},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestNewAtlasJob(t *testing.T) {
+	newCell := func(mdgid string, sw, ne [2]float64) *grids.Cell {
+		return grids.NewCell(mdgid, sw, ne, "", "", nil, nil, time.Time{}, "", "", "V795", [2]string{}, [2]string{}, nil)
+	}
+
+	type tcase struct {
+		pages    []*grids.Cell
+		title    string
+		sheet    string
+		sw, ne   [2]float32
+		filename string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.filename, func(t *testing.T) {
+			jb := NewAtlasJob("50k", tc.pages, AtlasOptions{Title: tc.title}, nil)
+			if !jb.IsAtlas() {
+				t.Errorf("is atlas, expected true got false")
+			}
+			if len(jb.Pages) != len(tc.pages) {
+				t.Errorf("pages, expected %v got %v", len(tc.pages), len(jb.Pages))
+			}
+			cell := jb.Cell
+			if cell.GetMdgid().AsString() != AtlasMDGID {
+				t.Errorf("mdgid, expected %v got %v", AtlasMDGID, cell.GetMdgid().AsString())
+			}
+			if cell.Sheet != tc.sheet {
+				t.Errorf("sheet, expected %v got %v", tc.sheet, cell.Sheet)
+			}
+			if sw := [2]float32{cell.Sw.Lat, cell.Sw.Lng}; sw != tc.sw {
+				t.Errorf("sw, expected %v got %v", tc.sw, sw)
+			}
+			if ne := [2]float32{cell.Ne.Lat, cell.Ne.Lng}; ne != tc.ne {
+				t.Errorf("ne, expected %v got %v", tc.ne, ne)
+			}
+			if cell.MetaData["filename"] != tc.filename {
+				t.Errorf("filename, expected %v got %v", tc.filename, cell.MetaData["filename"])
+			}
+		}
+	}
+
+	pageA := newCell("V795G25492", [2]float64{32.5, -117.25}, [2]float64{32.75, -117.0})
+	pageB := newCell("V795G25493", [2]float64{32.5, -117.0}, [2]float64{32.75, -116.75})
+
+	tests := []tcase{
+		{
+			pages:    []*grids.Cell{pageA, pageB},
+			title:    "San Diego",
+			sheet:    "San Diego",
+			sw:       [2]float32{32.5, -117.25},
+			ne:       [2]float32{32.75, -116.75},
+			filename: "atlas_9a77c32a8557",
+		},
+		{
+			pages: []*grids.Cell{pageB},
+			// default title
+			sheet:    "50k atlas",
+			sw:       [2]float32{32.5, -117.0},
+			ne:       [2]float32{32.75, -116.75},
+			filename: "atlas_046b0a1eeec6",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -0,0 +1,61 @@
+package atlante
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type jobCancelKey struct{}
+
+// jobCancel records the cause a job context was cancelled with
+type jobCancel struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock  sync.Mutex
+	cause error
+}
+
+func (jc *jobCancel) Cancel(cause error) {
+	jc.lock.Lock()
+	// only the first cancel of a context that is not already done has a cause
+	if jc.cause == nil && jc.ctx.Err() == nil {
+		jc.cause = cause
+	}
+	jc.lock.Unlock()
+	jc.cancel()
+}
+
+// WithJobCancel returns a copy of ctx, and a function that cancels it with the given
+// cause. Queues cancel a job with ErrJobCancelled; a job whose context is cancelled for
+// any other reason, such as its worker stopping, is not reported as cancelled.
+func WithJobCancel(ctx context.Context) (context.Context, func(cause error)) {
+	jc := new(jobCancel)
+	jc.ctx, jc.cancel = context.WithCancel(ctx)
+	jc.ctx = context.WithValue(jc.ctx, jobCancelKey{}, jc)
+	return jc.ctx, jc.Cancel
+}
+
+// CancelCause returns the cause the job context was cancelled with; ctx.Err() if it was
+// not cancelled with a cause, and nil if it is not done.
+func CancelCause(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	if jc, ok := ctx.Value(jobCancelKey{}).(*jobCancel); ok {
+		jc.lock.Lock()
+		cause := jc.cause
+		jc.lock.Unlock()
+		if cause != nil {
+			return cause
+		}
+	}
+	return ctx.Err()
+}
+
+// jobStopped returns weather the context of the job was cancelled without the job being
+// cancelled; the job is run again, so its failure is not emitted
+func jobStopped(ctx context.Context) bool {
+	return ctx.Err() == context.Canceled && !errors.Is(CancelCause(ctx), ErrJobCancelled)
+}
@@ -0,0 +1,85 @@
+package atlante
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+)
+
+func TestEmitResultCancelled(t *testing.T) {
+	type tcase struct {
+		name string
+		// stop cancels the context of the job
+		stop func(parent context.CancelFunc, cancel func(error))
+		err  error
+		// expected are the statuses emitted, by type
+		expected []string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			emitter := new(recordingEmitter)
+			parent, parentCancel := context.WithCancel(WithEmitter(context.Background(), emitter))
+			defer parentCancel()
+			ctx, cancel := WithJobCancel(parent)
+			defer cancel(nil)
+			if tc.stop != nil {
+				tc.stop(parentCancel, cancel)
+			}
+			EmitError(ctx, "render", tc.err)
+			new(Atlante).emitResult(ctx, tc.err)
+
+			got := make([]string, len(emitter.statuses))
+			for i, status := range emitter.statuses {
+				got[i] = fmt.Sprintf("%T", status)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.expected) {
+				t.Errorf("statuses, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	cancelled := fmt.Sprintf("%T", field.Cancelled{})
+	failed := fmt.Sprintf("%T", field.Failed{})
+	tests := []tcase{
+		{
+			name:     "completed",
+			expected: []string{fmt.Sprintf("%T", field.Completed{})},
+		},
+		{
+			name:     "failed",
+			err:      errors.New("render failed"),
+			expected: []string{failed, failed},
+		},
+		{
+			name:     "cancelled by the queue",
+			stop:     func(_ context.CancelFunc, cancel func(error)) { cancel(ErrJobCancelled) },
+			err:      context.Canceled,
+			expected: []string{failed, cancelled},
+		},
+		{
+			name: "stopped",
+			stop: func(_ context.CancelFunc, cancel func(error)) { cancel(nil) },
+			err:  context.Canceled,
+		},
+		{
+			name: "worker killed",
+			stop: func(parent context.CancelFunc, _ func(error)) { parent() },
+			err:  context.Canceled,
+		},
+		{
+			name: "cancelled after the worker was killed",
+			stop: func(parent context.CancelFunc, cancel func(error)) {
+				parent()
+				cancel(ErrJobCancelled)
+			},
+			err: context.Canceled,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
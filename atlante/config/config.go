@@ -1,8 +1,8 @@
 package config
 
 import (
-	"errors"
 	"io"
+	"io/ioutil"
 	"net/url"
 
 	"github.com/BurntSushi/toml"
@@ -22,6 +22,9 @@ type Config struct {
 
 	Notifier env.Dict `toml:"notifier"`
 
+	// Renderer is the renderer of the map images, defaults to mbgl
+	Renderer env.Dict `toml:"renderer"`
+
 	Providers []env.Dict `toml:"providers"`
 	Sheets    []Sheet    `toml:"sheets"`
 
@@ -34,9 +37,16 @@ type Config struct {
 
 	// Styles are the styles that can be used
 	Styles []Style `toml:"styles" `
+
+	// SkipTemplateCheck skips checking that the templates of the sheets can be
+	// reached when the config is validated, for validating offline
+	SkipTemplateCheck env.Bool `toml:"skip_template_check"`
 	// metadata holds the metadata from parsing the toml
 	// file
 	metadata toml.MetaData `toml:"-"`
+	// positions are the files and lines the keys were set
+	// on, used to report the lines of validation errors
+	positions map[string]position `toml:"-"`
 }
 
 // Style describes information about the various styles that will be
@@ -56,7 +66,11 @@ type Webserver struct {
 	Headers               map[string]string `toml:"headers"`
 	Queue                 env.Dict          `toml:"queue"`
 	DisableNotificationEP bool              `toml:"disable_notification_endpoint"`
+	EnableReloadEP        bool              `toml:"enable_reload_endpoint"`
 	Coordinator           env.Dict          `toml:"coordinator"`
+	RequesterHeader       env.String        `toml:"requester_header"`
+	APIKeys               env.Dict          `toml:"api_keys"`
+	MaxPriority           env.Int           `toml:"max_priority"`
 }
 
 // Sheet models a sheet in the config file
@@ -71,39 +85,83 @@ type Sheet struct {
 	Description  env.String     `toml:"description"`
 	Width        env.Float      `toml:"width"`
 	Height       env.Float      `toml:"height"`
-}
-
-// Validate will validate the config and make sure the is valid
-func (c *Config) Validate() error {
-	// TODO(gdey): Actually do the validation
-	if c == nil {
-		return errors.New("error config not initialized")
-	}
-	return nil
+	GeoPDF       env.Bool       `toml:"geopdf"`
+	GeoTIFF      env.Bool       `toml:"geotiff"`
+	PaperSize    env.String     `toml:"paper_size"`
+	Orientation  env.String     `toml:"orientation"`
+	PaperMargin  env.Float      `toml:"paper_margin"`
+
+	PosterPaperSize   env.String `toml:"poster_paper_size"`
+	PosterOrientation env.String `toml:"poster_orientation"`
+	PosterOverlap     env.Float  `toml:"poster_overlap"`
+	PosterMargin      env.Float  `toml:"poster_margin"`
+
+	Bleed      env.Float `toml:"bleed"`
+	CropMarks  env.Bool  `toml:"crop_marks"`
+	LiveMargin env.Float `toml:"live_margin"`
+
+	PDFTitle    env.String `toml:"pdf_title"`
+	PDFAuthor   env.String `toml:"pdf_author"`
+	PDFSubject  env.String `toml:"pdf_subject"`
+	PDFKeywords env.String `toml:"pdf_keywords"`
+	Series      env.String `toml:"series"`
+	Edition     env.String `toml:"edition"`
+	PDFA        env.Bool   `toml:"pdfa"`
+
+	RetryMaxAttempts env.Int        `toml:"retry_max_attempts"`
+	RetryBackoff     env.Int        `toml:"retry_backoff"`
+	RetryMaxBackoff  env.Int        `toml:"retry_max_backoff"`
+	RetryMultiplier  env.Float      `toml:"retry_multiplier"`
+	RetryOn          env.StringList `toml:"retry_on"`
 }
 
 // Parse will parse a config file in the io.Reader
 func Parse(reader io.Reader, fileLocation *url.URL) (conf Config, err error) {
-	// decode conf file, don't care about the meta data.
-	_, err = toml.DecodeReader(reader, &conf)
+	source, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return conf, err
+	}
+	conf.metadata, err = toml.Decode(string(source), &conf)
 	conf.FileLocation = fileLocation
+	conf.positions = linePositions(source, conf.metadata, "")
 
 	return conf, err
 }
 
 // Load will load and parse the config file from the given location.
-func Load(location *url.URL) (conf Config, err error) {
+// The files listed in the include key of the config are loaded first, and
+// the config is merged on top of them, then the overlays are merged on top
+// of the config, in order. Include paths are relative to the file including
+// them. Tables are merged key by key, and the providers, file stores, styles
+// and sheets by name; other values are replaced.
+func Load(location *url.URL, overlays ...*url.URL) (conf Config, err error) {
 	err = urlutil.VisitReader(location, func(r io.Reader) error {
 		var e error
 		conf, e = Parse(r, location)
 		return e
 	})
-	return conf, err
+	if err != nil || (len(overlays) == 0 && !conf.metadata.IsDefined(KeyInclude)) {
+		return conf, err
+	}
+
+	loading := make(map[string]bool)
+	doc, err := loadDocument(location, true, loading, 0)
+	if err != nil {
+		return conf, err
+	}
+	for _, overlay := range overlays {
+		odoc, err := loadDocument(overlay, false, loading, 0)
+		if err != nil {
+			return conf, err
+		}
+		doc.merge(odoc)
+	}
+	return doc.decode(location)
 }
 
 // LoadAndValidate is helper function that just calls load and then validate
-func LoadAndValidate(location *url.URL) (cfg Config, err error) {
-	cfg, err = Load(location)
+func LoadAndValidate(location *url.URL, overlays ...*url.URL) (cfg Config, err error) {
+	cfg, err = Load(location, overlays...)
 	if err != nil {
 		return cfg, err
 	}
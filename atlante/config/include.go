@@ -0,0 +1,249 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante/internal/urlutil"
+)
+
+const (
+	// KeyInclude is the config key for the list of files to include
+	KeyInclude = "include"
+
+	// MaxIncludeDepth is the max depth of nested includes
+	MaxIncludeDepth = 10
+
+	// ErrIncludeDepth is returned when includes are nested deeper than MaxIncludeDepth
+	ErrIncludeDepth = errors.String("includes are nested too deep")
+)
+
+// ErrIncludeCycle is returned when a file includes itself, directly or through other files
+type ErrIncludeCycle string
+
+func (err ErrIncludeCycle) Error() string {
+	return fmt.Sprintf("include cycle: %v includes itself", string(err))
+}
+
+// mergedByName are the arrays of tables whose entries are merged by name
+var mergedByName = map[string]bool{
+	SectionProviders:  true,
+	SectionFileStores: true,
+	"sheets":          true,
+	"styles":          true,
+}
+
+// position is the file, and line in the file, a key was set on
+type position struct {
+	// File is empty for the main config file
+	File string
+	Line int
+}
+
+// linePositions returns the positions of the keys of the toml source of the file
+func linePositions(source []byte, md toml.MetaData, file string) map[string]position {
+	lines := keyLines(source, md)
+	positions := make(map[string]position, len(lines))
+	for k, line := range lines {
+		positions[k] = position{File: file, Line: line}
+	}
+	return positions
+}
+
+// document is the decoded, but not typed, values of a config file merged with the
+// files it includes
+type document struct {
+	values    map[string]interface{}
+	positions map[string]position
+}
+
+// joinPath joins the key to the path of its table
+func joinPath(table, key string) string {
+	if table == "" {
+		return key
+	}
+	return table + "." + key
+}
+
+// copyPositions replaces the positions of the key at dstPath and its children with the
+// positions of the key at srcPath in the src document
+func (doc *document) copyPositions(dstPath string, src document, srcPath string) {
+	for k := range doc.positions {
+		if k == dstPath || strings.HasPrefix(k, dstPath+".") {
+			delete(doc.positions, k)
+		}
+	}
+	for k, pos := range src.positions {
+		if k == srcPath || strings.HasPrefix(k, srcPath+".") {
+			doc.positions[dstPath+k[len(srcPath):]] = pos
+		}
+	}
+}
+
+// mergeTable merges the src table into the dst table; tables are merged key by key, and
+// the entries of the arrays of tables in mergedByName by name, other values are replaced
+func (doc *document) mergeTable(dstPath string, dst map[string]interface{}, src document, srcPath string, values map[string]interface{}) {
+	for key, val := range values {
+		dp, sp := joinPath(dstPath, key), joinPath(srcPath, key)
+		switch v := val.(type) {
+		case map[string]interface{}:
+			if table, ok := dst[key].(map[string]interface{}); ok {
+				doc.mergeTable(dp, table, src, sp, v)
+				continue
+			}
+		case []map[string]interface{}:
+			if tables, ok := dst[key].([]map[string]interface{}); ok && mergedByName[dp] {
+				dst[key] = doc.mergeNamed(dp, tables, src, sp, v)
+				continue
+			}
+		}
+		dst[key] = val
+		doc.copyPositions(dp, src, sp)
+	}
+}
+
+// tableName returns the normalized name of the table
+func tableName(table map[string]interface{}) string {
+	name, _ := table[KeyName].(string)
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// mergeNamed merges the src tables into the dst tables with the same name, tables that
+// don't have a name, or whose name is not in dst, are appended
+func (doc *document) mergeNamed(dstPath string, dst []map[string]interface{}, src document, srcPath string, values []map[string]interface{}) []map[string]interface{} {
+	for j, table := range values {
+		sp := joinPath(srcPath, fmt.Sprint(j))
+		idx := -1
+		if name := tableName(table); name != "" {
+			for i := range dst {
+				if tableName(dst[i]) == name {
+					idx = i
+					break
+				}
+			}
+		}
+		if idx == -1 {
+			dst = append(dst, table)
+			doc.copyPositions(joinPath(dstPath, fmt.Sprint(len(dst)-1)), src, sp)
+			continue
+		}
+		doc.mergeTable(joinPath(dstPath, fmt.Sprint(idx)), dst[idx], src, sp, table)
+	}
+	return dst
+}
+
+// merge merges the src document on top of the document
+func (doc *document) merge(src document) {
+	if doc.values == nil {
+		doc.values = make(map[string]interface{}, len(src.values))
+		doc.positions = make(map[string]position, len(src.positions))
+	}
+	doc.mergeTable("", doc.values, src, "", src.values)
+}
+
+// includeURL returns the location of the include, relative to the file including it
+func includeURL(base *url.URL, include string) (*url.URL, error) {
+	ref, err := url.Parse(include)
+	if err != nil {
+		return nil, err
+	}
+	if ref.IsAbs() || strings.HasPrefix(ref.Path, "/") {
+		return ref, nil
+	}
+	if urlutil.IsRemote(base) {
+		return base.ResolveReference(ref), nil
+	}
+	loc := *base
+	loc.Path = path.Join(path.Dir(base.Path), ref.Path)
+	loc.RawPath = ""
+	return &loc, nil
+}
+
+// includes returns the list of files the values include
+func includes(values map[string]interface{}) ([]string, error) {
+	switch inc := values[KeyInclude].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{inc}, nil
+	case []interface{}:
+		files := make([]string, len(inc))
+		for i := range inc {
+			file, ok := inc[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("%v: expected a list of strings", KeyInclude)
+			}
+			files[i] = file
+		}
+		return files, nil
+	default:
+		return nil, fmt.Errorf("%v: expected a list of strings", KeyInclude)
+	}
+}
+
+// loadDocument loads the config file at the location, merged on top of the files it
+// includes. The file of the positions of the main config file is empty.
+func loadDocument(location *url.URL, main bool, loading map[string]bool, depth int) (doc document, err error) {
+	if depth > MaxIncludeDepth {
+		return doc, ErrIncludeDepth
+	}
+	key := location.String()
+	if loading[key] {
+		return doc, ErrIncludeCycle(key)
+	}
+	loading[key] = true
+	defer delete(loading, key)
+
+	source, err := urlutil.ReadAll(location)
+	if err != nil {
+		return doc, err
+	}
+	var values map[string]interface{}
+	md, err := toml.Decode(string(source), &values)
+	if err != nil {
+		return doc, fmt.Errorf("%v: %v", key, err)
+	}
+	file := key
+	if main {
+		file = ""
+	}
+	files, err := includes(values)
+	if err != nil {
+		return doc, fmt.Errorf("%v: %v", key, err)
+	}
+	delete(values, KeyInclude)
+
+	for _, inc := range files {
+		incURL, err := includeURL(location, inc)
+		if err != nil {
+			return doc, fmt.Errorf("%v: include %v: %v", key, inc, err)
+		}
+		incDoc, err := loadDocument(incURL, false, loading, depth+1)
+		if err != nil {
+			return doc, err
+		}
+		doc.merge(incDoc)
+	}
+	doc.merge(document{
+		values:    values,
+		positions: linePositions(source, md, file),
+	})
+	return doc, nil
+}
+
+// decode decodes the merged document into a config
+func (doc document) decode(location *url.URL) (conf Config, err error) {
+	var buf bytes.Buffer
+	if err = toml.NewEncoder(&buf).Encode(doc.values); err != nil {
+		return conf, err
+	}
+	conf.metadata, err = toml.Decode(buf.String(), &conf)
+	conf.FileLocation = location
+	conf.positions = doc.positions
+	return conf, err
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadInclude(t *testing.T) {
+	type tcase struct {
+		name     string
+		files    map[string]string
+		overlays []string
+		err      string
+		check    func(*testing.T, Config)
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "atlante_include")
+			if err != nil {
+				t.Fatalf("temp dir, expected nil got %v", err)
+			}
+			defer os.RemoveAll(dir)
+			for name, body := range tc.files {
+				fn := filepath.Join(dir, name)
+				if err = os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+					t.Fatalf("mkdir, expected nil got %v", err)
+				}
+				if err = ioutil.WriteFile(fn, []byte(body), 0644); err != nil {
+					t.Fatalf("write %v, expected nil got %v", name, err)
+				}
+			}
+			overlays := make([]*url.URL, len(tc.overlays))
+			for i := range tc.overlays {
+				overlays[i] = &url.URL{Path: filepath.Join(dir, tc.overlays[i])}
+			}
+			conf, err := Load(&url.URL{Path: filepath.Join(dir, "config.toml")}, overlays...)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			tc.check(t, conf)
+		}
+	}
+
+	tests := []tcase{
+		{
+			name: "include and overlay",
+			files: map[string]string{
+				"config.toml": `
+include = ["common/providers.toml"]
+
+[webserver]
+  port = "8080"
+
+[[sheets]]
+  name = "50k"
+  provider_grid = "pg"
+  template = "50k.svg"
+  dpi = 144
+`,
+				"common/providers.toml": `
+include = ["styles.toml"]
+
+[[providers]]
+  name = "pg"
+  type = "postgresql"
+  host = "localhost"
+  query_mdgid = "SELECT 1"
+`,
+				"common/styles.toml": `
+[[styles]]
+  name = "topo"
+  location = "topo.json"
+`,
+				"prod.toml": `
+[webserver]
+  hostname = "maps.example.com"
+
+[[providers]]
+  name = "PG"
+  host = "db.example.com"
+
+[[sheets]]
+  name = "50k"
+  dpi = 300
+
+[[sheets]]
+  name = "25k"
+  template = "25k.svg"
+`,
+			},
+			overlays: []string{"prod.toml"},
+			check: func(t *testing.T, conf Config) {
+				if conf.Webserver.Port != "8080" || conf.Webserver.HostName != "maps.example.com" {
+					t.Errorf("webserver, expected merged got %+v", conf.Webserver)
+				}
+				if len(conf.Styles) != 1 || conf.Styles[0].Name != "topo" {
+					t.Errorf("styles, expected [topo] got %v", conf.Styles)
+				}
+				if len(conf.Providers) != 1 {
+					t.Fatalf("providers, expected 1 got %v", conf.Providers)
+				}
+				if host, _ := conf.Providers[0].String("host", nil); host != "db.example.com" {
+					t.Errorf("provider host, expected db.example.com got %v", host)
+				}
+				if query, _ := conf.Providers[0].String("query_mdgid", nil); query != "SELECT 1" {
+					t.Errorf("provider query, expected SELECT 1 got %v", query)
+				}
+				if len(conf.Sheets) != 2 {
+					t.Fatalf("sheets, expected 2 got %v", len(conf.Sheets))
+				}
+				if conf.Sheets[0].DPI != 300 || conf.Sheets[0].Template != "50k.svg" {
+					t.Errorf("sheet 50k, expected dpi 300 and template 50k.svg got %v %v", conf.Sheets[0].DPI, conf.Sheets[0].Template)
+				}
+				if conf.Sheets[1].Name != "25k" {
+					t.Errorf("sheet 1, expected 25k got %v", conf.Sheets[1].Name)
+				}
+
+				v := validator{positions: conf.positions}
+				pos := v.position("sheets.0.dpi")
+				if !strings.HasSuffix(pos.File, "prod.toml") || pos.Line != 11 {
+					t.Errorf("dpi position, expected prod.toml:11 got %v", pos)
+				}
+				pos = v.position("sheets.0.template")
+				if pos.File != "" || pos.Line != 10 {
+					t.Errorf("template position, expected line 10 got %v", pos)
+				}
+				pos = v.position("providers.0.query_mdgid")
+				if !strings.HasSuffix(pos.File, "providers.toml") || pos.Line != 8 {
+					t.Errorf("query position, expected providers.toml:8 got %v", pos)
+				}
+			},
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"config.toml": `include = ["a.toml"]`,
+				"a.toml":      `include = ["config.toml"]`,
+			},
+			err: "include cycle",
+		},
+		{
+			name: "missing",
+			files: map[string]string{
+				"config.toml": `include = ["missing.toml"]`,
+			},
+			err: "missing.toml",
+		},
+		{
+			name: "bad include",
+			files: map[string]string{
+				"config.toml": `include = [1]`,
+			},
+			err: "expected a list of strings",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	// SectionProviders is the section of the config for the grid providers
+	SectionProviders = "providers"
+	// SectionFileStores is the section of the config for the file stores
+	SectionFileStores = "file_stores"
+	// SectionQueue is the section of the config for the queue
+	SectionQueue = "webserver.queue"
+	// SectionNotifier is the section of the config for the notifier
+	SectionNotifier = "notifier"
+
+	// KeyType is the config key for the type of a provider, it is allowed for all providers
+	KeyType = "type"
+	// KeyName is the config key for the name of a provider, it is allowed for all providers
+	KeyName = "name"
+)
+
+// Schema describes the config keys of a type of provider
+type Schema struct {
+	// Required are the keys that must be set
+	Required []string
+	// Optional are the keys that may be set
+	Optional []string
+}
+
+// allows returns weather the key is a known key of the schema
+func (s Schema) allows(key string) bool {
+	if key == KeyType || key == KeyName {
+		return true
+	}
+	for _, k := range s.Required {
+		if k == key {
+			return true
+		}
+	}
+	for _, k := range s.Optional {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaFunc returns the schema of the provider type, and weather the provider type
+// is registered. The schema is nil if the provider type did not register one, in
+// which case the keys of the provider are not checked.
+type SchemaFunc func(providerType string) (schema *Schema, registered bool)
+
+var schemasLock sync.RWMutex
+var schemas map[string]SchemaFunc
+
+// RegisterSchemas is called by the registries of the providers to let Validate check
+// the entries of the section against the registered provider types
+func RegisterSchemas(section string, fn SchemaFunc) {
+	schemasLock.Lock()
+	defer schemasLock.Unlock()
+	if schemas == nil {
+		schemas = make(map[string]SchemaFunc)
+	}
+	schemas[section] = fn
+}
+
+// schemasFor returns the schema func for the section, nil if the registry of the section
+// was not loaded
+func schemasFor(section string) SchemaFunc {
+	schemasLock.RLock()
+	defer schemasLock.RUnlock()
+	return schemas[section]
+}
+
+// sortedKeys returns the keys of the dict in order, so errors are reported in a stable order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
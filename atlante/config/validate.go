@@ -0,0 +1,415 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/go-spatial/atlante/atlante/internal/env"
+	"github.com/go-spatial/atlante/atlante/internal/urlutil"
+)
+
+// ValidationError is a problem found validating the config
+type ValidationError struct {
+	// File is the included or overlay file the problem is in, empty for the config file
+	File string
+	// Line is the line of the file the problem is on, 0 if not known
+	Line int
+	// Key is the path of the key, or table, with the problem; e.g. sheets[1].dpi
+	Key string
+	Msg string
+}
+
+// Error implements the error interface
+func (err ValidationError) Error() string {
+	var str strings.Builder
+	switch {
+	case err.File != "" && err.Line > 0:
+		fmt.Fprintf(&str, "%v:%v: ", err.File, err.Line)
+	case err.File != "":
+		fmt.Fprintf(&str, "%v: ", err.File)
+	case err.Line > 0:
+		fmt.Fprintf(&str, "line %v: ", err.Line)
+	}
+	if err.Key != "" {
+		fmt.Fprintf(&str, "%v: ", err.Key)
+	}
+	str.WriteString(err.Msg)
+	return str.String()
+}
+
+// ValidationErrors are all the problems found validating the config, ordered by file and line
+type ValidationErrors []ValidationError
+
+// Error implements the error interface
+func (errs ValidationErrors) Error() string {
+	var str strings.Builder
+	fmt.Fprintf(&str, "%v config errors:", len(errs))
+	for _, err := range errs {
+		str.WriteString("\n\t")
+		str.WriteString(err.Error())
+	}
+	return str.String()
+}
+
+// keyLines returns the line each table and key of the toml source starts on, using the
+// keys of the decoded toml, which are in the order they are in the source. Elements of an
+// array of tables are indexed by their position; e.g. the dpi of the second sheet is at
+// "sheets.1.dpi".
+func keyLines(source []byte, md toml.MetaData) map[string]int {
+	var (
+		lines  = make(map[string]int)
+		arrays = make(map[string]int)
+		text   = strings.Split(string(source), "\n")
+		// next is the line to start looking for the next key on; the keys of an
+		// inline table share a line
+		next int
+	)
+	// tablePath indexes the array of tables that are the parents of the key
+	tablePath := func(key toml.Key) string {
+		path := make([]string, 0, len(key)*2)
+		for i, part := range key {
+			path = append(path, part)
+			if count, ok := arrays[key[:i+1].String()]; ok && i < len(key)-1 {
+				path = append(path, strconv.Itoa(count-1))
+			}
+		}
+		return strings.Join(path, ".")
+	}
+	// find returns the first line, from next, with the header (when not empty) or the
+	// assignment (when assign) of the key, and the value assigned; -1 if there is none
+	find := func(key toml.Key, header string, assign bool) (int, string) {
+		last := regexp.QuoteMeta(key[len(key)-1])
+		assignment := regexp.MustCompile(`(^|[{,])\s*("` + last + `"|'` + last + `'|` + last + `)\s*=(.*)$`)
+		for i := next; i < len(text); i++ {
+			line := strings.TrimSpace(text[i])
+			if header != "" && strings.HasPrefix(line, header) && !strings.HasPrefix(line, header+"[") {
+				if end := strings.Index(line, "]"); end != -1 && normalizeKey(line[len(header):end]) == key.String() {
+					return i, ""
+				}
+			}
+			if !assign || strings.HasPrefix(line, "[") {
+				continue
+			}
+			if m := assignment.FindStringSubmatch(line); m != nil {
+				return i, strings.TrimSpace(m[3])
+			}
+		}
+		return -1, ""
+	}
+	for _, key := range md.Keys() {
+		var (
+			path  string
+			idx   int
+			value string
+		)
+		switch md.Type(key...) {
+		case "ArrayHash":
+			if idx, _ = find(key, "[[", false); idx == -1 {
+				continue
+			}
+			name := key.String()
+			path = tablePath(key) + "." + strconv.Itoa(arrays[name])
+			arrays[name]++
+		case "Hash":
+			// a table, or an inline table
+			if idx, value = find(key, "[", true); idx == -1 {
+				continue
+			}
+			path = tablePath(key)
+		default:
+			if idx, value = find(key, "", true); idx == -1 {
+				continue
+			}
+			path = tablePath(key)
+		}
+		if _, ok := lines[path]; !ok {
+			lines[path] = idx + 1
+		}
+		next = idx
+		// skip the lines of a multi-line string, they may look like keys
+		for _, delim := range []string{`"""`, `'''`} {
+			if !strings.HasPrefix(value, delim) || strings.Count(value, delim) != 1 {
+				continue
+			}
+			for next++; next < len(text) && !strings.Contains(text[next], delim); next++ {
+			}
+		}
+	}
+	return lines
+}
+
+// normalizeKey removes the spaces, and quotes from the parts of a dotted key
+func normalizeKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(parts[i]), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// displayKey returns the path with the indexes of array of tables in brackets; e.g.
+// sheets.1.dpi is displayed as sheets[1].dpi
+func displayKey(path string) string {
+	var str strings.Builder
+	for i, part := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			fmt.Fprintf(&str, "[%v]", part)
+			continue
+		}
+		if i > 0 {
+			str.WriteByte('.')
+		}
+		str.WriteString(part)
+	}
+	return str.String()
+}
+
+// validator collects the problems found in the config
+type validator struct {
+	positions map[string]position
+	// checkTemplates checks that the templates of the sheets can be reached
+	checkTemplates bool
+	errs           ValidationErrors
+}
+
+// position returns the position of the key, or of the nearest table containing it
+func (v *validator) position(path string) position {
+	for path != "" {
+		if pos, ok := v.positions[path]; ok {
+			return pos
+		}
+		idx := strings.LastIndex(path, ".")
+		if idx == -1 {
+			break
+		}
+		path = path[:idx]
+	}
+	return position{}
+}
+
+// defined returns weather the key was set in the config source
+func (v *validator) defined(path string) bool {
+	_, ok := v.positions[path]
+	return ok
+}
+
+func (v *validator) addf(path string, format string, args ...interface{}) {
+	pos := v.position(path)
+	v.errs = append(v.errs, ValidationError{
+		File: pos.File,
+		Line: pos.Line,
+		Key:  displayKey(path),
+		Msg:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		if v.errs[i].File != v.errs[j].File {
+			return v.errs[i].File < v.errs[j].File
+		}
+		li, lj := v.errs[i].Line, v.errs[j].Line
+		// errors without a line go last
+		if li == 0 || lj == 0 {
+			return li != 0 && lj == 0
+		}
+		return li < lj
+	})
+	return v.errs
+}
+
+// provider validates a provider entry against the schema of its type, and returns its name
+func (v *validator) provider(section string, path string, entry env.Dict, named bool) (name string) {
+	if named {
+		n, err := entry.String(KeyName, nil)
+		switch {
+		case err != nil:
+			v.addf(path, "%v is required", KeyName)
+		case strings.TrimSpace(n) == "":
+			v.addf(path+"."+KeyName, "%v is blank", KeyName)
+		default:
+			name = strings.ToLower(strings.TrimSpace(n))
+		}
+	}
+	typ, err := entry.String(KeyType, nil)
+	if err != nil {
+		v.addf(path, "%v is required", KeyType)
+		return name
+	}
+	schemaFor := schemasFor(section)
+	if schemaFor == nil {
+		// the registry for the section is not loaded, nothing to check against
+		return name
+	}
+	schema, ok := schemaFor(typ)
+	if !ok {
+		v.addf(path+"."+KeyType, "unknown type %v", typ)
+		return name
+	}
+	if schema == nil {
+		return name
+	}
+	for _, key := range schema.Required {
+		if _, ok := entry[key]; !ok {
+			v.addf(path, "%v is required for type %v", key, typ)
+		}
+	}
+	for _, key := range sortedKeys(entry) {
+		if !schema.allows(key) {
+			v.addf(path+"."+key, "unknown key for type %v", typ)
+		}
+	}
+	return name
+}
+
+// providers validates the entries of the section, returning the names of the entries
+func (v *validator) providers(section string, entries []env.Dict) map[string]bool {
+	names := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		path := section + "." + strconv.Itoa(i)
+		name := v.provider(section, path, entry, true)
+		if name == "" {
+			continue
+		}
+		if names[name] {
+			v.addf(path+"."+KeyName, "duplicate name %v", name)
+			continue
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// template validates that the sheet has a template, and if reachable is true that the
+// template location can be reached
+func (v *validator) template(path string, template string, reachable bool) {
+	if strings.TrimSpace(template) == "" {
+		v.addf(path, "template is required")
+		return
+	}
+	path += ".template"
+	location, err := url.Parse(template)
+	if err != nil {
+		v.addf(path, "invalid url %v: %v", template, err)
+		return
+	}
+	if !reachable {
+		return
+	}
+	if err = urlutil.Exists(location); err != nil {
+		v.addf(path, "unreachable: %v", err)
+	}
+}
+
+// sheet validates the sheet against the providers, file stores and styles of the config
+func (v *validator) sheet(path string, sheet Sheet, providers, filestores, styles map[string]bool) {
+	if name := strings.TrimSpace(string(sheet.Name)); name == "" {
+		v.addf(path, "name is required")
+	}
+	if grid := strings.ToLower(strings.TrimSpace(string(sheet.ProviderGrid))); grid != "" && !providers[grid] {
+		v.addf(path+".provider_grid", "unknown provider %v", grid)
+	}
+	for _, fs := range sheet.Filestores {
+		if name := strings.ToLower(strings.TrimSpace(string(fs))); name != "" && !filestores[name] {
+			v.addf(path+".file_stores", "unknown file store %v", name)
+		}
+	}
+	for _, s := range sheet.Styles {
+		if !styles[s] {
+			v.addf(path+".styles", "unknown style %v", s)
+		}
+	}
+	v.template(path, string(sheet.Template), v.checkTemplates)
+
+	if sheet.DPI < 0 || (sheet.DPI == 0 && v.defined(path+".dpi")) {
+		v.addf(path+".dpi", "must be greater than zero, got %v", sheet.DPI)
+	}
+	for _, size := range []struct {
+		key string
+		val env.Float
+	}{{"width", sheet.Width}, {"height", sheet.Height}} {
+		if size.val < 0 || (size.val == 0 && v.defined(path+"."+size.key)) {
+			v.addf(path+"."+size.key, "must be greater than zero, got %v", size.val)
+		}
+	}
+	for _, margin := range []struct {
+		key string
+		val env.Float
+	}{
+		{"paper_margin", sheet.PaperMargin},
+		{"poster_overlap", sheet.PosterOverlap},
+		{"poster_margin", sheet.PosterMargin},
+		{"bleed", sheet.Bleed},
+		{"live_margin", sheet.LiveMargin},
+	} {
+		if margin.val < 0 {
+			v.addf(path+"."+margin.key, "must not be negative, got %v", margin.val)
+		}
+	}
+}
+
+// Validate checks the config: the providers, file stores, queue and notifier must be
+// of a registered type and have the keys required by the type, and no unknown keys; the
+// providers, file stores and styles the sheets use must be configured; the sheets must
+// have a template, that can be reached unless SkipTemplateCheck is set; and the sizes of the
+// sheets must be positive. All of the problems found are returned as ValidationErrors,
+// with the line of the problem if the config was parsed.
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("error config not initialized")
+	}
+	v := validator{positions: c.positions, checkTemplates: !bool(c.SkipTemplateCheck)}
+
+	providers := v.providers(SectionProviders, c.Providers)
+	filestores := v.providers(SectionFileStores, c.FileStores)
+
+	// a queue without a type, or of type none, is not used
+	if typ, _ := c.Webserver.Queue.String(KeyType, nil); typ != "" && typ != "none" {
+		v.provider(SectionQueue, SectionQueue, c.Webserver.Queue, false)
+	}
+	if len(c.Notifier) != 0 {
+		v.provider(SectionNotifier, SectionNotifier, c.Notifier, false)
+	}
+
+	styles := make(map[string]bool, len(c.Styles))
+	for i, s := range c.Styles {
+		path := "styles." + strconv.Itoa(i)
+		name := string(s.Name)
+		if strings.TrimSpace(name) == "" {
+			v.addf(path, "name is required")
+			continue
+		}
+		if styles[name] {
+			v.addf(path+".name", "duplicate name %v", name)
+		}
+		styles[name] = true
+	}
+
+	if len(c.Sheets) == 0 {
+		v.addf("", "no sheets configured")
+	}
+	sheets := make(map[string]bool, len(c.Sheets))
+	for i, sheet := range c.Sheets {
+		path := "sheets." + strconv.Itoa(i)
+		v.sheet(path, sheet, providers, filestores, styles)
+		name := strings.ToLower(strings.TrimSpace(string(sheet.Name)))
+		if name == "" {
+			continue
+		}
+		if sheets[name] {
+			v.addf(path+".name", "duplicate name %v", name)
+		}
+		sheets[name] = true
+	}
+	return v.err()
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-spatial/atlante/atlante/internal/env"
+)
+
+func TestKeyLines(t *testing.T) {
+	source := `# config
+work_directory = "/tmp"
+
+[webserver]
+  port = "8080"
+
+  [webserver.queue]
+  note = '''
+  type = "not a key"
+  '''
+  type = "local"
+
+[[providers]]
+  name = "a"
+  query = """
+  name = "not a key"
+  """
+
+[[providers]]
+  name = "b"
+  files = [
+    "x = y",
+  ]
+  type = "geojson"
+
+[[sheets]]
+  "name" = "50k"
+  paper = { size = "a0", margin = 5 }
+`
+	md, err := toml.Decode(source, new(map[string]interface{}))
+	if err != nil {
+		t.Fatalf("decode, expected nil got %v", err)
+	}
+	lines := keyLines([]byte(source), md)
+	expected := map[string]int{
+		"work_directory":        2,
+		"webserver":             4,
+		"webserver.port":        5,
+		"webserver.queue":       7,
+		"webserver.queue.note":  8,
+		"webserver.queue.type":  11,
+		"providers.0":           13,
+		"providers.0.name":      14,
+		"providers.0.query":     15,
+		"providers.1":           19,
+		"providers.1.name":      20,
+		"providers.1.files":     21,
+		"providers.1.type":      24,
+		"sheets.0":              26,
+		"sheets.0.name":         27,
+		"sheets.0.paper":        28,
+		"sheets.0.paper.size":   28,
+		"sheets.0.paper.margin": 28,
+	}
+	for key, line := range expected {
+		if lines[key] != line {
+			t.Errorf("%v line, expected %v got %v", key, line, lines[key])
+		}
+	}
+	if len(lines) != len(expected) {
+		t.Errorf("lines, expected %v got %v", expected, lines)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	type tcase struct {
+		name              string
+		config            string
+		skipTemplateCheck bool
+		errs              []string
+	}
+
+	dir, err := ioutil.TempDir("", "atlante_config")
+	if err != nil {
+		t.Fatalf("temp dir, expected nil got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	template := filepath.Join(dir, "sheet.svg")
+	if err = ioutil.WriteFile(template, []byte("<svg></svg>"), 0644); err != nil {
+		t.Fatalf("write template, expected nil got %v", err)
+	}
+
+	RegisterSchemas(SectionProviders, func(typ string) (*Schema, bool) {
+		switch typ {
+		case "grid":
+			return &Schema{Required: []string{"file", "scale"}, Optional: []string{"edit_by"}}, true
+		case "any":
+			return nil, true
+		default:
+			return nil, false
+		}
+	})
+	RegisterSchemas(SectionFileStores, func(typ string) (*Schema, bool) {
+		if typ != "file" {
+			return nil, false
+		}
+		return &Schema{Required: []string{"base_path"}}, true
+	})
+
+	header := `
+[[providers]]
+  name = "grid50k"
+  type = "grid"
+  file = "grid.geojson"
+  scale = 50000
+
+[[file_stores]]
+  name = "local"
+  type = "file"
+  base_path = "/tmp"
+
+[[styles]]
+  name = "topo"
+  location = "style.json"
+`
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			conf, err := Parse(strings.NewReader(header+tc.config), nil)
+			if err != nil {
+				t.Fatalf("parse, expected nil got %v", err)
+			}
+			conf.SkipTemplateCheck = env.Bool(tc.skipTemplateCheck)
+			err = conf.Validate()
+			if len(tc.errs) == 0 {
+				if err != nil {
+					t.Errorf("error, expected nil got %v", err)
+				}
+				return
+			}
+			verrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("error, expected ValidationErrors got %T: %v", err, err)
+			}
+			if len(verrs) != len(tc.errs) {
+				t.Fatalf("errors, expected %v got %v", len(tc.errs), verrs)
+			}
+			for i := range verrs {
+				if got := verrs[i].Error(); !strings.Contains(got, tc.errs[i]) {
+					t.Errorf("error %v, expected %q got %q", i, tc.errs[i], got)
+				}
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name: "good",
+			config: `
+[[sheets]]
+  name = "50k"
+  provider_grid = "grid50k"
+  file_stores = ["local"]
+  styles = ["topo"]
+  template = "` + template + `"
+  dpi = 144
+`,
+		},
+		{
+			name:   "no sheets",
+			errs:   []string{"no sheets configured"},
+			config: ``,
+		},
+		{
+			name: "providers",
+			config: `
+[[providers]]
+  name = "GRID50K"
+  type = "grid"
+  scale = 50000
+  bogus = true
+
+[[providers]]
+  name = "other"
+  type = "unknown"
+
+[[providers]]
+  type = "any"
+  anything = 1
+
+[[sheets]]
+  name = "50k"
+  template = "` + template + `"
+`,
+			errs: []string{
+				"line 17: providers[1]: file is required for type grid",
+				"line 18: providers[1].name: duplicate name grid50k",
+				"line 21: providers[1].bogus: unknown key for type grid",
+				"line 25: providers[2].type: unknown type unknown",
+				"line 27: providers[3]: name is required",
+			},
+		},
+		{
+			name:              "unchecked template",
+			skipTemplateCheck: true,
+			config: `
+[[sheets]]
+  name = "50k"
+  template = "` + filepath.Join(dir, "missing.svg") + `"
+`,
+		},
+		{
+			name: "sheet references",
+			config: `
+[[sheets]]
+  name = "50k"
+  provider_grid = "grid5k"
+  file_stores = ["local", "s3"]
+  styles = ["topo", "night"]
+  template = "` + filepath.Join(dir, "missing.svg") + `"
+
+[[sheets]]
+  name = "50K"
+  template = ""
+`,
+			errs: []string{
+				"line 19: sheets[0].provider_grid: unknown provider grid5k",
+				"line 20: sheets[0].file_stores: unknown file store s3",
+				"line 21: sheets[0].styles: unknown style night",
+				"line 22: sheets[0].template: unreachable",
+				"line 24: sheets[1]: template is required",
+				"line 25: sheets[1].name: duplicate name 50k",
+			},
+		},
+		{
+			name: "sizes",
+			config: `
+[[sheets]]
+  name = "50k"
+  template = "` + template + `"
+  dpi = 0
+  width = -10.0
+  height = 0.0
+  bleed = -1.0
+`,
+			errs: []string{
+				"line 20: sheets[0].dpi: must be greater than zero, got 0",
+				"line 21: sheets[0].width: must be greater than zero, got -10",
+				"line 22: sheets[0].height: must be greater than zero, got 0",
+				"line 23: sheets[0].bleed: must not be negative, got -1",
+			},
+		},
+		{
+			name: "queue none",
+			config: `
+[webserver.queue]
+  type = "none"
+
+[[sheets]]
+  name = "50k"
+  template = "` + template + `"
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
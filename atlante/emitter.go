@@ -0,0 +1,69 @@
+package atlante
+
+import (
+	"context"
+
+	"github.com/go-spatial/atlante/atlante/notifiers"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/prometheus/common/log"
+)
+
+type emitterKey struct{}
+
+// WithEmitter returns a copy of ctx that carries the emitter the statuses of a job are
+// emitted to. Jobs run at the same time each have their own emitter; a nil emitter drops
+// the statuses.
+func WithEmitter(ctx context.Context, emitter notifiers.Emitter) context.Context {
+	return context.WithValue(ctx, emitterKey{}, emitter)
+}
+
+// EmitterFrom returns the emitter carried by ctx, nil if there is none
+func EmitterFrom(ctx context.Context) notifiers.Emitter {
+	emitter, _ := ctx.Value(emitterKey{}).(notifiers.Emitter)
+	return emitter
+}
+
+// JobContext returns a copy of ctx that carries a new emitter for the job; if there is no
+// notifier or job id the statuses are dropped
+func (a *Atlante) JobContext(ctx context.Context, jobID string) context.Context {
+	if a == nil || a.Notifier == nil || jobID == "" {
+		return WithEmitter(ctx, nil)
+	}
+	emitter, err := a.Notifier.NewEmitter(jobID)
+	if err != nil {
+		log.Warnf("Failed to init emitter: %v", err)
+		return WithEmitter(ctx, nil)
+	}
+	return WithEmitter(ctx, emitter)
+}
+
+// jobContext returns ctx if it already carries an emitter, otherwise a copy of ctx that
+// carries a new emitter for the job
+func (a *Atlante) jobContext(ctx context.Context, job Job) context.Context {
+	if EmitterFrom(ctx) != nil {
+		return ctx
+	}
+	return a.JobContext(ctx, job.MetaData["job_id"])
+}
+
+// Emit will emit a notifier event if ctx carries an emitter.
+func Emit(ctx context.Context, status field.StatusEnum) error {
+	emitter := EmitterFrom(ctx)
+	if emitter == nil {
+		return nil
+	}
+	return emitter.Emit(status)
+}
+
+// EmitError will emit a notifier event for a failed processing job; nothing is emitted
+// for a job that was stopped, but not cancelled, as it is run again
+func EmitError(ctx context.Context, desc string, err error) error {
+	emitter := EmitterFrom(ctx)
+	if emitter == nil || err == nil || jobStopped(ctx) {
+		return nil
+	}
+	return emitter.Emit(field.Failed{
+		Description: desc,
+		Error:       err,
+	})
+}
@@ -0,0 +1,139 @@
+package atlante
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/atlante/notifiers"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+)
+
+// jobNotifier records the statuses emitted for each job id
+type jobNotifier struct {
+	lock     sync.Mutex
+	emitters map[string]*recordingEmitter
+}
+
+func (jn *jobNotifier) NewEmitter(jobid string) (notifiers.Emitter, error) {
+	jn.lock.Lock()
+	defer jn.lock.Unlock()
+	if jn.emitters == nil {
+		jn.emitters = make(map[string]*recordingEmitter)
+	}
+	if _, ok := jn.emitters[jobid]; ok {
+		return nil, fmt.Errorf("second emitter for job %v", jobid)
+	}
+	jn.emitters[jobid] = new(recordingEmitter)
+	return jn.emitters[jobid], nil
+}
+
+// barrierRenderer waits for all of the jobs to be rendering before rendering, so
+// the jobs run at the same time
+type barrierRenderer struct {
+	Renderer
+	wg *sync.WaitGroup
+}
+
+func (br barrierRenderer) Render(ctx context.Context, req RenderRequest) (RenderedImage, error) {
+	br.wg.Done()
+	br.wg.Wait()
+	return br.Renderer.Render(ctx, req)
+}
+
+func TestGeneratePDFJobConcurrentEmitters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atlante_jobs")
+	if err != nil {
+		t.Fatalf("temp dir, expected nil got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	jobIDs := []string{"job1", "job2"}
+	var barrier sync.WaitGroup
+	barrier.Add(len(jobIDs))
+
+	sheet := &Sheet{
+		Name:   "50k",
+		DPI:    72,
+		Scale:  50000,
+		Width:  200,
+		Height: 150,
+		Renderer: barrierRenderer{
+			Renderer: FakeRenderer{Pattern: FakePatternSolid, Color: color.RGBA{R: 0xff, A: 0xff}, CellSize: 64},
+			wg:       &barrier,
+		},
+		PDFConverter: FakePDFConverter{},
+	}
+	sheet.svgTemplate = template.Must(
+		template.New("test").Funcs(sheet.AddTemplateFuncs(funcMap)).Parse(
+			`<svg width="{{.Width}}" height="{{.Height}}">{{ .Image.Place 0 0 .Width .Height }}</svg>`,
+		),
+	)
+	notifier := new(jobNotifier)
+	a := &Atlante{Notifier: notifier}
+	if err = a.AddSheet(sheet); err != nil {
+		t.Fatalf("add sheet, expected nil got %v", err)
+	}
+
+	filenameTemplate := filepath.Join(dir, "{{.Grid.MetaData.filename}}.{{.Ext}}")
+	errs := make([]error, len(jobIDs))
+	var wg sync.WaitGroup
+	for i, jobID := range jobIDs {
+		wg.Add(1)
+		go func(i int, jobID string) {
+			defer wg.Done()
+			cell := grids.NewCell("V795G25492", [2]float64{32.5, -117.25}, [2]float64{32.75, -117}, "", "", nil, nil, time.Time{}, "", "", "V795", [2]string{}, [2]string{}, nil)
+			cell.MetaData = map[string]string{"styleLocation": "fake"}
+			job := NewJob(sheet.Name, cell, map[string]string{"job_id": jobID, "filename": jobID})
+			_, errs[i] = a.GeneratePDFJob(context.Background(), *job, filenameTemplate)
+		}(i, jobID)
+	}
+	wg.Wait()
+
+	for i, jobID := range jobIDs {
+		if errs[i] != nil {
+			t.Errorf("%v, expected nil got %v", jobID, errs[i])
+			continue
+		}
+		emitter := notifier.emitters[jobID]
+		if emitter == nil {
+			t.Errorf("%v, expected an emitter", jobID)
+			continue
+		}
+		statuses := emitter.statuses
+		if len(statuses) < 2 {
+			t.Errorf("%v statuses, expected started to completed got %v", jobID, statuses)
+			continue
+		}
+		if _, ok := statuses[0].(field.Started); !ok {
+			t.Errorf("%v first status, expected %T got %T", jobID, field.Started{}, statuses[0])
+		}
+		for _, status := range statuses[1:] {
+			if _, ok := status.(field.Completed); ok {
+				continue
+			}
+			p, ok := status.(field.Processing)
+			if !ok {
+				t.Errorf("%v status, expected %T got %#v", jobID, field.Processing{}, status)
+				continue
+			}
+			for _, other := range jobIDs {
+				if other != jobID && strings.Contains(p.Description, other) {
+					t.Errorf("%v status, expected only its own files got %q", jobID, p.Description)
+				}
+			}
+		}
+		if _, ok := statuses[len(statuses)-1].(field.Completed); !ok {
+			t.Errorf("%v last status, expected %T got %#v", jobID, field.Completed{}, statuses[len(statuses)-1])
+		}
+	}
+}
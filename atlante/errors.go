@@ -2,6 +2,7 @@ package atlante
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gdey/errors"
 )
@@ -19,6 +20,22 @@ const (
 	ErrDuplicateSheetName = errors.String("duplicate sheet name")
 	// ErrNoSheets is returned when no sheets were configured into the system
 	ErrNoSheets = errors.String("no sheets configured")
+	// ErrNoAtlasPages is returned when an atlas is requested without any pages
+	ErrNoAtlasPages = errors.String("no pages for atlas")
+	// ErrNoPaperSizeFits is returned when none of the paper sizes are big enough for the grid
+	ErrNoPaperSizeFits = errors.String("no paper size fits the grid")
+	// ErrPosterOverlapTooLarge is returned when the overlap and margins of the poster pages leave no room for the sheet
+	ErrPosterOverlapTooLarge = errors.String("poster overlap and margin are larger then the page")
+	// ErrInvalidSVG is returned when the root svg element of a svg file could not be found
+	ErrInvalidSVG = errors.String("unable to find svg element")
+	// ErrNoRenderer is returned when the map image is needed, the sheet does not have a renderer
+	// and the default renderer is not registered
+	ErrNoRenderer = errors.String("no renderer for the sheet")
+	// ErrNoPDFConverter is returned when the sheet does not have a pdf converter and the
+	// default pdf converter is not registered
+	ErrNoPDFConverter = errors.String("no pdf converter for the sheet")
+	// ErrJobCancelled is the cause a queue cancels the context of a job with, see WithJobCancel
+	ErrJobCancelled = errors.String("job cancelled")
 )
 
 // ErrUnknownSheetName is returned when the sheet requested is not found or known.
@@ -27,3 +44,85 @@ type ErrUnknownSheetName string
 func (eusn ErrUnknownSheetName) Error() string {
 	return fmt.Sprintf("unknown sheet named %v", string(eusn))
 }
+
+// ErrUnknownPaperSize is returned when the paper size is not known
+type ErrUnknownPaperSize string
+
+func (err ErrUnknownPaperSize) Error() string {
+	return fmt.Sprintf("unknown paper size %v", string(err))
+}
+
+// ErrUnknownOrientation is returned when the orientation is not portrait or landscape
+type ErrUnknownOrientation string
+
+func (err ErrUnknownOrientation) Error() string {
+	return fmt.Sprintf("unknown orientation %v, expected portrait or landscape", string(err))
+}
+
+// ErrPDFMetadataTemplate is returned when a pdf metadata template fails to parse or execute
+type ErrPDFMetadataTemplate struct {
+	Field string
+	Err   error
+}
+
+func (err ErrPDFMetadataTemplate) Error() string {
+	return fmt.Sprintf("pdf %v template: %v", err.Field, err.Err)
+}
+
+// ErrRendererExists is returned when a renderer is registered with the same type as another renderer
+type ErrRendererExists string
+
+func (err ErrRendererExists) Error() string {
+	return "renderer (" + string(err) + ") already exists"
+}
+
+// ErrUnknownRenderer is returned when the requested renderer type is not registered
+type ErrUnknownRenderer string
+
+func (err ErrUnknownRenderer) Error() string {
+	return fmt.Sprintf("unknown renderer %v", string(err))
+}
+
+// ErrPDFConverterExists is returned when a pdf converter is registered with the same type as another pdf converter
+type ErrPDFConverterExists string
+
+func (err ErrPDFConverterExists) Error() string {
+	return "pdf converter (" + string(err) + ") already exists"
+}
+
+// ErrUnknownPDFConverter is returned when the requested pdf converter type is not registered
+type ErrUnknownPDFConverter string
+
+func (err ErrUnknownPDFConverter) Error() string {
+	return fmt.Sprintf("unknown pdf converter %v", string(err))
+}
+
+// ErrRender is returned when the renderer fails to render the map image
+type ErrRender struct {
+	Err error
+}
+
+func (err ErrRender) Error() string {
+	return fmt.Sprintf("render failed: %v", err.Err)
+}
+
+// Unwrap returns the error of the renderer
+func (err ErrRender) Unwrap() error { return err.Err }
+
+// ErrRetryJob is returned by RunJob when the attempt of the job failed, and the job will
+// be run again
+type ErrRetryJob struct {
+	// Attempt is the attempt that will be run, starting at 1
+	Attempt int
+	// After is how long to wait before running the attempt
+	After time.Duration
+	// Err is the error of the failed attempt
+	Err error
+}
+
+func (err ErrRetryJob) Error() string {
+	return fmt.Sprintf("attempt %v failed, retrying in %v: %v", err.Attempt-1, err.After, err.Err)
+}
+
+// Unwrap returns the error of the failed attempt
+func (err ErrRetryJob) Unwrap() error { return err.Err }
@@ -28,3 +28,6 @@ func (err ErrPath) Timeout() bool {
 }
 
 func (err ErrPath) Error() string { return err.Err.Error() }
+
+// Unwrap returns the underlying error
+func (err ErrPath) Unwrap() error { return err.Err }
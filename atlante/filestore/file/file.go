@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/atlante/atlante/filestore"
 )
 
@@ -54,6 +55,10 @@ func intiFunc(cfg filestore.Config) (filestore.Provider, error) {
 
 func init() {
 	filestore.Register(TYPE, intiFunc, nil)
+	filestore.RegisterSchema(TYPE, config.Schema{
+			Required: []string{ConfigKeyBasepath},
+			Optional: []string{ConfigKeyGroup, ConfigKeyIntermediate},
+		})
 }
 
 // Provider provides a filestore that write to the local file system.
@@ -73,21 +73,47 @@ type Pather interface {
 // writing.
 var globalWaitGroupPipe sync.WaitGroup
 
-// Pipe creates a pipe that can be use to connect something that requires a io.Reader
+// pipeWriter is the write side of a pipe; Close waits for the reader to finish
+type pipeWriter struct {
+	*io.PipeWriter
+	done chan struct{}
+	err  error
+}
+
+// Close closes the pipe, and returns the error, if any, of the reader once it
+// is finished with the pipe.
+func (w *pipeWriter) Close() error {
+	w.PipeWriter.Close()
+	<-w.done
+	return w.err
+}
+
+// Pipe creates a pipe that can be use to connect something that requires a io.Reader.
+// Closing the returned writer waits for fn to return; an error from fn is returned by
+// Close as an ErrPath, and by any writes made after fn returned.
 func Pipe(typ, name string, fn func(r io.Reader) error) io.WriteCloser {
 	r, w := io.Pipe()
+	pw := &pipeWriter{
+		PipeWriter: w,
+		done:       make(chan struct{}),
+	}
 	globalWaitGroupPipe.Add(1)
 	go func() {
-		// TODO(gdey): Need to figure something better for handeling errors here.
-		// one idea is to use the CloseWithError() method, to pass the error to
-		// the write side of the pipe.
+		defer globalWaitGroupPipe.Done()
+		defer close(pw.done)
 		err := fn(r)
 		if err != nil {
 			log.Printf("error putting to %v (%v): %v", name, typ, err)
+			pw.err = ErrPath{
+				Filepath:      name,
+				FilestoreType: typ,
+				Err:           err,
+			}
 		}
-		globalWaitGroupPipe.Done()
+		// unblock any writes still waiting on the reader
+		r.CloseWithError(err)
 	}()
-	return w
+	return pw
 }
 
 func cleanup() {
@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/atlante/atlante/filestore"
 )
 
@@ -75,6 +76,9 @@ func New(providers ...filestore.Provider) (provider Provider) {
 
 func init() {
 	filestore.Register(TYPE, initFunc, nil)
+	filestore.RegisterSchema(TYPE, config.Schema{
+			Required: []string{ConfigKeyFileStore},
+		})
 }
 
 // Writer creates a writer that duplicates its writes to all the
@@ -104,12 +108,15 @@ func (t *Writer) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-// Close implements the io.Closer interface
-func (t *Writer) Close() error {
+// Close implements the io.Closer interface. All the writers are closed, the
+// first error is returned.
+func (t *Writer) Close() (err error) {
 	for _, w := range t.writers {
-		w.Close()
+		if e := w.Close(); e != nil && err == nil {
+			err = e
+		}
 	}
-	return nil
+	return err
 }
 
 // Provider duplexes writes to multiple other filestore providers
@@ -4,6 +4,7 @@ import (
 	"io"
 	"log"
 
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/atlante/atlante/filestore"
 )
 
@@ -30,6 +31,9 @@ func initFunc(cfg filestore.Config) (filestore.Provider, error) {
 
 func init() {
 	filestore.Register(TYPE, initFunc, nil)
+	filestore.RegisterSchema(TYPE, config.Schema{
+			Optional: []string{ConfigKeyLog, ConfigKeyIntermediate},
+		})
 }
 
 // Writer is a null writer
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/tegola/dict"
 )
 
@@ -52,6 +53,7 @@ type funcs struct {
 
 var providerLock sync.RWMutex
 var providers map[string]funcs
+var schemas map[string]config.Schema
 
 // Register is called by the init functions of each of the providers
 func Register(providerType string, init InitFunc, cleanup CleanupFunc) error {
@@ -73,6 +75,36 @@ func Register(providerType string, init InitFunc, cleanup CleanupFunc) error {
 	return nil
 }
 
+// RegisterSchema is called by the init functions of the providers to describe the config keys
+// of the filestore provider; it is used to validate the config.
+func RegisterSchema(providerType string, schema config.Schema) {
+	providerLock.Lock()
+	defer providerLock.Unlock()
+	if schemas == nil {
+		schemas = make(map[string]config.Schema)
+	}
+	schemas[providerType] = schema
+}
+
+// SchemaFor returns the config schema of the filestore provider type, nil if the type did not register
+// one, and weather the type is registered
+func SchemaFor(providerType string) (*config.Schema, bool) {
+	providerLock.RLock()
+	defer providerLock.RUnlock()
+	if _, ok := providers[providerType]; !ok {
+		return nil, false
+	}
+	schema, ok := schemas[providerType]
+	if !ok {
+		return nil, true
+	}
+	return &schema, true
+}
+
+func init() {
+	config.RegisterSchemas(config.SectionFileStores, SchemaFor)
+}
+
 // Unregister will remove a provider and call it's cleanup function.
 func Unregister(providerType string) {
 	providerLock.Lock()
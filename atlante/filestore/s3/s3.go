@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/go-spatial/atlante/atlante/config"
 	cfgaws "github.com/go-spatial/atlante/atlante/config/aws"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -111,6 +112,10 @@ func initFunc(cfg filestore.Config) (filestore.Provider, error) {
 
 func init() {
 	filestore.Register(TYPE, initFunc, nil)
+	filestore.RegisterSchema(TYPE, config.Schema{
+			Required: []string{ConfigKeyBucket},
+			Optional: []string{ConfigKeyBasePath, ConfigKeyGroup, ConfigKeyIntermediate, ConfigKeyIntermediateBucket, ConfigKeyIntermediateBasePath, ConfigKeyURLTimeout, ConfigKeyGenPresigned, cfgaws.ConfigKeyRegion, cfgaws.ConfigKeyEndPoint, cfgaws.ConfigKeyAWSAccessKeyID, cfgaws.ConfigKeyAWSSecretKey},
+		})
 }
 
 // Provider provides a filestore that can write to s3 object stores
@@ -0,0 +1,147 @@
+// Package golden compares rendered sheets against stored golden images, and reports
+// the differences.
+package golden
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+const (
+	// DefaultThreshold is the default perceptual difference, from 0 to 1, above
+	// which two pixels are considered different
+	DefaultThreshold = 0.1
+	// DefaultMaxDiff is the default fraction of the pixels that may be different
+	// before the images are considered different
+	DefaultMaxDiff = 0.001
+
+	// maxDelta is the largest yiq delta possible between two colours
+	maxDelta = 35215.0
+)
+
+var (
+	// diffColor is the colour of pixels that are different in the diff image
+	diffColor = color.RGBA{R: 0xff, A: 0xff}
+	// sizeColor is the colour of pixels that are only in one of the images in the diff image
+	sizeColor = color.RGBA{R: 0xff, B: 0xff, A: 0xff}
+)
+
+// Result is the result of comparing two images
+type Result struct {
+	// Pixels is the number of pixels compared, the area of the larger of the two images
+	Pixels int
+	// Different is the number of pixels that are perceptually different
+	Different int
+	// SizeMismatch is true if the images are not the same size; the pixels outside of
+	// the smaller image are counted as different
+	SizeMismatch bool
+	// Diff is an image of the expected image faded out, with the different pixels in red
+	Diff *image.RGBA
+}
+
+// Ratio returns the fraction of the pixels that are different
+func (r Result) Ratio() float64 {
+	if r.Pixels == 0 {
+		return 0
+	}
+	return float64(r.Different) / float64(r.Pixels)
+}
+
+// Passed returns weather the fraction of different pixels is no more then maxDiff, and
+// the images are the same size
+func (r Result) Passed(maxDiff float64) bool {
+	return !r.SizeMismatch && r.Ratio() <= maxDiff
+}
+
+// rgb returns the colour blended on to a white background, each component is from 0 to 255
+func rgb(c color.Color) (r, g, b float64) {
+	cr, cg, cb, ca := c.RGBA()
+	a := float64(ca) / 0xffff
+	// the components are alpha-premultiplied
+	blend := func(v uint32) float64 { return float64(v)/0x101 + 255*(1-a) }
+	return blend(cr), blend(cg), blend(cb)
+}
+
+// delta returns the perceptual difference between the two colours from 0 to 1. The
+// difference is measured in the YIQ colour space, which weights brightness over
+// the colour as our eyes do; see "Measuring perceived color difference using YIQ
+// NTSC transmission color space in mobile applications" by Kotsarenko and Ramos
+func delta(c1, c2 color.Color) float64 {
+	r1, g1, b1 := rgb(c1)
+	r2, g2, b2 := rgb(c2)
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	y := dr*0.29889531 + dg*0.58662247 + db*0.11448223
+	i := dr*0.59597799 - dg*0.27417610 - db*0.32180189
+	q := dr*0.21147017 - dg*0.52261711 + db*0.31114694
+	return (0.5053*y*y + 0.299*i*i + 0.1957*q*q) / maxDelta
+}
+
+// faded returns a light grey version of the colour for the background of the diff image
+func faded(c color.Color) color.RGBA {
+	r, g, b := rgb(c)
+	y := uint8(255 - (255-(r*0.29889531+g*0.58662247+b*0.11448223))*0.1)
+	return color.RGBA{R: y, G: y, B: y, A: 0xff}
+}
+
+// Compare compares the actual image against the expected image. Pixels are different if
+// their perceptual difference is more then threshold, from 0 (exact) to 1.
+func Compare(expected, actual image.Image, threshold float64) Result {
+	eb, ab := expected.Bounds(), actual.Bounds()
+	width, height := eb.Dx(), eb.Dy()
+	if ab.Dx() > width {
+		width = ab.Dx()
+	}
+	if ab.Dy() > height {
+		height = ab.Dy()
+	}
+	res := Result{
+		Pixels:       width * height,
+		SizeMismatch: eb.Dx() != ab.Dx() || eb.Dy() != ab.Dy(),
+		Diff:         image.NewRGBA(image.Rect(0, 0, width, height)),
+	}
+	// pixelmatch squares the threshold so it is closer to linear for the user
+	limit := threshold * threshold
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ep, ap := image.Pt(eb.Min.X+x, eb.Min.Y+y), image.Pt(ab.Min.X+x, ab.Min.Y+y)
+			if !ep.In(eb) || !ap.In(ab) {
+				res.Different++
+				res.Diff.SetRGBA(x, y, sizeColor)
+				continue
+			}
+			ec := expected.At(ep.X, ep.Y)
+			if delta(ec, actual.At(ap.X, ap.Y)) > limit {
+				res.Different++
+				res.Diff.SetRGBA(x, y, diffColor)
+				continue
+			}
+			res.Diff.SetRGBA(x, y, faded(ec))
+		}
+	}
+	return res
+}
+
+// ReadPNG reads the png file
+func ReadPNG(filename string) (image.Image, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// WritePNG writes the image to the png file
+func WritePNG(filename string, img image.Image) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
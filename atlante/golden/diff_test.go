@@ -0,0 +1,121 @@
+package golden
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestCompare(t *testing.T) {
+	type tcase struct {
+		name      string
+		expected  image.Image
+		actual    image.Image
+		threshold float64
+		different int
+		mismatch  bool
+		passed    bool
+	}
+
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	speckled := solid(10, 10, white)
+	speckled.SetRGBA(2, 3, color.RGBA{A: 0xff})
+	nearlyWhite := solid(10, 10, color.RGBA{R: 0xfc, G: 0xfc, B: 0xfc, A: 0xff})
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			res := Compare(tc.expected, tc.actual, tc.threshold)
+			if res.Different != tc.different {
+				t.Errorf("different, expected %v got %v", tc.different, res.Different)
+			}
+			if res.SizeMismatch != tc.mismatch {
+				t.Errorf("size mismatch, expected %v got %v", tc.mismatch, res.SizeMismatch)
+			}
+			if got := res.Passed(DefaultMaxDiff); got != tc.passed {
+				t.Errorf("passed, expected %v got %v", tc.passed, got)
+			}
+			if res.Diff == nil || res.Diff.Bounds().Dx()*res.Diff.Bounds().Dy() != res.Pixels {
+				t.Errorf("diff image, expected %v pixels", res.Pixels)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:      "same",
+			expected:  solid(10, 10, white),
+			actual:    solid(10, 10, white),
+			threshold: DefaultThreshold,
+			passed:    true,
+		},
+		{
+			name:      "one pixel",
+			expected:  solid(10, 10, white),
+			actual:    speckled,
+			threshold: DefaultThreshold,
+			different: 1,
+		},
+		{
+			name:      "under threshold",
+			expected:  solid(10, 10, white),
+			actual:    nearlyWhite,
+			threshold: DefaultThreshold,
+			passed:    true,
+		},
+		{
+			name:      "exact",
+			expected:  solid(10, 10, white),
+			actual:    nearlyWhite,
+			threshold: 0,
+			different: 100,
+		},
+		{
+			name:      "transparent is white",
+			expected:  solid(10, 10, white),
+			actual:    image.NewRGBA(image.Rect(0, 0, 10, 10)),
+			threshold: DefaultThreshold,
+			passed:    true,
+		},
+		{
+			name:      "size",
+			expected:  solid(10, 10, white),
+			actual:    solid(10, 12, white),
+			threshold: DefaultThreshold,
+			different: 20,
+			mismatch:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestPNG(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden_png")
+	if err != nil {
+		t.Fatalf("tempdir, expected nil got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "img.png")
+	img := solid(4, 3, color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff})
+	if err = WritePNG(fn, img); err != nil {
+		t.Fatalf("write, expected nil got %v", err)
+	}
+	got, err := ReadPNG(fn)
+	if err != nil {
+		t.Fatalf("read, expected nil got %v", err)
+	}
+	if res := Compare(img, got, 0); res.Different != 0 {
+		t.Errorf("different, expected 0 got %v", res.Different)
+	}
+}
@@ -0,0 +1,100 @@
+package golden
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// Case is the result of checking a fixture against its golden image. The image paths
+// are relative to the report.
+type Case struct {
+	Name string
+	// Sheet the fixture was rendered with
+	Sheet string
+	// Golden, Actual and Diff are the paths to the images; Golden and Diff are empty
+	// if there is no golden image
+	Golden string
+	Actual string
+	Diff   string
+	Result Result
+	Passed bool
+	// Updated is true if the golden image was written from the actual image
+	Updated bool
+	// Err is the error rendering or comparing the fixture
+	Err error
+}
+
+// Status returns a one word status of the case
+func (c Case) Status() string {
+	switch {
+	case c.Err != nil:
+		return "error"
+	case c.Updated:
+		return "updated"
+	case c.Passed:
+		return "passed"
+	default:
+		return "failed"
+	}
+}
+
+// Report is the results of checking the fixtures
+type Report struct {
+	Threshold float64
+	MaxDiff   float64
+	Generated time.Time
+	Cases     []Case
+}
+
+// Failed returns the number of cases that did not pass or were not updated
+func (r Report) Failed() (n int) {
+	for _, c := range r.Cases {
+		if c.Err != nil || !(c.Passed || c.Updated) {
+			n++
+		}
+	}
+	return n
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return fmt.Sprintf("%.3f%%", f*100) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>atlante template test report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 0.5em; vertical-align: top; }
+img { max-width: 400px; border: 1px solid #eee; }
+.passed, .updated { color: #2a7d2a; }
+.failed, .error { color: #c00; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>atlante template test report</h1>
+<p>{{ len .Cases }} fixtures, {{ .Failed }} failed; threshold {{ .Threshold }}, max difference {{ percent .MaxDiff }}; generated {{ .Generated.Format "2006-01-02 15:04:05 MST" }}</p>
+<table>
+<tr><th>fixture</th><th>golden</th><th>actual</th><th>diff</th></tr>
+{{- range .Cases }}
+<tr>
+<td><strong>{{ .Name }}</strong><br>sheet: {{ .Sheet }}<br><span class="{{ .Status }}">{{ .Status }}</span>
+{{- if .Err }}<br><pre>{{ .Err }}</pre>
+{{- else if .Diff }}<br>{{ .Result.Different }} of {{ .Result.Pixels }} pixels ({{ percent .Result.Ratio }}) differ{{ if .Result.SizeMismatch }}<br>the images are different sizes{{ end }}{{ end }}</td>
+<td>{{ if .Golden }}<a href="{{ .Golden }}"><img src="{{ .Golden }}"></a>{{ end }}</td>
+<td>{{ if .Actual }}<a href="{{ .Actual }}"><img src="{{ .Actual }}"></a>{{ end }}</td>
+<td>{{ if .Diff }}<a href="{{ .Diff }}"><img src="{{ .Diff }}"></a>{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as a html page with the golden, actual and diff images side by side
+func (r Report) WriteHTML(w io.Writer) error {
+	return reportTemplate.Execute(w, r)
+}
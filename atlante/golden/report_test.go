@@ -0,0 +1,40 @@
+package golden
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReportWriteHTML(t *testing.T) {
+	rpt := Report{
+		Threshold: DefaultThreshold,
+		MaxDiff:   DefaultMaxDiff,
+		Cases: []Case{
+			{Name: "passes", Sheet: "50k", Golden: "passes.png", Actual: "passes.actual.png", Diff: "passes.diff.png", Passed: true},
+			{Name: "fails", Sheet: "50k", Golden: "fails.png", Actual: "fails.actual.png", Diff: "fails.diff.png", Result: Result{Pixels: 100, Different: 5}},
+			{Name: "broken", Sheet: "50k", Err: errors.New("template: <bad> function")},
+			{Name: "new", Sheet: "50k", Actual: "new.actual.png", Updated: true},
+		},
+	}
+	if got := rpt.Failed(); got != 2 {
+		t.Errorf("failed, expected 2 got %v", got)
+	}
+	var buff bytes.Buffer
+	if err := rpt.WriteHTML(&buff); err != nil {
+		t.Fatalf("write html, expected nil got %v", err)
+	}
+	html := buff.String()
+	for _, want := range []string{
+		"4 fixtures, 2 failed",
+		`<img src="fails.diff.png">`,
+		"5 of 100 pixels (5.000%) differ",
+		"template: &lt;bad&gt; function",
+		`<span class="updated">updated</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("html, expected to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package atlante
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/geom"
+)
+
+// rowProvider has a row of three cells, west to east: A, B and C
+type rowProvider struct{}
+
+func (rowProvider) cell(name string) *grids.Cell {
+	lng := map[string]float64{"A": -117.5, "B": -117.25, "C": -117.0}[name]
+	return grids.NewCell(
+		"V795G2549"+name,
+		[2]float64{32.5, lng},
+		[2]float64{32.75, lng + 0.25},
+		"", "", nil, nil, time.Time{}, "", "sheet"+name, "V795",
+		[2]string{}, [2]string{}, nil,
+	)
+}
+func (rowProvider) CellForBounds(geom.Extent, uint) (*grids.Cell, error) {
+	return nil, grids.ErrNotFound
+}
+func (rp rowProvider) CellForLatLng(lat, lng float64, _ uint) (*grids.Cell, error) {
+	for _, name := range []string{"A", "B", "C"} {
+		cell := rp.cell(name)
+		if cell.Hull().ContainsPoint([2]float64{lng, lat}) {
+			return cell, nil
+		}
+	}
+	return nil, grids.ErrNotFound
+}
+func (rowProvider) CellForMDGID(*grids.MDGID) (*grids.Cell, error) { return nil, grids.ErrNotFound }
+func (rowProvider) CellSize() grids.CellSize                       { return grids.CellSize50K }
+
+func TestGridTemplateContextNeighbors(t *testing.T) {
+	type tcase struct {
+		name     string
+		provider grids.Provider
+		cell     string
+		expected string
+	}
+
+	tpl := template.Must(template.New("neighbors").Parse(
+		`W:{{ with .Neighbors.W }}{{ .Sheet }} {{ .MDGID }}{{ end }};` +
+			`E:{{ with .Neighbors.E }}{{ .Sheet }} {{ .MDGID }}{{ end }};` +
+			`N:{{ with .Neighbors.N }}{{ .Sheet }}{{ end }};` +
+			`all:{{ range .Neighbors.All }}{{ .Direction }}{{ end }}`,
+	))
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			grctx := &GridTemplateContext{
+				Grid:     rowProvider{}.cell(tc.cell),
+				Provider: tc.provider,
+			}
+			var out bytes.Buffer
+			if err := tpl.Execute(&out, grctx); err != nil {
+				t.Fatalf("execute, expected nil got %v", err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("output, expected %q got %q", tc.expected, out.String())
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:     "middle",
+			provider: rowProvider{},
+			cell:     "B",
+			expected: "W:sheetA V795G2549A;E:sheetC V795G2549C;N:;all:EW",
+		},
+		{
+			name:     "west end",
+			provider: rowProvider{},
+			cell:     "A",
+			expected: "W:;E:sheetB V795G2549B;N:;all:E",
+		},
+		{
+			name:     "no provider",
+			cell:     "B",
+			expected: "W:;E:;N:;all:",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
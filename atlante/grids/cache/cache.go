@@ -0,0 +1,286 @@
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/common/log"
+
+	"github.com/go-spatial/atlante/atlante/config"
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/geom"
+)
+
+// Type of the grid this provider is providing
+const Type = "cache"
+
+const (
+	// DefaultSize is the default max number of entries to keep
+	DefaultSize = 1000
+	// DefaultTTL is the default number of seconds an entry is valid for
+	DefaultTTL = 300
+	// DefaultPrecision is the default number of decimal places lat/lng values
+	// are rounded to for the cache key; 4 places is about 11 meters
+	DefaultPrecision = 4
+)
+
+const (
+	// ConfigKeyProvider is the config key for the provider to cache
+	ConfigKeyProvider = "provider"
+	// ConfigKeySize is the config key for the max number of entries to keep
+	ConfigKeySize = "size"
+	// ConfigKeyTTL is the config key for the number of seconds an entry is valid for
+	ConfigKeyTTL = "ttl"
+	// ConfigKeyPrecision is the config key for the number of decimal places lat/lng
+	// values are rounded to for the cache key
+	ConfigKeyPrecision = "precision"
+)
+
+func init() {
+	grids.Register(Type, NewGridProvider, nil)
+	grids.RegisterSchema(Type, config.Schema{
+		Required: []string{ConfigKeyProvider},
+		Optional: []string{ConfigKeySize, ConfigKeyTTL, ConfigKeyPrecision},
+	})
+}
+
+// Stats are the counters for the cache
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Expired   uint64 `json:"expired"`
+	Len       int    `json:"len"`
+}
+
+// HitRatio is the ratio of hits to lookups
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("hits: %v misses: %v evictions: %v expired: %v len: %v", s.Hits, s.Misses, s.Evictions, s.Expired, s.Len)
+}
+
+type entry struct {
+	key     string
+	cell    *grids.Cell
+	err     error
+	expires time.Time
+}
+
+// Provider caches the cells returned by CellForMDGID and CellForLatLng of
+// another provider in a bounded LRU.
+type Provider struct {
+	Provider grids.Provider
+
+	size      int
+	ttl       time.Duration
+	precision int
+
+	lock    sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	stats   Stats
+
+	// now is used for testing
+	now func() time.Time
+}
+
+// New returns a new caching provider. A ttl of zero means entries do not expire.
+func New(base grids.Provider, size int, ttl time.Duration, precision int) (*Provider, error) {
+	if base == nil {
+		return nil, ErrNilBaseProvider
+	}
+	if size <= 0 {
+		return nil, ErrInvalidSize(size)
+	}
+	if precision < 0 {
+		precision = 0
+	}
+	return &Provider{
+		Provider:  base,
+		size:      size,
+		ttl:       ttl,
+		precision: precision,
+		lru:       list.New(),
+		entries:   make(map[string]*list.Element, size),
+		now:       time.Now,
+	}, nil
+}
+
+// NewGridProvider returns a grid provider that caches the cells of another provider
+func NewGridProvider(config grids.ProviderConfig) (grids.Provider, error) {
+	subp, err := config.String(ConfigKeyProvider, nil)
+	if err != nil {
+		return nil, err
+	}
+	if subp == "" {
+		return nil, ErrBlankSubprovider
+	}
+
+	log.Infof("getting provider(%v) from config.", subp)
+	prv, err := config.NameGridProvider(subp)
+	if err != nil {
+		log.Warnf("got error getting provider: %v", err)
+		return nil, err
+	}
+
+	size := DefaultSize
+	if size, err = config.Int(ConfigKeySize, &size); err != nil {
+		return nil, err
+	}
+
+	ttl := uint(DefaultTTL)
+	if ttl, err = config.Uint(ConfigKeyTTL, &ttl); err != nil {
+		return nil, err
+	}
+
+	precision := DefaultPrecision
+	if precision, err = config.Int(ConfigKeyPrecision, &precision); err != nil {
+		return nil, err
+	}
+
+	return New(prv, size, time.Duration(ttl)*time.Second, precision)
+}
+
+// Stats returns a snapshot of the counters of the cache
+func (p *Provider) Stats() Stats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	stats := p.stats
+	stats.Len = p.lru.Len()
+	return stats
+}
+
+// Purge removes all entries from the cache
+func (p *Provider) Purge() {
+	p.lock.Lock()
+	p.lru.Init()
+	p.entries = make(map[string]*list.Element, p.size)
+	p.lock.Unlock()
+}
+
+// get returns the entry for the key, if it exists and has not expired
+func (p *Provider) get(key string) (*entry, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	elm, ok := p.entries[key]
+	if !ok {
+		p.stats.Misses++
+		return nil, false
+	}
+	ent := elm.Value.(*entry)
+	if !ent.expires.IsZero() && !p.now().Before(ent.expires) {
+		p.lru.Remove(elm)
+		delete(p.entries, key)
+		p.stats.Expired++
+		p.stats.Misses++
+		return nil, false
+	}
+	p.lru.MoveToFront(elm)
+	p.stats.Hits++
+	return ent, true
+}
+
+// set adds the entry to the cache, evicting the least recently used entries as needed
+func (p *Provider) set(key string, cell *grids.Cell, err error) {
+	ent := &entry{
+		key:  key,
+		cell: cell,
+		err:  err,
+	}
+	if p.ttl > 0 {
+		ent.expires = p.now().Add(p.ttl)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if elm, ok := p.entries[key]; ok {
+		elm.Value = ent
+		p.lru.MoveToFront(elm)
+		return
+	}
+	p.entries[key] = p.lru.PushFront(ent)
+	for p.lru.Len() > p.size {
+		elm := p.lru.Back()
+		p.lru.Remove(elm)
+		delete(p.entries, elm.Value.(*entry).key)
+		p.stats.Evictions++
+	}
+}
+
+// cacheable returns weather the result of a lookup should be cached
+func cacheable(err error) bool { return err == nil || err == grids.ErrNotFound }
+
+// copyCell returns a copy of the cell, so callers can not modify the cached cell
+func copyCell(cell *grids.Cell) *grids.Cell {
+	if cell == nil {
+		return nil
+	}
+	return proto.Clone(cell).(*grids.Cell)
+}
+
+// quantize rounds the value to the precision of the provider
+func (p *Provider) quantize(v float64) float64 {
+	pow := math.Pow10(p.precision)
+	return math.Round(v*pow) / pow
+}
+
+// CellSize returns the cell size of the base provider
+func (p *Provider) CellSize() grids.CellSize { return p.Provider.CellSize() }
+
+// CellForBounds is not cached, as the bounds of the returned cell are the given bounds
+func (p *Provider) CellForBounds(bounds geom.Extent, srid uint) (*grids.Cell, error) {
+	return p.Provider.CellForBounds(bounds, srid)
+}
+
+// CellForLatLng returns the cell from the cache, if the cell for the lat lng,
+// rounded to the precision of the cache, has been seen; otherwise the base
+// provider is asked.
+func (p *Provider) CellForLatLng(lat, lng float64, srid uint) (*grids.Cell, error) {
+	key := fmt.Sprintf("latlng:%v:%v:%v", srid, p.quantize(lat), p.quantize(lng))
+	if ent, ok := p.get(key); ok {
+		return copyCell(ent.cell), ent.err
+	}
+	cell, err := p.Provider.CellForLatLng(lat, lng, srid)
+	if cacheable(err) {
+		p.set(key, copyCell(cell), err)
+	}
+	return cell, err
+}
+
+// CellForMDGID returns the cell from the cache, if the mdgid has been seen;
+// otherwise the base provider is asked.
+func (p *Provider) CellForMDGID(mdgid *grids.MDGID) (*grids.Cell, error) {
+	key := fmt.Sprintf("mdgid:%v:%v", mdgid.GetId(), mdgid.GetPart())
+	if ent, ok := p.get(key); ok {
+		return copyCell(ent.cell), ent.err
+	}
+	cell, err := p.Provider.CellForMDGID(mdgid)
+	if cacheable(err) {
+		p.set(key, copyCell(cell), err)
+	}
+	return cell, err
+}
+
+// CellsForBounds passes through to the base provider, if it supports enumeration
+func (p *Provider) CellsForBounds(bounds geom.Extent, srid uint) (grids.CellIterator, error) {
+	return grids.CellsForBounds(p.Provider, bounds, srid)
+}
+
+// Neighbors passes through to the base provider, if it supports it; otherwise
+// the neighbors are probed through the cache.
+func (p *Provider) Neighbors(cell *grids.Cell) (*grids.Neighbors, error) {
+	if nbr, ok := p.Provider.(grids.Neighborer); ok {
+		return nbr.Neighbors(cell)
+	}
+	return grids.ProbeNeighbors(p, cell)
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/geom"
+)
+
+// countingProvider has a single cell, counting the number of lookups
+type countingProvider struct {
+	lookups int
+}
+
+func (*countingProvider) cell() *grids.Cell {
+	return grids.NewCell(
+		"V795G25492",
+		[2]float64{32.5, -117.25},
+		[2]float64{32.75, -117.0},
+		"", "", nil, nil, time.Time{}, "", "2242I", "V795",
+		[2]string{}, [2]string{}, nil,
+	)
+}
+func (cp *countingProvider) CellForBounds(geom.Extent, uint) (*grids.Cell, error) {
+	cp.lookups++
+	return cp.cell(), nil
+}
+func (cp *countingProvider) CellForLatLng(lat, lng float64, srid uint) (*grids.Cell, error) {
+	cp.lookups++
+	if !cp.cell().Hull().ContainsPoint([2]float64{lng, lat}) {
+		return nil, grids.ErrNotFound
+	}
+	return cp.cell(), nil
+}
+func (cp *countingProvider) CellForMDGID(mdgid *grids.MDGID) (*grids.Cell, error) {
+	cp.lookups++
+	if mdgid.Id != "V795G25492" {
+		return nil, grids.ErrNotFound
+	}
+	return cp.cell(), nil
+}
+func (*countingProvider) CellSize() grids.CellSize { return grids.CellSize50K }
+
+func TestCache(t *testing.T) {
+	type lookup struct {
+		// mdgid is used if set, otherwise lat, lng
+		mdgid    string
+		lat, lng float64
+		// advance the clock before the lookup
+		advance time.Duration
+		err     error
+	}
+	type tcase struct {
+		name    string
+		size    int
+		ttl     time.Duration
+		lookups []lookup
+		// number of lookups that reach the base provider
+		base  int
+		stats Stats
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			base := new(countingProvider)
+			p, err := New(base, tc.size, tc.ttl, DefaultPrecision)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			now := time.Now()
+			p.now = func() time.Time { return now }
+			for i, l := range tc.lookups {
+				now = now.Add(l.advance)
+				var cell *grids.Cell
+				if l.mdgid != "" {
+					cell, err = p.CellForMDGID(grids.NewMDGID(l.mdgid))
+				} else {
+					cell, err = p.CellForLatLng(l.lat, l.lng, 4326)
+				}
+				if err != l.err {
+					t.Errorf("lookup %v error, expected %v got %v", i, l.err, err)
+					continue
+				}
+				if err == nil && cell.GetMdgid().GetId() != "V795G25492" {
+					t.Errorf("lookup %v mdgid, expected V795G25492 got %v", i, cell.GetMdgid().GetId())
+				}
+			}
+			if base.lookups != tc.base {
+				t.Errorf("base lookups, expected %v got %v", tc.base, base.lookups)
+			}
+			if stats := p.Stats(); stats != tc.stats {
+				t.Errorf("stats, expected %v got %v", tc.stats, stats)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name: "hits",
+			size: 10,
+			lookups: []lookup{
+				{mdgid: "V795G25492"},
+				{mdgid: "V795G25492"},
+				{lat: 32.6, lng: -117.1},
+				// rounds to the same key
+				{lat: 32.600001, lng: -117.100001},
+				{lat: 32.7, lng: -117.1},
+			},
+			base:  3,
+			stats: Stats{Hits: 2, Misses: 3, Len: 3},
+		},
+		{
+			name: "not found",
+			size: 10,
+			lookups: []lookup{
+				{mdgid: "V795G00000", err: grids.ErrNotFound},
+				{mdgid: "V795G00000", err: grids.ErrNotFound},
+				{lat: 10, lng: 10, err: grids.ErrNotFound},
+				{lat: 10, lng: 10, err: grids.ErrNotFound},
+			},
+			base:  2,
+			stats: Stats{Hits: 2, Misses: 2, Len: 2},
+		},
+		{
+			name: "evictions",
+			size: 2,
+			lookups: []lookup{
+				{lat: 32.6, lng: -117.1},
+				{lat: 32.7, lng: -117.1},
+				{lat: 32.6, lng: -117.1},
+				// evicts 32.7
+				{lat: 32.6, lng: -117.2},
+				{lat: 32.6, lng: -117.1},
+				{lat: 32.7, lng: -117.1},
+			},
+			base:  4,
+			stats: Stats{Hits: 2, Misses: 4, Evictions: 2, Len: 2},
+		},
+		{
+			name: "ttl",
+			size: 10,
+			ttl:  time.Minute,
+			lookups: []lookup{
+				{mdgid: "V795G25492"},
+				{mdgid: "V795G25492", advance: 30 * time.Second},
+				{mdgid: "V795G25492", advance: 30 * time.Second},
+				{mdgid: "V795G25492"},
+			},
+			base:  2,
+			stats: Stats{Hits: 2, Misses: 2, Expired: 1, Len: 1},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestCopy(t *testing.T) {
+	p, err := New(new(countingProvider), 10, 0, DefaultPrecision)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	cell, _ := p.CellForMDGID(grids.NewMDGID("V795G25492"))
+	cell.Sheet = "modified"
+	cell, _ = p.CellForMDGID(grids.NewMDGID("V795G25492"))
+	if cell.Sheet != "2242I" {
+		t.Errorf("sheet, expected %v got %v", "2242I", cell.Sheet)
+	}
+	if _, err = New(new(countingProvider), 0, 0, 0); err != ErrInvalidSize(0) {
+		t.Errorf("error, expected %v got %v", ErrInvalidSize(0), err)
+	}
+}
@@ -0,0 +1,22 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/gdey/errors"
+)
+
+const (
+	// ErrBlankSubprovider is returned when the base provider name is blank
+	ErrBlankSubprovider = errors.String("error, base provider name is blank")
+
+	// ErrNilBaseProvider is returned when the base provider is nil
+	ErrNilBaseProvider = errors.String("error, base provider is nil")
+)
+
+// ErrInvalidSize is returned when the size of the cache is not a positive number
+type ErrInvalidSize int
+
+func (err ErrInvalidSize) Error() string {
+	return fmt.Sprintf("error, invalid cache size (%d), must be greater then zero", int(err))
+}
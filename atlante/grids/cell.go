@@ -14,8 +14,10 @@ import (
 	"github.com/go-spatial/atlante/atlante/internal/resolution"
 	"github.com/go-spatial/atlante/mbgl/bounds"
 	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb"
 	"github.com/go-spatial/geom/planar/coord"
 	"github.com/go-spatial/geom/spherical"
+	"github.com/go-spatial/tegola"
 )
 
 // CellSize is the size of the cell in meters
@@ -43,6 +45,10 @@ const (
 	CellSize250K = 250000
 )
 
+// MetaDataKeyPartLabel is the metadata key providers that sub-divide cells
+// use to store a human readable label for the part.
+const MetaDataKeyPartLabel = "part_label"
+
 // Provider returns a grid object that can be used to generate
 // a Map Grid, if the grid object is not found or does not exist
 // the CellFor* methods should return a nil cell, and ErrNotFound
@@ -54,6 +60,12 @@ type Provider interface {
 	CellSize() CellSize
 }
 
+// Closer is implemented by the Providers holding resources, like database connections,
+// that should be released once the provider is no longer used
+type Closer interface {
+	Close()
+}
+
 // AsString provides a human readable version of the MDGID
 func (m *MDGID) AsString() string {
 	if m == nil {
@@ -211,11 +223,15 @@ func (c *Cell) ReferenceNumber() string {
 }
 
 // SheetNumber is the sheet number for the grid, including a
-// sub-part number if there is one.
+// sub-part number if there is one. If the provider labeled the part
+// the label is used instead of the part number.
 func (c *Cell) SheetNumber() string {
 	if c.Mdgid.Part == 0 {
 		return c.Sheet
 	}
+	if lbl := c.MetaData[MetaDataKeyPartLabel]; lbl != "" {
+		return fmt.Sprintf("%s-%v", c.Sheet, lbl)
+	}
 	return fmt.Sprintf("%s-%v", c.Sheet, c.Mdgid.Part)
 }
 
@@ -316,6 +332,29 @@ func (c *Cell) SE() [2]float64 {
 // Hull returns the hull of the Cell
 func (c *Cell) Hull() *geom.Extent { return spherical.Hull(c.NE(), c.SW()) }
 
+// HasFootprint returns weather the cell has a footprint geometry
+func (c *Cell) HasFootprint() bool { return len(c.GetGeometry()) != 0 }
+
+// Footprint returns the footprint geometry of the cell, in 4326. If the cell
+// does not have a footprint, the hull of the cell is returned as a polygon.
+func (c *Cell) Footprint() (geom.Geometry, error) {
+	if !c.HasFootprint() {
+		return c.Hull().AsPolygon(), nil
+	}
+	return wkb.DecodeBytes(c.Geometry)
+}
+
+// SetFootprint sets the footprint geometry of the cell, the geometry should be in 4326.
+// A nil geometry will clear the footprint.
+func (c *Cell) SetFootprint(g geom.Geometry) (err error) {
+	if g == nil {
+		c.Geometry = nil
+		return nil
+	}
+	c.Geometry, err = wkb.EncodeBytes(g)
+	return err
+}
+
 // CenterPtForZoom returns the center point of the bounds for the given zoom value
 func (c *Cell) CenterPtForZoom(zoom float64) [2]float64 {
 	return bounds.Center(c.Hull(), zoom)
@@ -432,6 +471,20 @@ func ToDMS(lat, lng float64) (todms [2]DMS) {
 	}
 }
 
+// LatLngForSRID will convert the given x, y coordinate in the srid to a lat, lng pair.
+// Currently only 4326 and 3857 are supported.
+func LatLngForSRID(x, y float64, srid uint) (lat, lng float64, err error) {
+	switch srid {
+	case tegola.WGS84:
+		return y, x, nil
+	case tegola.WebMercator:
+		latlng := bounds.ESPG3857.Unproject([2]float64{x, y})
+		return latlng[0], latlng[1], nil
+	default:
+		return 0, 0, ErrUnsupportedSRID(srid)
+	}
+}
+
 // zoneFromLatLng get the lat zone given the two values.
 // The returned value will be from 1-60, if 0 is returned
 // it means that the lat,lng value was in the polar region
@@ -205,6 +205,7 @@ type Cell struct {
 	Series               string               `protobuf:"bytes,14,opt,name=series,proto3" json:"series,omitempty"`
 	SwDms                *Cell_LatLngDMS      `protobuf:"bytes,15,opt,name=sw_dms,json=swDms,proto3" json:"sw_dms,omitempty"`
 	NeDms                *Cell_LatLngDMS      `protobuf:"bytes,16,opt,name=ne_dms,json=neDms,proto3" json:"ne_dms,omitempty"`
+	Geometry             []byte               `protobuf:"bytes,17,opt,name=geometry,proto3" json:"geometry,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -340,6 +341,13 @@ func (m *Cell) GetNeDms() *Cell_LatLngDMS {
 	return nil
 }
 
+func (m *Cell) GetGeometry() []byte {
+	if m != nil {
+		return m.Geometry
+	}
+	return nil
+}
+
 type Cell_LatLng struct {
 	Lat                  float32  `protobuf:"fixed32,1,opt,name=lat,proto3" json:"lat,omitempty"`
 	Lng                  float32  `protobuf:"fixed32,2,opt,name=lng,proto3" json:"lng,omitempty"`
@@ -448,42 +456,43 @@ func init() {
 func init() { proto.RegisterFile("cell.proto", fileDescriptor_d2698cf62ebc0420) }
 
 var fileDescriptor_d2698cf62ebc0420 = []byte{
-	// 586 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xdf, 0x6f, 0xd3, 0x30,
-	0x10, 0xc7, 0xe9, 0xaf, 0xac, 0xbd, 0xae, 0xa3, 0xb2, 0xa6, 0x61, 0x55, 0x42, 0x94, 0x3e, 0x55,
-	0xdb, 0x48, 0x50, 0xe1, 0x01, 0x81, 0x40, 0x02, 0x3a, 0xb1, 0x4d, 0x2b, 0x93, 0xbc, 0xee, 0x85,
-	0x97, 0xc9, 0x6d, 0xbc, 0xd4, 0x5a, 0xe2, 0x44, 0xf5, 0x95, 0xaa, 0xfc, 0x05, 0xfc, 0xd9, 0xc8,
-	0x97, 0x64, 0x62, 0x03, 0x34, 0xde, 0xee, 0x2e, 0x9f, 0xef, 0xd9, 0xf7, 0xbd, 0x18, 0x60, 0xae,
-	0xe2, 0xd8, 0xcf, 0x96, 0x29, 0xa6, 0xac, 0x23, 0x31, 0x96, 0x06, 0x95, 0x1f, 0x2d, 0x75, 0x68,
-	0x7b, 0xcf, 0xa2, 0x34, 0x8d, 0x62, 0x15, 0xd0, 0xc7, 0xd9, 0xea, 0x3a, 0x40, 0x9d, 0x28, 0x8b,
-	0x32, 0xc9, 0x72, 0x7e, 0x70, 0x00, 0x8d, 0xc9, 0xf8, 0xcb, 0xc9, 0x98, 0xed, 0x40, 0x55, 0x87,
-	0xbc, 0xd2, 0xaf, 0x0c, 0x5b, 0xa2, 0xaa, 0x43, 0xc6, 0xa0, 0x9e, 0xc9, 0x25, 0xf2, 0x6a, 0xbf,
-	0x32, 0xec, 0x08, 0x8a, 0x07, 0xa7, 0xd0, 0x3c, 0x0a, 0x35, 0x9e, 0x98, 0xeb, 0xd4, 0xf1, 0xb3,
-	0x4d, 0xc9, 0xcf, 0x36, 0xcc, 0x87, 0x7a, 0x28, 0x51, 0x11, 0xdf, 0x1e, 0xf5, 0xfc, 0xfc, 0x60,
-	0xbf, 0x3c, 0xd8, 0x9f, 0x96, 0x07, 0x0b, 0xe2, 0x06, 0xa7, 0xb0, 0x75, 0x39, 0x9d, 0x50, 0x2b,
-	0x06, 0xf5, 0x1f, 0xa9, 0x51, 0xd4, 0xac, 0x23, 0x28, 0x66, 0x07, 0x50, 0x5f, 0xa8, 0x44, 0x53,
-	0xbb, 0x9d, 0xd1, 0x13, 0xff, 0xce, 0x58, 0xfe, 0xf1, 0xd1, 0xe4, 0x64, 0xba, 0xc9, 0x94, 0x20,
-	0x68, 0xf0, 0xd3, 0x83, 0xfa, 0x67, 0x15, 0xc7, 0x6c, 0x1f, 0x1a, 0x49, 0x18, 0x15, 0x73, 0xb4,
-	0x47, 0xbb, 0xf7, 0x64, 0x34, 0xa9, 0xc8, 0x11, 0xb6, 0x0f, 0x55, 0xbb, 0xbe, 0xbd, 0xee, 0x5d,
-	0xd0, 0x35, 0xf3, 0xcf, 0x24, 0x9e, 0x99, 0x48, 0x54, 0xed, 0xda, 0xb1, 0x46, 0xf1, 0xda, 0xc3,
-	0xac, 0x51, 0xec, 0x10, 0x6a, 0xb1, 0x32, 0xbc, 0xfe, 0x20, 0xec, 0x30, 0xc6, 0x61, 0x6b, 0x9e,
-	0xae, 0x0c, 0x2e, 0x37, 0xbc, 0x41, 0x5e, 0x96, 0xa9, 0x73, 0x65, 0xae, 0x71, 0xc3, 0x3d, 0x2a,
-	0x53, 0xcc, 0x86, 0x50, 0x5b, 0x61, 0xc2, 0xb7, 0xa8, 0xf7, 0xde, 0xbd, 0xde, 0x85, 0x9d, 0xc2,
-	0x21, 0x2c, 0x00, 0x4f, 0x85, 0x1a, 0x55, 0xc8, 0x9b, 0x04, 0xdf, 0x77, 0xb0, 0xdc, 0xa3, 0x28,
-	0x30, 0xf6, 0x1e, 0xb6, 0xb3, 0xd5, 0x2c, 0xd6, 0x76, 0xa1, 0xc2, 0x2b, 0x89, 0x1c, 0x1e, 0xdc,
-	0x63, 0xfb, 0x96, 0xff, 0x88, 0xec, 0x03, 0xb4, 0x12, 0x85, 0xf2, 0x2a, 0x94, 0x28, 0x79, 0xbb,
-	0x5f, 0x1b, 0xb6, 0x47, 0xcf, 0xff, 0x36, 0xfb, 0x44, 0xa1, 0x1c, 0x4b, 0x94, 0x47, 0x6e, 0x46,
-	0xd1, 0x4c, 0x8a, 0x94, 0x75, 0xa1, 0x66, 0x96, 0x86, 0x6f, 0xd3, 0xb0, 0x2e, 0x64, 0xbb, 0xd0,
-	0xb0, 0x0b, 0xa5, 0x90, 0x77, 0xa8, 0x96, 0x27, 0x6c, 0x0f, 0x3c, 0xab, 0x96, 0x5a, 0x59, 0xbe,
-	0x43, 0xe5, 0x22, 0x63, 0xaf, 0xc1, 0xb3, 0xeb, 0xab, 0x30, 0xb1, 0xfc, 0x31, 0x5d, 0xfc, 0xe9,
-	0xbf, 0x8d, 0x1f, 0x4f, 0x2e, 0x44, 0xc3, 0xae, 0xc7, 0x09, 0xa9, 0x8c, 0x22, 0x55, 0xf7, 0xbf,
-	0x54, 0x46, 0x8d, 0x13, 0xdb, 0x3b, 0x04, 0x2f, 0xaf, 0xb9, 0x5b, 0xc7, 0x12, 0xe9, 0x6f, 0xab,
-	0x0a, 0x17, 0x52, 0xc5, 0x44, 0xf4, 0x5b, 0xb9, 0x8a, 0x89, 0x7a, 0x01, 0xb4, 0x6e, 0x3b, 0xfc,
-	0x2e, 0x68, 0xfd, 0x21, 0x68, 0xe5, 0x82, 0x77, 0xd0, 0xb9, 0xe3, 0x92, 0x43, 0x6e, 0x54, 0xf9,
-	0xd6, 0x5c, 0xe8, 0xbc, 0xf9, 0x2e, 0xe3, 0x95, 0x2a, 0x64, 0x79, 0xf2, 0xb6, 0xfa, 0xa6, 0xb2,
-	0xdf, 0x87, 0x66, 0xf9, 0x38, 0x58, 0x0b, 0x1a, 0x5f, 0xcf, 0xc5, 0xf4, 0xb8, 0xfb, 0xc8, 0x85,
-	0x17, 0xe7, 0x97, 0xd3, 0xe3, 0x6e, 0xe5, 0xd3, 0xcb, 0x6f, 0x7e, 0xa4, 0x71, 0xb1, 0x9a, 0xf9,
-	0xf3, 0x34, 0x09, 0xa2, 0xf4, 0x85, 0xcd, 0x24, 0x6a, 0x19, 0x07, 0x89, 0xcc, 0x30, 0x4d, 0xe3,
-	0x1b, 0x8d, 0x41, 0xe1, 0x42, 0x40, 0x2e, 0xcc, 0x3c, 0x5a, 0xfe, 0xab, 0x5f, 0x01, 0x00, 0x00,
-	0xff, 0xff, 0x6d, 0x24, 0x53, 0x48, 0x68, 0x04, 0x00, 0x00,
+	// 597 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0x41, 0x6f, 0xd3, 0x4e,
+	0x10, 0xc5, 0xff, 0x71, 0x12, 0x37, 0x99, 0x34, 0xfd, 0x87, 0x55, 0x55, 0x56, 0x91, 0x10, 0x21,
+	0xa7, 0xa8, 0x2d, 0x36, 0x0a, 0x1c, 0x10, 0x08, 0x24, 0x20, 0x15, 0x6d, 0xd5, 0x50, 0x69, 0x9b,
+	0x5e, 0xb8, 0x54, 0x9b, 0x78, 0xea, 0xac, 0x6a, 0xaf, 0xad, 0x78, 0x43, 0x14, 0x3e, 0x0f, 0x1f,
+	0x14, 0xed, 0xd8, 0x8e, 0x68, 0x01, 0x85, 0xdb, 0xcc, 0xf8, 0xf7, 0x66, 0xfd, 0xde, 0xda, 0x00,
+	0x33, 0x8c, 0x22, 0x2f, 0x5d, 0x24, 0x26, 0x61, 0x6d, 0x69, 0x22, 0xa9, 0x0d, 0x7a, 0xe1, 0x42,
+	0x05, 0x59, 0xf7, 0x69, 0x98, 0x24, 0x61, 0x84, 0x3e, 0x3d, 0x9c, 0x2e, 0x6f, 0x7d, 0xa3, 0x62,
+	0xcc, 0x8c, 0x8c, 0xd3, 0x9c, 0xef, 0x1f, 0x41, 0x7d, 0x3c, 0xfa, 0x7c, 0x36, 0x62, 0x7b, 0xe0,
+	0xa8, 0x80, 0x57, 0x7a, 0x95, 0x41, 0x53, 0x38, 0x2a, 0x60, 0x0c, 0x6a, 0xa9, 0x5c, 0x18, 0xee,
+	0xf4, 0x2a, 0x83, 0xb6, 0xa0, 0xba, 0x7f, 0x0e, 0x8d, 0x93, 0x40, 0x99, 0x33, 0x7d, 0x9b, 0x58,
+	0x7e, 0xba, 0x2e, 0xf9, 0xe9, 0x9a, 0x79, 0x50, 0x0b, 0xa4, 0x41, 0xe2, 0x5b, 0xc3, 0xae, 0x97,
+	0x1f, 0xec, 0x95, 0x07, 0x7b, 0x93, 0xf2, 0x60, 0x41, 0x5c, 0xff, 0x1c, 0x76, 0xae, 0x27, 0x63,
+	0x5a, 0xc5, 0xa0, 0xf6, 0x3d, 0xd1, 0x48, 0xcb, 0xda, 0x82, 0x6a, 0x76, 0x04, 0xb5, 0x39, 0xc6,
+	0x8a, 0xd6, 0xed, 0x0d, 0x1f, 0x7b, 0xf7, 0x6c, 0x79, 0xa7, 0x27, 0xe3, 0xb3, 0xc9, 0x3a, 0x45,
+	0x41, 0x50, 0xff, 0x87, 0x0b, 0xb5, 0x4f, 0x18, 0x45, 0xec, 0x10, 0xea, 0x71, 0x10, 0x16, 0x3e,
+	0x5a, 0xc3, 0xfd, 0x07, 0x32, 0x72, 0x2a, 0x72, 0x84, 0x1d, 0x82, 0x93, 0xad, 0x36, 0xaf, 0x7b,
+	0x1f, 0xb4, 0xcb, 0xbc, 0x0b, 0x69, 0x2e, 0x74, 0x28, 0x9c, 0x6c, 0x65, 0x59, 0x8d, 0xbc, 0xba,
+	0x9d, 0xd5, 0xc8, 0x8e, 0xa1, 0x1a, 0xa1, 0xe6, 0xb5, 0xad, 0xb0, 0xc5, 0x18, 0x87, 0x9d, 0x59,
+	0xb2, 0xd4, 0x66, 0xb1, 0xe6, 0x75, 0xca, 0xb2, 0x6c, 0x6d, 0x2a, 0x33, 0x65, 0xd6, 0xdc, 0xa5,
+	0x31, 0xd5, 0x6c, 0x00, 0xd5, 0xa5, 0x89, 0xf9, 0x0e, 0xed, 0x3e, 0x78, 0xb0, 0xbb, 0x88, 0x53,
+	0x58, 0x84, 0xf9, 0xe0, 0x62, 0xa0, 0x0c, 0x06, 0xbc, 0x41, 0xf0, 0xc3, 0x04, 0xcb, 0x7b, 0x14,
+	0x05, 0xc6, 0xde, 0xc1, 0x6e, 0xba, 0x9c, 0x46, 0x2a, 0x9b, 0x63, 0x70, 0x23, 0x0d, 0x87, 0xad,
+	0xf7, 0xd8, 0xda, 0xf0, 0x1f, 0x0c, 0x7b, 0x0f, 0xcd, 0x18, 0x8d, 0xbc, 0x09, 0xa4, 0x91, 0xbc,
+	0xd5, 0xab, 0x0e, 0x5a, 0xc3, 0x67, 0x7f, 0xf2, 0x3e, 0x46, 0x23, 0x47, 0xd2, 0xc8, 0x13, 0xeb,
+	0x51, 0x34, 0xe2, 0xa2, 0x65, 0x1d, 0xa8, 0xea, 0x85, 0xe6, 0xbb, 0x64, 0xd6, 0x96, 0x6c, 0x1f,
+	0xea, 0xd9, 0x1c, 0xd1, 0xf0, 0x36, 0xcd, 0xf2, 0x86, 0x1d, 0x80, 0x9b, 0xe1, 0x42, 0x61, 0xc6,
+	0xf7, 0x68, 0x5c, 0x74, 0xec, 0x15, 0xb8, 0xd9, 0xea, 0x26, 0x88, 0x33, 0xfe, 0x3f, 0xbd, 0xf8,
+	0x93, 0xbf, 0x07, 0x3f, 0x1a, 0x5f, 0x89, 0x7a, 0xb6, 0x1a, 0xc5, 0xa4, 0xd2, 0x48, 0xaa, 0xce,
+	0x3f, 0xa9, 0x34, 0x5a, 0x55, 0x17, 0x1a, 0x21, 0x26, 0x31, 0xda, 0x4b, 0x7b, 0xd4, 0xab, 0x0c,
+	0x76, 0xc5, 0xa6, 0xef, 0x1e, 0x83, 0x9b, 0xf3, 0xd6, 0x51, 0x24, 0x0d, 0x7d, 0x89, 0x8e, 0xb0,
+	0x25, 0x4d, 0x74, 0xc8, 0x9d, 0x62, 0xa2, 0xc3, 0xae, 0x0f, 0xcd, 0xcd, 0xf6, 0x5f, 0x05, 0xcd,
+	0xdf, 0x04, 0xcd, 0x5c, 0xf0, 0x16, 0xda, 0xf7, 0x12, 0xb4, 0xc8, 0x1d, 0x96, 0xff, 0xa1, 0x2d,
+	0x6d, 0x6e, 0xdf, 0x64, 0xb4, 0xc4, 0x42, 0x96, 0x37, 0x6f, 0x9c, 0xd7, 0x95, 0xc3, 0x1e, 0x34,
+	0xca, 0x1f, 0x87, 0x35, 0xa1, 0xfe, 0xe5, 0x52, 0x4c, 0x4e, 0x3b, 0xff, 0xd9, 0xf2, 0xea, 0xf2,
+	0x7a, 0x72, 0xda, 0xa9, 0x7c, 0x7c, 0xf1, 0xd5, 0x0b, 0x95, 0x99, 0x2f, 0xa7, 0xde, 0x2c, 0x89,
+	0xfd, 0x30, 0x79, 0x9e, 0xa5, 0xd2, 0x28, 0x19, 0xf9, 0xb1, 0x4c, 0x4d, 0x92, 0x44, 0x77, 0xca,
+	0xf8, 0x45, 0x42, 0x3e, 0x25, 0x34, 0x75, 0xe9, 0xc3, 0x78, 0xf9, 0x73, 0x00, 0x9b, 0xa8, 0x33,
+	0xd8, 0x84, 0x04, 0x00, 0x00,
 }
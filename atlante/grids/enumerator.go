@@ -0,0 +1,90 @@
+package grids
+
+import (
+	"github.com/go-spatial/geom"
+)
+
+// CellIterator is used to walk through a set of cells.
+//
+//	iter, err := grids.CellsForBounds(prv, bounds, srid)
+//	if err != nil { … }
+//	defer iter.Close()
+//	for iter.Next() {
+//		cell := iter.Cell()
+//		…
+//	}
+//	if err := iter.Err(); err != nil { … }
+type CellIterator interface {
+	// Next advances the iterator to the next cell, it returns false
+	// when there are no more cells or an error has occurred
+	Next() bool
+	// Cell returns the current cell
+	Cell() *Cell
+	// Err returns the error, if any, that stopped the iteration
+	Err() error
+	// Close releases any resources held by the iterator
+	Close() error
+}
+
+// Enumerator is an optional interface that Providers can implement to list
+// all the cells that intersect a bounds.
+type Enumerator interface {
+	CellsForBounds(bounds geom.Extent, srid uint) (CellIterator, error)
+}
+
+// CellsForBounds returns an iterator for all the cells of the provider that
+// intersect the bounds. If the provider does not support enumeration
+// ErrEnumerationNotSupported is returned.
+func CellsForBounds(prv Provider, bounds geom.Extent, srid uint) (CellIterator, error) {
+	enum, ok := prv.(Enumerator)
+	if !ok {
+		return nil, ErrEnumerationNotSupported
+	}
+	return enum.CellsForBounds(bounds, srid)
+}
+
+// AllCells will read all the cells from the iterator, closing the iterator.
+func AllCells(iter CellIterator) (cells []*Cell, err error) {
+	defer iter.Close()
+	for iter.Next() {
+		cells = append(cells, iter.Cell())
+	}
+	return cells, iter.Err()
+}
+
+// SliceIterator is a CellIterator over a slice of cells
+type SliceIterator struct {
+	cells []*Cell
+	idx   int
+}
+
+// NewSliceIterator returns a CellIterator for the given cells
+func NewSliceIterator(cells ...*Cell) *SliceIterator {
+	return &SliceIterator{cells: cells, idx: -1}
+}
+
+// Next implements the CellIterator interface
+func (si *SliceIterator) Next() bool {
+	if si.idx >= len(si.cells) {
+		return false
+	}
+	si.idx++
+	return si.idx < len(si.cells)
+}
+
+// Cell implements the CellIterator interface
+func (si *SliceIterator) Cell() *Cell {
+	if si.idx < 0 || si.idx >= len(si.cells) {
+		return nil
+	}
+	return si.cells[si.idx]
+}
+
+// Err implements the CellIterator interface
+func (*SliceIterator) Err() error { return nil }
+
+// Close implements the CellIterator interface
+func (si *SliceIterator) Close() error {
+	si.idx = len(si.cells)
+	return nil
+}
@@ -1,6 +1,8 @@
 package grids
 
 import (
+	"fmt"
+
 	"github.com/gdey/errors"
 )
 
@@ -11,6 +13,9 @@ const (
 
 	// ErrNoProvidersRegistered is returned when providers have not been registered with the system
 	ErrNoProvidersRegistered = errors.String("no providers registered")
+
+	// ErrEnumerationNotSupported is returned when a provider is not able to list cells
+	ErrEnumerationNotSupported = errors.String("provider does not support enumerating cells")
 )
 
 // ErrProviderTypeExists is returned when the Provider type was already registered.
@@ -26,3 +31,10 @@ type ErrProviderNotRegistered string
 func (err ErrProviderNotRegistered) Error() string {
 	return "provider (" + string(err) + ") not registered"
 }
+
+// ErrUnsupportedSRID is returned when a provider is unable to convert coordinates in the given srid
+type ErrUnsupportedSRID uint
+
+func (err ErrUnsupportedSRID) Error() string {
+	return fmt.Sprintf("unsupported srid (%d)", uint(err))
+}
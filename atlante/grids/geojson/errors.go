@@ -0,0 +1,17 @@
+package geojson
+
+import (
+	"github.com/gdey/errors"
+)
+
+const (
+	// ErrBlankFile is returned when the file location is blank
+	ErrBlankFile = errors.String("error, " + ConfigKeyFile + " is blank")
+)
+
+// ErrDuplicateMDGID is returned when more then one feature has the same mdgid
+type ErrDuplicateMDGID string
+
+func (err ErrDuplicateMDGID) Error() string {
+	return "error, duplicate mdgid (" + string(err) + ")"
+}
@@ -0,0 +1,498 @@
+package geojson
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/common/log"
+
+	"github.com/go-spatial/atlante/atlante/config"
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/atlante/internal/urlutil"
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+// Name is the name of the provider type
+const Name = "geojson"
+
+const (
+	// DefaultEditDateFormat the time format to expect
+	DefaultEditDateFormat = time.RFC3339
+	// DefaultEditBy who edited the content if not provided
+	DefaultEditBy = ""
+)
+
+const (
+	// ConfigKeyFile is the config key for the location of the geojson file
+	ConfigKeyFile = "file"
+	// ConfigKeyScale is the scale of this provider
+	ConfigKeyScale = "scale"
+	// ConfigKeyEditDateFormat is the format to use for dates
+	ConfigKeyEditDateFormat = "edit_date_format"
+	// ConfigKeyEditBy who the default user for edit_by should be
+	ConfigKeyEditBy = "edit_by"
+
+	// PropertyMDGID is the expected feature property name for the mdgid
+	PropertyMDGID = "mdg_id"
+	// PropertySheet is the expected feature property name for the sheet
+	PropertySheet = "sheet"
+	// PropertySeries is the expected feature property name for the series
+	PropertySeries = "series"
+	// PropertyNRN is the expected feature property name for the nrn
+	PropertyNRN = "nrn"
+	// PropertySWLAT is the expected feature property name for the southwest lat
+	PropertySWLAT = "swlat"
+	// PropertySWLNG is the expected feature property name for the southwest lng
+	PropertySWLNG = "swlng"
+	// PropertyNELAT is the expected feature property name for the northeast lat
+	PropertyNELAT = "nelat"
+	// PropertyNELNG is the expected feature property name for the northeast lng
+	PropertyNELNG = "nelng"
+	// PropertySWLATDMS is the expected feature property name for the southwest lat in degree minute seconds
+	PropertySWLATDMS = "swlat_dms"
+	// PropertySWLNGDMS is the expected feature property name for the southwest lng in degree minute seconds
+	PropertySWLNGDMS = "swlng_dms"
+	// PropertyNELATDMS is the expected feature property name for the northeast lat in degree minute seconds
+	PropertyNELATDMS = "nelat_dms"
+	// PropertyNELNGDMS is the expected feature property name for the northeast lng in degree minute seconds
+	PropertyNELNGDMS = "nelng_dms"
+	// PropertyCountry is the property for the country
+	PropertyCountry = "country"
+	// PropertyCity is the property for the city
+	PropertyCity = "city"
+	// PropertyEditedBy is the property for the edited by
+	PropertyEditedBy = "edited_by"
+	// PropertyEditedAt is the property for the edited at
+	PropertyEditedAt = "edited_at"
+)
+
+func init() {
+	grids.Register(Name, NewGridProvider, nil)
+	grids.RegisterSchema(Name, config.Schema{
+		Required: []string{ConfigKeyFile, ConfigKeyScale},
+		Optional: []string{ConfigKeyEditBy, ConfigKeyEditDateFormat},
+	})
+}
+
+// feature is a geojson feature; we don't use geojson.Feature as
+// the id of the feature may be a string or a number.
+type feature struct {
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   geojson.Geometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type featureCollection struct {
+	Features []feature `json:"features"`
+}
+
+// entry is a loaded grid cell along with the footprint it covers
+type entry struct {
+	geometry geom.Geometry
+	extent   *geom.Extent
+	cell     *grids.Cell
+}
+
+// Provider implements the grids.Provider interface, backed by a geojson
+// FeatureCollection that is loaded into memory.
+type Provider struct {
+	cellSize grids.CellSize
+	entries  []entry
+	mdgids   map[string]int
+}
+
+// NewGridProvider returns a grid provider based on a geojson file
+func NewGridProvider(config grids.ProviderConfig) (grids.Provider, error) {
+	file, err := config.String(ConfigKeyFile, nil)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(file) == "" {
+		return nil, ErrBlankFile
+	}
+
+	var scale uint
+	if scale, err = config.Uint(ConfigKeyScale, nil); err != nil {
+		return nil, err
+	}
+
+	editedBy := DefaultEditBy
+	if editedBy, err = config.String(ConfigKeyEditBy, &editedBy); err != nil {
+		return nil, err
+	}
+
+	editedDateFormat := DefaultEditDateFormat
+	if editedDateFormat, err = config.String(ConfigKeyEditDateFormat, &editedDateFormat); err != nil {
+		return nil, err
+	}
+
+	location, err := url.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file location (%v): %w", file, err)
+	}
+
+	p := Provider{
+		cellSize: grids.CellSize(scale),
+		mdgids:   make(map[string]int),
+	}
+	err = urlutil.VisitReader(location, func(r io.Reader) error {
+		return p.load(r, editedBy, editedDateFormat)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("loaded %v cells from %v", len(p.entries), file)
+	return &p, nil
+}
+
+// New returns a new grid provider with the cells described by the geojson FeatureCollection
+// in the reader
+func New(r io.Reader, cellSize grids.CellSize, editedBy, editedDateFormat string) (*Provider, error) {
+	p := Provider{
+		cellSize: cellSize,
+		mdgids:   make(map[string]int),
+	}
+	if err := p.load(r, editedBy, editedDateFormat); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *Provider) load(r io.Reader, editedBy, editedDateFormat string) error {
+	var fc featureCollection
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return fmt.Errorf("error decoding geojson: %w", err)
+	}
+	for i := range fc.Features {
+		e, err := entryFromFeature(fc.Features[i], editedBy, editedDateFormat)
+		if err != nil {
+			return fmt.Errorf("error feature %v: %w", i, err)
+		}
+		if _, ok := p.mdgids[e.cell.Mdgid.Id]; ok {
+			return ErrDuplicateMDGID(e.cell.Mdgid.Id)
+		}
+		p.mdgids[e.cell.Mdgid.Id] = len(p.entries)
+		p.entries = append(p.entries, e)
+	}
+	return nil
+}
+
+func stringProperty(name string, val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("error unabled to convert property %v (%v) into string", name, val)
+	}
+}
+
+func floatProperty(name string, val interface{}) (*float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return &v, nil
+	case string:
+		vv, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error failed to parse property %v (%v) as float64: %w", name, val, err)
+		}
+		return &vv, nil
+	default:
+		return nil, fmt.Errorf("error unabled to convert property %v (%v) into float64", name, val)
+	}
+}
+
+// entryFromFeature parses the feature properties into a grids.Cell
+func entryFromFeature(f feature, editedBy, editedDateFormat string) (e entry, err error) {
+	var (
+		mdgid    string
+		sheet    string
+		series   string
+		nrn      string
+		swlat    *float64
+		swlng    *float64
+		nelat    *float64
+		nelng    *float64
+		swlatDMS string
+		swlngDMS string
+		nelatDMS string
+		nelngDMS string
+		country  string
+		city     string
+		editedAt time.Time
+
+		metadata = make(map[string]string)
+	)
+
+	for name, val := range f.Properties {
+		if val == nil {
+			continue
+		}
+		switch name {
+		case PropertyMDGID:
+			mdgid, err = stringProperty(name, val)
+		case PropertySheet:
+			sheet, err = stringProperty(name, val)
+		case PropertySeries:
+			series, err = stringProperty(name, val)
+		case PropertyNRN:
+			nrn, err = stringProperty(name, val)
+		case PropertySWLAT:
+			swlat, err = floatProperty(name, val)
+		case PropertySWLNG:
+			swlng, err = floatProperty(name, val)
+		case PropertyNELAT:
+			nelat, err = floatProperty(name, val)
+		case PropertyNELNG:
+			nelng, err = floatProperty(name, val)
+		case PropertySWLATDMS:
+			swlatDMS, err = stringProperty(name, val)
+		case PropertySWLNGDMS:
+			swlngDMS, err = stringProperty(name, val)
+		case PropertyNELATDMS:
+			nelatDMS, err = stringProperty(name, val)
+		case PropertyNELNGDMS:
+			nelngDMS, err = stringProperty(name, val)
+		case PropertyCountry:
+			country, err = stringProperty(name, val)
+		case PropertyCity:
+			city, err = stringProperty(name, val)
+		case PropertyEditedBy:
+			editedBy, err = stringProperty(name, val)
+		case PropertyEditedAt:
+			var at string
+			if at, err = stringProperty(name, val); err != nil {
+				break
+			}
+			if editedAt, err = time.Parse(editedDateFormat, at); err != nil {
+				err = fmt.Errorf("error failed to parse property %v (%v) as time: %w", name, val, err)
+			}
+		default:
+			// everything else is passed to the template through the metadata
+			switch v := val.(type) {
+			case string:
+				metadata[name] = v
+			case float64:
+				metadata[name] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				metadata[name] = fmt.Sprint(v)
+			}
+		}
+		if err != nil {
+			return e, err
+		}
+	}
+
+	if mdgid == "" && f.ID != nil {
+		mdgid, _ = stringProperty("id", f.ID)
+	}
+	if mdgid == "" {
+		return e, fmt.Errorf("error required property %v not provided", PropertyMDGID)
+	}
+	if sheet == "" {
+		return e, fmt.Errorf("error required property %v not provided", PropertySheet)
+	}
+	if series == "" {
+		return e, fmt.Errorf("error required property %v not provided", PropertySeries)
+	}
+
+	if f.Geometry.Geometry != nil {
+		e.geometry = f.Geometry.Geometry
+		if e.extent, err = geom.NewExtentFromGeometry(e.geometry); err != nil {
+			return e, err
+		}
+	}
+
+	if swlat == nil || nelat == nil || swlng == nil || nelng == nil {
+		// if anyone is nil we will just calculate all of them
+		if e.extent == nil {
+			return e, fmt.Errorf("error required geometry not provided")
+		}
+		swlngv, swlatv, nelngv, nelatv := e.extent.MinX(), e.extent.MinY(), e.extent.MaxX(), e.extent.MaxY()
+		swlng, swlat, nelng, nelat = &swlngv, &swlatv, &nelngv, &nelatv
+		// dms values are recalculated by NewCell
+		swlatDMS, swlngDMS, nelatDMS, nelngDMS = "", "", "", ""
+	}
+	if e.extent == nil {
+		e.extent = geom.NewExtent([2]float64{*swlng, *swlat}, [2]float64{*nelng, *nelat})
+	}
+
+	e.cell = grids.NewCell(
+		mdgid,                                 // mdgid
+		[2]float64{*swlat, *swlng},            // sw
+		[2]float64{*nelat, *nelng},            // ne
+		country,                               // country
+		city,                                  // city
+		nil,                                   // utminfo
+		grids.NewEditInfo(editedBy, editedAt), // edited info
+		time.Time{},                           // publishedAt is set when the cell is requested
+		nrn,                                   // nrn
+		sheet,                                 // sheet
+		series,                                // series
+		[2]string{swlatDMS, swlngDMS},         // sw dms
+		[2]string{nelatDMS, nelngDMS},         // ne dms
+		metadata,                              // metadata
+	)
+	switch e.geometry.(type) {
+	case geom.Polygoner, geom.MultiPolygoner:
+		// only areas are footprints
+		if err = e.cell.SetFootprint(e.geometry); err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
+
+// contains returns whether the given point is in the footprint of the entry
+func (e entry) contains(pt [2]float64) bool {
+	if !e.extent.ContainsPoint(pt) {
+		return false
+	}
+	switch g := e.geometry.(type) {
+	case geom.Polygoner:
+		return polygonContains(g.LinearRings(), pt)
+	case geom.MultiPolygoner:
+		for _, plyg := range g.Polygons() {
+			if polygonContains(plyg, pt) {
+				return true
+			}
+		}
+		return false
+	default:
+		// no geometry, or not an area; use the extent
+		return true
+	}
+}
+
+// polygonContains uses the even-odd rule to determine if the point is inside
+// of the polygon; holes are taken into account.
+func polygonContains(rings [][][2]float64, pt [2]float64) bool {
+	inside := false
+	for _, ring := range rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			a, b := ring[i], ring[j]
+			if (a[1] > pt[1]) != (b[1] > pt[1]) &&
+				pt[0] < (b[0]-a[0])*(pt[1]-a[1])/(b[1]-a[1])+a[0] {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// copyCell returns a copy of the cell for the entry
+func (e entry) copyCell() *grids.Cell {
+	cell := proto.Clone(e.cell).(*grids.Cell)
+	// don't care for the error
+	cell.PublishedAt, _ = ptypes.TimestampProto(time.Now())
+	return cell
+}
+
+// CellSize returns the grid cell size
+func (p *Provider) CellSize() grids.CellSize {
+	if p == nil {
+		return grids.CellSize50K
+	}
+	return p.cellSize
+}
+
+// CellForBounds get the cell for the given bounds
+func (p *Provider) CellForBounds(bounds geom.Extent, srid uint) (*grids.Cell, error) {
+	swlat, swlng, err := grids.LatLngForSRID(bounds[0], bounds[1], srid)
+	if err != nil {
+		return nil, err
+	}
+	nelat, nelng, err := grids.LatLngForSRID(bounds[2], bounds[3], srid)
+	if err != nil {
+		return nil, err
+	}
+	ext := geom.NewExtent([2]float64{swlng, swlat}, [2]float64{nelng, nelat})
+
+	for i := range p.entries {
+		if _, ok := p.entries[i].extent.Intersect(ext); !ok {
+			continue
+		}
+
+		cell := p.entries[i].copyCell()
+		cell.Sw = &grids.Cell_LatLng{
+			Lng: float32(ext.MinX()),
+			Lat: float32(ext.MinY()),
+		}
+		cell.Ne = &grids.Cell_LatLng{
+			Lng: float32(ext.MaxX()),
+			Lat: float32(ext.MaxY()),
+		}
+		// the footprint is the bounds
+		cell.Geometry = nil
+		latlen, lnglen := grids.CalculateSecLengths(float64(cell.Ne.Lat))
+		cell.Len = &grids.Cell_LatLng{Lat: float32(latlen), Lng: float32(lnglen)}
+		cell.SwDms = nil
+		cell.NeDms = nil
+		cell.Init()
+
+		h := sha1.New()
+		fmt.Fprintf(h, "%v %v %v", bounds, srid, time.Now())
+		cell.MetaData["filename"] = fmt.Sprintf("%x", h.Sum(nil))
+		return cell, nil
+	}
+	return nil, grids.ErrNotFound
+}
+
+// CellForLatLng returns a grid cell object that matches the closest grid cell.
+func (p *Provider) CellForLatLng(lat, lng float64, srid uint) (*grids.Cell, error) {
+	lat, lng, err := grids.LatLngForSRID(lng, lat, srid)
+	if err != nil {
+		return nil, err
+	}
+	pt := [2]float64{lng, lat}
+	for i := range p.entries {
+		if p.entries[i].contains(pt) {
+			return p.entries[i].copyCell(), nil
+		}
+	}
+	return nil, grids.ErrNotFound
+}
+
+// CellForMDGID returns an grid cell object for the given mdgid
+func (p *Provider) CellForMDGID(mdgid *grids.MDGID) (*grids.Cell, error) {
+	if mdgid == nil {
+		return nil, grids.ErrNotFound
+	}
+	i, ok := p.mdgids[mdgid.Id]
+	if !ok {
+		return nil, grids.ErrNotFound
+	}
+	return p.entries[i].copyCell(), nil
+}
+
+// CellsForBounds returns an iterator of all the grid cells that intersect the given bounds
+func (p *Provider) CellsForBounds(bounds geom.Extent, srid uint) (grids.CellIterator, error) {
+	swlat, swlng, err := grids.LatLngForSRID(bounds[0], bounds[1], srid)
+	if err != nil {
+		return nil, err
+	}
+	nelat, nelng, err := grids.LatLngForSRID(bounds[2], bounds[3], srid)
+	if err != nil {
+		return nil, err
+	}
+	ext := geom.NewExtent([2]float64{swlng, swlat}, [2]float64{nelng, nelat})
+
+	var cells []*grids.Cell
+	for i := range p.entries {
+		if _, ok := p.entries[i].extent.Intersect(ext); !ok {
+			continue
+		}
+		cells = append(cells, p.entries[i].copyCell())
+	}
+	return grids.NewSliceIterator(cells...), nil
+}
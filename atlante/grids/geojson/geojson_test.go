@@ -0,0 +1,200 @@
+package geojson
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/mbgl/bounds"
+	"github.com/go-spatial/geom"
+)
+
+func loadTestProvider(t *testing.T) *Provider {
+	t.Helper()
+	f, err := os.Open("testdata/grid.geojson")
+	if err != nil {
+		t.Fatalf("failed to open testdata: %v", err)
+	}
+	defer f.Close()
+	p, err := New(f, grids.CellSize50K, "", DefaultEditDateFormat)
+	if err != nil {
+		t.Fatalf("failed to load testdata: %v", err)
+	}
+	return p
+}
+
+func TestCellForLatLng(t *testing.T) {
+	type tcase struct {
+		lat, lng float64
+		srid     uint
+		mdgid    string
+		err      error
+	}
+
+	prv := loadTestProvider(t)
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%v,%v@%v", tc.lat, tc.lng, tc.srid), func(t *testing.T) {
+			cell, err := prv.CellForLatLng(tc.lat, tc.lng, tc.srid)
+			if err != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+				return
+			}
+			if tc.err != nil {
+				return
+			}
+			if cell.Mdgid.Id != tc.mdgid {
+				t.Errorf("mdgid, expected %v got %v", tc.mdgid, cell.Mdgid.Id)
+			}
+		}
+	}
+
+	webMercator := func(lat, lng float64) tcase {
+		xy := bounds.ESPG3857.Project([2]float64{lat, lng})
+		return tcase{lat: xy[1], lng: xy[0], srid: 3857}
+	}
+	mercatorCase := webMercator(32.6, -117.1)
+	mercatorCase.mdgid = "V795G25492"
+
+	tests := []tcase{
+		{lat: 32.6, lng: -117.1, srid: 4326, mdgid: "V795G25492"},
+		{lat: 32.6, lng: -116.8, srid: 4326, mdgid: "V795G25493"},
+		// inside the extent, but outside the footprint of the second feature
+		{lat: 32.7, lng: -116.8, srid: 4326, err: grids.ErrNotFound},
+		{lat: 10.0, lng: 10.0, srid: 4326, err: grids.ErrNotFound},
+		{lat: 32.6, lng: -117.1, srid: 2000, err: grids.ErrUnsupportedSRID(2000)},
+		mercatorCase,
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestCellForMDGID(t *testing.T) {
+	type tcase struct {
+		mdgid    string
+		sheet    string
+		editedBy string
+		sw, ne   [2]float32
+		metadata map[string]string
+		// number of points in the outer ring of the footprint
+		footprint int
+		err       error
+	}
+
+	prv := loadTestProvider(t)
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.mdgid, func(t *testing.T) {
+			cell, err := prv.CellForMDGID(grids.NewMDGID(tc.mdgid))
+			if err != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+				return
+			}
+			if tc.err != nil {
+				return
+			}
+			if cell.Sheet != tc.sheet {
+				t.Errorf("sheet, expected %v got %v", tc.sheet, cell.Sheet)
+			}
+			if cell.GetEdited().GetBy() != tc.editedBy {
+				t.Errorf("edited by, expected %v got %v", tc.editedBy, cell.GetEdited().GetBy())
+			}
+			if sw := [2]float32{cell.Sw.Lat, cell.Sw.Lng}; sw != tc.sw {
+				t.Errorf("sw, expected %v got %v", tc.sw, sw)
+			}
+			if ne := [2]float32{cell.Ne.Lat, cell.Ne.Lng}; ne != tc.ne {
+				t.Errorf("ne, expected %v got %v", tc.ne, ne)
+			}
+			if len(cell.MetaData) != len(tc.metadata) {
+				t.Errorf("metadata len, expected %v got %v", len(tc.metadata), len(cell.MetaData))
+			}
+			for k, v := range tc.metadata {
+				if cell.MetaData[k] != v {
+					t.Errorf("metadata[%v], expected %v got %v", k, v, cell.MetaData[k])
+				}
+			}
+			if cell.SwDms.GetLat() == "" || cell.NeDms.GetLng() == "" {
+				t.Errorf("dms, expected values got %v %v", cell.SwDms, cell.NeDms)
+			}
+			footprint, err := cell.Footprint()
+			if err != nil {
+				t.Fatalf("footprint error, expected nil got %v", err)
+			}
+			plyg, ok := footprint.(geom.Polygoner)
+			if !ok {
+				t.Fatalf("footprint, expected polygon got %T", footprint)
+			}
+			if got := len(plyg.LinearRings()[0]); got != tc.footprint {
+				t.Errorf("footprint points, expected %v got %v", tc.footprint, got)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			mdgid:     "V795G25492",
+			sheet:     "2242I",
+			editedBy:  "gdey",
+			sw:        [2]float32{32.5, -117.25},
+			ne:        [2]float32{32.75, -117.0},
+			metadata:  map[string]string{"edition": "2"},
+			footprint: 4,
+		},
+		{
+			mdgid: "V795G25493",
+			sheet: "2242II",
+			sw:    [2]float32{32.5, -117.0},
+			ne:    [2]float32{32.75, -116.75},
+			// L-shaped
+			footprint: 6,
+		},
+		{
+			mdgid: "V795G00000",
+			err:   grids.ErrNotFound,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestCellsForBounds(t *testing.T) {
+	type tcase struct {
+		bounds geom.Extent
+		mdgids []string
+	}
+
+	prv := loadTestProvider(t)
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%v", tc.bounds), func(t *testing.T) {
+			iter, err := prv.CellsForBounds(tc.bounds, 4326)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			cells, err := grids.AllCells(iter)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(cells) != len(tc.mdgids) {
+				t.Fatalf("len, expected %v got %v", len(tc.mdgids), len(cells))
+			}
+			for i := range cells {
+				if cells[i].Mdgid.Id != tc.mdgids[i] {
+					t.Errorf("mdgid %v, expected %v got %v", i, tc.mdgids[i], cells[i].Mdgid.Id)
+				}
+			}
+		}
+	}
+
+	tests := []tcase{
+		{bounds: geom.Extent{-117.2, 32.55, -116.9, 32.6}, mdgids: []string{"V795G25492", "V795G25493"}},
+		{bounds: geom.Extent{-117.2, 32.55, -117.1, 32.6}, mdgids: []string{"V795G25492"}},
+		{bounds: geom.Extent{10, 10, 11, 11}, mdgids: nil},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
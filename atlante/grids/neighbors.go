@@ -0,0 +1,238 @@
+package grids
+
+import (
+	"math"
+
+	"github.com/go-spatial/tegola"
+)
+
+// Direction is the direction of a neighboring cell
+type Direction uint8
+
+const (
+	// North of the cell
+	North Direction = iota
+	// NorthEast of the cell
+	NorthEast
+	// East of the cell
+	East
+	// SouthEast of the cell
+	SouthEast
+	// South of the cell
+	South
+	// SouthWest of the cell
+	SouthWest
+	// West of the cell
+	West
+	// NorthWest of the cell
+	NorthWest
+)
+
+// Directions is the list of all the directions, clockwise starting at North
+var Directions = [...]Direction{North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest}
+
+func (d Direction) String() string {
+	switch d {
+	case North:
+		return "N"
+	case NorthEast:
+		return "NE"
+	case East:
+		return "E"
+	case SouthEast:
+		return "SE"
+	case South:
+		return "S"
+	case SouthWest:
+		return "SW"
+	case West:
+		return "W"
+	case NorthWest:
+		return "NW"
+	default:
+		return ""
+	}
+}
+
+// Offset returns the unit lng (x), lat (y) offset for the direction
+func (d Direction) Offset() (x, y float64) {
+	switch d {
+	case North:
+		return 0, 1
+	case NorthEast:
+		return 1, 1
+	case East:
+		return 1, 0
+	case SouthEast:
+		return 1, -1
+	case South:
+		return 0, -1
+	case SouthWest:
+		return -1, -1
+	case West:
+		return -1, 0
+	case NorthWest:
+		return -1, 1
+	default:
+		return 0, 0
+	}
+}
+
+// Neighbor is a cell adjoining another cell
+type Neighbor struct {
+	Direction Direction
+	Cell      *Cell
+}
+
+// MDGID returns the mdgid of the neighboring cell
+func (n *Neighbor) MDGID() string {
+	if n == nil {
+		return ""
+	}
+	return n.Cell.GetMdgid().AsString()
+}
+
+// Sheet returns the sheet name of the neighboring cell
+func (n *Neighbor) Sheet() string {
+	if n == nil {
+		return ""
+	}
+	return n.Cell.GetSheet()
+}
+
+// SheetNumber returns the sheet number of the neighboring cell
+func (n *Neighbor) SheetNumber() string {
+	if n == nil {
+		return ""
+	}
+	return n.Cell.SheetNumber()
+}
+
+// Neighbors are the cells adjoining a cell. A direction will be nil if
+// there isn't a cell in that direction. In a template:
+//
+//	{{ with .Neighbors.N }}{{ .Sheet }}{{ end }}
+type Neighbors struct {
+	N, NE, E, SE, S, SW, W, NW *Neighbor
+}
+
+// For returns the neighbor in the given direction
+func (ns *Neighbors) For(d Direction) *Neighbor {
+	if ns == nil {
+		return nil
+	}
+	switch d {
+	case North:
+		return ns.N
+	case NorthEast:
+		return ns.NE
+	case East:
+		return ns.E
+	case SouthEast:
+		return ns.SE
+	case South:
+		return ns.S
+	case SouthWest:
+		return ns.SW
+	case West:
+		return ns.W
+	case NorthWest:
+		return ns.NW
+	default:
+		return nil
+	}
+}
+
+// Set sets the neighbor in the given direction
+func (ns *Neighbors) Set(d Direction, cell *Cell) {
+	var n *Neighbor
+	if cell != nil {
+		n = &Neighbor{Direction: d, Cell: cell}
+	}
+	switch d {
+	case North:
+		ns.N = n
+	case NorthEast:
+		ns.NE = n
+	case East:
+		ns.E = n
+	case SouthEast:
+		ns.SE = n
+	case South:
+		ns.S = n
+	case SouthWest:
+		ns.SW = n
+	case West:
+		ns.W = n
+	case NorthWest:
+		ns.NW = n
+	}
+}
+
+// All returns the neighbors that exist, clockwise starting at North
+func (ns *Neighbors) All() (all []*Neighbor) {
+	for _, d := range Directions {
+		if n := ns.For(d); n != nil {
+			all = append(all, n)
+		}
+	}
+	return all
+}
+
+// Neighborer is an optional interface that Providers can implement to
+// return the cells adjoining a cell.
+type Neighborer interface {
+	Neighbors(cell *Cell) (*Neighbors, error)
+}
+
+// NeighborsFor returns the cells adjoining the cell. If the provider does not
+// implement Neighborer, ProbeNeighbors is used.
+func NeighborsFor(prv Provider, cell *Cell) (*Neighbors, error) {
+	if nbr, ok := prv.(Neighborer); ok {
+		return nbr.Neighbors(cell)
+	}
+	return ProbeNeighbors(prv, cell)
+}
+
+// ProbeNeighbors will find the neighbors of a cell by asking the provider
+// for the cell containing a point just past each edge and corner of the cell.
+func ProbeNeighbors(prv Provider, cell *Cell) (*Neighbors, error) {
+	var ns Neighbors
+	if prv == nil || cell == nil {
+		return &ns, nil
+	}
+	sw, ne := cell.SW(), cell.NE()
+	width, height := ne[0]-sw[0], ne[1]-sw[1]
+	// the cell bounds are float32, we need to step past the edge by more
+	// then that precision
+	eps := math.Max(math.Max(width, height)/100, 1e-4)
+	cx, cy := sw[0]+width/2, sw[1]+height/2
+	id := cell.GetMdgid().AsString()
+
+	for _, d := range Directions {
+		dx, dy := d.Offset()
+		lat := cy + dy*(height/2+eps)
+		if lat > 90 || lat < -90 {
+			continue
+		}
+		lng := cx + dx*(width/2+eps)
+		// wrap around the anti-meridian
+		if lng > 180 {
+			lng -= 360
+		} else if lng < -180 {
+			lng += 360
+		}
+		ncell, err := prv.CellForLatLng(lat, lng, tegola.WGS84)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ncell == nil || ncell.GetMdgid().AsString() == id {
+			continue
+		}
+		ns.Set(d, ncell)
+	}
+	return &ns, nil
+}
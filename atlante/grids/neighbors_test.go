@@ -0,0 +1,141 @@
+package grids
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/geom"
+)
+
+// degreeProvider has a cell for each whole degree in rows lat [minLat, maxLat),
+// for each of the lngs (the west edges of the columns). The mdgid of a cell is
+// lat_lng, with lng in [0, 360) as mdgids can not have a '-'.
+type degreeProvider struct {
+	minLat, maxLat int
+	lngs           []int
+}
+
+func (dp degreeProvider) cell(lat, lng int) *Cell {
+	return NewCell(
+		fmt.Sprintf("%d_%d", lat, (lng+360)%360),
+		[2]float64{float64(lat), float64(lng)},
+		[2]float64{float64(lat + 1), float64(lng + 1)},
+		"", "", nil, nil, time.Time{}, "", "", "",
+		[2]string{}, [2]string{}, nil,
+	)
+}
+
+func (dp degreeProvider) CellForBounds(geom.Extent, uint) (*Cell, error) {
+	return nil, ErrNotFound
+}
+
+func (dp degreeProvider) CellForLatLng(lat, lng float64, _ uint) (*Cell, error) {
+	row, col := int(math.Floor(lat)), int(math.Floor(lng))
+	if row < dp.minLat || row >= dp.maxLat {
+		return nil, ErrNotFound
+	}
+	for _, l := range dp.lngs {
+		if l == col {
+			return dp.cell(row, col), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (dp degreeProvider) CellForMDGID(*MDGID) (*Cell, error) { return nil, ErrNotFound }
+
+func (dp degreeProvider) CellSize() CellSize { return CellSize50K }
+
+func TestProbeNeighbors(t *testing.T) {
+	type tcase struct {
+		name     string
+		prv      degreeProvider
+		lat, lng int
+		// expected are the mdgids of the neighbors by direction
+		expected map[Direction]string
+	}
+
+	grid := degreeProvider{minLat: 10, maxLat: 13, lngs: []int{20, 21, 22}}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			ns, err := ProbeNeighbors(tc.prv, tc.prv.cell(tc.lat, tc.lng))
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			for _, d := range Directions {
+				got := ns.For(d).MDGID()
+				if got != tc.expected[d] {
+					t.Errorf("%v, expected %q got %q", d, tc.expected[d], got)
+				}
+			}
+			if len(ns.All()) != len(tc.expected) {
+				t.Errorf("all, expected %v neighbors got %v", len(tc.expected), len(ns.All()))
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name: "center",
+			prv:  grid,
+			lat:  11, lng: 21,
+			expected: map[Direction]string{
+				North: "12_21", NorthEast: "12_22", East: "11_22", SouthEast: "10_22",
+				South: "10_21", SouthWest: "10_20", West: "11_20", NorthWest: "12_20",
+			},
+		},
+		{
+			name: "south west corner",
+			prv:  grid,
+			lat:  10, lng: 20,
+			expected: map[Direction]string{
+				North: "11_20", NorthEast: "11_21", East: "10_21",
+			},
+		},
+		{
+			name: "north east corner",
+			prv:  grid,
+			lat:  12, lng: 22,
+			expected: map[Direction]string{
+				South: "11_22", SouthWest: "11_21", West: "12_21",
+			},
+		},
+		{
+			name: "north edge",
+			prv:  grid,
+			lat:  12, lng: 21,
+			expected: map[Direction]string{
+				East: "12_22", SouthEast: "11_22", South: "11_21", SouthWest: "11_20", West: "12_20",
+			},
+		},
+		{
+			name: "west edge",
+			prv:  grid,
+			lat:  11, lng: 20,
+			expected: map[Direction]string{
+				North: "12_20", NorthEast: "12_21", East: "11_21", SouthEast: "10_21", South: "10_20",
+			},
+		},
+		{
+			name: "pole and anti-meridian",
+			prv:  degreeProvider{minLat: 88, maxLat: 90, lngs: []int{178, 179, -180}},
+			lat:  89, lng: 179,
+			expected: map[Direction]string{
+				East: "89_180", SouthEast: "88_180", South: "88_179", SouthWest: "88_178", West: "89_178",
+			},
+		},
+		{
+			name:     "single cell",
+			prv:      degreeProvider{minLat: 10, maxLat: 11, lngs: []int{20}},
+			lat:      10,
+			lng:      20,
+			expected: map[Direction]string{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/go-spatial/geom/encoding/wkb"
 
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/atlante/atlante/grids"
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/tegola"
@@ -35,6 +36,7 @@ type Provider struct {
 	queryLngLat      string
 	queryMDGID       string
 	queryBounds      string
+	queryCells       string
 }
 
 const (
@@ -93,6 +95,8 @@ const (
 	ConfigKeyQueryLngLat = "query_lnglat"
 	// ConfigKeyQueryBounds is the sql for getting grid values from a bounds value
 	ConfigKeyQueryBounds = "query_bounds"
+	// ConfigKeyQueryCells is the sql for getting all the grid values that intersect a bounds value
+	ConfigKeyQueryCells = "query_cells"
 
 	// SQLGeometryField is the expected sql field name for the geometry
 	SQLGeometryField = "geometry"
@@ -132,6 +136,10 @@ const (
 
 func init() {
 	grids.Register(Name, NewGridProvider, Cleanup)
+	grids.RegisterSchema(Name, config.Schema{
+		Required: []string{ConfigKeyHost, ConfigKeyDB, ConfigKeyUser, ConfigKeyPassword, ConfigKeyScale},
+		Optional: []string{ConfigKeyPort, ConfigKeySSLMode, ConfigKeySSLKey, ConfigKeySSLCert, ConfigKeySSLRootCert, ConfigKeyMaxConn, ConfigKeySRID, ConfigKeyEditBy, ConfigKeyEditDateFormat, ConfigKeyQueryMDGID, ConfigKeyQueryLngLat, ConfigKeyQueryBounds, ConfigKeyQueryCells},
+	})
 }
 
 // NewGridProvider returns a grid provider based on the postgis database
@@ -213,6 +221,8 @@ func NewGridProvider(config grids.ProviderConfig) (grids.Provider, error) {
 	queryMDGID, _ = config.String(ConfigKeyQueryMDGID, &queryMDGID)
 	var queryBounds string
 	queryBounds, _ = config.String(ConfigKeyQueryBounds, &queryBounds)
+	var queryCells string
+	queryCells, _ = config.String(ConfigKeyQueryCells, &queryCells)
 
 	connConfig := pgx.ConnConfig{
 		Host:     host,
@@ -244,6 +254,7 @@ func NewGridProvider(config grids.ProviderConfig) (grids.Provider, error) {
 		queryLngLat:      queryLngLat,
 		queryMDGID:       queryMDGID,
 		queryBounds:      queryBounds,
+		queryCells:       queryCells,
 	}
 	if p.pool, err = pgx.NewConnPool(p.config); err != nil {
 		return nil, fmt.Errorf("failed while creating connection pool: %v", err)
@@ -251,7 +262,7 @@ func NewGridProvider(config grids.ProviderConfig) (grids.Provider, error) {
 
 	// track the provider so we can clean it up later
 	pLock.Lock()
-	providers = append(providers, p)
+	providers = append(providers, &p)
 	pLock.Unlock()
 	return &p, nil
 }
@@ -377,6 +388,8 @@ func (p *Provider) CellForBounds(bounds geom.Extent, srid uint) (*grids.Cell, er
 		Lng: float32(bounds[2]),
 		Lat: float32(bounds[3]),
 	}
+	// the footprint is the bounds
+	cell.Geometry = nil
 	latlen, lnglen := grids.CalculateSecLengths(float64(cell.Ne.Lat))
 	cell.SwDms = nil
 	cell.NeDms = nil
@@ -416,7 +429,8 @@ SELECT
 
   country,
   last_edite AS edited_by,
-  last_edi_1 AS edited_at
+  last_edi_1 AS edited_at,
+  ST_AsBinary(wkb_geometry) AS geometry
 FROM
   grids.grid50K
 WHERE
@@ -465,7 +479,8 @@ SELECT
 
   country,
   last_edite AS edited_by,
-  last_edi_1 AS edited_at
+  last_edi_1 AS edited_at,
+  ST_AsBinary(wkb_geometry) AS geometry
 FROM
   grids.grid50K
 WHERE
@@ -483,6 +498,99 @@ LIMIT 1;
 	return p.cellFromRow(row)
 }
 
+// CellsForBounds returns an iterator of all the grid cells that intersect the given bounds
+func (p *Provider) CellsForBounds(bounds geom.Extent, srid uint) (grids.CellIterator, error) {
+	const selectQuery = `
+SELECT
+  mdg_id,
+  sheet,
+  series,
+  nrn,
+
+  swlat_dms,
+  swlon_dms AS swlng_dms,
+  nelat_dms,
+  nelon_dms AS nelng_dms,
+
+  swlat,
+  swlon AS swlng,
+  nelat,
+  nelon AS nelng,
+
+  country,
+  last_edite AS edited_by,
+  last_edi_1 AS edited_at,
+  ST_AsBinary(wkb_geometry) AS geometry
+FROM
+  grids.grid50K
+WHERE
+  ST_Intersects(
+    wkb_geometry,
+    ST_Transform(
+      ST_SetSRID(
+        ST_MakeEnvelope($1,$2,$3,$4),
+        $5
+      ),
+      4326
+    )
+  )
+ORDER BY mdg_id;
+`
+	query := selectQuery
+	if p.queryCells != "" {
+		query = p.queryCells
+	}
+
+	rows, err := p.pool.Query(query, bounds[0], bounds[1], bounds[2], bounds[3], srid)
+	if err != nil {
+		return nil, err
+	}
+	return &cellIterator{
+		provider: p,
+		rows:     rows,
+	}, nil
+}
+
+// cellIterator walks through the rows returned by a query, converting them into cells
+type cellIterator struct {
+	provider *Provider
+	rows     *pgx.Rows
+	cell     *grids.Cell
+	err      error
+}
+
+// Next implements the grids.CellIterator interface
+func (ci *cellIterator) Next() bool {
+	ci.cell = nil
+	if ci.err != nil || !ci.rows.Next() {
+		return false
+	}
+	vals, err := ci.rows.Values()
+	if err != nil {
+		ci.err = err
+		return false
+	}
+	ci.cell, ci.err = ci.provider.cellFromValues(ci.rows.FieldDescriptions(), vals)
+	return ci.err == nil
+}
+
+// Cell implements the grids.CellIterator interface
+func (ci *cellIterator) Cell() *grids.Cell { return ci.cell }
+
+// Err implements the grids.CellIterator interface
+func (ci *cellIterator) Err() error {
+	if ci.err != nil {
+		return ci.err
+	}
+	return ci.rows.Err()
+}
+
+// Close implements the grids.CellIterator interface
+func (ci *cellIterator) Close() error {
+	ci.rows.Close()
+	return nil
+}
+
 type assignTo interface {
 	AssignTo(interface{}) error
 }
@@ -544,10 +652,17 @@ func (p *Provider) cellFromRows(rows *pgx.Rows) (*grids.Cell, error) {
 	if err != nil {
 		return nil, err
 	}
+	return p.cellFromValues(fdescs, vals)
+}
+
+// cellFromValues parse the grid attributes of a row into a grids.Cell struct
+func (p *Provider) cellFromValues(fdescs []pgx.FieldDescription, vals []interface{}) (*grids.Cell, error) {
 	var (
-		ok bool
+		err error
+		ok  bool
 
 		geomExtent *geom.Extent
+		geomBytes  []byte
 		mdgid      string
 		sheet      string
 		series     string
@@ -573,8 +688,7 @@ func (p *Provider) cellFromRows(rows *pgx.Rows) (*grids.Cell, error) {
 		switch fdescs[i].Name {
 
 		case SQLGeometryField:
-			geomBytes, ok := vals[i].([]byte)
-			if !ok {
+			if geomBytes, ok = vals[i].([]byte); !ok {
 				return nil, fmt.Errorf("error unabled to convert field %v (%v) into bytes to decode into geometry", fdescs[i].Name, i)
 			}
 			geometry, err := wkb.DecodeBytes(geomBytes)
@@ -713,7 +827,7 @@ func (p *Provider) cellFromRows(rows *pgx.Rows) (*grids.Cell, error) {
 
 	metadata := make(map[string]string)
 
-	return grids.NewCell(
+	cell := grids.NewCell(
 		mdgid,                                 // mdgid
 		[2]float64{*swlat, *swlng},            // sw
 		[2]float64{*nelat, *nelng},            // ne
@@ -728,28 +842,41 @@ func (p *Provider) cellFromRows(rows *pgx.Rows) (*grids.Cell, error) {
 		[2]string{swlatDMS, swlngDMS},         // sw dms
 		[2]string{nelatDMS, nelngDMS},         // ne dms
 		metadata,                              // metadata
-	), nil
+	)
+	// the geometry is the footprint of the cell; keep it so clipped sheets
+	// are not treated as boxes
+	if geomExtent != nil {
+		cell.Geometry = geomBytes
+	}
+	return cell, nil
 
 }
 
-// Close will close the provider's database connection
-func (p *Provider) Close() { p.pool.Close() }
+// Close will close the provider's database connection, and stop tracking the provider
+func (p *Provider) Close() {
+	pLock.Lock()
+	for i := range providers {
+		if providers[i] == p {
+			providers = append(providers[:i], providers[i+1:]...)
+			break
+		}
+	}
+	pLock.Unlock()
+	p.pool.Close()
+}
 
 var pLock sync.RWMutex
 
-// reference to all instantiated providers
-var providers []Provider
+// reference to all instantiated providers that have not been closed
+var providers []*Provider
 
 // Cleanup will close all database connections and destroy all prviously instantiated Provider instatnces
 func Cleanup() {
-	if len(providers) == 0 {
-		// Nothing to do
-		return
-	}
 	pLock.Lock()
-	for i := range providers {
-		providers[i].Close()
-	}
-	providers = make([]Provider, 0)
+	closing := providers
+	providers = nil
 	pLock.Unlock()
+	for i := range closing {
+		closing[i].pool.Close()
+	}
 }
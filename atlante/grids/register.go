@@ -4,6 +4,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/tegola/dict"
 	"github.com/prometheus/common/log"
 )
@@ -35,6 +36,7 @@ type funcs struct {
 
 var providersLock sync.RWMutex
 var providers map[string]funcs
+var schemas map[string]config.Schema
 
 // Register is called by the init functions of the provider
 func Register(providerType string, init InitFunc, cleanup CleanupFunc) error {
@@ -55,6 +57,36 @@ func Register(providerType string, init InitFunc, cleanup CleanupFunc) error {
 	return nil
 }
 
+// RegisterSchema is called by the init functions of the providers to describe the config keys
+// of the grid provider; it is used to validate the config.
+func RegisterSchema(providerType string, schema config.Schema) {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	if schemas == nil {
+		schemas = make(map[string]config.Schema)
+	}
+	schemas[providerType] = schema
+}
+
+// SchemaFor returns the config schema of the grid provider type, nil if the type did not register
+// one, and weather the type is registered
+func SchemaFor(providerType string) (*config.Schema, bool) {
+	providersLock.RLock()
+	defer providersLock.RUnlock()
+	if _, ok := providers[providerType]; !ok {
+		return nil, false
+	}
+	schema, ok := schemas[providerType]
+	if !ok {
+		return nil, true
+	}
+	return &schema, true
+}
+
+func init() {
+	config.RegisterSchemas(config.SectionProviders, SchemaFor)
+}
+
 // Unregister will remove a provider and call it's clean up function.
 func Unregister(providerType string) {
 
@@ -0,0 +1,50 @@
+package subdivide
+
+import (
+	"fmt"
+
+	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante/grids"
+)
+
+const (
+	// ErrBlankSubprovider is returned when the base provider name is blank
+	ErrBlankSubprovider = errors.String("error, base provider name is blank")
+
+	// ErrNilBaseProvider is returned when the base provider is nil
+	ErrNilBaseProvider = errors.String("error, base provider is nil")
+)
+
+// ErrInvalidPart is returned when the part number is out of range for the grid
+type ErrInvalidPart uint32
+
+func (err ErrInvalidPart) Error() string {
+	return fmt.Sprintf("error, invalid part number (%d)", uint32(err))
+}
+
+// ErrUnknownScheme is returned when the numbering scheme is not known
+type ErrUnknownScheme string
+
+func (err ErrUnknownScheme) Error() string {
+	return fmt.Sprintf("error, unknown numbering scheme (%v), expected one of %v", string(err), schemeNames)
+}
+
+// ErrInvalidDivisions is returned when the number of rows or columns is zero
+type ErrInvalidDivisions struct {
+	Rows uint
+	Cols uint
+}
+
+func (err ErrInvalidDivisions) Error() string {
+	return fmt.Sprintf("error, invalid divisions rows: %v cols: %v; %v and %v or %v must be provided", err.Rows, err.Cols, ConfigKeyRows, ConfigKeyCols, ConfigKeyScale)
+}
+
+// ErrIncompatibleScale is returned when the requested scale does not evenly divide the base scale
+type ErrIncompatibleScale struct {
+	Base  grids.CellSize
+	Scale grids.CellSize
+}
+
+func (err ErrIncompatibleScale) Error() string {
+	return fmt.Sprintf("error, scale (%v) does not evenly divide the base provider's scale (%v)", err.Scale, err.Base)
+}
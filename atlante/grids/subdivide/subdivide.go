@@ -0,0 +1,411 @@
+package subdivide
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/common/log"
+
+	"github.com/go-spatial/atlante/atlante/config"
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/geom"
+)
+
+// Type of the grid this provider is providing
+const Type = "subdivide"
+
+const (
+	// ConfigKeyProvider is the config key for the base provider
+	ConfigKeyProvider = "provider"
+	// ConfigKeyRows is the config key for the number of rows to split the base cell into
+	ConfigKeyRows = "rows"
+	// ConfigKeyCols is the config key for the number of columns to split the base cell into
+	ConfigKeyCols = "cols"
+	// ConfigKeyScale is the config key for the target cell size (scale) of the parts
+	ConfigKeyScale = "scale"
+	// ConfigKeyNumbering is the config key for the part numbering scheme
+	ConfigKeyNumbering = "numbering"
+
+	// MetaDataKeyPartLabel is the metadata key the part label is stored under
+	// for schemes that label parts with something other then the part number.
+	MetaDataKeyPartLabel = grids.MetaDataKeyPartLabel
+)
+
+// Scheme is the way the parts of a cell are numbered. Parts are always
+// numbered starting at 1 from the north west corner of the cell.
+type Scheme uint8
+
+const (
+	// RowMajor numbers parts left to right, then top to bottom
+	RowMajor Scheme = iota
+	// Serpentine numbers parts left to right on the first row,
+	// right to left on the next row, and so on.
+	Serpentine
+	// Lettered orders parts as RowMajor, but labels them A, B, C … Z, AA, AB …
+	Lettered
+)
+
+var schemeNames = [...]string{
+	RowMajor:   "row-major",
+	Serpentine: "serpentine",
+	Lettered:   "lettered",
+}
+
+func (s Scheme) String() string {
+	if int(s) >= len(schemeNames) {
+		return fmt.Sprintf("scheme(%d)", uint8(s))
+	}
+	return schemeNames[s]
+}
+
+// ParseScheme returns the scheme for the given name
+func ParseScheme(name string) (Scheme, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i := range schemeNames {
+		if schemeNames[i] == name {
+			return Scheme(i), nil
+		}
+	}
+	return RowMajor, ErrUnknownScheme(name)
+}
+
+func init() {
+	grids.Register(Type, NewGridProvider, nil)
+	grids.RegisterSchema(Type, config.Schema{
+		Required: []string{ConfigKeyProvider},
+		Optional: []string{ConfigKeyRows, ConfigKeyCols, ConfigKeyScale, ConfigKeyNumbering},
+	})
+}
+
+// Provider splits each cell of a base provider into a rows × cols grid of parts
+// addressed via the MDGID part.
+type Provider struct {
+	Provider grids.Provider
+	Rows     uint
+	Cols     uint
+	Scheme   Scheme
+	Size     grids.CellSize
+}
+
+// New returns a new subdividing provider. If size is zero the cell size will
+// be derived from the base provider's cell size and the number of rows.
+func New(base grids.Provider, rows, cols uint, scheme Scheme, size grids.CellSize) (*Provider, error) {
+	if base == nil {
+		return nil, ErrNilBaseProvider
+	}
+	if rows == 0 || cols == 0 {
+		return nil, ErrInvalidDivisions{Rows: rows, Cols: cols}
+	}
+	if int(scheme) >= len(schemeNames) {
+		return nil, ErrUnknownScheme(scheme.String())
+	}
+	if size == 0 {
+		size = base.CellSize() / grids.CellSize(rows)
+	}
+	return &Provider{
+		Provider: base,
+		Rows:     rows,
+		Cols:     cols,
+		Scheme:   scheme,
+		Size:     size,
+	}, nil
+}
+
+// NewGridProvider returns a grid provider based off the another provider
+// creating subdivisions of those grids.
+func NewGridProvider(config grids.ProviderConfig) (grids.Provider, error) {
+	subp, err := config.String(ConfigKeyProvider, nil)
+	if err != nil {
+		return nil, err
+	}
+	if subp == "" {
+		return nil, ErrBlankSubprovider
+	}
+
+	log.Infof("getting provider(%v) from config.", subp)
+	prv, err := config.NameGridProvider(subp)
+	if err != nil {
+		log.Warnf("got error getting provider: %v", err)
+		return nil, err
+	}
+
+	var scale uint
+	if scale, err = config.Uint(ConfigKeyScale, &scale); err != nil {
+		return nil, err
+	}
+
+	// if rows or cols are not given, they are derived from the ratio of the
+	// base scale to the requested scale
+	var divs uint
+	if scale != 0 {
+		base := uint(prv.CellSize())
+		if base < scale || base%scale != 0 {
+			return nil, ErrIncompatibleScale{Base: prv.CellSize(), Scale: grids.CellSize(scale)}
+		}
+		divs = base / scale
+	}
+
+	rows := divs
+	if rows, err = config.Uint(ConfigKeyRows, &rows); err != nil {
+		return nil, err
+	}
+	cols := rows
+	if cols, err = config.Uint(ConfigKeyCols, &cols); err != nil {
+		return nil, err
+	}
+
+	numbering := RowMajor.String()
+	if numbering, err = config.String(ConfigKeyNumbering, &numbering); err != nil {
+		return nil, err
+	}
+	scheme, err := ParseScheme(numbering)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(prv, rows, cols, scheme, grids.CellSize(scale))
+}
+
+// CellSize returns the grid cell size
+func (p *Provider) CellSize() grids.CellSize { return p.Size }
+
+// Parts returns the number of parts each base cell is split into
+func (p *Provider) Parts() uint { return p.Rows * p.Cols }
+
+// Part returns the part number for the given row and column. Row 0 is the
+// northern most row, and column 0 is the western most column.
+func (p *Provider) Part(row, col uint) uint32 {
+	if p.Scheme == Serpentine && row%2 == 1 {
+		col = p.Cols - 1 - col
+	}
+	return uint32(row*p.Cols + col + 1)
+}
+
+// RowCol returns the row and column of the given part number
+func (p *Provider) RowCol(part uint32) (row, col uint, err error) {
+	if part < 1 || uint(part) > p.Parts() {
+		return 0, 0, ErrInvalidPart(part)
+	}
+	idx := uint(part - 1)
+	row, col = idx/p.Cols, idx%p.Cols
+	if p.Scheme == Serpentine && row%2 == 1 {
+		col = p.Cols - 1 - col
+	}
+	return row, col, nil
+}
+
+// Label returns the human readable label of the given part
+func (p *Provider) Label(part uint32) string {
+	if p.Scheme != Lettered {
+		return fmt.Sprintf("%d", part)
+	}
+	return letters(part)
+}
+
+// letters returns the spreadsheet style lettering for n; 1 is A, 27 is AA
+func letters(n uint32) string {
+	var buf []byte
+	for n > 0 {
+		n--
+		buf = append([]byte{byte('A' + n%26)}, buf...)
+		n /= 26
+	}
+	return string(buf)
+}
+
+// partFromLabel is the inverse of letters
+func partFromLabel(lbl string) (uint32, bool) {
+	if lbl == "" {
+		return 0, false
+	}
+	var n uint32
+	for _, r := range strings.ToUpper(lbl) {
+		if r < 'A' || r > 'Z' {
+			return 0, false
+		}
+		n = n*26 + uint32(r-'A') + 1
+	}
+	return n, true
+}
+
+// CellForBounds returns a grid cell for the given bounds
+func (p *Provider) CellForBounds(bounds geom.Extent, srid uint) (*grids.Cell, error) {
+	return p.Provider.CellForBounds(bounds, srid)
+}
+
+// CellForLatLng returns a grid cell for the given Lat Lng
+func (p *Provider) CellForLatLng(lat, lng float64, srid uint) (*grids.Cell, error) {
+	plat, plng, err := grids.LatLngForSRID(lng, lat, srid)
+	if err != nil {
+		return nil, err
+	}
+	grd, err := p.Provider.CellForLatLng(lat, lng, srid)
+	if err != nil {
+		return nil, err
+	}
+
+	sw, ne := grd.GetSw(), grd.GetNe()
+	height := float64(ne.GetLat()-sw.GetLat()) / float64(p.Rows)
+	width := float64(ne.GetLng()-sw.GetLng()) / float64(p.Cols)
+
+	row := clamp((float64(ne.GetLat())-plat)/height, p.Rows)
+	col := clamp((plng-float64(sw.GetLng()))/width, p.Cols)
+
+	return p.adjustCell(grd, p.Part(row, col))
+}
+
+func clamp(v float64, max uint) uint {
+	if v < 0 || math.IsNaN(v) {
+		return 0
+	}
+	if uint(v) >= max {
+		return max - 1
+	}
+	return uint(v)
+}
+
+// CellForMDGID returns a grid cell for the given mdgid. For the lettered scheme the
+// part can also be given as a letter suffix on the id, i.e. V795G25492-C
+func (p *Provider) CellForMDGID(mdgid *grids.MDGID) (*grids.Cell, error) {
+	if mdgid == nil {
+		return nil, grids.ErrNotFound
+	}
+	id, part := mdgid.Id, mdgid.Part
+	if part == 0 && p.Scheme == Lettered {
+		if idx := strings.LastIndexAny(id, "-:"); idx != -1 {
+			if n, ok := partFromLabel(id[idx+1:]); ok {
+				id, part = id[:idx], n
+			}
+		}
+	}
+	if part == 0 {
+		part = 1
+	}
+	log.Infof("Getting mdgid %v part %v", id, part)
+	grd, err := p.Provider.CellForMDGID(&grids.MDGID{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return p.adjustCell(grd, part)
+}
+
+// adjustCell changes the bounds (and everything derived from them) of the base cell to the given part.
+func (p *Provider) adjustCell(grd *grids.Cell, part uint32) (*grids.Cell, error) {
+	row, col, err := p.RowCol(part)
+	if err != nil {
+		return nil, err
+	}
+	sw, ne := grd.GetSw(), grd.GetNe()
+	if sw == nil || ne == nil {
+		return nil, fmt.Errorf("error base cell (%v) is missing bounds", grd.GetMdgid().AsString())
+	}
+	height := float64(ne.GetLat()-sw.GetLat()) / float64(p.Rows)
+	width := float64(ne.GetLng()-sw.GetLng()) / float64(p.Cols)
+
+	n := float64(ne.GetLat()) - float64(row)*height
+	w := float64(sw.GetLng()) + float64(col)*width
+
+	cell := proto.Clone(grd).(*grids.Cell)
+	if cell.Mdgid == nil {
+		cell.Mdgid = &grids.MDGID{}
+	}
+	cell.Mdgid.Part = part
+	cell.Ne = &grids.Cell_LatLng{Lat: float32(n), Lng: float32(w + width)}
+	cell.Sw = &grids.Cell_LatLng{Lat: float32(n - height), Lng: float32(w)}
+	// These are all derived from the bounds; Init will recalculate them.
+	cell.NeDms = nil
+	cell.SwDms = nil
+	cell.Utm = nil
+	cell.Len = nil
+	// the footprint is of the base cell, not the part
+	cell.Geometry = nil
+	cell.Init()
+
+	if cell.MetaData == nil {
+		cell.MetaData = make(map[string]string)
+	}
+	if p.Scheme == Lettered {
+		cell.MetaData[MetaDataKeyPartLabel] = p.Label(part)
+	} else {
+		delete(cell.MetaData, MetaDataKeyPartLabel)
+	}
+	return cell, nil
+}
+
+// CellsForBounds returns an iterator of all the parts that intersect the bounds. The base
+// provider must implement the grids.Enumerator interface.
+func (p *Provider) CellsForBounds(bounds geom.Extent, srid uint) (grids.CellIterator, error) {
+	swlat, swlng, err := grids.LatLngForSRID(bounds[0], bounds[1], srid)
+	if err != nil {
+		return nil, err
+	}
+	nelat, nelng, err := grids.LatLngForSRID(bounds[2], bounds[3], srid)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := grids.CellsForBounds(p.Provider, bounds, srid)
+	if err != nil {
+		return nil, err
+	}
+	return &partIterator{
+		provider: p,
+		base:     iter,
+		extent:   geom.NewExtent([2]float64{swlng, swlat}, [2]float64{nelng, nelat}),
+	}, nil
+}
+
+// partIterator walks through the parts of each cell of the base iterator
+// that intersect the extent
+type partIterator struct {
+	provider *Provider
+	base     grids.CellIterator
+	extent   *geom.Extent
+	parts    []*grids.Cell
+	cell     *grids.Cell
+	err      error
+}
+
+// Next implements the grids.CellIterator interface
+func (pi *partIterator) Next() bool {
+	pi.cell = nil
+	for len(pi.parts) == 0 {
+		if pi.err != nil || !pi.base.Next() {
+			return false
+		}
+		pi.parts, pi.err = pi.provider.partsFor(pi.base.Cell(), pi.extent)
+	}
+	pi.cell, pi.parts = pi.parts[0], pi.parts[1:]
+	return true
+}
+
+// Cell implements the grids.CellIterator interface
+func (pi *partIterator) Cell() *grids.Cell { return pi.cell }
+
+// Err implements the grids.CellIterator interface
+func (pi *partIterator) Err() error {
+	if pi.err != nil {
+		return pi.err
+	}
+	return pi.base.Err()
+}
+
+// Close implements the grids.CellIterator interface
+func (pi *partIterator) Close() error { return pi.base.Close() }
+
+// partsFor returns the parts of the base cell, in part order, that intersect the extent
+func (p *Provider) partsFor(grd *grids.Cell, extent *geom.Extent) ([]*grids.Cell, error) {
+	var parts []*grids.Cell
+	for part := uint32(1); uint(part) <= p.Parts(); part++ {
+		cell, err := p.adjustCell(grd, part)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := cell.Hull().Intersect(extent); !ok {
+			continue
+		}
+		parts = append(parts, cell)
+	}
+	return parts, nil
+}
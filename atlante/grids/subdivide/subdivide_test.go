@@ -0,0 +1,256 @@
+package subdivide
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/geom"
+)
+
+// baseProvider always returns the same 50k cell
+type baseProvider struct{}
+
+func (baseProvider) cell() *grids.Cell {
+	return grids.NewCell(
+		"V795G25492",
+		[2]float64{32.5, -117.25},
+		[2]float64{32.75, -117.0},
+		"", "", nil, nil, time.Time{}, "", "2242I", "V795",
+		[2]string{}, [2]string{}, nil,
+	)
+}
+func (b baseProvider) CellForBounds(geom.Extent, uint) (*grids.Cell, error) { return b.cell(), nil }
+func (b baseProvider) CellForLatLng(lat, lng float64, srid uint) (*grids.Cell, error) {
+	return b.cell(), nil
+}
+func (b baseProvider) CellForMDGID(mdgid *grids.MDGID) (*grids.Cell, error) {
+	if mdgid.Id != "V795G25492" || mdgid.Part != 0 {
+		return nil, grids.ErrNotFound
+	}
+	return b.cell(), nil
+}
+func (baseProvider) CellSize() grids.CellSize { return grids.CellSize50K }
+func (b baseProvider) CellsForBounds(geom.Extent, uint) (grids.CellIterator, error) {
+	return grids.NewSliceIterator(b.cell()), nil
+}
+
+func TestPartRowCol(t *testing.T) {
+	type tcase struct {
+		rows, cols uint
+		scheme     Scheme
+		// expected part number for each row, col
+		parts [][]uint32
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%vx%v %v", tc.rows, tc.cols, tc.scheme), func(t *testing.T) {
+			p, err := New(baseProvider{}, tc.rows, tc.cols, tc.scheme, 0)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			for row := range tc.parts {
+				for col, part := range tc.parts[row] {
+					if got := p.Part(uint(row), uint(col)); got != part {
+						t.Errorf("part(%v,%v), expected %v got %v", row, col, part, got)
+					}
+					grow, gcol, err := p.RowCol(part)
+					if err != nil {
+						t.Errorf("rowcol(%v) error, expected nil got %v", part, err)
+						continue
+					}
+					if grow != uint(row) || gcol != uint(col) {
+						t.Errorf("rowcol(%v), expected %v,%v got %v,%v", part, row, col, grow, gcol)
+					}
+				}
+			}
+			if _, _, err := p.RowCol(uint32(p.Parts() + 1)); err == nil {
+				t.Errorf("rowcol(%v) error, expected ErrInvalidPart got nil", p.Parts()+1)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			rows: 2, cols: 3, scheme: RowMajor,
+			parts: [][]uint32{{1, 2, 3}, {4, 5, 6}},
+		},
+		{
+			rows: 3, cols: 3, scheme: Serpentine,
+			parts: [][]uint32{{1, 2, 3}, {6, 5, 4}, {7, 8, 9}},
+		},
+		{
+			rows: 2, cols: 2, scheme: Lettered,
+			parts: [][]uint32{{1, 2}, {3, 4}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestLetters(t *testing.T) {
+	tests := map[uint32]string{1: "A", 2: "B", 26: "Z", 27: "AA", 52: "AZ", 53: "BA", 703: "AAA"}
+	for n, lbl := range tests {
+		if got := letters(n); got != lbl {
+			t.Errorf("letters(%v), expected %v got %v", n, lbl, got)
+		}
+		if got, ok := partFromLabel(lbl); !ok || got != n {
+			t.Errorf("partFromLabel(%v), expected %v got %v", lbl, n, got)
+		}
+	}
+}
+
+func TestCellFor(t *testing.T) {
+	type tcase struct {
+		scheme   Scheme
+		rows     uint
+		lat, lng float64
+		mdgid    string
+		part     uint32
+		sheet    string
+		sw, ne   [2]float32
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%v %v", tc.scheme, tc.mdgid), func(t *testing.T) {
+			p, err := New(baseProvider{}, tc.rows, tc.rows, tc.scheme, 0)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			check := func(t *testing.T, cell *grids.Cell) {
+				if cell.Mdgid.Part != tc.part {
+					t.Errorf("part, expected %v got %v", tc.part, cell.Mdgid.Part)
+				}
+				if sw := [2]float32{cell.Sw.Lat, cell.Sw.Lng}; sw != tc.sw {
+					t.Errorf("sw, expected %v got %v", tc.sw, sw)
+				}
+				if ne := [2]float32{cell.Ne.Lat, cell.Ne.Lng}; ne != tc.ne {
+					t.Errorf("ne, expected %v got %v", tc.ne, ne)
+				}
+				if sheet := cell.SheetNumber(); sheet != tc.sheet {
+					t.Errorf("sheet number, expected %v got %v", tc.sheet, sheet)
+				}
+				dms := grids.ToDMS(float64(cell.Sw.Lat), float64(cell.Sw.Lng))
+				if cell.SwDms.GetLat() != dms[0].String() {
+					t.Errorf("sw lat dms, expected %v got %v", dms[0].String(), cell.SwDms.GetLat())
+				}
+				if cell.Utm.GetZone() != 11 {
+					t.Errorf("utm zone, expected 11 got %v", cell.Utm.GetZone())
+				}
+			}
+			t.Run("latlng", func(t *testing.T) {
+				cell, err := p.CellForLatLng(tc.lat, tc.lng, 4326)
+				if err != nil {
+					t.Fatalf("error, expected nil got %v", err)
+				}
+				check(t, cell)
+			})
+			t.Run("mdgid", func(t *testing.T) {
+				cell, err := p.CellForMDGID(grids.NewMDGID(tc.mdgid))
+				if err != nil {
+					t.Fatalf("error, expected nil got %v", err)
+				}
+				check(t, cell)
+			})
+		}
+	}
+
+	tests := []tcase{
+		{
+			scheme: RowMajor,
+			rows:   10,
+			lat:    32.74, lng: -117.24,
+			mdgid: "V795G25492-1",
+			part:  1,
+			sheet: "2242I-1",
+			sw:    [2]float32{32.725, -117.25},
+			ne:    [2]float32{32.75, -117.225},
+		},
+		{
+			scheme: RowMajor,
+			rows:   10,
+			lat:    32.73, lng: -117.01,
+			mdgid: "V795G25492-10",
+			part:  10,
+			sheet: "2242I-10",
+			sw:    [2]float32{32.725, -117.025},
+			ne:    [2]float32{32.75, -117.0},
+		},
+		{
+			scheme: Serpentine,
+			rows:   2,
+			lat:    32.6, lng: -117.2,
+			mdgid: "V795G25492-4",
+			part:  4,
+			sheet: "2242I-4",
+			sw:    [2]float32{32.5, -117.25},
+			ne:    [2]float32{32.625, -117.125},
+		},
+		{
+			scheme: Lettered,
+			rows:   2,
+			lat:    32.6, lng: -117.05,
+			mdgid: "V795G25492-D",
+			part:  4,
+			sheet: "2242I-D",
+			sw:    [2]float32{32.5, -117.125},
+			ne:    [2]float32{32.625, -117.0},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestCellsForBounds(t *testing.T) {
+	type tcase struct {
+		bounds geom.Extent
+		parts  []uint32
+	}
+
+	p, err := New(baseProvider{}, 10, 10, RowMajor, 0)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%v", tc.bounds), func(t *testing.T) {
+			iter, err := p.CellsForBounds(tc.bounds, 4326)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			cells, err := grids.AllCells(iter)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(cells) != len(tc.parts) {
+				t.Fatalf("len, expected %v got %v", len(tc.parts), len(cells))
+			}
+			for i := range cells {
+				if cells[i].Mdgid.Part != tc.parts[i] {
+					t.Errorf("part %v, expected %v got %v", i, tc.parts[i], cells[i].Mdgid.Part)
+				}
+			}
+		}
+	}
+
+	tests := []tcase{
+		// the north west corner
+		{bounds: geom.Extent{-117.26, 32.73, -117.22, 32.76}, parts: []uint32{1, 2}},
+		// straddles the first two rows of the last two columns
+		{bounds: geom.Extent{-117.04, 32.71, -116.9, 32.74}, parts: []uint32{9, 10, 19, 20}},
+		{bounds: geom.Extent{-117.3, 32.4, -116.9, 32.9}, parts: seq(1, 100)},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func seq(start, end uint32) (s []uint32) {
+	for i := start; i <= end; i++ {
+		s = append(s, i)
+	}
+	return s
+}
@@ -0,0 +1,39 @@
+package utm
+
+import (
+	"fmt"
+)
+
+// ErrInvalidMGRS is returned when the MGRS reference could not be parsed
+type ErrInvalidMGRS string
+
+func (err ErrInvalidMGRS) Error() string {
+	return fmt.Sprintf("error, invalid mgrs reference (%v)", string(err))
+}
+
+// ErrPolarRegion is returned when the latitude is in the polar region; UPS is not supported
+type ErrPolarRegion float64
+
+func (err ErrPolarRegion) Error() string {
+	return fmt.Sprintf("error, latitude (%v) is outside of the UTM region", float64(err))
+}
+
+// ErrUnknownUnits is returned when the units are not meters or degrees
+type ErrUnknownUnits Units
+
+func (err ErrUnknownUnits) Error() string {
+	return fmt.Sprintf("error, unknown units (%v) expected %v or %v", string(err), Meters, Degrees)
+}
+
+// ErrInvalidCellSize is returned when the cell size is not usable
+type ErrInvalidCellSize struct {
+	Size  float64
+	Units Units
+}
+
+func (err ErrInvalidCellSize) Error() string {
+	if err.Units == Meters {
+		return fmt.Sprintf("error, invalid cell size (%v %v), must be a whole number that evenly divides 100000", err.Size, err.Units)
+	}
+	return fmt.Sprintf("error, invalid cell size (%v %v), must be between 0 and 90", err.Size, err.Units)
+}
@@ -0,0 +1,136 @@
+package utm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// latitude bands, 8° each starting at 80°S; X is 12°
+	bandLetters = "CDEFGHJKLMNPQRSTUVWX"
+	// 100km row letters, the cycle repeats every 2,000km
+	rowLetters = "ABCDEFGHJKLMNPQRSTUV"
+)
+
+// column letters for the 100km square, based on the zone set
+var columnLetters = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+// bandMinNorthing is the minimum northing of each latitude band; used
+// to recover the 2,000km cycle of the row letter.
+var bandMinNorthing = map[byte]float64{
+	'C': 1100000, 'D': 2000000, 'E': 2800000, 'F': 3700000,
+	'G': 4600000, 'H': 5500000, 'J': 6400000, 'K': 7300000,
+	'L': 8200000, 'M': 9100000, 'N': 0, 'P': 800000,
+	'Q': 1700000, 'R': 2600000, 'S': 3500000, 'T': 4400000,
+	'U': 5300000, 'V': 6200000, 'W': 7000000, 'X': 7900000,
+}
+
+func bandFor(lat float64) byte {
+	if lat >= 72 {
+		return 'X'
+	}
+	idx := int(math.Floor((lat + 80) / 8))
+	if idx < 0 {
+		idx = 0
+	}
+	return bandLetters[idx]
+}
+
+// MGRS returns the MGRS reference of the UTM coordinate, with the given number of digits
+// (0-5) for the easting and northing. The latitude band is based on lat.
+func (utm Coord) MGRS(lat float64, digits int) string {
+	if digits < 0 {
+		digits = 0
+	}
+	if digits > 5 {
+		digits = 5
+	}
+	col := int(math.Floor(utm.Easting / 100000))
+	row := int(math.Floor(utm.Northing/100000)) % 20
+	if utm.Zone%2 == 0 {
+		// even zones are offset by five letters
+		row = (row + 5) % 20
+	}
+	colLetters := columnLetters[(utm.Zone-1)%3]
+	colLetter := byte('?')
+	if col >= 1 && col <= 8 {
+		colLetter = colLetters[col-1]
+	}
+
+	div := math.Pow10(5 - digits)
+	e := int(math.Floor(math.Mod(utm.Easting, 100000) / div))
+	n := int(math.Floor(math.Mod(utm.Northing, 100000) / div))
+
+	ref := fmt.Sprintf("%02d%c%c%c", utm.Zone, bandFor(lat), colLetter, rowLetters[row])
+	if digits == 0 {
+		return ref
+	}
+	return fmt.Sprintf("%s%0*d%0*d", ref, digits, e, digits, n)
+}
+
+// ParseMGRS parses an MGRS reference returning the UTM coordinate of south west corner of
+// the referenced square and the precision (size of the square) in meters.
+func ParseMGRS(ref string) (utm Coord, precision float64, err error) {
+	s := strings.ToUpper(strings.Join(strings.Fields(ref), ""))
+
+	// zone number
+	i := 0
+	for i < len(s) && i < 2 && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 || len(s) < i+3 {
+		return utm, 0, ErrInvalidMGRS(ref)
+	}
+	zone, _ := strconv.Atoi(s[:i])
+	if zone < 1 || zone > 60 {
+		return utm, 0, ErrInvalidMGRS(ref)
+	}
+	band, colLetter, rowLetter := s[i], s[i+1], s[i+2]
+	minNorthing, ok := bandMinNorthing[band]
+	if !ok {
+		return utm, 0, ErrInvalidMGRS(ref)
+	}
+
+	col := strings.IndexByte(columnLetters[(zone-1)%3], colLetter)
+	row := strings.IndexByte(rowLetters, rowLetter)
+	if col == -1 || row == -1 {
+		return utm, 0, ErrInvalidMGRS(ref)
+	}
+	if zone%2 == 0 {
+		row = (row + 15) % 20
+	}
+
+	digits := s[i+3:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return utm, 0, ErrInvalidMGRS(ref)
+	}
+	p := len(digits) / 2
+	precision = math.Pow10(5 - p)
+
+	var e, n float64
+	if p > 0 {
+		ei, err := strconv.Atoi(digits[:p])
+		if err != nil {
+			return utm, 0, ErrInvalidMGRS(ref)
+		}
+		ni, err := strconv.Atoi(digits[p:])
+		if err != nil {
+			return utm, 0, ErrInvalidMGRS(ref)
+		}
+		e, n = float64(ei)*precision, float64(ni)*precision
+	}
+
+	northing := float64(row) * 100000
+	for northing < minNorthing {
+		northing += 2000000
+	}
+
+	return Coord{
+		Zone:     zone,
+		South:    band < 'N',
+		Easting:  float64(col+1)*100000 + e,
+		Northing: northing + n,
+	}, precision, nil
+}
@@ -0,0 +1,147 @@
+package utm
+
+import (
+	"math"
+)
+
+// WGS84 ellipsoid and UTM constants
+const (
+	k0 = 0.9996
+	a  = 6378137.0
+	f  = 1 / 298.257223563
+	e2 = f * (2 - f)
+	// second eccentricity squared
+	ep2 = e2 / (1 - e2)
+
+	falseEasting             = 500000.0
+	falseNorthingSouth       = 10000000.0
+	minLatitude, maxLatitude = -80.0, 84.0
+)
+
+const (
+	d2r = math.Pi / 180
+	r2d = 180 / math.Pi
+)
+
+// Coord is a coordinate in the UTM system
+type Coord struct {
+	Zone     int
+	South    bool
+	Easting  float64
+	Northing float64
+}
+
+// zoneFor returns the utm zone for the given lat lng, taking into account the
+// Norway and Svalbard exceptions.
+func zoneFor(lat, lng float64) int {
+	switch {
+	case lat >= 56.0 && lat < 64.0 && lng >= 3.0 && lng < 12.0:
+		return 32
+	case lat >= 72.0 && lat < 84.0:
+		switch {
+		case lng >= 0.0 && lng < 9.0:
+			return 31
+		case lng >= 9.0 && lng < 21.0:
+			return 33
+		case lng >= 21.0 && lng < 33.0:
+			return 35
+		case lng >= 33.0 && lng < 42.0:
+			return 37
+		}
+	}
+	z := int((lng+180)/6) + 1
+	if z > 60 {
+		// lng == 180
+		z = 1
+	}
+	return z
+}
+
+func centralMeridian(zone int) float64 { return float64((zone-1)*6-180+3) * d2r }
+
+// meridianArc returns the distance along the meridian from the equator to the latitude phi
+func meridianArc(phi float64) float64 {
+	e4, e6 := e2*e2, e2*e2*e2
+	return a * ((1-e2/4-3*e4/64-5*e6/256)*phi -
+		(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+		(35*e6/3072)*math.Sin(6*phi))
+}
+
+// FromLatLng converts the lat lng to a UTM coordinate in the standard zone for the lat lng.
+func FromLatLng(lat, lng float64) (Coord, error) {
+	if lat < minLatitude || lat > maxLatitude {
+		return Coord{}, ErrPolarRegion(lat)
+	}
+	return FromLatLngInZone(lat, lng, zoneFor(lat, lng)), nil
+}
+
+// FromLatLngInZone converts the lat lng to a UTM coordinate in the given zone.
+func FromLatLngInZone(lat, lng float64, zone int) Coord {
+	phi := lat * d2r
+	sin, cos, tan := math.Sin(phi), math.Cos(phi), math.Tan(phi)
+
+	n := a / math.Sqrt(1-e2*sin*sin)
+	t := tan * tan
+	c := ep2 * cos * cos
+	A := cos * (lng*d2r - centralMeridian(zone))
+	m := meridianArc(phi)
+
+	A2 := A * A
+	A3, A4 := A2*A, A2*A2
+	A5, A6 := A4*A, A4*A2
+
+	easting := k0*n*(A+(1-t+c)*A3/6+(5-18*t+t*t+72*c-58*ep2)*A5/120) + falseEasting
+	northing := k0 * (m + n*tan*(A2/2+(5-t+9*c+4*c*c)*A4/24+(61-58*t+t*t+600*c-330*ep2)*A6/720))
+
+	south := lat < 0
+	if south {
+		northing += falseNorthingSouth
+	}
+	return Coord{
+		Zone:     zone,
+		South:    south,
+		Easting:  easting,
+		Northing: northing,
+	}
+}
+
+// LatLng converts the UTM coordinate to a lat lng
+func (utm Coord) LatLng() (lat, lng float64) {
+	x := utm.Easting - falseEasting
+	y := utm.Northing
+	if utm.South {
+		y -= falseNorthingSouth
+	}
+
+	e4, e6 := e2*e2, e2*e2*e2
+	mu := (y / k0) / (a * (1 - e2/4 - 3*e4/64 - 5*e6/256))
+	sq := math.Sqrt(1 - e2)
+	e1 := (1 - sq) / (1 + sq)
+	e12, e13, e14 := e1*e1, e1*e1*e1, e1*e1*e1*e1
+
+	phi1 := mu +
+		(3*e1/2-27*e13/32)*math.Sin(2*mu) +
+		(21*e12/16-55*e14/32)*math.Sin(4*mu) +
+		(151*e13/96)*math.Sin(6*mu) +
+		(1097*e14/512)*math.Sin(8*mu)
+
+	sin, cos, tan := math.Sin(phi1), math.Cos(phi1), math.Tan(phi1)
+	c1 := ep2 * cos * cos
+	t1 := tan * tan
+	n1 := a / math.Sqrt(1-e2*sin*sin)
+	r1 := a * (1 - e2) / math.Pow(1-e2*sin*sin, 1.5)
+	d := x / (n1 * k0)
+
+	d2 := d * d
+	d3, d4 := d2*d, d2*d2
+	d5, d6 := d4*d, d4*d2
+
+	phi := phi1 - (n1*tan/r1)*(d2/2-
+		(5+3*t1+10*c1-4*c1*c1-9*ep2)*d4/24+
+		(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*d6/720)
+	lambda := centralMeridian(utm.Zone) +
+		(d-(1+2*t1+c1)*d3/6+(5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*d5/120)/cos
+
+	return phi * r2d, lambda * r2d
+}
@@ -0,0 +1,331 @@
+package utm
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/config"
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/geom"
+)
+
+// Name is the name of the provider type
+const Name = "utm"
+
+// Units are the units of the cell size
+type Units string
+
+const (
+	// Meters cells are squares in the UTM zone; the mdgid is an MGRS reference
+	Meters = Units("meters")
+	// Degrees cells are lat/lng squares; the mdgid is generated from the row and column of the cell
+	Degrees = Units("degrees")
+)
+
+const (
+	// DefaultUnits is the default units of the cell size
+	DefaultUnits = Meters
+	// DefaultSeries is the series name if one is not provided
+	DefaultSeries = ""
+	// DefaultEditBy who edited the content if not provided
+	DefaultEditBy = ""
+)
+
+const (
+	// ConfigKeyScale is the scale of this provider
+	ConfigKeyScale = "scale"
+	// ConfigKeyCellSize is the size of each cell, in units
+	ConfigKeyCellSize = "cell_size"
+	// ConfigKeyUnits is the units of the cell size, either meters or degrees
+	ConfigKeyUnits = "units"
+	// ConfigKeySeries is the series value of every cell
+	ConfigKeySeries = "series"
+	// ConfigKeyEditBy who the default user for edit_by should be
+	ConfigKeyEditBy = "edit_by"
+)
+
+func init() {
+	grids.Register(Name, NewGridProvider, nil)
+	grids.RegisterSchema(Name, config.Schema{
+		Required: []string{ConfigKeyScale, ConfigKeyCellSize},
+		Optional: []string{ConfigKeyUnits, ConfigKeySeries, ConfigKeyEditBy},
+	})
+}
+
+// Provider is a grid provider that computes it's cells.
+type Provider struct {
+	cellSize grids.CellSize
+	size     float64
+	units    Units
+	series   string
+	editedBy string
+	// digits is the number of MGRS digits for the easting and northing
+	digits int
+}
+
+// New returns a new computed grid provider; for meters the size must evenly divide 100km.
+func New(scale grids.CellSize, size float64, units Units, series, editedBy string) (*Provider, error) {
+	if size <= 0 {
+		return nil, ErrInvalidCellSize{Size: size, Units: units}
+	}
+	p := Provider{
+		cellSize: scale,
+		size:     size,
+		units:    units,
+		series:   series,
+		editedBy: editedBy,
+	}
+	switch units {
+	case Meters:
+		if size != math.Trunc(size) || size > 100000 || math.Mod(100000, size) != 0 {
+			return nil, ErrInvalidCellSize{Size: size, Units: units}
+		}
+		// we want the MGRS precision that can reference the corners of all the cells
+		for p.digits = 0; p.digits < 5; p.digits++ {
+			if math.Mod(size, math.Pow10(5-p.digits)) == 0 {
+				break
+			}
+		}
+	case Degrees:
+		if size > 90 {
+			return nil, ErrInvalidCellSize{Size: size, Units: units}
+		}
+	default:
+		return nil, ErrUnknownUnits(units)
+	}
+	return &p, nil
+}
+
+// NewGridProvider returns a grid provider that computes the cells
+func NewGridProvider(config grids.ProviderConfig) (grids.Provider, error) {
+	var (
+		err   error
+		scale uint
+		size  float64
+	)
+	if scale, err = config.Uint(ConfigKeyScale, nil); err != nil {
+		return nil, err
+	}
+	if size, err = config.Float(ConfigKeyCellSize, nil); err != nil {
+		return nil, err
+	}
+
+	units := string(DefaultUnits)
+	if units, err = config.String(ConfigKeyUnits, &units); err != nil {
+		return nil, err
+	}
+
+	series := DefaultSeries
+	if series, err = config.String(ConfigKeySeries, &series); err != nil {
+		return nil, err
+	}
+
+	editedBy := DefaultEditBy
+	if editedBy, err = config.String(ConfigKeyEditBy, &editedBy); err != nil {
+		return nil, err
+	}
+
+	return New(grids.CellSize(scale), size, Units(strings.ToLower(units)), series, editedBy)
+}
+
+// CellSize returns the grid cell size
+func (p *Provider) CellSize() grids.CellSize {
+	if p == nil {
+		return grids.CellSize50K
+	}
+	return p.cellSize
+}
+
+// newCell returns a cell with the given id and corners
+func (p *Provider) newCell(mdgid string, sw, ne [2]float64, utm *grids.UTMInfo) *grids.Cell {
+	editInfo := grids.NewEditInfo(p.editedBy, time.Time{})
+	metadata := map[string]string{"units": string(p.units)}
+	return grids.NewCell(
+		mdgid,       // mdgid
+		sw,          // sw
+		ne,          // ne
+		"",          // country
+		"",          // city
+		utm,         // utminfo
+		editInfo,    // edited info
+		time.Now(),  // publishedAt
+		"",          // nrn
+		mdgid,       // sheet
+		p.series,    // series
+		[2]string{}, // sw dms; calculated
+		[2]string{}, // ne dms; calculated
+		metadata,    // metadata
+	)
+}
+
+// degreesID returns the mdgid for the row and column
+func degreesID(row, col int) string { return fmt.Sprintf("R%dC%d", row, col) }
+
+func (p *Provider) degreesCell(row, col int) (*grids.Cell, error) {
+	rows, cols := int(math.Ceil(180/p.size)), int(math.Ceil(360/p.size))
+	if row < 0 || col < 0 || row >= rows || col >= cols {
+		return nil, grids.ErrNotFound
+	}
+	s := -90 + float64(row)*p.size
+	w := -180 + float64(col)*p.size
+	n, e := math.Min(s+p.size, 90), math.Min(w+p.size, 180)
+	return p.newCell(degreesID(row, col), [2]float64{s, w}, [2]float64{n, e}, nil), nil
+}
+
+// meterCell returns the cell in the same zone as the given utm coordinate
+// containing the coordinate.
+func (p *Provider) meterCell(utm Coord) (*grids.Cell, error) {
+	utm.Easting = math.Floor(utm.Easting/p.size) * p.size
+	utm.Northing = math.Floor(utm.Northing/p.size) * p.size
+
+	corners := [4]Coord{utm, utm, utm, utm}
+	corners[1].Easting += p.size
+	corners[2].Northing += p.size
+	corners[3].Easting += p.size
+	corners[3].Northing += p.size
+
+	// the cell is square in the utm zone, so we use the extent of the corners
+	var ext *geom.Extent
+	for _, c := range corners {
+		lat, lng := c.LatLng()
+		if ext == nil {
+			ext = geom.NewExtent([2]float64{lng, lat})
+			continue
+		}
+		ext.AddPoints([2]float64{lng, lat})
+	}
+	swlat, _ := utm.LatLng()
+
+	hemi := grids.HEMIType_NORTH
+	if utm.South {
+		hemi = grids.HEMIType_SOUTH
+	}
+	cell := p.newCell(
+		utm.MGRS(swlat, p.digits),
+		[2]float64{ext.MinY(), ext.MinX()},
+		[2]float64{ext.MaxY(), ext.MaxX()},
+		grids.NewUTM(uint8(utm.Zone), hemi),
+	)
+	cell.MetaData["easting"] = fmt.Sprintf("%.0f", utm.Easting)
+	cell.MetaData["northing"] = fmt.Sprintf("%.0f", utm.Northing)
+	return cell, nil
+}
+
+// cellFor returns the cell containing the lat, lng
+func (p *Provider) cellFor(lat, lng float64) (*grids.Cell, error) {
+	if p.units == Degrees {
+		row := int(math.Floor((lat + 90) / p.size))
+		col := int(math.Floor((lng + 180) / p.size))
+		return p.degreesCell(row, col)
+	}
+	utm, err := FromLatLng(lat, lng)
+	if err != nil {
+		return nil, err
+	}
+	return p.meterCell(utm)
+}
+
+// CellForBounds get the cell for the given bounds
+func (p *Provider) CellForBounds(bounds geom.Extent, srid uint) (*grids.Cell, error) {
+	swlat, swlng, err := grids.LatLngForSRID(bounds[0], bounds[1], srid)
+	if err != nil {
+		return nil, err
+	}
+	nelat, nelng, err := grids.LatLngForSRID(bounds[2], bounds[3], srid)
+	if err != nil {
+		return nil, err
+	}
+	cell, err := p.cellFor((swlat+nelat)/2, (swlng+nelng)/2)
+	if err != nil {
+		return nil, err
+	}
+
+	cell.Sw = &grids.Cell_LatLng{Lng: float32(swlng), Lat: float32(swlat)}
+	cell.Ne = &grids.Cell_LatLng{Lng: float32(nelng), Lat: float32(nelat)}
+	latlen, lnglen := grids.CalculateSecLengths(float64(cell.Ne.Lat))
+	cell.Len = &grids.Cell_LatLng{Lat: float32(latlen), Lng: float32(lnglen)}
+	cell.SwDms = nil
+	cell.NeDms = nil
+	cell.Init()
+
+	h := sha1.New()
+	fmt.Fprintf(h, "%v %v %v", bounds, srid, time.Now())
+	cell.MetaData["filename"] = fmt.Sprintf("%x", h.Sum(nil))
+	return cell, nil
+}
+
+// CellForLatLng returns the grid cell containing the lat lng
+func (p *Provider) CellForLatLng(lat, lng float64, srid uint) (*grids.Cell, error) {
+	lat, lng, err := grids.LatLngForSRID(lng, lat, srid)
+	if err != nil {
+		return nil, err
+	}
+	return p.cellFor(lat, lng)
+}
+
+// degreesRowCol returns the row and column from a generated degrees mdgid
+func degreesRowCol(mdgid *grids.MDGID) (row, col int, ok bool) {
+	var rest string
+	n, _ := fmt.Sscanf(strings.ToUpper(mdgid.GetId())+" ", "R%dC%d%s", &row, &col, &rest)
+	return row, col, n == 2
+}
+
+// CellForMDGID returns the grid cell for the mdgid. For meters this is any MGRS reference in
+// the cell; for degrees this is the generated R<row>C<col> id.
+func (p *Provider) CellForMDGID(mdgid *grids.MDGID) (*grids.Cell, error) {
+	if mdgid == nil {
+		return nil, grids.ErrNotFound
+	}
+	if p.units == Degrees {
+		row, col, ok := degreesRowCol(mdgid)
+		if !ok {
+			return nil, grids.ErrNotFound
+		}
+		return p.degreesCell(row, col)
+	}
+	utm, _, err := ParseMGRS(mdgid.Id)
+	if err != nil {
+		return nil, grids.ErrNotFound
+	}
+	return p.meterCell(utm)
+}
+
+// polarProbe is used to probe for neighbors, where the polar regions have no cells
+type polarProbe struct{ *Provider }
+
+func (p polarProbe) CellForLatLng(lat, lng float64, srid uint) (*grids.Cell, error) {
+	cell, err := p.Provider.CellForLatLng(lat, lng, srid)
+	if _, ok := err.(ErrPolarRegion); ok {
+		return nil, grids.ErrNotFound
+	}
+	return cell, err
+}
+
+// Neighbors returns the cells adjoining the cell. For degrees the neighbors
+// are calculated from the row and column, wrapping around the anti-meridian.
+func (p *Provider) Neighbors(cell *grids.Cell) (*grids.Neighbors, error) {
+	if p.units != Degrees {
+		return grids.ProbeNeighbors(polarProbe{p}, cell)
+	}
+	var ns grids.Neighbors
+	row, col, ok := degreesRowCol(cell.GetMdgid())
+	if !ok {
+		return nil, grids.ErrNotFound
+	}
+	cols := int(math.Ceil(360 / p.size))
+	for _, d := range grids.Directions {
+		dx, dy := d.Offset()
+		ncell, err := p.degreesCell(row+int(dy), (col+int(dx)+cols)%cols)
+		if err == grids.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ns.Set(d, ncell)
+	}
+	return &ns, nil
+}
@@ -0,0 +1,159 @@
+package utm
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+)
+
+func TestMGRS(t *testing.T) {
+	type tcase struct {
+		lat, lng float64
+		mgrs     string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.mgrs, func(t *testing.T) {
+			utm, err := FromLatLng(tc.lat, tc.lng)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			lat, lng := utm.LatLng()
+			if math.Abs(lat-tc.lat) > 1e-7 || math.Abs(lng-tc.lng) > 1e-7 {
+				t.Errorf("round trip, expected %v,%v got %v,%v", tc.lat, tc.lng, lat, lng)
+			}
+			if got := utm.MGRS(tc.lat, 5); got != tc.mgrs {
+				t.Errorf("mgrs, expected %v got %v", tc.mgrs, got)
+			}
+			parsed, precision, err := ParseMGRS(tc.mgrs)
+			if err != nil {
+				t.Fatalf("parse error, expected nil got %v", err)
+			}
+			if precision != 1 {
+				t.Errorf("precision, expected 1 got %v", precision)
+			}
+			if parsed.Zone != utm.Zone || parsed.South != utm.South ||
+				parsed.Easting != math.Floor(utm.Easting) || parsed.Northing != math.Floor(utm.Northing) {
+				t.Errorf("parse, expected %v got %v", utm, parsed)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{lat: 38.8895, lng: -77.0352, mgrs: "18SUJ2348606483"},
+		{lat: 0, lng: 3, mgrs: "31NEA0000000000"},
+		{lat: -33.8688, lng: 151.2093, mgrs: "56HLH3436850948"},
+		// Norway exception
+		{lat: 60.0, lng: 5.0, mgrs: "32VKM7697958157"},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestCellFor(t *testing.T) {
+	type tcase struct {
+		size     float64
+		units    Units
+		lat, lng float64
+		mdgid    string
+		zone     uint32
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%v %v %v", tc.size, tc.units, tc.mdgid), func(t *testing.T) {
+			p, err := New(grids.CellSize50K, tc.size, tc.units, "", "")
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			cell, err := p.CellForLatLng(tc.lat, tc.lng, 4326)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if cell.Mdgid.Id != tc.mdgid {
+				t.Errorf("mdgid, expected %v got %v", tc.mdgid, cell.Mdgid.Id)
+			}
+			if !cell.Hull().ContainsPoint([2]float64{tc.lng, tc.lat}) {
+				t.Errorf("hull, expected %v to contain %v,%v", cell.Hull(), tc.lng, tc.lat)
+			}
+			if cell.GetUtm().GetZone() != tc.zone {
+				t.Errorf("zone, expected %v got %v", tc.zone, cell.GetUtm().GetZone())
+			}
+			if cell.GetSwDms().GetLat() == "" || cell.GetLen() == nil {
+				t.Errorf("dms and len, expected values got %v %v", cell.GetSwDms(), cell.GetLen())
+			}
+
+			mcell, err := p.CellForMDGID(grids.NewMDGID(tc.mdgid))
+			if err != nil {
+				t.Fatalf("mdgid error, expected nil got %v", err)
+			}
+			if mcell.SW() != cell.SW() || mcell.NE() != cell.NE() {
+				t.Errorf("mdgid bounds, expected %v %v got %v %v", cell.Sw, cell.Ne, mcell.Sw, mcell.Ne)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{size: 10000, units: Meters, lat: 38.8895, lng: -77.0352, mdgid: "18SUJ20", zone: 18},
+		{size: 5000, units: Meters, lat: 38.8895, lng: -77.0352, mdgid: "18SUJ2005", zone: 18},
+		{size: 25000, units: Meters, lat: -33.8688, lng: 151.2093, mdgid: "56HLH2550", zone: 56},
+		{size: 0.25, units: Degrees, lat: 32.6, lng: -117.1, mdgid: "R490C251", zone: 11},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	type tcase struct {
+		size     float64
+		units    Units
+		lat, lng float64
+		// expected mdgid for each direction, clockwise from north; empty for no neighbor
+		mdgids [8]string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%v %v %v,%v", tc.size, tc.units, tc.lat, tc.lng), func(t *testing.T) {
+			p, err := New(grids.CellSize50K, tc.size, tc.units, "", "")
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			cell, err := p.CellForLatLng(tc.lat, tc.lng, 4326)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			ns, err := grids.NeighborsFor(p, cell)
+			if err != nil {
+				t.Fatalf("neighbors error, expected nil got %v", err)
+			}
+			for i, d := range grids.Directions {
+				if got := ns.For(d).MDGID(); got != tc.mdgids[i] {
+					t.Errorf("%v, expected %q got %q", d, tc.mdgids[i], got)
+				}
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			size: 10000, units: Meters, lat: 38.8895, lng: -77.0352,
+			// the southern neighbors are in the next 100km square
+			mdgids: [8]string{"18SUJ21", "18SUJ31", "18SUJ30", "18SUH39", "18SUH29", "18SUH19", "18SUJ10", "18SUJ11"},
+		},
+		{
+			size: 0.25, units: Degrees, lat: 32.6, lng: -117.1,
+			mdgids: [8]string{"R491C251", "R491C252", "R490C252", "R489C252", "R489C251", "R489C250", "R490C250", "R491C250"},
+		},
+		{
+			// wraps around the anti-meridian, nothing north of the pole
+			size: 10, units: Degrees, lat: 85, lng: 175,
+			mdgids: [8]string{"", "", "R17C0", "R16C0", "R16C35", "R16C34", "R17C34", ""},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -3,18 +3,20 @@ package atlante
 import (
 	"context"
 	"image/png"
+	"io"
+	"math"
 	"sync"
 
 	"github.com/go-spatial/atlante/atlante/filestore"
 	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/atlante/internal/geotiff"
 	"github.com/go-spatial/atlante/atlante/internal/resolution"
 	"github.com/go-spatial/atlante/mbgl/bounds"
-	"github.com/go-spatial/atlante/mbgl/image"
-	mbgl "github.com/go-spatial/atlante/mbgl/image"
+	"github.com/go-spatial/tegola/dict"
 	"github.com/prometheus/common/log"
 )
 
-// Img is a wrapper around an mbgl image that make the image available to the
+// Img is a wrapper around a rendered image that make the image available to the
 // template, and allows for the image to be encode only if it's requested
 // It also will allow the Desired With and Height of the Image to be set.
 // If this is set and a bounds is provided for the map region, then the
@@ -22,6 +24,9 @@ import (
 type Img struct {
 	File *filestore.File
 
+	// Renderer renders the map image
+	Renderer Renderer
+
 	DPI        uint
 	Grid       *grids.Cell
 	Projection bounds.AProjection
@@ -35,16 +40,48 @@ type Img struct {
 	// Did we already generate the base image
 	generated           bool
 	lck                 sync.Mutex
-	image               *mbgl.Image
+	image               RenderedImage
 	width, height       float64
 	imgWidth, imgHeight float64
 	groundMeasure       float64
 	zoom                float64
+	// renderErr is the error, if any, of the last render of the image
+	renderErr error
 
 	// staticWidthHeight determines if the Width and Height for this was statically defined
 	// if so, then we need to dynamically figure out the scale from the bounds, if bounds is
 	// not provided we may still dynamically determine a width and height
 	staticWidthHeight bool
+
+	// frame is where the template placed the image on the page
+	frame *Frame
+}
+
+// Frame is a region of the page in points, with the origin at the top left of the page
+type Frame struct {
+	X, Y          float64
+	Width, Height float64
+}
+
+// Place records where the image is placed on the page, in points from the top left
+// of the page. It is used to georeference the map frame of the pdf.
+//
+//	{{ .Image.Place 36 36 1000 800 }}
+func (img *Img) Place(x, y, width, height float64) string {
+	img.lck.Lock()
+	img.frame = &Frame{X: x, Y: y, Width: width, Height: height}
+	img.lck.Unlock()
+	return ""
+}
+
+// Frame returns where the image was placed on the page, nil if it was not placed
+func (img *Img) Frame() *Frame {
+	if img == nil {
+		return nil
+	}
+	img.lck.Lock()
+	defer img.lck.Unlock()
+	return img.frame
 }
 
 func (img *Img) initDynamicWidthHeight() {
@@ -98,7 +135,7 @@ func (img *Img) initStaticWidthHeight(tilesize float64) {
 	img.zoom = img.Grid.ZoomForScaleDPI(img.Scale, img.DPI) - 1
 }
 
-func (img *Img) initImage(ctx context.Context) (*mbgl.Image, error) {
+func (img *Img) initImage(ctx context.Context) (RenderedImage, error) {
 
 	if img == nil {
 		return nil, nil
@@ -106,6 +143,14 @@ func (img *Img) initImage(ctx context.Context) (*mbgl.Image, error) {
 	if img.image != nil {
 		return img.image, nil
 	}
+	renderer := img.Renderer
+	if renderer == nil {
+		var err error
+		if renderer, err = RendererFor(DefaultRendererType, dict.Dict{}); err != nil {
+			log.Warnf("default renderer: %v", err)
+			return nil, ErrNoRenderer
+		}
+	}
 
 	const tilesize = 4096 / 2
 	var (
@@ -125,26 +170,24 @@ func (img *Img) initImage(ctx context.Context) (*mbgl.Image, error) {
 
 	centerPt := bounds.LatLngToPoint(img.Projection, latLngCenterPt[0], latLngCenterPt[1], img.zoom, tilesize)
 	// Generate the PNG
-	img.image, err = image.New(
-		ctx,
-
-		img.Projection,
-		int(img.imgWidth), int(img.imgHeight),
-		centerPt,
-		img.zoom,
-		// TODO(gdey): Need to remove this hack and figure out how to used the
-		// ppi value as well as set the correct scale on the svg/pdf document
-		// that is produced later on. (https://github.com/go-spatial/atlante/issues/13)
-		1.0, // ppiRatio, (we adjust the zoom)
-		0.0, // Bearing
-		0.0, // Pitch
-		img.Style,
-		"", "",
-	)
-	return img.image, err
+	img.image, err = renderer.Render(ctx, RenderRequest{
+		Grid:       grid,
+		Projection: img.Projection,
+		Width:      int(img.imgWidth),
+		Height:     int(img.imgHeight),
+		Center:     centerPt,
+		Zoom:       img.zoom,
+		Style:      img.Style,
+	})
+	img.renderErr = nil
+	if err != nil {
+		img.renderErr = ErrRender{Err: err}
+		return img.image, img.renderErr
+	}
+	return img.image, nil
 }
 
-func (img *Img) Image() *mbgl.Image {
+func (img *Img) Image() RenderedImage {
 	image, err := img.initImage(context.Background())
 	if err != nil {
 		log.Infof("failed to init image: %v", err)
@@ -206,13 +249,53 @@ func (img *Img) Filename() (string, error) {
 }
 
 // Close closes out any open resources
-func (img Img) Close() error {
+func (img *Img) Close() error {
 	if !img.generated {
 		return nil
 	}
 	return img.File.Close()
 }
 
+// mbglTileSize is the size, in pixels, of a tile at zoom 0 for the images mbgl renders;
+// renderers are expected to draw at the same resolution
+const mbglTileSize = 512
+
+// Georeference returns where the rendered image is in web mercator
+func (img *Img) Georeference() (geotiff.Georeference, error) {
+	image, err := img.initImage(context.Background())
+	if err != nil {
+		return geotiff.Georeference{}, err
+	}
+	// The image is centered on the center of the grid, and each pixel is
+	// the size of a pixel of a 512 tile at the zoom
+	res := 2 * math.Pi * img.Projection.R() / bounds.ScaleTile(img.zoom, mbglTileSize)
+	latlng := img.Grid.CenterPtForZoom(img.zoom)
+	center := img.Projection.Project(latlng)
+	width, height := float64(image.Bounds().Dx()), float64(image.Bounds().Dy())
+	return geotiff.Georeference{
+		EPSG: geotiff.EPSG3857,
+		Origin: [2]float64{
+			center[0] - (width/2)*res,
+			center[1] + (height/2)*res,
+		},
+		PixelSize: [2]float64{res, res},
+	}, nil
+}
+
+// WriteGeoTIFF will render the image, if it has not been already, and write it to w as a GeoTIFF
+func (img *Img) WriteGeoTIFF(w io.Writer) error {
+	geo, err := img.Georeference()
+	if err != nil {
+		return err
+	}
+	img.lck.Lock()
+	defer img.lck.Unlock()
+	if err = img.image.GenerateImage(); err != nil {
+		return err
+	}
+	return geotiff.Encode(w, img.image, geo)
+}
+
 func (img *Img) GroundMeasure() float64 {
 	img.initImage(context.Background())
 	return img.groundMeasure
@@ -0,0 +1,21 @@
+package geotiff
+
+import (
+	"fmt"
+
+	"github.com/gdey/errors"
+)
+
+const (
+	// ErrEmptyImage is returned when the image has no pixels
+	ErrEmptyImage = errors.String("image is empty")
+	// ErrInvalidPixelSize is returned when the pixel size is not positive
+	ErrInvalidPixelSize = errors.String("pixel size must be positive")
+)
+
+// ErrUnsupportedEPSG is returned when the epsg code can not be encoded
+type ErrUnsupportedEPSG uint
+
+func (err ErrUnsupportedEPSG) Error() string {
+	return fmt.Sprintf("unsupported epsg code %d", uint(err))
+}
@@ -0,0 +1,287 @@
+// Package geotiff encodes an image as a deflate compressed, RGBA GeoTIFF
+package geotiff
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+)
+
+// RowsPerStrip is the number of rows of the image in each compressed strip
+const RowsPerStrip = 64
+
+// EPSG codes the encoder knows how to describe
+const (
+	// EPSG4326 is WGS 84 lng, lat in degrees
+	EPSG4326 = 4326
+	// EPSG3857 is WGS 84 / Pseudo-Mercator in meters
+	EPSG3857 = 3857
+)
+
+// Georeference describes where the image is in the world
+type Georeference struct {
+	// EPSG code of the coordinate system
+	EPSG uint
+	// Origin is the coordinate of the top left corner of the top left pixel
+	Origin [2]float64
+	// PixelSize is the size of a pixel in the units of the coordinate system,
+	// x, y; both should be positive
+	PixelSize [2]float64
+}
+
+// tiff tag ids
+const (
+	tagImageWidth      = 256
+	tagImageLength     = 257
+	tagBitsPerSample   = 258
+	tagCompression     = 259
+	tagPhotometric     = 262
+	tagStripOffsets    = 273
+	tagSamplesPerPixel = 277
+	tagRowsPerStrip    = 278
+	tagStripByteCounts = 279
+	tagPlanarConfig    = 284
+	tagExtraSamples    = 338
+	tagModelPixelScale = 33550
+	tagModelTiepoint   = 33922
+	tagGeoKeyDirectory = 34735
+)
+
+// tiff field types
+const (
+	dtShort  = 3
+	dtLong   = 4
+	dtDouble = 12
+)
+
+// geo keys
+const (
+	keyGTModelType        = 1024
+	keyGTRasterType       = 1025
+	keyGeographicType     = 2048
+	keyGeogAngularUnits   = 2054
+	keyProjectedCSType    = 3072
+	keyProjLinearUnits    = 3076
+	modelTypeProjected    = 1
+	modelTypeGeographic   = 2
+	rasterPixelIsArea     = 1
+	unitsLinearMeter      = 9001
+	unitsAngularDegree    = 9102
+	compressionAdobeFlate = 8
+	photometricRGB        = 2
+	extraSampleUnassocAlp = 2
+)
+
+type field struct {
+	tag   uint16
+	dtype uint16
+	// only one of these is set, based on dtype
+	shorts  []uint16
+	longs   []uint32
+	doubles []float64
+}
+
+func (f field) count() int {
+	switch f.dtype {
+	case dtShort:
+		return len(f.shorts)
+	case dtLong:
+		return len(f.longs)
+	default:
+		return len(f.doubles)
+	}
+}
+
+func (f field) size() int {
+	switch f.dtype {
+	case dtShort:
+		return 2 * len(f.shorts)
+	case dtLong:
+		return 4 * len(f.longs)
+	default:
+		return 8 * len(f.doubles)
+	}
+}
+
+func (f field) value() []byte {
+	var buff bytes.Buffer
+	switch f.dtype {
+	case dtShort:
+		binary.Write(&buff, binary.LittleEndian, f.shorts)
+	case dtLong:
+		binary.Write(&buff, binary.LittleEndian, f.longs)
+	default:
+		binary.Write(&buff, binary.LittleEndian, f.doubles)
+	}
+	return buff.Bytes()
+}
+
+// geoKeys returns the geo key directory for the epsg code
+func geoKeys(epsg uint) []uint16 {
+	var keys [][4]uint16
+	if epsg == EPSG4326 {
+		keys = [][4]uint16{
+			{keyGTModelType, 0, 1, modelTypeGeographic},
+			{keyGTRasterType, 0, 1, rasterPixelIsArea},
+			{keyGeographicType, 0, 1, uint16(epsg)},
+			{keyGeogAngularUnits, 0, 1, unitsAngularDegree},
+		}
+	} else {
+		keys = [][4]uint16{
+			{keyGTModelType, 0, 1, modelTypeProjected},
+			{keyGTRasterType, 0, 1, rasterPixelIsArea},
+			{keyProjectedCSType, 0, 1, uint16(epsg)},
+			{keyProjLinearUnits, 0, 1, unitsLinearMeter},
+		}
+	}
+	dir := []uint16{1, 1, 0, uint16(len(keys))}
+	for _, k := range keys {
+		dir = append(dir, k[:]...)
+	}
+	return dir
+}
+
+// strips returns the deflate compressed strips of the image as unassociated RGBA
+func strips(img image.Image) ([][]byte, error) {
+	b := img.Bounds()
+	var (
+		width  = b.Dx()
+		out    [][]byte
+		row    = make([]byte, 4*width)
+		rgba   = func(x, y int) color.NRGBA { return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA) }
+		nrgba  *image.NRGBA
+		isNRGB bool
+	)
+	nrgba, isNRGB = img.(*image.NRGBA)
+	for y := b.Min.Y; y < b.Max.Y; y += RowsPerStrip {
+		var buff bytes.Buffer
+		zw, err := zlib.NewWriterLevel(&buff, zlib.DefaultCompression)
+		if err != nil {
+			return nil, err
+		}
+		for sy := y; sy < y+RowsPerStrip && sy < b.Max.Y; sy++ {
+			if isNRGB {
+				start := nrgba.PixOffset(b.Min.X, sy)
+				copy(row, nrgba.Pix[start:start+4*width])
+			} else {
+				for x := 0; x < width; x++ {
+					c := rgba(b.Min.X+x, sy)
+					row[4*x], row[4*x+1], row[4*x+2], row[4*x+3] = c.R, c.G, c.B, c.A
+				}
+			}
+			if _, err = zw.Write(row); err != nil {
+				return nil, err
+			}
+		}
+		if err = zw.Close(); err != nil {
+			return nil, err
+		}
+		out = append(out, buff.Bytes())
+	}
+	return out, nil
+}
+
+// Encode writes the image to w as a GeoTIFF
+func Encode(w io.Writer, img image.Image, geo Georeference) error {
+	b := img.Bounds()
+	if b.Dx() <= 0 || b.Dy() <= 0 {
+		return ErrEmptyImage
+	}
+	if geo.PixelSize[0] <= 0 || geo.PixelSize[1] <= 0 {
+		return ErrInvalidPixelSize
+	}
+	if geo.EPSG == 0 || geo.EPSG > math.MaxUint16 {
+		return ErrUnsupportedEPSG(geo.EPSG)
+	}
+
+	data, err := strips(img)
+	if err != nil {
+		return err
+	}
+
+	var (
+		counts  = make([]uint32, len(data))
+		offsets = make([]uint32, len(data))
+	)
+	for i := range data {
+		counts[i] = uint32(len(data[i]))
+	}
+
+	fields := []field{
+		{tag: tagImageWidth, dtype: dtLong, longs: []uint32{uint32(b.Dx())}},
+		{tag: tagImageLength, dtype: dtLong, longs: []uint32{uint32(b.Dy())}},
+		{tag: tagBitsPerSample, dtype: dtShort, shorts: []uint16{8, 8, 8, 8}},
+		{tag: tagCompression, dtype: dtShort, shorts: []uint16{compressionAdobeFlate}},
+		{tag: tagPhotometric, dtype: dtShort, shorts: []uint16{photometricRGB}},
+		{tag: tagStripOffsets, dtype: dtLong, longs: offsets},
+		{tag: tagSamplesPerPixel, dtype: dtShort, shorts: []uint16{4}},
+		{tag: tagRowsPerStrip, dtype: dtLong, longs: []uint32{RowsPerStrip}},
+		{tag: tagStripByteCounts, dtype: dtLong, longs: counts},
+		{tag: tagPlanarConfig, dtype: dtShort, shorts: []uint16{1}},
+		{tag: tagExtraSamples, dtype: dtShort, shorts: []uint16{extraSampleUnassocAlp}},
+		{tag: tagModelPixelScale, dtype: dtDouble, doubles: []float64{geo.PixelSize[0], geo.PixelSize[1], 0}},
+		{tag: tagModelTiepoint, dtype: dtDouble, doubles: []float64{0, 0, 0, geo.Origin[0], geo.Origin[1], 0}},
+		{tag: tagGeoKeyDirectory, dtype: dtShort, shorts: geoKeys(geo.EPSG)},
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].tag < fields[j].tag })
+
+	// layout: header, ifd, values that don't fit in an entry, strips
+	const headerSize = 8
+	ifdSize := 2 + 12*len(fields) + 4
+	extraOffset := uint32(headerSize + ifdSize)
+	extra := extraOffset
+	for _, f := range fields {
+		if f.size() > 4 {
+			extra += uint32(f.size())
+			// values must start on a word boundary
+			extra += extra % 2
+		}
+	}
+	for i := range data {
+		offsets[i] = extra
+		extra += counts[i]
+	}
+
+	var buff bytes.Buffer
+	buff.WriteString("II")
+	binary.Write(&buff, binary.LittleEndian, uint16(42))
+	binary.Write(&buff, binary.LittleEndian, uint32(headerSize))
+
+	binary.Write(&buff, binary.LittleEndian, uint16(len(fields)))
+	var values bytes.Buffer
+	for _, f := range fields {
+		binary.Write(&buff, binary.LittleEndian, f.tag)
+		binary.Write(&buff, binary.LittleEndian, f.dtype)
+		binary.Write(&buff, binary.LittleEndian, uint32(f.count()))
+		val := f.value()
+		if len(val) <= 4 {
+			var entry [4]byte
+			copy(entry[:], val)
+			buff.Write(entry[:])
+			continue
+		}
+		binary.Write(&buff, binary.LittleEndian, extraOffset+uint32(values.Len()))
+		values.Write(val)
+		if values.Len()%2 == 1 {
+			values.WriteByte(0)
+		}
+	}
+	// no next ifd
+	binary.Write(&buff, binary.LittleEndian, uint32(0))
+	buff.Write(values.Bytes())
+
+	if _, err = w.Write(buff.Bytes()); err != nil {
+		return err
+	}
+	for i := range data {
+		if _, err = w.Write(data[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package geotiff
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// readTags reads the first ifd of the tiff, returning the values of the tags
+func readTags(t *testing.T, data []byte) map[uint16][]float64 {
+	t.Helper()
+	if string(data[:2]) != "II" || binary.LittleEndian.Uint16(data[2:]) != 42 {
+		t.Fatalf("header, expected little endian tiff got %v", data[:4])
+	}
+	le := binary.LittleEndian
+	off := le.Uint32(data[4:])
+	n := int(le.Uint16(data[off:]))
+	tags := make(map[uint16][]float64, n)
+	for i := 0; i < n; i++ {
+		entry := data[int(off)+2+12*i:]
+		tag, dtype, count := le.Uint16(entry), le.Uint16(entry[2:]), int(le.Uint32(entry[4:]))
+		size := map[uint16]int{dtShort: 2, dtLong: 4, dtDouble: 8}[dtype]
+		val := entry[8:12]
+		if size*count > 4 {
+			val = data[le.Uint32(entry[8:]):]
+		}
+		vals := make([]float64, count)
+		for j := range vals {
+			switch dtype {
+			case dtShort:
+				vals[j] = float64(le.Uint16(val[2*j:]))
+			case dtLong:
+				vals[j] = float64(le.Uint32(val[4*j:]))
+			case dtDouble:
+				vals[j] = math.Float64frombits(le.Uint64(val[8*j:]))
+			}
+		}
+		tags[tag] = vals
+	}
+	return tags
+}
+
+func TestEncode(t *testing.T) {
+	type tcase struct {
+		width, height int
+		geo           Georeference
+		geoKeys       []float64
+		err           error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%vx%v@%v", tc.width, tc.height, tc.geo.EPSG), func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, tc.width, tc.height))
+			for y := 0; y < tc.height; y++ {
+				for x := 0; x < tc.width; x++ {
+					img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+				}
+			}
+			var buff bytes.Buffer
+			err := Encode(&buff, img, tc.geo)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			data := buff.Bytes()
+			tags := readTags(t, data)
+
+			if got := tags[tagImageWidth]; len(got) != 1 || int(got[0]) != tc.width {
+				t.Errorf("width, expected %v got %v", tc.width, got)
+			}
+			if got := tags[tagImageLength]; len(got) != 1 || int(got[0]) != tc.height {
+				t.Errorf("height, expected %v got %v", tc.height, got)
+			}
+			scale := []float64{tc.geo.PixelSize[0], tc.geo.PixelSize[1], 0}
+			if got := tags[tagModelPixelScale]; !reflect.DeepEqual(got, scale) {
+				t.Errorf("pixel scale, expected %v got %v", scale, got)
+			}
+			tiepoint := []float64{0, 0, 0, tc.geo.Origin[0], tc.geo.Origin[1], 0}
+			if got := tags[tagModelTiepoint]; !reflect.DeepEqual(got, tiepoint) {
+				t.Errorf("tiepoint, expected %v got %v", tiepoint, got)
+			}
+			if got := tags[tagGeoKeyDirectory]; !reflect.DeepEqual(got, tc.geoKeys) {
+				t.Errorf("geokeys, expected %v got %v", tc.geoKeys, got)
+			}
+
+			// decode the pixels back out
+			offsets, counts := tags[tagStripOffsets], tags[tagStripByteCounts]
+			if len(offsets) != (tc.height+RowsPerStrip-1)/RowsPerStrip {
+				t.Fatalf("strips, expected %v got %v", (tc.height+RowsPerStrip-1)/RowsPerStrip, len(offsets))
+			}
+			var pix []byte
+			for i := range offsets {
+				zr, err := zlib.NewReader(bytes.NewReader(data[int(offsets[i]) : int(offsets[i])+int(counts[i])]))
+				if err != nil {
+					t.Fatalf("strip %v, expected nil got %v", i, err)
+				}
+				p, err := ioutil.ReadAll(zr)
+				if err != nil {
+					t.Fatalf("strip %v, expected nil got %v", i, err)
+				}
+				pix = append(pix, p...)
+			}
+			if !bytes.Equal(pix, img.Pix) {
+				t.Errorf("pixels, expected decoded pixels to match")
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			width: 10, height: 10,
+			geo: Georeference{
+				EPSG:      EPSG3857,
+				Origin:    [2]float64{-13052768.0, 3857382.0},
+				PixelSize: [2]float64{2.5, 2.5},
+			},
+			geoKeys: []float64{1, 1, 0, 4, 1024, 0, 1, 1, 1025, 0, 1, 1, 3072, 0, 1, 3857, 3076, 0, 1, 9001},
+		},
+		{
+			width: 200, height: 150,
+			geo: Georeference{
+				EPSG:      EPSG4326,
+				Origin:    [2]float64{-117.25, 32.75},
+				PixelSize: [2]float64{0.00125, 0.00125},
+			},
+			geoKeys: []float64{1, 1, 0, 4, 1024, 0, 1, 2, 1025, 0, 1, 1, 2048, 0, 1, 4326, 2054, 0, 1, 9102},
+		},
+		{
+			width: 0, height: 10,
+			geo: Georeference{EPSG: EPSG3857, PixelSize: [2]float64{1, 1}},
+			err: ErrEmptyImage,
+		},
+		{
+			width: 10, height: 10,
+			geo: Georeference{EPSG: EPSG3857},
+			err: ErrInvalidPixelSize,
+		},
+		{
+			width: 10, height: 10,
+			geo: Georeference{PixelSize: [2]float64{1, 1}},
+			err: ErrUnsupportedEPSG(0),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -174,3 +174,50 @@ func VisitReader(location *url.URL, fn func(io.Reader) error) error {
 	defer r.Close()
 	return fn(r)
 }
+
+// Exists returns an error if the resource at the location can not be reached. A local
+// file must exist, and a remote resource must answer a HEAD request without an error status.
+func Exists(location *url.URL) error {
+	if location == nil {
+		return errors.New("nil url provided")
+	}
+	switch strings.ToLower(location.Scheme) {
+	case "", "file":
+
+		filename := location.EscapedPath()
+		if _, err := os.Stat(filename); err != nil {
+			if os.IsNotExist(err) {
+				return ErrFileNotExists{Filename: filename, Err: err}
+			}
+			return ErrFile{Filename: filename, Err: err}
+		}
+		return nil
+
+	case "http", "https":
+
+		var httpClient = &http.Client{
+			Timeout: time.Duration(remoteTimeout) * time.Second,
+		}
+
+		res, err := httpClient.Head(location.String())
+		if err != nil {
+			return ErrRemoteFile{
+				Location: location,
+				Err:      err,
+			}
+		}
+		res.Body.Close()
+		if res.StatusCode >= http.StatusBadRequest {
+			return ErrRemoteFile{
+				Location: location,
+				Err:      errors.New(res.Status),
+			}
+		}
+		return nil
+
+	default:
+
+		return ErrUnsupportedScheme{Location: location}
+
+	}
+}
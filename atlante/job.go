@@ -4,6 +4,8 @@ package atlante
 
 import (
 	"encoding/base64"
+	"strconv"
+	"strings"
 
 	"github.com/go-spatial/atlante/atlante/grids"
 	"github.com/gogo/protobuf/proto"
@@ -23,6 +25,35 @@ func NewJob(sheet string, cell *grids.Cell, metadata map[string]string) *Job {
 	}
 }
 
+const (
+	// MetaDataKeyPriority is the job metadata key for the priority of the job; jobs with a
+	// higher priority are run first
+	MetaDataKeyPriority = "priority"
+	// MetaDataKeyRequester is the job metadata key for who requested the job, e.g. the
+	// api key of the client; queues share the runners fairly between requesters
+	MetaDataKeyRequester = "requester"
+)
+
+// Priority returns the priority of the job, 0 if it does not have one
+func (j *Job) Priority() int {
+	if j == nil {
+		return 0
+	}
+	priority, err := strconv.Atoi(strings.TrimSpace(j.MetaData[MetaDataKeyPriority]))
+	if err != nil {
+		return 0
+	}
+	return priority
+}
+
+// Requester returns who requested the job, "" if it is not known
+func (j *Job) Requester() string {
+	if j == nil {
+		return ""
+	}
+	return j.MetaData[MetaDataKeyRequester]
+}
+
 // Base64Marshal returns the job encode in a based64 string
 func (j *Job) Base64Marshal() (string, error) {
 	// first marshal to pbf
@@ -26,6 +26,7 @@ type Job struct {
 	SheetName            string            `protobuf:"bytes,1,opt,name=sheet_name,json=sheetName,proto3" json:"sheet_name,omitempty"`
 	Cell                 *grids.Cell       `protobuf:"bytes,2,opt,name=cell,proto3" json:"cell,omitempty"`
 	MetaData             map[string]string `protobuf:"bytes,3,rep,name=meta_data,json=metaData,proto3" json:"meta_data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Pages                []*grids.Cell     `protobuf:"bytes,4,rep,name=pages,proto3" json:"pages,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -77,6 +78,13 @@ func (m *Job) GetMetaData() map[string]string {
 	return nil
 }
 
+func (m *Job) GetPages() []*grids.Cell {
+	if m != nil {
+		return m.Pages
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Job)(nil), "atlante.Job")
 	proto.RegisterMapType((map[string]string)(nil), "atlante.Job.MetaDataEntry")
@@ -85,18 +93,19 @@ func init() {
 func init() { proto.RegisterFile("job.proto", fileDescriptor_f32c477d91a04ead) }
 
 var fileDescriptor_f32c477d91a04ead = []byte{
-	// 208 bytes of a gzipped FileDescriptorProto
+	// 223 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcc, 0xca, 0x4f, 0xd2,
 	0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x4f, 0x2c, 0xc9, 0x49, 0xcc, 0x2b, 0x49, 0x95, 0x12,
-	0x48, 0x2f, 0xca, 0x4c, 0x29, 0xd6, 0x4f, 0x4e, 0xcd, 0xc9, 0x81, 0x48, 0x29, 0x1d, 0x66, 0xe4,
+	0x48, 0x2f, 0xca, 0x4c, 0x29, 0xd6, 0x4f, 0x4e, 0xcd, 0xc9, 0x81, 0x48, 0x29, 0xbd, 0x63, 0xe4,
 	0x62, 0xf6, 0xca, 0x4f, 0x12, 0x92, 0xe5, 0xe2, 0x2a, 0xce, 0x48, 0x4d, 0x2d, 0x89, 0xcf, 0x4b,
 	0xcc, 0x4d, 0x95, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0xe2, 0x04, 0x8b, 0xf8, 0x25, 0xe6, 0xa6,
 	0x0a, 0xa9, 0x73, 0xb1, 0x80, 0x34, 0x49, 0x30, 0x29, 0x30, 0x6a, 0x70, 0x1b, 0x09, 0xeb, 0x41,
 	0x0d, 0xd4, 0x03, 0x9b, 0xa7, 0xe7, 0x9c, 0x9a, 0x93, 0x13, 0x04, 0x56, 0x20, 0x64, 0xce, 0xc5,
 	0x99, 0x9b, 0x5a, 0x92, 0x18, 0x9f, 0x92, 0x58, 0x92, 0x28, 0xc1, 0xac, 0xc0, 0xac, 0xc1, 0x6d,
 	0x24, 0x05, 0x57, 0xed, 0x95, 0x9f, 0xa4, 0xe7, 0x9b, 0x5a, 0x92, 0xe8, 0x92, 0x58, 0x92, 0xe8,
-	0x9a, 0x57, 0x52, 0x54, 0x19, 0xc4, 0x91, 0x0b, 0xe5, 0x4a, 0x59, 0x73, 0xf1, 0xa2, 0x48, 0x09,
-	0x09, 0x70, 0x31, 0x67, 0xa7, 0x56, 0x42, 0x9d, 0x02, 0x62, 0x0a, 0x89, 0x70, 0xb1, 0x96, 0x25,
-	0xe6, 0x94, 0xa6, 0x82, 0x5d, 0xc1, 0x19, 0x04, 0xe1, 0x58, 0x31, 0x59, 0x30, 0x26, 0xb1, 0x81,
-	0x3d, 0x63, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0x01, 0x5a, 0xd3, 0x92, 0xf4, 0x00, 0x00, 0x00,
+	0x9a, 0x57, 0x52, 0x54, 0x19, 0xc4, 0x91, 0x0b, 0xe5, 0x0a, 0x69, 0x72, 0xb1, 0x16, 0x24, 0xa6,
+	0xa7, 0x16, 0x4b, 0xb0, 0x28, 0x30, 0xe3, 0xb2, 0x02, 0xa2, 0x42, 0xca, 0x9a, 0x8b, 0x17, 0xc5,
+	0x14, 0x21, 0x01, 0x2e, 0xe6, 0xec, 0xd4, 0x4a, 0xa8, 0xab, 0x41, 0x4c, 0x21, 0x11, 0x2e, 0xd6,
+	0xb2, 0xc4, 0x9c, 0xd2, 0x54, 0xb0, 0x83, 0x39, 0x83, 0x20, 0x1c, 0x2b, 0x26, 0x0b, 0xc6, 0x24,
+	0x36, 0xb0, 0xbf, 0x8d, 0x01, 0x03, 0x00, 0x64, 0x56, 0x8a, 0xe1, 0x1f, 0x01, 0x00, 0x00,
 }
@@ -9,6 +9,7 @@ import (
 	"text/template"
 
 	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/atlante/atlante/notifiers"
 	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
 	"github.com/prometheus/common/log"
@@ -46,6 +47,9 @@ func initFunc(cfg notifiers.Config) (notifiers.Provider, error) {
 
 func init() {
 	notifiers.Register(TYPE, initFunc, nil)
+	notifiers.RegisterSchema(TYPE, config.Schema{
+		Optional: []string{ConfigKeyContentType, ConfigKeyURLTemplate},
+	})
 }
 
 type Provider struct {
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/tegola/dict"
 )
 
@@ -45,6 +46,7 @@ type funcs struct {
 var (
 	notifiersLock sync.RWMutex
 	notifiers     map[string]funcs
+	schemas       map[string]config.Schema
 )
 
 // Register is called by the init functions of the provider
@@ -65,6 +67,36 @@ func Register(notifierType string, init InitFunc, cleanup CleanupFunc) error {
 	return nil
 }
 
+// RegisterSchema is called by the init functions of the providers to describe the config keys
+// of the notifier; it is used to validate the config.
+func RegisterSchema(notifierType string, schema config.Schema) {
+	notifiersLock.Lock()
+	defer notifiersLock.Unlock()
+	if schemas == nil {
+		schemas = make(map[string]config.Schema)
+	}
+	schemas[notifierType] = schema
+}
+
+// SchemaFor returns the config schema of the notifier type, nil if the type did not register
+// one, and weather the type is registered
+func SchemaFor(notifierType string) (*config.Schema, bool) {
+	notifiersLock.RLock()
+	defer notifiersLock.RUnlock()
+	if _, ok := notifiers[notifierType]; !ok {
+		return nil, false
+	}
+	schema, ok := schemas[notifierType]
+	if !ok {
+		return nil, true
+	}
+	return &schema, true
+}
+
+func init() {
+	config.RegisterSchemas(config.SectionNotifier, SchemaFor)
+}
+
 // Unregister will remove a notifier and call it's clean up function
 func Unregister(notifierType string) {
 	notifiersLock.Lock()
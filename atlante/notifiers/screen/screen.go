@@ -5,6 +5,7 @@ package screen
 
 import (
 	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/atlante/atlante/notifiers"
 	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
 	"github.com/prometheus/common/log"
@@ -21,6 +22,7 @@ func initFunc(cfg notifiers.Config) (notifiers.Provider, error) {
 
 func init() {
 	notifiers.Register(TYPE, initFunc, nil)
+	notifiers.RegisterSchema(TYPE, config.Schema{})
 }
 
 // Provider supports the notifier Provider interface
@@ -57,6 +59,10 @@ func (e *emitter) Emit(se field.StatusEnum) error {
 		logger.Infof("job failed: %v , err: %v", s.Description, s.Error)
 	case field.Completed:
 		logger.Infof("job compleated")
+	case field.Cancelled:
+		logger.Infoln("job cancelled")
+	case field.Retrying:
+		logger.Infof("job retrying, attempt %v, err: %v", s.Attempt, s.Error)
 	}
 	return nil
 }
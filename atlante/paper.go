@@ -0,0 +1,289 @@
+package atlante
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+)
+
+const (
+	// MetaDataKeyPaperSize is the job metadata key for the name of the paper size to use
+	MetaDataKeyPaperSize = "paper_size"
+	// MetaDataKeyOrientation is the job metadata key for the orientation of the paper
+	MetaDataKeyOrientation = "orientation"
+
+	// PaperSizeAuto will pick the smallest standard paper size that fits the grid at the
+	// scale of the sheet. It can be restricted to a family of paper sizes, e.g. "auto:a"
+	PaperSizeAuto = "auto"
+)
+
+// Orientation of the paper
+type Orientation uint8
+
+const (
+	// OrientationDefault is portrait for named paper sizes, and either for auto
+	OrientationDefault Orientation = iota
+	// Portrait is taller then it is wide
+	Portrait
+	// Landscape is wider then it is tall
+	Landscape
+)
+
+func (o Orientation) String() string {
+	switch o {
+	case Portrait:
+		return "portrait"
+	case Landscape:
+		return "landscape"
+	default:
+		return ""
+	}
+}
+
+// ParseOrientation parses the orientation name, an empty string is the default orientation
+func ParseOrientation(s string) (Orientation, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return OrientationDefault, nil
+	case "portrait":
+		return Portrait, nil
+	case "landscape":
+		return Landscape, nil
+	default:
+		return OrientationDefault, ErrUnknownOrientation(s)
+	}
+}
+
+// PaperSize is a standard paper size, the Width and Height are in mm in portrait orientation
+type PaperSize struct {
+	Name   string
+	Family string
+	Width  float64
+	Height float64
+}
+
+// Area of the paper in mm²
+func (ps PaperSize) Area() float64 { return ps.Width * ps.Height }
+
+// Oriented returns the width and height of the paper in the given orientation
+func (ps PaperSize) Oriented(o Orientation) (width, height float64) {
+	if o == Landscape {
+		return ps.Height, ps.Width
+	}
+	return ps.Width, ps.Height
+}
+
+// PaperSizes are the known standard paper sizes, from smallest to largest
+var PaperSizes = sortedPaperSizes(
+	PaperSize{Name: "a4", Family: "a", Width: 210, Height: 297},
+	PaperSize{Name: "a3", Family: "a", Width: 297, Height: 420},
+	PaperSize{Name: "a2", Family: "a", Width: 420, Height: 594},
+	PaperSize{Name: "a1", Family: "a", Width: 594, Height: 841},
+	PaperSize{Name: "a0", Family: "a", Width: 841, Height: 1189},
+	PaperSize{Name: "letter", Family: "ansi", Width: 215.9, Height: 279.4},
+	PaperSize{Name: "tabloid", Family: "ansi", Width: 279.4, Height: 431.8},
+	PaperSize{Name: "ansi-c", Family: "ansi", Width: 431.8, Height: 558.8},
+	PaperSize{Name: "ansi-d", Family: "ansi", Width: 558.8, Height: 863.6},
+	PaperSize{Name: "ansi-e", Family: "ansi", Width: 863.6, Height: 1117.6},
+	PaperSize{Name: "arch-a", Family: "arch", Width: 228.6, Height: 304.8},
+	PaperSize{Name: "arch-b", Family: "arch", Width: 304.8, Height: 457.2},
+	PaperSize{Name: "arch-c", Family: "arch", Width: 457.2, Height: 609.6},
+	PaperSize{Name: "arch-d", Family: "arch", Width: 609.6, Height: 914.4},
+	PaperSize{Name: "arch-e", Family: "arch", Width: 914.4, Height: 1219.2},
+)
+
+// paperSizeAliases are other names for the paper sizes
+var paperSizeAliases = map[string]string{
+	"ansi-a": "letter",
+	"ansi-b": "tabloid",
+}
+
+func sortedPaperSizes(sizes ...PaperSize) []PaperSize {
+	sort.SliceStable(sizes, func(i, j int) bool { return sizes[i].Area() < sizes[j].Area() })
+	return sizes
+}
+
+// normalizePaperName lower cases the name, and uses '-' as the separator; so "ANSI D" is "ansi-d"
+func normalizePaperName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(name)
+}
+
+// PaperSizeFor returns the standard paper size with the given name
+func PaperSizeFor(name string) (PaperSize, bool) {
+	name = normalizePaperName(name)
+	if alias, ok := paperSizeAliases[name]; ok {
+		name = alias
+	}
+	for _, ps := range PaperSizes {
+		if ps.Name == name {
+			return ps, true
+		}
+	}
+	return PaperSize{}, false
+}
+
+// isAutoPaperSize returns weather the name is auto, and the family to restrict the
+// paper sizes to
+func isAutoPaperSize(name string) (family string, ok bool) {
+	name = normalizePaperName(name)
+	if name == PaperSizeAuto {
+		return "", true
+	}
+	if !strings.HasPrefix(name, PaperSizeAuto+":") {
+		return "", false
+	}
+	return strings.TrimPrefix(name, PaperSizeAuto+":"), true
+}
+
+// ValidatePaperSize returns an error if the name is not empty, auto or a known paper size
+func ValidatePaperSize(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return nil
+	}
+	if family, ok := isAutoPaperSize(name); ok {
+		if family == "" {
+			return nil
+		}
+		for _, ps := range PaperSizes {
+			if ps.Family == family {
+				return nil
+			}
+		}
+		return ErrUnknownPaperSize(name)
+	}
+	if _, ok := PaperSizeFor(name); !ok {
+		return ErrUnknownPaperSize(name)
+	}
+	return nil
+}
+
+// GroundSize returns the width and height in meters of the grid
+func GroundSize(grid *grids.Cell) (width, height float64) {
+	sw, ne := grid.SW(), grid.NE()
+	latLen, lngLen := grids.CalculateSecLengths((sw[1] + ne[1]) / 2)
+	return math.Abs(ne[0]-sw[0]) * 3600 * lngLen, math.Abs(ne[1]-sw[1]) * 3600 * latLen
+}
+
+// AutoPaperSize returns the smallest paper size, of the family if one is given, that will fit the grid at the given scale
+// with a margin (in mm) on every side. If the orientation is the default, both orientations are tried.
+func AutoPaperSize(grid *grids.Cell, scale uint, margin float64, family string, orientation Orientation) (PaperSize, Orientation, error) {
+	if grid == nil {
+		return PaperSize{}, orientation, ErrNilGrid
+	}
+	if scale == 0 {
+		return PaperSize{}, orientation, ErrNoPaperSizeFits
+	}
+	gw, gh := GroundSize(grid)
+	// meters to mm at scale
+	width := gw*1000/float64(scale) + 2*margin
+	height := gh*1000/float64(scale) + 2*margin
+
+	orientations := []Orientation{orientation}
+	if orientation == OrientationDefault {
+		orientations = []Orientation{Portrait, Landscape}
+	}
+	for _, ps := range PaperSizes {
+		if family != "" && ps.Family != family {
+			continue
+		}
+		for _, o := range orientations {
+			w, h := ps.Oriented(o)
+			if width <= w && height <= h {
+				return ps, o, nil
+			}
+		}
+	}
+	return PaperSize{}, orientation, ErrNoPaperSizeFits
+}
+
+// PageSize is the size of the page a grid is rendered on
+type PageSize struct {
+	// Name of the paper size, empty if the width and height of the sheet are used
+	Name        string
+	Orientation Orientation
+	// Width and Height in mm
+	Width  float64
+	Height float64
+}
+
+// WidthInPoints returns the width in points given the dpi (dots per inch)
+func (ps PageSize) WidthInPoints(dpi uint) uint64 { return mmToPoint(ps.Width, dpi) }
+
+// HeightInPoints returns the height in points given the dpi (dots per inch)
+func (ps PageSize) HeightInPoints(dpi uint) uint64 { return mmToPoint(ps.Height, dpi) }
+
+// SetPaperSize sets the paper size and orientation of the sheet. For a named paper size the
+// Width and Height of the sheet are set to the paper size.
+func (sheet *Sheet) SetPaperSize(name string, orientation Orientation) error {
+	if err := ValidatePaperSize(name); err != nil {
+		return err
+	}
+	sheet.PaperSize = name
+	sheet.Orientation = orientation
+	if _, ok := isAutoPaperSize(name); ok || name == "" {
+		return nil
+	}
+	ps, _ := PaperSizeFor(name)
+	sheet.Width, sheet.Height = ps.Oriented(orientation)
+	return nil
+}
+
+// PageSizeFor returns the size of the page for the grid. The paper size and orientation in the metadata
+// of the grid (from the job) override the ones of the sheet.
+func (sheet *Sheet) PageSizeFor(grid *grids.Cell) (PageSize, error) {
+	var (
+		err         error
+		name        = sheet.PaperSize
+		orientation = sheet.Orientation
+	)
+	if md := grid.GetMetaData(); md != nil {
+		if v := strings.TrimSpace(md[MetaDataKeyPaperSize]); v != "" {
+			name = v
+		}
+		if v := strings.TrimSpace(md[MetaDataKeyOrientation]); v != "" {
+			if orientation, err = ParseOrientation(v); err != nil {
+				return PageSize{}, err
+			}
+		}
+	}
+
+	if name == "" {
+		width, height := sheet.Width, sheet.Height
+		if (orientation == Landscape && width < height) || (orientation == Portrait && width > height) {
+			width, height = height, width
+		}
+		if orientation == OrientationDefault {
+			orientation = Portrait
+			if width > height {
+				orientation = Landscape
+			}
+		}
+		return PageSize{Orientation: orientation, Width: width, Height: height}, nil
+	}
+
+	var ps PaperSize
+	if family, ok := isAutoPaperSize(name); ok {
+		ps, orientation, err = AutoPaperSize(grid, sheet.Scale, sheet.PaperMargin, family, orientation)
+		if err != nil {
+			return PageSize{}, err
+		}
+	} else {
+		var ok bool
+		if ps, ok = PaperSizeFor(name); !ok {
+			return PageSize{}, ErrUnknownPaperSize(name)
+		}
+		if orientation == OrientationDefault {
+			orientation = Portrait
+		}
+	}
+	width, height := ps.Oriented(orientation)
+	return PageSize{
+		Name:        ps.Name,
+		Orientation: orientation,
+		Width:       width,
+		Height:      height,
+	}, nil
+}
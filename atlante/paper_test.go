@@ -0,0 +1,181 @@
+package atlante
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+)
+
+func TestPaperSizeFor(t *testing.T) {
+	type tcase struct {
+		name     string
+		expected string
+		ok       bool
+		err      error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			ps, ok := PaperSizeFor(tc.name)
+			if ok != tc.ok {
+				t.Fatalf("ok, expected %v got %v", tc.ok, ok)
+			}
+			if ps.Name != tc.expected {
+				t.Errorf("name, expected %v got %v", tc.expected, ps.Name)
+			}
+			if err := ValidatePaperSize(tc.name); err != tc.err {
+				t.Errorf("validate, expected %v got %v", tc.err, err)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{name: "a0", expected: "a0", ok: true},
+		{name: "A4", expected: "a4", ok: true},
+		{name: "ANSI D", expected: "ansi-d", ok: true},
+		{name: "arch_e", expected: "arch-e", ok: true},
+		{name: "ansi-a", expected: "letter", ok: true},
+		{name: "ansi-b", expected: "tabloid", ok: true},
+		{name: "auto"},
+		{name: "auto:arch"},
+		{name: "auto:b", err: ErrUnknownPaperSize("auto:b")},
+		{name: "b5", err: ErrUnknownPaperSize("b5")},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestAutoPaperSize(t *testing.T) {
+	newCell := func(sw, ne [2]float64) *grids.Cell {
+		return grids.NewCell("V795G25492", sw, ne, "", "", nil, nil, time.Time{}, "", "", "V795", [2]string{}, [2]string{}, nil)
+	}
+	// sw, ne are lat, lng; 469mm x 554mm at 1:50,000
+	quad := newCell([2]float64{32.5, -117.25}, [2]float64{32.75, -117})
+	// 235mm x 554mm at 1:50,000
+	half := newCell([2]float64{32.5, -117.25}, [2]float64{32.75, -117.125})
+
+	type tcase struct {
+		grid        *grids.Cell
+		scale       uint
+		margin      float64
+		family      string
+		orientation Orientation
+		expected    string
+		expectedO   Orientation
+		err         error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		name := fmt.Sprintf("%v %v %v %v", tc.family, tc.orientation, tc.margin, tc.expected)
+		return name, func(t *testing.T) {
+			ps, o, err := AutoPaperSize(tc.grid, tc.scale, tc.margin, tc.family, tc.orientation)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if ps.Name != tc.expected {
+				t.Errorf("paper size, expected %v got %v", tc.expected, ps.Name)
+			}
+			if o != tc.expectedO {
+				t.Errorf("orientation, expected %v got %v", tc.expectedO, o)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{grid: quad, scale: 50000, expected: "ansi-d", expectedO: Portrait},
+		{grid: quad, scale: 50000, family: "a", expected: "a1", expectedO: Portrait},
+		{grid: quad, scale: 50000, family: "arch", orientation: Landscape, expected: "arch-d", expectedO: Landscape},
+		{grid: quad, scale: 50000, family: "a", margin: 50, expected: "a1", expectedO: Portrait},
+		{grid: quad, scale: 50000, family: "a", margin: 70, expected: "a0", expectedO: Portrait},
+		{grid: half, scale: 50000, expected: "ansi-c", expectedO: Portrait},
+		{grid: half, scale: 50000, orientation: Landscape, expected: "ansi-d", expectedO: Landscape},
+		{grid: quad, scale: 5000, err: ErrNoPaperSizeFits},
+		{grid: quad, err: ErrNoPaperSizeFits},
+		{err: ErrNilGrid},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestSheetPageSizeFor(t *testing.T) {
+	type tcase struct {
+		paperSize   string
+		orientation Orientation
+		metadata    map[string]string
+		expected    PageSize
+		err         error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		name := fmt.Sprintf("%v %v %v", tc.paperSize, tc.orientation, tc.metadata)
+		return name, func(t *testing.T) {
+			sheet := &Sheet{
+				Scale:  50000,
+				Width:  DefaultWidthMM,
+				Height: DefaultHeightMM,
+			}
+			if err := sheet.SetPaperSize(tc.paperSize, tc.orientation); err != nil {
+				t.Fatalf("set paper size, expected nil got %v", err)
+			}
+			grid := grids.NewCell("V795G25492", [2]float64{32.5, -117.25}, [2]float64{32.75, -117}, "", "", nil, nil, time.Time{}, "", "", "V795", [2]string{}, [2]string{}, nil)
+			grid.MetaData = tc.metadata
+			got, err := sheet.PageSizeFor(grid)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if got != tc.expected {
+				t.Errorf("page size, expected %+v got %+v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			expected: PageSize{Orientation: Portrait, Width: DefaultWidthMM, Height: DefaultHeightMM},
+		},
+		{
+			orientation: Landscape,
+			expected:    PageSize{Orientation: Landscape, Width: DefaultHeightMM, Height: DefaultWidthMM},
+		},
+		{
+			paperSize: "letter",
+			expected:  PageSize{Name: "letter", Orientation: Portrait, Width: 215.9, Height: 279.4},
+		},
+		{
+			paperSize:   "a3",
+			orientation: Landscape,
+			expected:    PageSize{Name: "a3", Orientation: Landscape, Width: 420, Height: 297},
+		},
+		{
+			paperSize: "a3",
+			metadata:  map[string]string{MetaDataKeyPaperSize: "auto:a"},
+			expected:  PageSize{Name: "a1", Orientation: Portrait, Width: 594, Height: 841},
+		},
+		{
+			paperSize: "auto",
+			metadata:  map[string]string{MetaDataKeyOrientation: "landscape"},
+			expected:  PageSize{Name: "ansi-d", Orientation: Landscape, Width: 863.6, Height: 558.8},
+		},
+		{
+			metadata: map[string]string{MetaDataKeyOrientation: "sideways"},
+			err:      ErrUnknownOrientation("sideways"),
+		},
+		{
+			metadata: map[string]string{MetaDataKeyPaperSize: "b5"},
+			err:      ErrUnknownPaperSize("b5"),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
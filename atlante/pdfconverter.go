@@ -0,0 +1,64 @@
+package atlante
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/go-spatial/atlante/svg2pdf"
+)
+
+const (
+	// DefaultPDFConverterType is the type of pdf converter used if the sheet does not have one
+	DefaultPDFConverterType = "cairo"
+)
+
+// PDFConverter converts the generated svgs to pdfs
+type PDFConverter interface {
+	// GeneratePDF converts the svg to a single page pdf, the width and height are in points
+	GeneratePDF(svgfn, pdffn string, width, height float64) error
+	// GenerateMultiPagePDF converts each of the svg pages into a page of the pdf
+	GenerateMultiPagePDF(pages []svg2pdf.Page, pdffn string) error
+}
+
+var (
+	pdfConvertersLock sync.RWMutex
+	pdfConverters     map[string]PDFConverter
+)
+
+// RegisterPDFConverter is called by the init functions of the pdf converters
+func RegisterPDFConverter(converterType string, converter PDFConverter) error {
+	pdfConvertersLock.Lock()
+	defer pdfConvertersLock.Unlock()
+
+	if pdfConverters == nil {
+		pdfConverters = make(map[string]PDFConverter)
+	}
+	if _, ok := pdfConverters[converterType]; ok {
+		return ErrPDFConverterExists(converterType)
+	}
+	pdfConverters[converterType] = converter
+	return nil
+}
+
+// RegisteredPDFConverters returns the types of the pdf converters that have been registered
+func RegisteredPDFConverters() []string {
+	pdfConvertersLock.RLock()
+	r := make([]string, 0, len(pdfConverters))
+	for k := range pdfConverters {
+		r = append(r, k)
+	}
+	pdfConvertersLock.RUnlock()
+	sort.Strings(r)
+	return r
+}
+
+// PDFConverterFor returns the pdf converter of the given type
+func PDFConverterFor(converterType string) (PDFConverter, error) {
+	pdfConvertersLock.RLock()
+	defer pdfConvertersLock.RUnlock()
+	converter, ok := pdfConverters[converterType]
+	if !ok {
+		return nil, ErrUnknownPDFConverter(converterType)
+	}
+	return converter, nil
+}
@@ -0,0 +1,30 @@
+// Package cairo converts the svgs of the sheets to pdfs with cairo and librsvg.
+package cairo
+
+import (
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/svg2pdf"
+	"github.com/go-spatial/atlante/svg2pdf/cairo"
+)
+
+const (
+	// TYPE of the pdf converter
+	TYPE = atlante.DefaultPDFConverterType
+)
+
+func init() {
+	atlante.RegisterPDFConverter(TYPE, Converter{})
+}
+
+// Converter converts the svgs to pdfs with cairo
+type Converter struct{}
+
+// GeneratePDF implements the atlante.PDFConverter interface
+func (Converter) GeneratePDF(svgfn, pdffn string, width, height float64) error {
+	return cairo.GeneratePDF(svgfn, pdffn, width, height)
+}
+
+// GenerateMultiPagePDF implements the atlante.PDFConverter interface
+func (Converter) GenerateMultiPagePDF(pages []svg2pdf.Page, pdffn string) error {
+	return cairo.GenerateMultiPagePDF(pages, pdffn)
+}
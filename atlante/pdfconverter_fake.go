@@ -0,0 +1,77 @@
+package atlante
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/go-spatial/atlante/svg2pdf"
+)
+
+const (
+	// FakePDFConverterType is the type of the fake pdf converter
+	FakePDFConverterType = "fake"
+)
+
+// FakePDFConverter writes a pdf with an empty page of the size of each svg, without
+// reading the svgs. It is used to test the sheets and the templates where cairo is
+// not available.
+type FakePDFConverter struct{}
+
+// GeneratePDF implements the PDFConverter interface
+func (FakePDFConverter) GeneratePDF(svgfn, pdffn string, width, height float64) error {
+	return FakePDFConverter{}.GenerateMultiPagePDF(
+		[]svg2pdf.Page{{Filename: svgfn, Width: width, Height: height}},
+		pdffn,
+	)
+}
+
+// GenerateMultiPagePDF implements the PDFConverter interface
+func (FakePDFConverter) GenerateMultiPagePDF(pages []svg2pdf.Page, pdffn string) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("error no pages")
+	}
+	for _, page := range pages {
+		if _, err := os.Stat(page.Filename); err != nil {
+			return err
+		}
+	}
+
+	// objects 1 and 2 are the catalog and the pages, followed by the info and the pages
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", i+4)
+	}
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [ %v ] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Producer (atlante fake pdf converter) >>",
+	}
+	for _, page := range pages {
+		objs = append(objs, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 %v %v ] >>",
+			page.Width, page.Height,
+		))
+	}
+
+	var buff bytes.Buffer
+	buff.WriteString("%PDF-1.5\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buff.Len()
+		fmt.Fprintf(&buff, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buff.Len()
+	fmt.Fprintf(&buff, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buff, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buff, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return ioutil.WriteFile(pdffn, buff.Bytes(), 0644)
+}
+
+func init() {
+	RegisterPDFConverter(FakePDFConverterType, FakePDFConverter{})
+}
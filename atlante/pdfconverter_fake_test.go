@@ -0,0 +1,118 @@
+package atlante
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/go-spatial/atlante/svg2pdf"
+)
+
+var mediabox = regexp.MustCompile(`/MediaBox\s+\[\s*0 0 (\S+) (\S+)\s*\]`)
+
+func TestFakePDFConverter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atlante_fake_pdf")
+	if err != nil {
+		t.Fatalf("temp dir, expected nil got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	svgfn := filepath.Join(dir, "page.svg")
+	if err = ioutil.WriteFile(svgfn, []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), 0644); err != nil {
+		t.Fatalf("write svg, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		name  string
+		pages []svg2pdf.Page
+		// expected are the width and height of each page
+		expected [][2]string
+		err      bool
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			pdffn := filepath.Join(dir, tc.name+".pdf")
+			converter, err := PDFConverterFor(FakePDFConverterType)
+			if err != nil {
+				t.Fatalf("converter, expected nil got %v", err)
+			}
+			err = converter.GenerateMultiPagePDF(tc.pages, pdffn)
+			if tc.err {
+				if err == nil {
+					t.Errorf("error, expected error got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			// the pdf should be readable by the svg2pdf post processing
+			boxes := make([]svg2pdf.PageBoxes, len(tc.pages))
+			if err = svg2pdf.SetPageBoxes(pdffn, boxes); err != nil {
+				t.Fatalf("set page boxes, expected nil got %v", err)
+			}
+			data, err := ioutil.ReadFile(pdffn)
+			if err != nil {
+				t.Fatalf("read pdf, expected nil got %v", err)
+			}
+			got := mediabox.FindAllSubmatch(data, -1)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("pages, expected %v got %v", len(tc.expected), len(got))
+			}
+			for i, size := range tc.expected {
+				if string(got[i][1]) != size[0] || string(got[i][2]) != size[1] {
+					t.Errorf("page %v, expected %v got %s %s", i, size, got[i][1], got[i][2])
+				}
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:     "single",
+			pages:    []svg2pdf.Page{{Filename: svgfn, Width: 720, Height: 720.5}},
+			expected: [][2]string{{"720", "720.5"}},
+		},
+		{
+			name: "multi",
+			pages: []svg2pdf.Page{
+				{Filename: svgfn, Width: 800, Height: 600},
+				{Filename: svgfn, Width: 612, Height: 792},
+			},
+			expected: [][2]string{{"800", "600"}, {"612", "792"}},
+		},
+		{
+			name: "no pages",
+			err:  true,
+		},
+		{
+			name:  "missing svg",
+			pages: []svg2pdf.Page{{Filename: filepath.Join(dir, "missing.svg"), Width: 1, Height: 1}},
+			err:   true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestRegisterPDFConverter(t *testing.T) {
+	err := RegisterPDFConverter(FakePDFConverterType, FakePDFConverter{})
+	if _, ok := err.(ErrPDFConverterExists); !ok {
+		t.Errorf("register, expected ErrPDFConverterExists got %v", err)
+	}
+	_, err = PDFConverterFor("unknown")
+	if _, ok := err.(ErrUnknownPDFConverter); !ok {
+		t.Errorf("pdf converter for, expected ErrUnknownPDFConverter got %v", err)
+	}
+	// without a pdf converter the sheet uses the default one, which is not registered here
+	if _, err = (&Sheet{}).pdfConverter(); err != ErrNoPDFConverter {
+		t.Errorf("sheet pdf converter, expected %v got %v", ErrNoPDFConverter, err)
+	}
+	converter, err := (&Sheet{PDFConverter: FakePDFConverter{}}).pdfConverter()
+	if err != nil || converter != (FakePDFConverter{}) {
+		t.Errorf("sheet pdf converter, expected fake got %v %v", converter, err)
+	}
+}
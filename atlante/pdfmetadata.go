@@ -0,0 +1,177 @@
+package atlante
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/go-spatial/atlante/svg2pdf"
+	"github.com/prometheus/common/log"
+)
+
+const (
+	// DefaultPDFTitle is the template for the title of the pdf if one is not given
+	DefaultPDFTitle = "{{if .Atlas}}{{.Atlas}}{{else}}{{.Sheet}} {{.MDGID}}{{end}}"
+	// DefaultPDFKeywords is the template for the keywords of the pdf if they are not given
+	DefaultPDFKeywords = "{{.Sheet}}, {{.MDGID}}, {{.Series}}, {{.Edition}}"
+
+	// pdfCreator is the creator of the pdf documents
+	pdfCreator = "atlante"
+)
+
+// PDFMetadata is the document metadata of the pdfs of a sheet. Title, Author, Subject and
+// Keywords are templates, executed with a PDFMetadataContext; Keywords are comma separated.
+type PDFMetadata struct {
+	Title    string
+	Author   string
+	Subject  string
+	Keywords string
+
+	// Series and Edition of the sheet
+	Series  string
+	Edition string
+
+	// PDFA will make the pdfs PDF/A-2b for archiving; the pdfs are checked once generated
+	PDFA bool
+}
+
+// PDFMetadataContext is the context the metadata templates are executed with
+type PDFMetadataContext struct {
+	// Sheet is the name of the sheet
+	Sheet string
+	// MDGID of the grid, empty for an atlas
+	MDGID string
+	// Atlas is the title of the atlas, empty if the pdf is not an atlas
+	Atlas string
+	// Pages is the number of pages in the pdf
+	Pages uint
+
+	Series  string
+	Edition string
+
+	// Generated is the time the pdf was generated
+	Generated time.Time
+	// JobID is the id of the job the pdf was generated for, if any
+	JobID string
+	// MetaData is the metadata of the job
+	MetaData map[string]string
+}
+
+// newPDFMetadataContext returns the context for a pdf of the grid
+func newPDFMetadataContext(sheet *Sheet, grid *grids.Cell) PDFMetadataContext {
+	md := grid.GetMetaData()
+	return PDFMetadataContext{
+		Sheet:     sheet.Name,
+		MDGID:     grid.GetMdgid().AsString(),
+		Pages:     1,
+		Series:    sheet.PDFMetadata.Series,
+		Edition:   sheet.PDFMetadata.Edition,
+		Generated: time.Now(),
+		JobID:     md["job_id"],
+		MetaData:  md,
+	}
+}
+
+// templates returns the templates of the metadata with the defaults filled in
+func (md PDFMetadata) templates(desc string) map[string]string {
+	tpls := map[string]string{
+		"title":    md.Title,
+		"author":   md.Author,
+		"subject":  md.Subject,
+		"keywords": md.Keywords,
+	}
+	if tpls["title"] == "" {
+		tpls["title"] = DefaultPDFTitle
+	}
+	if tpls["subject"] == "" {
+		tpls["subject"] = desc
+	}
+	if tpls["keywords"] == "" {
+		tpls["keywords"] = DefaultPDFKeywords
+	}
+	return tpls
+}
+
+// Validate checks the templates of the metadata parse
+func (md PDFMetadata) Validate() error {
+	for name, tpl := range md.templates("") {
+		if _, err := template.New(name).Option("missingkey=zero").Parse(tpl); err != nil {
+			return ErrPDFMetadataTemplate{Field: name, Err: err}
+		}
+	}
+	return nil
+}
+
+// Execute returns the document metadata of a pdf; desc is used as the subject if one is not given
+func (md PDFMetadata) Execute(desc string, mdc PDFMetadataContext) (svg2pdf.Metadata, error) {
+	values := make(map[string]string, 4)
+	for name, tpl := range md.templates(desc) {
+		t, err := template.New(name).Option("missingkey=zero").Parse(tpl)
+		if err != nil {
+			return svg2pdf.Metadata{}, ErrPDFMetadataTemplate{Field: name, Err: err}
+		}
+		var buff bytes.Buffer
+		if err = t.Execute(&buff, mdc); err != nil {
+			return svg2pdf.Metadata{}, ErrPDFMetadataTemplate{Field: name, Err: err}
+		}
+		values[name] = strings.TrimSpace(buff.String())
+	}
+
+	var keywords []string
+	for _, kw := range strings.Split(values["keywords"], ",") {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			keywords = append(keywords, kw)
+		}
+	}
+	return svg2pdf.Metadata{
+		Title:    values["title"],
+		Author:   values["author"],
+		Subject:  values["subject"],
+		Keywords: keywords,
+		Creator:  pdfCreator,
+		Created:  mdc.Generated,
+		Custom: map[string]string{
+			"Sheet":   mdc.Sheet,
+			"MDGID":   mdc.MDGID,
+			"Series":  mdc.Series,
+			"Edition": mdc.Edition,
+			"JobID":   mdc.JobID,
+		},
+	}, nil
+}
+
+// writePDFMetadata sets the document metadata of the pdf, and if the sheet is PDF/A checks the
+// pdf meets the requirements. The result of the check is emitted; a pdf that fails the check
+// is still delivered.
+func writePDFMetadata(ctx context.Context, sheet *Sheet, pdffn string, mdc PDFMetadataContext) error {
+	md, err := sheet.PDFMetadata.Execute(sheet.Desc, mdc)
+	if err != nil {
+		EmitError(ctx, "pdf metadata template failure", err)
+		return err
+	}
+	if err = svg2pdf.SetMetadata(pdffn, md, sheet.PDFMetadata.PDFA); err != nil {
+		log.Warnf("error setting pdf metadata: %v", err)
+		EmitError(ctx, "set pdf metadata failed", err)
+		return err
+	}
+	if !sheet.PDFMetadata.PDFA {
+		return nil
+	}
+	if err = svg2pdf.ValidatePDFAFile(pdffn); err != nil {
+		log.Warnf("pdf %v failed PDF/A validation: %v", pdffn, err)
+		Emit(ctx, field.Processing{
+			Description: fmt.Sprintf("warning: PDF/A validation failed: %v: %v", filepath.Base(pdffn), err),
+		})
+		return nil
+	}
+	Emit(ctx, field.Processing{
+		Description: fmt.Sprintf("PDF/A validation passed: %v", filepath.Base(pdffn)),
+	})
+	return nil
+}
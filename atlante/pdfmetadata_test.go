@@ -0,0 +1,196 @@
+package atlante
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/go-spatial/atlante/svg2pdf"
+)
+
+func TestPDFMetadataExecute(t *testing.T) {
+	type tcase struct {
+		name     string
+		md       PDFMetadata
+		mdc      PDFMetadataContext
+		expected svg2pdf.Metadata
+		err      bool
+	}
+
+	generated := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+	mdc := PDFMetadataContext{
+		Sheet:     "50k",
+		MDGID:     "V795G25492",
+		Pages:     1,
+		Series:    "V795",
+		Edition:   "2",
+		Generated: generated,
+		JobID:     "1234",
+		MetaData:  map[string]string{"style": "topo"},
+	}
+	atlas := mdc
+	atlas.MDGID, atlas.Atlas, atlas.Pages = "", "Northern Region", 12
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			if err := tc.md.Validate(); (err != nil) != tc.err {
+				t.Fatalf("validate, expected error %v got %v", tc.err, err)
+			}
+			got, err := tc.md.Execute("Topographic sheet", tc.mdc)
+			if (err != nil) != tc.err {
+				t.Fatalf("error, expected error %v got %v", tc.err, err)
+			}
+			if tc.err {
+				if _, ok := err.(ErrPDFMetadataTemplate); !ok {
+					t.Errorf("error, expected ErrPDFMetadataTemplate got %T", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("metadata, expected %+v got %+v", tc.expected, got)
+			}
+		}
+	}
+
+	custom := map[string]string{
+		"Sheet":   "50k",
+		"MDGID":   "V795G25492",
+		"Series":  "V795",
+		"Edition": "2",
+		"JobID":   "1234",
+	}
+	atlasCustom := make(map[string]string, len(custom))
+	for k, v := range custom {
+		atlasCustom[k] = v
+	}
+	atlasCustom["MDGID"] = ""
+
+	tests := []tcase{
+		{
+			name: "defaults",
+			mdc:  mdc,
+			expected: svg2pdf.Metadata{
+				Title:    "50k V795G25492",
+				Subject:  "Topographic sheet",
+				Keywords: []string{"50k", "V795G25492", "V795", "2"},
+				Creator:  "atlante",
+				Created:  generated,
+				Custom:   custom,
+			},
+		},
+		{
+			name: "atlas",
+			mdc:  atlas,
+			expected: svg2pdf.Metadata{
+				Title:    "Northern Region",
+				Subject:  "Topographic sheet",
+				Keywords: []string{"50k", "V795", "2"},
+				Creator:  "atlante",
+				Created:  generated,
+				Custom:   atlasCustom,
+			},
+		},
+		{
+			name: "templates",
+			md: PDFMetadata{
+				Title:    `{{.Series}} {{.MDGID}} edition {{.Edition}}`,
+				Author:   "Mapping Office",
+				Subject:  `{{index .MetaData "style"}} map, job {{.JobID}}`,
+				Keywords: `{{.Sheet}}, , {{.Generated.Format "2006"}}`,
+			},
+			mdc: mdc,
+			expected: svg2pdf.Metadata{
+				Title:    "V795 V795G25492 edition 2",
+				Author:   "Mapping Office",
+				Subject:  "topo map, job 1234",
+				Keywords: []string{"50k", "2020"},
+				Creator:  "atlante",
+				Created:  generated,
+				Custom:   custom,
+			},
+		},
+		{
+			name: "bad template",
+			md:   PDFMetadata{Title: "{{.Sheet"},
+			mdc:  mdc,
+			err:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+// recordingEmitter keeps the statuses emitted
+type recordingEmitter struct {
+	statuses []field.StatusEnum
+}
+
+func (re *recordingEmitter) Emit(status field.StatusEnum) error {
+	re.statuses = append(re.statuses, status)
+	return nil
+}
+
+// fontPDF returns a small pdf whose font is not embedded, so it is not PDF/A
+func fontPDF() []byte {
+	var buff bytes.Buffer
+	buff.WriteString("%PDF-1.5\n")
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [ 3 0 R ] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 612 792 ] >>",
+		"<< /Type /FontDescriptor /FontName /DejaVuSans >>",
+	}
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buff.Len()
+		fmt.Fprintf(&buff, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buff.Len()
+	fmt.Fprintf(&buff, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buff, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buff, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return buff.Bytes()
+}
+
+func TestWritePDFMetadataPDFAFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atlante_pdfa")
+	if err != nil {
+		t.Fatalf("temp dir, expected nil got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	pdffn := filepath.Join(dir, "sheet.pdf")
+	if err = ioutil.WriteFile(pdffn, fontPDF(), 0644); err != nil {
+		t.Fatalf("write pdf, expected nil got %v", err)
+	}
+
+	emitter := new(recordingEmitter)
+	sheet := &Sheet{
+		Name:        "50k",
+		PDFMetadata: PDFMetadata{PDFA: true},
+	}
+	ctx := WithEmitter(context.Background(), emitter)
+	if err = writePDFMetadata(ctx, sheet, pdffn, PDFMetadataContext{Sheet: "50k", MDGID: "V795G25492"}); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(emitter.statuses) != 1 {
+		t.Fatalf("statuses, expected 1 got %v", emitter.statuses)
+	}
+	status, ok := emitter.statuses[0].(field.Processing)
+	if !ok {
+		t.Fatalf("status, expected %T got %T", field.Processing{}, emitter.statuses[0])
+	}
+	if !strings.Contains(status.Description, "PDF/A validation failed") || !strings.Contains(status.Description, "not embedded") {
+		t.Errorf("description, expected the PDF/A problems got %q", status.Description)
+	}
+}
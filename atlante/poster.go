@@ -0,0 +1,445 @@
+package atlante
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	fsfile "github.com/go-spatial/atlante/atlante/filestore/file"
+	fsmulti "github.com/go-spatial/atlante/atlante/filestore/multi"
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/go-spatial/atlante/svg2pdf"
+	"github.com/prometheus/common/log"
+)
+
+const (
+	// MetaDataKeyPosterPaperSize is the job metadata key for the paper size of the pages
+	// of the poster
+	MetaDataKeyPosterPaperSize = "poster_paper_size"
+	// MetaDataKeyPosterOrientation is the job metadata key for the orientation of the
+	// pages of the poster
+	MetaDataKeyPosterOrientation = "poster_orientation"
+
+	// DefaultPosterOverlap is the default overlap, in mm, of adjoining pages of a poster
+	DefaultPosterOverlap = 10
+	// DefaultPosterMargin is the default margin, in mm, around each page of a poster
+	DefaultPosterMargin = 10
+)
+
+// PosterOptions describe how to split a sheet into smaller pages, so that it can be
+// printed on a smaller printer and assembled into a poster
+type PosterOptions struct {
+	// PaperSize is the name of the paper size of the pages, the sheet is not split
+	// if it is empty
+	PaperSize string
+	// Orientation of the pages, if it is the default the orientation needing the
+	// fewest pages is used
+	Orientation Orientation
+	// Overlap, in mm, of adjoining pages
+	Overlap float64
+	// Margin, in mm, around each page for the crop and registration marks
+	Margin float64
+}
+
+// ForGrid returns the options with the paper size and orientation from the metadata of
+// the grid (from the job) overriding the ones of the sheet
+func (opts PosterOptions) ForGrid(grid *grids.Cell) (PosterOptions, error) {
+	md := grid.GetMetaData()
+	if v := strings.TrimSpace(md[MetaDataKeyPosterPaperSize]); v != "" {
+		opts.PaperSize = v
+	}
+	if v := strings.TrimSpace(md[MetaDataKeyPosterOrientation]); v != "" {
+		o, err := ParseOrientation(v)
+		if err != nil {
+			return opts, err
+		}
+		opts.Orientation = o
+	}
+	return opts, nil
+}
+
+// PosterTile is a page of the poster
+type PosterTile struct {
+	// Name of the tile; the row as a letter, and the column as a number: A1, A2, B1...
+	Name string
+	// Number of the page, starting at 1
+	Number   uint
+	Row, Col uint
+	// X, Y, Width and Height is the region of the sheet, in points, on the tile
+	X, Y          float64
+	Width, Height float64
+}
+
+// PosterLayout is how the sheet is split into pages. All sizes are in points.
+type PosterLayout struct {
+	PaperSize   string
+	Orientation Orientation
+	Rows, Cols  uint
+
+	SheetWidth  float64
+	SheetHeight float64
+	PageWidth   float64
+	PageHeight  float64
+	Margin      float64
+	Overlap     float64
+
+	Tiles []PosterTile
+}
+
+// posterTileName returns the name of the tile; rows are lettered as in a spreadsheet
+func posterTileName(row, col uint) string {
+	var name []byte
+	for r := row + 1; r > 0; r = (r - 1) / 26 {
+		name = append([]byte{byte('A' + (r-1)%26)}, name...)
+	}
+	return string(name) + strconv.FormatUint(uint64(col+1), 10)
+}
+
+// posterTileCount returns the number of tiles needed to cover the length
+func posterTileCount(length, printable, overlap float64) uint {
+	if length <= printable {
+		return 1
+	}
+	return uint(math.Ceil((length - overlap) / (printable - overlap)))
+}
+
+// NewPosterLayout returns the layout of the pages for a sheet of the given width and height in points
+func NewPosterLayout(width, height float64, opts PosterOptions) (*PosterLayout, error) {
+	ps, ok := PaperSizeFor(opts.PaperSize)
+	if !ok {
+		return nil, ErrUnknownPaperSize(opts.PaperSize)
+	}
+	orientations := []Orientation{opts.Orientation}
+	if opts.Orientation == OrientationDefault {
+		orientations = []Orientation{Portrait, Landscape}
+	}
+	var (
+		best *PosterLayout
+		err  error
+	)
+	for _, o := range orientations {
+		var layout *PosterLayout
+		layout, err = newPosterLayout(width, height, ps, o, opts)
+		if err != nil {
+			continue
+		}
+		if best == nil || len(layout.Tiles) < len(best.Tiles) {
+			best = layout
+		}
+	}
+	if best == nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+func newPosterLayout(width, height float64, ps PaperSize, o Orientation, opts PosterOptions) (*PosterLayout, error) {
+	pw, ph := ps.Oriented(o)
+	layout := PosterLayout{
+		PaperSize:   ps.Name,
+		Orientation: o,
+		SheetWidth:  width,
+		SheetHeight: height,
+		PageWidth:   float64(mmToPoint(pw, 72)),
+		PageHeight:  float64(mmToPoint(ph, 72)),
+		Margin:      float64(mmToPoint(opts.Margin, 72)),
+		Overlap:     float64(mmToPoint(opts.Overlap, 72)),
+	}
+	printW, printH := layout.PageWidth-2*layout.Margin, layout.PageHeight-2*layout.Margin
+	if printW <= layout.Overlap || printH <= layout.Overlap {
+		return nil, ErrPosterOverlapTooLarge
+	}
+
+	layout.Cols = posterTileCount(width, printW, layout.Overlap)
+	layout.Rows = posterTileCount(height, printH, layout.Overlap)
+	layout.Tiles = make([]PosterTile, 0, layout.Rows*layout.Cols)
+	for r := uint(0); r < layout.Rows; r++ {
+		y := float64(r) * (printH - layout.Overlap)
+		for c := uint(0); c < layout.Cols; c++ {
+			x := float64(c) * (printW - layout.Overlap)
+			layout.Tiles = append(layout.Tiles, PosterTile{
+				Name:   posterTileName(r, c),
+				Number: uint(len(layout.Tiles)) + 1,
+				Row:    r,
+				Col:    c,
+				X:      x,
+				Y:      y,
+				Width:  math.Min(printW, width-x),
+				Height: math.Min(printH, height-y),
+			})
+		}
+	}
+	return &layout, nil
+}
+
+var (
+	svgRootRegex = regexp.MustCompile(`<svg[\s>/]`)
+	svgAttrRegex = regexp.MustCompile(`([^\s=]+)\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+// embeddableSVG returns the svg document so it can be nested in another svg document. The
+// prolog is removed, and the root element is given the width and height (in points) of the
+// sheet, and a view box if it does not have one.
+func embeddableSVG(svg []byte, width, height float64) ([]byte, error) {
+	loc := svgRootRegex.FindIndex(svg)
+	if loc == nil {
+		return nil, ErrInvalidSVG
+	}
+	start := loc[0]
+	// find the end of the start tag, skipping over quoted values
+	var (
+		end   = -1
+		quote byte
+	)
+	for i := start; i < len(svg) && end == -1; i++ {
+		switch c := svg[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			end = i
+		}
+	}
+	if end == -1 {
+		return nil, ErrInvalidSVG
+	}
+	selfClosing := svg[end-1] == '/'
+	tag := string(svg[start+len("<svg") : end])
+	if selfClosing {
+		tag = tag[:len(tag)-1]
+	}
+
+	var (
+		root       strings.Builder
+		hasViewBox bool
+	)
+	fmt.Fprintf(&root, `<svg width="%v" height="%v"`, width, height)
+	for _, attr := range svgAttrRegex.FindAllStringSubmatch(tag, -1) {
+		switch attr[1] {
+		case "width", "height", "x", "y":
+			continue
+		case "viewBox":
+			hasViewBox = true
+		}
+		fmt.Fprintf(&root, " %v=%v", attr[1], attr[2])
+	}
+	if !hasViewBox {
+		fmt.Fprintf(&root, ` viewBox="0 0 %v %v"`, width, height)
+	}
+	if selfClosing {
+		root.WriteString("/>")
+		return []byte(root.String()), nil
+	}
+	root.WriteString(">")
+
+	body := svg[end+1:]
+	if idx := strings.LastIndex(string(body), "</svg>"); idx != -1 {
+		body = body[:idx+len("</svg>")]
+	}
+	return append([]byte(root.String()), body...), nil
+}
+
+// posterTemplateContext is the context the poster key and tile templates are executed with
+type posterTemplateContext struct {
+	*PosterLayout
+	Title string
+	// Tile is the current tile, for the key page it is the zero value
+	Tile PosterTile
+	// SVG is the sheet, from embeddableSVG
+	SVG string
+}
+
+// KeyScale returns the scale the sheet is drawn at on the key page
+func (ptc posterTemplateContext) KeyScale() float64 {
+	w, h := ptc.PageWidth-2*ptc.Margin, ptc.PageHeight-2*ptc.Margin-posterKeyTitle
+	return math.Min(w/ptc.SheetWidth, h/ptc.SheetHeight)
+}
+
+// KeyFontSize returns the size of the tile names on the key page, in the units of the sheet
+func (ptc posterTemplateContext) KeyFontSize() float64 {
+	if len(ptc.Tiles) == 0 {
+		return 0
+	}
+	return math.Min(ptc.Tiles[0].Width, ptc.Tiles[0].Height) / 4
+}
+
+// KeyCellSize returns the size, in points, of a tile in the small key in the top margin of a tile page
+func (ptc posterTemplateContext) KeyCellSize() float64 {
+	return math.Max(1, math.Min(4, (ptc.Margin-8)/float64(ptc.Rows)))
+}
+
+// HasNeighbor returns weather the tile has a neighboring tile to the right or bottom
+func (ptc posterTemplateContext) HasNeighbor(dir string) bool {
+	switch dir {
+	case "right":
+		return ptc.Tile.Col+1 < ptc.Cols
+	case "bottom":
+		return ptc.Tile.Row+1 < ptc.Rows
+	}
+	return false
+}
+
+// size, in points, of the title of the key page
+const posterKeyTitle = 40
+
+var posterFuncs = template.FuncMap{
+	"escape": template.HTMLEscapeString,
+	"add":    func(a, b float64) float64 { return a + b },
+	"sub":    func(a, b float64) float64 { return a - b },
+	"mul":    func(a, b float64) float64 { return a * b },
+	"half":   func(a float64) float64 { return a / 2 },
+	"float":  func(a uint) float64 { return float64(a) },
+}
+
+// posterTileTemplate draws the region of the sheet for the tile with crop marks at the corners
+// of the printable area, registration marks in the middle of each margin, dashed lines where
+// the neighboring tiles go, and a small key of the tiles with the current one filled in.
+var posterTileTemplate = template.Must(template.New("tile").Funcs(posterFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.PageWidth}}pt" height="{{.PageHeight}}pt" viewBox="0 0 {{.PageWidth}} {{.PageHeight}}">
+  <rect x="0" y="0" width="{{.PageWidth}}" height="{{.PageHeight}}" fill="white"/>
+  <svg x="{{.Margin}}" y="{{.Margin}}" width="{{.Tile.Width}}" height="{{.Tile.Height}}" viewBox="{{.Tile.X}} {{.Tile.Y}} {{.Tile.Width}} {{.Tile.Height}}" overflow="hidden">
+{{.SVG}}
+  </svg>
+{{- $m := .Margin }}{{ $r := add .Margin .Tile.Width }}{{ $b := add .Margin .Tile.Height }}
+{{- $cx := add $m (half .Tile.Width) }}{{ $cy := add $m (half .Tile.Height) }}{{ $hm := half $m }}
+  <g stroke="gray" stroke-width="0.5" stroke-dasharray="4 2" fill="none">
+{{- if .HasNeighbor "right" }}
+    <path d="M {{sub $r .Overlap}} {{$m}} V {{$b}}"/>
+{{- end }}
+{{- if .HasNeighbor "bottom" }}
+    <path d="M {{$m}} {{sub $b .Overlap}} H {{$r}}"/>
+{{- end }}
+  </g>
+  <g stroke="black" stroke-width="0.5" fill="none">
+    <path d="M 0 {{$m}} H {{sub $m 3}} M {{$m}} 0 V {{sub $m 3}}"/>
+    <path d="M {{add $r 3}} {{$m}} H {{.PageWidth}} M {{$r}} 0 V {{sub $m 3}}"/>
+    <path d="M 0 {{$b}} H {{sub $m 3}} M {{$m}} {{add $b 3}} V {{.PageHeight}}"/>
+    <path d="M {{add $r 3}} {{$b}} H {{.PageWidth}} M {{$r}} {{add $b 3}} V {{.PageHeight}}"/>
+    <g transform="translate({{$cx}} {{$hm}})"><circle r="5"/><path d="M -8 0 H 8 M 0 -8 V 8"/></g>
+    <g transform="translate({{$cx}} {{add $b $hm}})"><circle r="5"/><path d="M -8 0 H 8 M 0 -8 V 8"/></g>
+    <g transform="translate({{$hm}} {{$cy}})"><circle r="5"/><path d="M -8 0 H 8 M 0 -8 V 8"/></g>
+    <g transform="translate({{add $r $hm}} {{$cy}})"><circle r="5"/><path d="M -8 0 H 8 M 0 -8 V 8"/></g>
+  </g>
+  <text x="{{$m}}" y="{{sub .PageHeight 4}}" font-family="sans-serif" font-size="7">{{escape .Title}} — {{.Tile.Name}} (page {{.Tile.Number}} of {{len .Tiles}})</text>
+  <g transform="translate({{sub .PageWidth $m}} 4)" stroke="black" stroke-width="0.25">
+{{- $size := .KeyCellSize }}{{ $cur := .Tile.Number }}{{ $cols := float .Cols }}
+{{- range .Tiles }}
+    <rect x="{{mul (sub (float .Col) $cols) $size}}" y="{{mul (float .Row) $size}}" width="{{$size}}" height="{{$size}}" fill="{{if eq .Number $cur}}black{{else}}white{{end}}"/>
+{{- end }}
+  </g>
+</svg>
+`))
+
+// posterKeyTemplate draws the whole sheet with the tiles outlined and named
+var posterKeyTemplate = template.Must(template.New("key").Funcs(posterFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.PageWidth}}pt" height="{{.PageHeight}}pt" viewBox="0 0 {{.PageWidth}} {{.PageHeight}}">
+  <rect x="0" y="0" width="{{.PageWidth}}" height="{{.PageHeight}}" fill="white"/>
+  <text x="{{.Margin}}" y="{{add .Margin 18}}" font-family="sans-serif" font-size="18">{{escape .Title}} — {{len .Tiles}} pages, {{.Rows}} rows by {{.Cols}} columns</text>
+  <text x="{{.Margin}}" y="{{add .Margin 32}}" font-family="sans-serif" font-size="9">Trim the top and left edges of each page at the crop marks, and line them up with the dashed lines of the page above and to the left.</text>
+  <g transform="translate({{.Margin}} {{add .Margin 40}}) scale({{.KeyScale}})">
+    <svg x="0" y="0" width="{{.SheetWidth}}" height="{{.SheetHeight}}" viewBox="0 0 {{.SheetWidth}} {{.SheetHeight}}" overflow="hidden">
+{{.SVG}}
+    </svg>
+    <g stroke="red" stroke-width="1" vector-effect="non-scaling-stroke" fill="none" font-family="sans-serif" font-size="{{.KeyFontSize}}">
+{{- range .Tiles }}
+      <rect x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}"/>
+      <text x="{{add .X (half .Width)}}" y="{{add .Y (half .Height)}}" text-anchor="middle" fill="red" stroke="none">{{.Name}}</text>
+{{- end }}
+    </g>
+  </g>
+</svg>
+`))
+
+// posterFilenames returns the filenames for the given page of the poster
+func posterFilenames(filenames *GeneratedFiles, name string) *GeneratedFiles {
+	return atlasPageFilenames(filenames, "poster_"+name)
+}
+
+// GeneratePoster will split the svg of the sheet into pages of the poster paper size, and
+// generate a single pdf of the pages; the first page is the key for assembling the pages.
+func GeneratePoster(ctx context.Context, sheet *Sheet, gtc *GridTemplateContext, opts PosterOptions, assetsWriter fsfile.Writer, multiWriter fsmulti.FileWriter, filenames *GeneratedFiles) error {
+	layout, err := NewPosterLayout(gtc.Width, gtc.Height, opts)
+	if err != nil {
+		EmitError(ctx, "poster layout failed", err)
+		return err
+	}
+
+	svg, err := ioutil.ReadFile(assetsWriter.Path(filenames.SVG))
+	if err != nil {
+		EmitError(ctx, "failed to read svg", err)
+		return err
+	}
+	svg, err = embeddableSVG(svg, gtc.Width, gtc.Height)
+	if err != nil {
+		EmitError(ctx, "failed to read svg", err)
+		return err
+	}
+
+	title := fmt.Sprintf("%v %v", sheet.Name, gtc.Grid.GetMdgid().AsString())
+	pages := make([]svg2pdf.Page, 0, len(layout.Tiles)+1)
+	writePage := func(name string, tpl *template.Template, tile PosterTile) error {
+		if ctx.Err() != nil {
+			EmitError(ctx, "generate poster canceled", ctx.Err())
+			return ctx.Err()
+		}
+		fn := posterFilenames(filenames, name).SVG
+		file, err := multiWriter.Writer(fn, true)
+		if err != nil {
+			EmitError(ctx, "failed to copy files", err)
+			return err
+		}
+		defer file.Close()
+		err = tpl.Execute(file, posterTemplateContext{
+			PosterLayout: layout,
+			Title:        title,
+			Tile:         tile,
+			SVG:          string(svg),
+		})
+		if err != nil {
+			EmitError(ctx, "template processing failure", err)
+			return err
+		}
+		pages = append(pages, svg2pdf.Page{
+			Filename: assetsWriter.Path(fn),
+			Width:    layout.PageWidth,
+			Height:   layout.PageHeight,
+		})
+		return nil
+	}
+
+	Emit(ctx, field.Processing{
+		Description: fmt.Sprintf("poster: %d %v pages", len(layout.Tiles), layout.PaperSize),
+	})
+	if err = writePage("key", posterKeyTemplate, PosterTile{}); err != nil {
+		return err
+	}
+	for _, tile := range layout.Tiles {
+		if err = writePage(tile.Name, posterTileTemplate, tile); err != nil {
+			return err
+		}
+	}
+
+	Emit(ctx, field.Processing{
+		Description: fmt.Sprintf("generate file: %v ", filenames.Poster),
+	})
+	converter, err := sheet.pdfConverter()
+	if err != nil {
+		EmitError(ctx, "generate poster pdf failed", err)
+		return err
+	}
+	if err = converter.GenerateMultiPagePDF(pages, assetsWriter.Path(filenames.Poster)); err != nil {
+		log.Warnf("error generating poster pdf: %v", err)
+		EmitError(ctx, "generate poster pdf failed", err)
+		return err
+	}
+	return copyToFilestore(ctx, sheet, assetsWriter, multiWriter, filenames.Poster)
+}
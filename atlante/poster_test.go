@@ -0,0 +1,200 @@
+package atlante
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNewPosterLayout(t *testing.T) {
+	type tcase struct {
+		width, height float64
+		opts          PosterOptions
+		orientation   Orientation
+		rows, cols    uint
+		err           error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return fmt.Sprintf("%vx%v %+v", tc.width, tc.height, tc.opts), func(t *testing.T) {
+			layout, err := NewPosterLayout(tc.width, tc.height, tc.opts)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if layout.Orientation != tc.orientation {
+				t.Errorf("orientation, expected %v got %v", tc.orientation, layout.Orientation)
+			}
+			if layout.Rows != tc.rows || layout.Cols != tc.cols {
+				t.Fatalf("rows x cols, expected %vx%v got %vx%v", tc.rows, tc.cols, layout.Rows, layout.Cols)
+			}
+			if len(layout.Tiles) != int(tc.rows*tc.cols) {
+				t.Fatalf("tiles, expected %v got %v", tc.rows*tc.cols, len(layout.Tiles))
+			}
+			printW, printH := layout.PageWidth-2*layout.Margin, layout.PageHeight-2*layout.Margin
+			for i, tile := range layout.Tiles {
+				if tile.Number != uint(i+1) {
+					t.Errorf("tile %v number, expected %v got %v", i, i+1, tile.Number)
+				}
+				if tile.Width > printW || tile.Height > printH {
+					t.Errorf("tile %v, expected to fit %vx%v got %vx%v", tile.Name, printW, printH, tile.Width, tile.Height)
+				}
+				// adjoining tiles should overlap
+				if tile.Col > 0 {
+					prev := layout.Tiles[i-1]
+					if got := prev.X + prev.Width - tile.X; got != layout.Overlap {
+						t.Errorf("tile %v overlap, expected %v got %v", tile.Name, layout.Overlap, got)
+					}
+				}
+			}
+			last := layout.Tiles[len(layout.Tiles)-1]
+			if last.X+last.Width != tc.width || last.Y+last.Height != tc.height {
+				t.Errorf("last tile, expected to end at %vx%v got %vx%v", tc.width, tc.height, last.X+last.Width, last.Y+last.Height)
+			}
+		}
+	}
+
+	a4 := PosterOptions{PaperSize: "a4", Overlap: DefaultPosterOverlap, Margin: DefaultPosterMargin}
+	a4Landscape := a4
+	a4Landscape.Orientation = Landscape
+
+	tests := []tcase{
+		// an A0 sheet
+		{width: 2384, height: 3370, opts: a4, orientation: Portrait, rows: 5, cols: 5},
+		{width: 2384, height: 3370, opts: a4Landscape, orientation: Landscape, rows: 7, cols: 4},
+		// a wide sheet is better on landscape pages
+		{width: 3370, height: 500, opts: a4, orientation: Landscape, rows: 1, cols: 5},
+		// fits on one page
+		{width: 500, height: 700, opts: a4, orientation: Portrait, rows: 1, cols: 1},
+		{
+			width: 2384, height: 3370,
+			opts: PosterOptions{PaperSize: "a4", Overlap: 150, Margin: 30},
+			err:  ErrPosterOverlapTooLarge,
+		},
+		{width: 2384, height: 3370, opts: PosterOptions{PaperSize: "auto"}, err: ErrUnknownPaperSize("auto")},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestPosterTileName(t *testing.T) {
+	tests := map[[2]uint]string{
+		{0, 0}:  "A1",
+		{1, 2}:  "B3",
+		{25, 0}: "Z1",
+		{26, 9}: "AA10",
+	}
+	for rc, expected := range tests {
+		if got := posterTileName(rc[0], rc[1]); got != expected {
+			t.Errorf("%v, expected %v got %v", rc, expected, got)
+		}
+	}
+}
+
+func TestEmbeddableSVG(t *testing.T) {
+	type tcase struct {
+		name     string
+		svg      string
+		expected string
+		err      error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			got, err := embeddableSVG([]byte(tc.svg), 200, 100)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if string(got) != tc.expected {
+				t.Errorf("svg, expected\n%s\ngot\n%s", tc.expected, got)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:     "prolog",
+			svg:      "<?xml version=\"1.0\"?>\n<!-- sheet -->\n<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"200pt\" height='100pt'><rect/></svg>\n",
+			expected: `<svg width="200" height="100" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 200 100"><rect/></svg>`,
+		},
+		{
+			name:     "view box",
+			svg:      `<svg viewBox="0 0 400 200" data-x="a>b" width="200"><g/></svg>`,
+			expected: `<svg width="200" height="100" viewBox="0 0 400 200" data-x="a>b"><g/></svg>`,
+		},
+		{
+			name:     "self closing",
+			svg:      `<svg xmlns="http://www.w3.org/2000/svg"/>`,
+			expected: `<svg width="200" height="100" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 200 100"/>`,
+		},
+		{
+			name: "not svg",
+			svg:  `<html></html>`,
+			err:  ErrInvalidSVG,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestPosterTemplates(t *testing.T) {
+	layout, err := NewPosterLayout(2384, 3370, PosterOptions{PaperSize: "a3", Overlap: DefaultPosterOverlap, Margin: DefaultPosterMargin})
+	if err != nil {
+		t.Fatalf("layout, expected nil got %v", err)
+	}
+	svg, err := embeddableSVG([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect width="10" height="10"/></svg>`), 2384, 3370)
+	if err != nil {
+		t.Fatalf("embed, expected nil got %v", err)
+	}
+	ptc := posterTemplateContext{
+		PosterLayout: layout,
+		Title:        "50k <V795G25492>",
+		SVG:          string(svg),
+	}
+
+	// wellFormed checks the generated svg is well formed xml
+	wellFormed := func(t *testing.T, out []byte) {
+		t.Helper()
+		dec := xml.NewDecoder(bytes.NewReader(out))
+		for {
+			_, err := dec.Token()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				t.Fatalf("xml, expected nil got %v\n%s", err, out)
+			}
+		}
+	}
+
+	var buff bytes.Buffer
+	if err = posterKeyTemplate.Execute(&buff, ptc); err != nil {
+		t.Fatalf("key, expected nil got %v", err)
+	}
+	wellFormed(t, buff.Bytes())
+	for _, tile := range layout.Tiles {
+		if !strings.Contains(buff.String(), ">"+tile.Name+"<") {
+			t.Errorf("key, expected tile %v", tile.Name)
+		}
+	}
+
+	for _, tile := range layout.Tiles {
+		buff.Reset()
+		ptc.Tile = tile
+		if err = posterTileTemplate.Execute(&buff, ptc); err != nil {
+			t.Fatalf("tile %v, expected nil got %v", tile.Name, err)
+		}
+		wellFormed(t, buff.Bytes())
+		viewBox := fmt.Sprintf(`viewBox="%v %v %v %v"`, tile.X, tile.Y, tile.Width, tile.Height)
+		if !strings.Contains(buff.String(), viewBox) {
+			t.Errorf("tile %v, expected %v", tile.Name, viewBox)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package atlante
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-spatial/atlante/svg2pdf"
+)
+
+const (
+	// cropMarkOffset is the gap, in points, between the bleed and the start of a crop mark
+	cropMarkOffset = 3
+	// cropMarkLength is the length, in points, of a crop mark
+	cropMarkLength = 18
+	// cropMarkSlug is the space, in points, added outside of the bleed for the crop and
+	// registration marks
+	cropMarkSlug = cropMarkOffset + cropMarkLength + 3
+)
+
+// Right returns the x coordinate of the right edge of the frame
+func (f Frame) Right() float64 { return f.X + f.Width }
+
+// Bottom returns the y coordinate of the bottom edge of the frame
+func (f Frame) Bottom() float64 { return f.Y + f.Height }
+
+// PrintBoxes are the regions of a page used by a printer, in points from the top left of the page
+type PrintBoxes struct {
+	// Media is the whole page; the trim, the bleed and the space for crop marks
+	Media Frame
+	// Bleed is the trim extended by the bleed margin; backgrounds should extend to it so
+	// there is no white edge if the page is not cut exactly on the trim
+	Bleed Frame
+	// Trim is the size of the page after it is cut; the paper size
+	Trim Frame
+	// Live is the trim less the live margin; text and other content that must not be cut
+	// should be kept in it
+	Live Frame
+}
+
+// printBoxes returns the boxes of a page of the given paper size for the bleed, live margin
+// and crop marks of the sheet. With none set all the boxes are the same as the page.
+func (sheet *Sheet) printBoxes(pageSize PageSize) PrintBoxes {
+	var (
+		width  = float64(pageSize.WidthInPoints(72))
+		height = float64(pageSize.HeightInPoints(72))
+		bleed  = float64(mmToPoint(sheet.Bleed, 72))
+		live   = float64(mmToPoint(sheet.LiveMargin, 72))
+		slug   float64
+	)
+	if sheet.CropMarks {
+		slug = cropMarkSlug
+	}
+	offset := slug + bleed
+	return PrintBoxes{
+		Media: Frame{Width: width + 2*offset, Height: height + 2*offset},
+		Bleed: Frame{X: slug, Y: slug, Width: width + 2*bleed, Height: height + 2*bleed},
+		Trim:  Frame{X: offset, Y: offset, Width: width, Height: height},
+		Live:  Frame{X: offset + live, Y: offset + live, Width: width - 2*live, Height: height - 2*live},
+	}
+}
+
+// HasBleed returns weather the page is larger than the trim
+func (pb PrintBoxes) HasBleed() bool { return pb.Media != pb.Trim }
+
+// pdfBox returns the frame as a box in pdf user space, which has the origin at the bottom left
+func (pb PrintBoxes) pdfBox(f Frame) svg2pdf.Box {
+	return svg2pdf.Box{f.X, pb.Media.Height - f.Bottom(), f.Right(), pb.Media.Height - f.Y}
+}
+
+// PageBoxes returns the bleed and trim boxes for the pdf page
+func (pb PrintBoxes) PageBoxes() svg2pdf.PageBoxes {
+	return svg2pdf.PageBoxes{
+		Bleed: pb.pdfBox(pb.Bleed),
+		Trim:  pb.pdfBox(pb.Trim),
+	}
+}
+
+// CropMarks returns an svg group with crop marks at the corners of the trim, just outside of
+// the bleed, and registration marks in the middle of each side of the slug.
+func (pb PrintBoxes) CropMarks() string {
+	var (
+		marks  strings.Builder
+		t, b   = pb.Trim, pb.Bleed
+		start  = float64(cropMarkOffset)
+		length = float64(cropMarkLength)
+		// the registration marks are in the middle of the slug
+		mid = b.X / 2
+	)
+	marks.WriteString(`<g class="crop-marks" stroke="black" stroke-width="0.25" fill="none">`)
+	for _, x := range []float64{t.X, t.Right()} {
+		for _, y := range []float64{t.Y, t.Bottom()} {
+			// horizontal marks go out from the left or right of the bleed, vertical from the top or bottom
+			hx, vy := b.X-start-length, b.Y-start-length
+			if x == t.Right() {
+				hx = b.Right() + start
+			}
+			if y == t.Bottom() {
+				vy = b.Bottom() + start
+			}
+			fmt.Fprintf(&marks, `<path d="M %v %v h %v M %v %v v %v"/>`, hx, y, length, x, vy, length)
+		}
+	}
+	for _, c := range [][2]float64{
+		{t.X + t.Width/2, mid},
+		{t.X + t.Width/2, b.Bottom() + mid},
+		{mid, t.Y + t.Height/2},
+		{b.Right() + mid, t.Y + t.Height/2},
+	} {
+		fmt.Fprintf(&marks, `<g transform="translate(%v %v)"><circle r="5"/><path d="M -8 0 H 8 M 0 -8 V 8"/></g>`, c[0], c[1])
+	}
+	marks.WriteString(`</g>`)
+	return marks.String()
+}
+
+// addCropMarks adds the crop marks to the end of the svg document, so they are drawn on top
+func addCropMarks(svg []byte, pb PrintBoxes) ([]byte, error) {
+	idx := bytes.LastIndex(svg, []byte("</svg>"))
+	if idx == -1 {
+		return nil, ErrInvalidSVG
+	}
+	marks := pb.CropMarks()
+	out := make([]byte, 0, len(svg)+len(marks)+1)
+	out = append(out, svg[:idx]...)
+	out = append(out, marks...)
+	out = append(out, '\n')
+	return append(out, svg[idx:]...), nil
+}
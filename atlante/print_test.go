@@ -0,0 +1,114 @@
+package atlante
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/go-spatial/atlante/svg2pdf"
+)
+
+func TestPrintBoxes(t *testing.T) {
+	type tcase struct {
+		sheet *Sheet
+		boxes PrintBoxes
+		pdf   svg2pdf.PageBoxes
+	}
+
+	// letter, 612 x 792 points
+	letter := PageSize{Name: "letter", Orientation: Portrait, Width: 215.9, Height: 279.4}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		name := fmt.Sprintf("bleed %v marks %v live %v", tc.sheet.Bleed, tc.sheet.CropMarks, tc.sheet.LiveMargin)
+		return name, func(t *testing.T) {
+			boxes := tc.sheet.printBoxes(letter)
+			if boxes != tc.boxes {
+				t.Errorf("boxes, expected %+v got %+v", tc.boxes, boxes)
+			}
+			if pdf := boxes.PageBoxes(); pdf != tc.pdf {
+				t.Errorf("pdf boxes, expected %v got %v", tc.pdf, pdf)
+			}
+			if has := tc.sheet.Bleed != 0 || tc.sheet.CropMarks; boxes.HasBleed() != has {
+				t.Errorf("has bleed, expected %v got %v", has, boxes.HasBleed())
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			sheet: &Sheet{},
+			boxes: PrintBoxes{
+				Media: Frame{Width: 612, Height: 792},
+				Bleed: Frame{Width: 612, Height: 792},
+				Trim:  Frame{Width: 612, Height: 792},
+				Live:  Frame{Width: 612, Height: 792},
+			},
+			pdf: svg2pdf.PageBoxes{
+				Bleed: svg2pdf.Box{0, 0, 612, 792},
+				Trim:  svg2pdf.Box{0, 0, 612, 792},
+			},
+		},
+		{
+			// 3mm bleed is 9 points, 5mm live margin is 14 points
+			sheet: &Sheet{Bleed: 3, LiveMargin: 5},
+			boxes: PrintBoxes{
+				Media: Frame{Width: 630, Height: 810},
+				Bleed: Frame{Width: 630, Height: 810},
+				Trim:  Frame{X: 9, Y: 9, Width: 612, Height: 792},
+				Live:  Frame{X: 23, Y: 23, Width: 584, Height: 764},
+			},
+			pdf: svg2pdf.PageBoxes{
+				Bleed: svg2pdf.Box{0, 0, 630, 810},
+				Trim:  svg2pdf.Box{9, 9, 621, 801},
+			},
+		},
+		{
+			sheet: &Sheet{Bleed: 3, CropMarks: true},
+			boxes: PrintBoxes{
+				Media: Frame{Width: 678, Height: 858},
+				Bleed: Frame{X: 24, Y: 24, Width: 630, Height: 810},
+				Trim:  Frame{X: 33, Y: 33, Width: 612, Height: 792},
+				Live:  Frame{X: 33, Y: 33, Width: 612, Height: 792},
+			},
+			pdf: svg2pdf.PageBoxes{
+				Bleed: svg2pdf.Box{24, 24, 654, 834},
+				Trim:  svg2pdf.Box{33, 33, 645, 825},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestAddCropMarks(t *testing.T) {
+	sheet := Sheet{Bleed: 3, CropMarks: true}
+	boxes := sheet.printBoxes(PageSize{Width: 215.9, Height: 279.4})
+
+	out, err := addCropMarks([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect/></svg>`+"\n"), boxes)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect/><g class="crop-marks"`)) {
+		t.Errorf("crop marks, expected to be added after the content got\n%s", out)
+	}
+	if !bytes.HasSuffix(out, []byte("</g>\n</svg>\n")) {
+		t.Errorf("crop marks, expected to be added before the end of the svg got\n%s", out)
+	}
+	dec := xml.NewDecoder(bytes.NewReader(out))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("xml, expected nil got %v\n%s", err, out)
+		}
+	}
+
+	if _, err = addCropMarks([]byte(`<html></html>`), boxes); err != ErrInvalidSVG {
+		t.Errorf("error, expected %v got %v", ErrInvalidSVG, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/batch"
 	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/config"
 	cfgaws "github.com/go-spatial/atlante/atlante/config/aws"
 	"github.com/go-spatial/atlante/atlante/internal/env"
 	"github.com/go-spatial/atlante/atlante/queuer"
@@ -37,10 +38,17 @@ const (
 	// DefaultJobObjectKey is the default that is used by if job_object_key
 	// is not set.
 	DefaultJobObjectKey = "job-data"
+
+	// CancelReason is the reason given to aws batch for jobs that are cancelled
+	CancelReason = "cancelled by atlante"
 )
 
 func init() {
 	queuer.Register(TYPE, initFunc, nil)
+	queuer.RegisterSchema(TYPE, config.Schema{
+		Required: []string{ConfigKeyJobQueue},
+		Optional: []string{ConfigKeyJobDefinition, ConfigKeyJobName, ConfigKeyJobParams, ConfigKeyJobObjectKey, cfgaws.ConfigKeyRegion, cfgaws.ConfigKeyEndPoint, cfgaws.ConfigKeyAWSAccessKeyID, cfgaws.ConfigKeyAWSSecretKey},
+	})
 }
 
 // Provider implements the queuer interface
@@ -188,3 +196,60 @@ func (p *Provider) Enqueue(key string, job *atlante.Job) (jobid string, err erro
 	}
 	*/
 }
+
+// statusFor returns the queue status for the aws batch job status
+func statusFor(status, reason string) queuer.Status {
+	switch status {
+	case batch.JobStatusSubmitted, batch.JobStatusPending, batch.JobStatusRunnable:
+		return queuer.Enqueded
+	case batch.JobStatusStarting:
+		return queuer.Started
+	case batch.JobStatusRunning:
+		return queuer.Processing
+	case batch.JobStatusSucceeded:
+		return queuer.Compleated
+	case batch.JobStatusFailed:
+		if reason == CancelReason {
+			return queuer.Cancelled
+		}
+		return queuer.Failed
+	default:
+		return queuer.Unknown
+	}
+}
+
+// Info returns the status of the job in aws batch
+func (p *Provider) Info(jobid string) queuer.Status {
+	result, err := p.Client.DescribeJobs(&batch.DescribeJobsInput{
+		Jobs: []*string{aws.String(jobid)},
+	})
+	if err != nil {
+		log.Warnf("Got the error describing job(%v): %v", jobid, err)
+		return queuer.Unknown
+	}
+	if len(result.Jobs) == 0 || result.Jobs[0] == nil {
+		return queuer.Unknown
+	}
+	job := result.Jobs[0]
+	return statusFor(aws.StringValue(job.Status), aws.StringValue(job.StatusReason))
+}
+
+// Cancel terminates the job in aws batch; jobs that have not started are cancelled, and
+// running jobs are stopped
+func (p *Provider) Cancel(jobid string) error {
+	switch p.Info(jobid) {
+	case queuer.Unknown:
+		return queuer.ErrUnknownJob
+	case queuer.Compleated, queuer.Failed, queuer.Cancelled:
+		return queuer.ErrJobFinished
+	}
+	_, err := p.Client.TerminateJob(&batch.TerminateJobInput{
+		JobId:  aws.String(jobid),
+		Reason: aws.String(CancelReason),
+	})
+	if err != nil {
+		log.Warnf("Got the error terminating job(%v): %v", jobid, err)
+		return err
+	}
+	return nil
+}
@@ -2,11 +2,14 @@ package local
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/atlante/atlante/queuer"
 	"github.com/prometheus/common/log"
 )
@@ -15,6 +18,16 @@ const (
 
 	// TYPE is the name of the provider
 	TYPE = "local"
+
+	// ConfigKeyMaxRunners is the config key for the max number of jobs to run at once
+	ConfigKeyMaxRunners = "max_runners"
+
+	// ConfigKeyMaxPerRequester is the config key for the max number of jobs of a
+	// requester to run at once
+	ConfigKeyMaxPerRequester = "max_per_requester"
+
+	// MaxFinishedJobs is the number of finished jobs whose status is kept for Info
+	MaxFinishedJobs = 1000
 )
 
 var (
@@ -25,25 +38,111 @@ func init() {
 	var cancel context.CancelFunc
 	globalCtx, cancel = context.WithCancel(context.Background())
 	queuer.Register(TYPE, initFunc, queuer.CleanupFunc(cancel))
+	queuer.RegisterSchema(TYPE, config.Schema{
+		Optional: append([]string{ConfigKeyMaxRunners, ConfigKeyMaxPerRequester}, queuer.RetryConfigKeys...),
+	})
 }
 
 type jobInfo struct {
 	jobid string
 	key   string
 	job   *atlante.Job
+	// attempt is the attempt of the job being run, starting at 1
+	attempt int
+
+	// priority and requester of the job, used by the scheduler
+	priority  int
+	requester string
+	seq       uint64
 }
 
 func (ji *jobInfo) Reset() {
 	ji.jobid = ""
 	ji.key = ""
 	ji.job = nil
+	ji.attempt = 0
+	ji.priority = 0
+	ji.requester = ""
+	ji.seq = 0
+}
+
+// jobState is the status of a job, and the cancel func of its context while it's running
+type jobState struct {
+	status queuer.Status
+	cancel func(cause error)
 }
 
 type Provider struct {
 	atlante     *atlante.Atlante
 	jobInfoPool sync.Pool
-	jobChannel  chan *jobInfo
+	sched       *scheduler
 	count       *uint32
+
+	jobsLock sync.Mutex
+	jobs     map[string]*jobState
+	// finished are the ids of the finished jobs, oldest first
+	finished []string
+
+	// Retry is the retry policy for failed jobs, the retry policy of the sheet of
+	// the job is used if it has one
+	Retry *atlante.RetryPolicy
+}
+
+// start marks the job as processing, and returns the context to run it with; false
+// if the job was cancelled before it started
+func (p *Provider) start(ctx context.Context, jobid string) (context.Context, func(cause error), bool) {
+	p.jobsLock.Lock()
+	defer p.jobsLock.Unlock()
+	state, ok := p.jobs[jobid]
+	if !ok {
+		state = new(jobState)
+		p.jobs[jobid] = state
+	}
+	if state.status == queuer.Cancelled {
+		return nil, nil, false
+	}
+	jobCtx, cancel := atlante.WithJobCancel(ctx)
+	state.status = queuer.Processing
+	state.cancel = cancel
+	return jobCtx, cancel, true
+}
+
+// finish records the final status of the job, a cancelled job stays cancelled. Only the
+// last MaxFinishedJobs finished jobs are kept.
+func (p *Provider) finish(jobid string, status queuer.Status) {
+	p.jobsLock.Lock()
+	defer p.jobsLock.Unlock()
+	state, ok := p.jobs[jobid]
+	if !ok {
+		state = new(jobState)
+		p.jobs[jobid] = state
+	}
+	if state.status != queuer.Cancelled {
+		state.status = status
+	}
+	state.cancel = nil
+	p.finished = append(p.finished, jobid)
+	if len(p.finished) > MaxFinishedJobs {
+		delete(p.jobs, p.finished[0])
+		p.finished = p.finished[1:]
+	}
+}
+
+// retry puts the job back in the queue once after has passed
+func (p *Provider) retry(ctx context.Context, ji *jobInfo, after time.Duration) {
+	p.jobsLock.Lock()
+	if state, ok := p.jobs[ji.jobid]; ok && state.status != queuer.Cancelled {
+		state.status = queuer.Enqueded
+		state.cancel = nil
+	}
+	p.jobsLock.Unlock()
+	ji.attempt++
+	time.AfterFunc(after, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		p.sched.push(ji)
+	})
 }
 
 func (p *Provider) jobRunner(ctx context.Context) {
@@ -55,47 +154,79 @@ func (p *Provider) jobRunner(ctx context.Context) {
 	log.Infof("jobRunner started")
 
 	for {
-		select {
-		case <-ctx.Done():
+		ji, ok = p.sched.next()
+		if !ok {
 			log.Infof("jobRunner got context cancel")
 			// we need to exit
 			return
-		case ji, ok = <-p.jobChannel:
-			if !ok {
-				log.Infof("jobRunner jobChannel closed")
-				return
-			}
-			if ji == nil {
-				log.Infof("jobRunner ji is nil")
-				continue
-			}
-			log.Infof("starting job(%v)", ji.jobid)
-			_, err = p.atlante.GeneratePDFJob(ctx, *(ji.job), "")
-			if err != nil {
-				log.Infof("Local runner job(%v) failed: %v", ji.jobid, err)
-			}
+		}
+		jobCtx, cancel, run := p.start(ctx, ji.jobid)
+		if !run {
+			log.Infof("skipping cancelled job(%v)", ji.jobid)
+			p.sched.done(ji.requester)
+			p.finish(ji.jobid, queuer.Cancelled)
 			p.jobInfoPool.Put(ji)
+			continue
+		}
+		log.Infof("starting job(%v) attempt %v", ji.jobid, ji.attempt)
+		// the retry is decided by RunJob, before the status of the failure is emitted
+		_, err = p.atlante.RunJob(jobCtx, *(ji.job), ji.attempt, p.Retry, "")
+		p.sched.done(ji.requester)
+		status := queuer.Compleated
+		var retry atlante.ErrRetryJob
+		switch {
+		case jobCtx.Err() == context.Canceled:
+			log.Infof("Local runner job(%v) cancelled", ji.jobid)
+			status = queuer.Cancelled
+		case errors.As(err, &retry):
+			log.Infof("Local runner job(%v) failed: %v, will be retried in %v", ji.jobid, retry.Err, retry.After)
+			cancel(nil)
+			p.retry(ctx, ji, retry.After)
+			continue
+		case err != nil:
+			log.Infof("Local runner job(%v) failed: %v", ji.jobid, err)
+			status = queuer.Failed
 		}
+		cancel(nil)
+		p.finish(ji.jobid, status)
+		p.jobInfoPool.Put(ji)
 	}
 }
 
 func initFunc(cfg queuer.Config, a *atlante.Atlante) (queuer.Provider, error) {
-	runners, _ := cfg.Int("max_runners", nil)
-	return NewProvider(globalCtx, a, runners), nil
+	runners, _ := cfg.Int(ConfigKeyMaxRunners, nil)
+	perRequester, _ := cfg.Int(ConfigKeyMaxPerRequester, nil)
+	if perRequester < 0 {
+		return nil, fmt.Errorf("local queue: %v must not be negative, got %v", ConfigKeyMaxPerRequester, perRequester)
+	}
+	retry, err := queuer.RetryPolicyFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	prv := NewProvider(globalCtx, a, runners, perRequester)
+	prv.Retry = retry
+	return prv, nil
 }
 
-func NewProvider(ctx context.Context, a *atlante.Atlante, runners int) *Provider {
+// NewProvider returns a provider that runs the jobs with the given number of runners;
+// at most maxPerRequester jobs of a requester are run at once, 0 for no limit
+func NewProvider(ctx context.Context, a *atlante.Atlante, runners int, maxPerRequester int) *Provider {
 	prv := &Provider{
 		atlante: a,
 		jobInfoPool: sync.Pool{
 			New: func() interface{} { return new(jobInfo) },
 		},
-		jobChannel: make(chan *jobInfo),
-		count:      new(uint32),
+		sched: newScheduler(maxPerRequester),
+		count: new(uint32),
+		jobs:  make(map[string]*jobState),
 	}
 	if runners <= 0 {
 		runners = 1
 	}
+	go func() {
+		<-ctx.Done()
+		prv.sched.close()
+	}()
 	for i := 0; i < runners; i++ {
 		go prv.jobRunner(ctx)
 	}
@@ -106,7 +237,7 @@ func (p *Provider) Enqueue(key string, job *atlante.Job) (jobid string, err erro
 	if p == nil {
 		return "", fmt.Errorf("nil provider")
 	}
-	if p.jobChannel == nil {
+	if p.sched == nil {
 		return "", fmt.Errorf("no queue available")
 	}
 	idNum := atomic.AddUint32(p.count, 1)
@@ -116,8 +247,47 @@ func (p *Provider) Enqueue(key string, job *atlante.Job) (jobid string, err erro
 	ji.jobid = jobid
 	ji.key = key
 	ji.job = job
-	log.Infof("enqueing job(%v)", ji.jobid)
-	p.jobChannel <- ji
+	ji.attempt = 1
+	ji.priority = job.Priority()
+	ji.requester = job.Requester()
+	log.Infof("enqueing job(%v) with priority %v for %q", ji.jobid, ji.priority, ji.requester)
+	p.jobsLock.Lock()
+	p.jobs[jobid] = &jobState{status: queuer.Enqueded}
+	p.jobsLock.Unlock()
+	p.sched.push(ji)
 
 	return jobid, nil
 }
+
+// Info returns the status of the job, Unknown if the job was not enqueued, or finished
+// more than MaxFinishedJobs jobs ago
+func (p *Provider) Info(jobid string) queuer.Status {
+	p.jobsLock.Lock()
+	defer p.jobsLock.Unlock()
+	state, ok := p.jobs[jobid]
+	if !ok {
+		return queuer.Unknown
+	}
+	return state.status
+}
+
+// Cancel cancels the job; a job that has not started will not be run, and the context
+// of a running job is cancelled
+func (p *Provider) Cancel(jobid string) error {
+	p.jobsLock.Lock()
+	defer p.jobsLock.Unlock()
+	state, ok := p.jobs[jobid]
+	if !ok {
+		return queuer.ErrUnknownJob
+	}
+	switch state.status {
+	case queuer.Compleated, queuer.Failed, queuer.Cancelled:
+		return queuer.ErrJobFinished
+	}
+	state.status = queuer.Cancelled
+	if state.cancel != nil {
+		state.cancel(atlante.ErrJobCancelled)
+	}
+	log.Infof("cancelled job(%v)", jobid)
+	return nil
+}
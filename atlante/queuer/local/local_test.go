@@ -0,0 +1,110 @@
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/queuer"
+)
+
+func TestCancel(t *testing.T) {
+	type tcase struct {
+		name    string
+		status  queuer.Status
+		running bool
+		err     error
+		// status after the cancel, and the runner finishing the job
+		expected queuer.Status
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			p := &Provider{jobs: make(map[string]*jobState)}
+			if tc.status != queuer.Unknown {
+				p.jobs["job"] = &jobState{status: tc.status}
+			}
+			var ctx context.Context
+			if tc.running {
+				var ok bool
+				ctx, _, ok = p.start(context.Background(), "job")
+				if !ok {
+					t.Fatalf("start, expected true got false")
+				}
+			}
+			if err := p.Cancel("job"); err != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.running {
+				if ctx.Err() != context.Canceled {
+					t.Errorf("context, expected %v got %v", context.Canceled, ctx.Err())
+				}
+				p.finish("job", queuer.Compleated)
+			} else if tc.expected == queuer.Cancelled {
+				if _, _, ok := p.start(context.Background(), "job"); ok {
+					t.Errorf("start, expected cancelled job not to start")
+				}
+			}
+			if status := p.Info("job"); status != tc.expected {
+				t.Errorf("status, expected %v got %v", tc.expected, status)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:     "enqueued",
+			status:   queuer.Enqueded,
+			expected: queuer.Cancelled,
+		},
+		{
+			name:     "running",
+			status:   queuer.Enqueded,
+			running:  true,
+			expected: queuer.Cancelled,
+		},
+		{
+			name:     "completed",
+			status:   queuer.Compleated,
+			err:      queuer.ErrJobFinished,
+			expected: queuer.Compleated,
+		},
+		{
+			name:     "cancelled",
+			status:   queuer.Cancelled,
+			err:      queuer.ErrJobFinished,
+			expected: queuer.Cancelled,
+		},
+		{
+			name:     "unknown",
+			status:   queuer.Unknown,
+			err:      queuer.ErrUnknownJob,
+			expected: queuer.Unknown,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestInfo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := NewProvider(ctx, new(atlante.Atlante), 1, 0)
+	jobid, err := p.Enqueue("test", atlante.NewJob("missing", nil, nil))
+	if err != nil {
+		t.Fatalf("enqueue, expected nil got %v", err)
+	}
+	// the atlante has no sheets, so the job fails
+	deadline := time.Now().Add(time.Second)
+	for p.Info(jobid) != queuer.Failed {
+		if time.Now().After(deadline) {
+			t.Fatalf("status, expected %v got %v", queuer.Failed, p.Info(jobid))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if status := p.Info("unknown"); status != queuer.Unknown {
+		t.Errorf("unknown status, expected %v got %v", queuer.Unknown, status)
+	}
+}
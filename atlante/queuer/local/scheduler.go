@@ -0,0 +1,144 @@
+package local
+
+import (
+	"sort"
+	"sync"
+)
+
+// requesterQueue are the waiting jobs of a requester
+type requesterQueue struct {
+	// jobs are the waiting jobs, highest priority first, then oldest first
+	jobs []*jobInfo
+	// running is the number of jobs of the requester being run
+	running int
+	// served is the tick the requester last had a job started
+	served uint64
+}
+
+// scheduler hands the waiting jobs to the runners. Jobs with a higher priority are
+// run first; jobs with the same priority are taken in turn from each requester,
+// starting with the requester that was served least recently, so a requester with
+// many jobs does not starve everyone else.
+type scheduler struct {
+	lock sync.Mutex
+	cond *sync.Cond
+
+	// maxPerRequester is the max number of jobs of a requester that are run at
+	// once, 0 for no limit
+	maxPerRequester int
+
+	requesters map[string]*requesterQueue
+	// seq orders jobs of the same priority by the time they were pushed
+	seq uint64
+	// tick is increased each time a job is started
+	tick   uint64
+	closed bool
+}
+
+func newScheduler(maxPerRequester int) *scheduler {
+	s := &scheduler{
+		maxPerRequester: maxPerRequester,
+		requesters:      make(map[string]*requesterQueue),
+	}
+	s.cond = sync.NewCond(&s.lock)
+	return s
+}
+
+// push adds the job to the waiting jobs of its requester; jobs pushed after the
+// scheduler is closed are dropped
+func (s *scheduler) push(ji *jobInfo) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return
+	}
+	s.seq++
+	ji.seq = s.seq
+	rq, ok := s.requesters[ji.requester]
+	if !ok {
+		// a new requester waits for its turn like the others
+		rq = &requesterQueue{served: s.tick}
+		s.requesters[ji.requester] = rq
+	}
+	i := sort.Search(len(rq.jobs), func(i int) bool {
+		return rq.jobs[i].priority < ji.priority
+	})
+	rq.jobs = append(rq.jobs, nil)
+	copy(rq.jobs[i+1:], rq.jobs[i:])
+	rq.jobs[i] = ji
+	s.cond.Signal()
+}
+
+// pop returns the next job to run, nil if no requester under its cap has a waiting
+// job; the lock must be held
+func (s *scheduler) pop() *jobInfo {
+	var (
+		next   *requesterQueue
+		nextJi *jobInfo
+	)
+	for _, rq := range s.requesters {
+		if len(rq.jobs) == 0 || (s.maxPerRequester > 0 && rq.running >= s.maxPerRequester) {
+			continue
+		}
+		ji := rq.jobs[0]
+		switch {
+		case next == nil,
+			ji.priority > nextJi.priority,
+			ji.priority == nextJi.priority && rq.served < next.served,
+			ji.priority == nextJi.priority && rq.served == next.served && ji.seq < nextJi.seq:
+			next, nextJi = rq, ji
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	next.jobs[0] = nil
+	next.jobs = next.jobs[1:]
+	next.running++
+	s.tick++
+	next.served = s.tick
+	return nextJi
+}
+
+// next waits for the next job to run; false once the scheduler is closed. The
+// runner must call done with the requester of the job once it's finished with it.
+func (s *scheduler) next() (*jobInfo, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for {
+		if s.closed {
+			return nil, false
+		}
+		if ji := s.pop(); ji != nil {
+			return ji, true
+		}
+		s.cond.Wait()
+	}
+}
+
+// done records that a job of the requester is no longer running
+func (s *scheduler) done(requester string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	rq, ok := s.requesters[requester]
+	if !ok {
+		return
+	}
+	if rq.running > 0 {
+		rq.running--
+	}
+	if rq.running == 0 && len(rq.jobs) == 0 {
+		delete(s.requesters, requester)
+	}
+	// a runner may be waiting for the requester to be under its cap
+	s.cond.Broadcast()
+}
+
+// close wakes up the waiting runners, and drops the waiting jobs
+func (s *scheduler) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	s.requesters = make(map[string]*requesterQueue)
+	s.cond.Broadcast()
+}
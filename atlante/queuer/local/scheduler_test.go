@@ -0,0 +1,156 @@
+package local
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	type job struct {
+		id        string
+		requester string
+		priority  int
+	}
+	type tcase struct {
+		name            string
+		maxPerRequester int
+		jobs            []job
+		// done are the requesters whose jobs finish before each pop, by index of the pop
+		done map[int][]string
+		// expected are the ids of the jobs in the order they are started, "" if no job
+		// can be started
+		expected []string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			s := newScheduler(tc.maxPerRequester)
+			for _, j := range tc.jobs {
+				s.push(&jobInfo{jobid: j.id, requester: j.requester, priority: j.priority})
+			}
+			var got []string
+			for i := range tc.expected {
+				for _, requester := range tc.done[i] {
+					s.done(requester)
+				}
+				s.lock.Lock()
+				ji := s.pop()
+				s.lock.Unlock()
+				if ji == nil {
+					got = append(got, "")
+					continue
+				}
+				got = append(got, ji.jobid)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("order, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name: "fifo",
+			jobs: []job{
+				{id: "a1", requester: "a"},
+				{id: "a2", requester: "a"},
+				{id: "a3", requester: "a"},
+			},
+			expected: []string{"a1", "a2", "a3", ""},
+		},
+		{
+			name: "round robin",
+			jobs: []job{
+				{id: "a1", requester: "a"},
+				{id: "a2", requester: "a"},
+				{id: "a3", requester: "a"},
+				{id: "b1", requester: "b"},
+				{id: "c1", requester: "c"},
+				{id: "b2", requester: "b"},
+			},
+			expected: []string{"a1", "b1", "c1", "a2", "b2", "a3"},
+		},
+		{
+			name: "priority",
+			jobs: []job{
+				{id: "a1", requester: "a"},
+				{id: "a2", requester: "a", priority: 5},
+				{id: "b1", requester: "b", priority: -1},
+				{id: "b2", requester: "b", priority: 10},
+				{id: "c1", requester: "c"},
+			},
+			expected: []string{"b2", "a2", "c1", "a1", "b1"},
+		},
+		{
+			name:            "max per requester",
+			maxPerRequester: 1,
+			jobs: []job{
+				{id: "a1", requester: "a", priority: 5},
+				{id: "a2", requester: "a", priority: 5},
+				{id: "b1", requester: "b"},
+			},
+			done:     map[int][]string{3: {"a"}},
+			expected: []string{"a1", "b1", "", "a2"},
+		},
+		{
+			name:            "max per requester no limit",
+			maxPerRequester: 0,
+			jobs: []job{
+				{id: "a1", requester: "a", priority: 5},
+				{id: "a2", requester: "a", priority: 5},
+				{id: "b1", requester: "b"},
+			},
+			expected: []string{"a1", "a2", "b1"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestSchedulerNext(t *testing.T) {
+	s := newScheduler(1)
+	s.push(&jobInfo{jobid: "a1", requester: "a"})
+	s.push(&jobInfo{jobid: "a2", requester: "a"})
+	if ji, ok := s.next(); !ok || ji.jobid != "a1" {
+		t.Fatalf("next, expected a1 got %v %v", ji, ok)
+	}
+
+	next := make(chan string)
+	go func() {
+		ji, ok := s.next()
+		if !ok {
+			close(next)
+			return
+		}
+		next <- ji.jobid
+	}()
+	select {
+	case id := <-next:
+		t.Fatalf("next, expected to wait for a1 to be done got %v", id)
+	case <-time.After(20 * time.Millisecond):
+	}
+	s.done("a")
+	select {
+	case id := <-next:
+		if id != "a2" {
+			t.Errorf("next, expected a2 got %v", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("next, expected a2 once a1 was done")
+	}
+
+	go func() {
+		_, ok := s.next()
+		if !ok {
+			close(next)
+		}
+	}()
+	s.close()
+	select {
+	case <-next:
+	case <-time.After(time.Second):
+		t.Fatalf("next, expected to return once closed")
+	}
+}
@@ -0,0 +1,762 @@
+package postgresql
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdey/errors"
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/config"
+	"github.com/go-spatial/atlante/atlante/queuer"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/jackc/pgx"
+	"github.com/prometheus/common/log"
+)
+
+// TYPE is the name of the provider
+const TYPE = "postgresql"
+
+// AppName is shown by the pqclient
+var AppName = "atlante-queue"
+
+const (
+	// DefaultPort is the default port for postgres
+	DefaultPort = 5432
+	// DefaultMaxConn is the max number of connections to attempt
+	DefaultMaxConn = 10
+	// DefaultSSLMode by default ssl is disabled
+	DefaultSSLMode = "disable"
+	// DefaultSSLKey by default is empty
+	DefaultSSLKey = ""
+	// DefaultSSLCert by default is empty
+	DefaultSSLCert = ""
+	// DefaultTable is the table the jobs are stored in
+	DefaultTable = "atlante_queue"
+	// DefaultVisibilityTimeout is the number of seconds a job is locked to a worker
+	// before it is given to another worker
+	DefaultVisibilityTimeout = 300
+	// DefaultMaxAttempts is the number of times a job is delivered before it is failed
+	DefaultMaxAttempts = 3
+)
+
+const (
+	// ConfigKeyHost is the config key for the postgres host
+	ConfigKeyHost = "host"
+	// ConfigKeyPort is the config key for the postgres port
+	ConfigKeyPort = "port"
+	// ConfigKeyDB is the config key for the postgres db
+	ConfigKeyDB = "database"
+	// ConfigKeyUser is the config key for the postgres user
+	ConfigKeyUser = "user"
+	// ConfigKeyPassword is the config key for the postgres user's password
+	ConfigKeyPassword = "password"
+	// ConfigKeySSLMode is the config key for the postgres SSL
+	ConfigKeySSLMode = "ssl_mode"
+	// ConfigKeySSLKey is the config key for the postgres SSL
+	ConfigKeySSLKey = "ssl_key"
+	// ConfigKeySSLCert is the config key for the postgres SSL
+	ConfigKeySSLCert = "ssl_cert"
+	// ConfigKeySSLRootCert is the config key for the postgres SSL
+	ConfigKeySSLRootCert = "ssl_root_cert"
+	// ConfigKeyMaxConn is the max number of connections to keep in the pool
+	ConfigKeyMaxConn = "max_connections"
+	// ConfigKeyTable is the config key for the table the jobs are stored in
+	ConfigKeyTable = "table"
+	// ConfigKeyVisibilityTimeout is the config key for the number of seconds a job
+	// is locked to a worker without a heartbeat
+	ConfigKeyVisibilityTimeout = "visibility_timeout"
+	// ConfigKeyMaxAttempts is the config key for the number of times a job is
+	// delivered before it is failed
+	ConfigKeyMaxAttempts = "max_attempts"
+	// ConfigKeyMaxPerRequester is the config key for the max number of jobs of a
+	// requester that are run at once, across all the workers
+	ConfigKeyMaxPerRequester = "max_per_requester"
+)
+
+const (
+	// StatusEnqueued the job is waiting for a worker
+	StatusEnqueued = "enqueued"
+	// StatusRunning the job is locked to a worker
+	StatusRunning = "running"
+	// StatusCompleted the job is done
+	StatusCompleted = "completed"
+	// StatusFailed the job failed, or was delivered max_attempts times
+	StatusFailed = "failed"
+	// StatusCancelled the job was cancelled
+	StatusCancelled = "cancelled"
+)
+
+// ErrInvalidSSLMode is returned when something is wrong with SSL configuration
+type ErrInvalidSSLMode string
+
+func (e ErrInvalidSSLMode) Error() string {
+	return fmt.Sprintf("postgresql queue: invalid ssl mode (%v)", string(e))
+}
+
+// ErrInvalidTableName is returned when the table name is not a valid identifier
+type ErrInvalidTableName string
+
+func (e ErrInvalidTableName) Error() string {
+	return fmt.Sprintf("postgresql queue: invalid table name (%v)", string(e))
+}
+
+// ErrInvalidJobID is returned when the job id was not one returned by Enqueue
+type ErrInvalidJobID string
+
+func (e ErrInvalidJobID) Error() string {
+	return fmt.Sprintf("postgresql queue: invalid job id (%v)", string(e))
+}
+
+func init() {
+	queuer.Register(TYPE, initFunc, cleanup)
+	queuer.RegisterSchema(TYPE, config.Schema{
+		Required: []string{ConfigKeyHost, ConfigKeyDB, ConfigKeyUser, ConfigKeyPassword},
+		Optional: append([]string{
+			ConfigKeyPort, ConfigKeySSLMode, ConfigKeySSLKey, ConfigKeySSLCert, ConfigKeySSLRootCert,
+			ConfigKeyMaxConn, ConfigKeyTable, ConfigKeyVisibilityTimeout, ConfigKeyMaxAttempts,
+			ConfigKeyMaxPerRequester,
+		}, queuer.RetryConfigKeys...),
+	})
+}
+
+// Provider is a durable queue of jobs stored in a postgres table. Workers, in
+// one or more processes, pull jobs from the table; see queuer.Puller.
+type Provider struct {
+	config pgx.ConnPoolConfig
+	pool   *pgx.ConnPool
+
+	// VisibilityTimeout is the number of seconds a job is locked to a worker
+	VisibilityTimeout int
+	// MaxAttempts is the number of times a job is delivered before it is failed
+	MaxAttempts int
+	// MaxPerRequester is the max number of jobs of a requester that are run at
+	// once, 0 for no limit
+	MaxPerRequester int
+
+	queries queries
+
+	// atlante is used to report the status of the jobs that are failed by the queue
+	// instead of by a worker
+	atlante *atlante.Atlante
+}
+
+// queries are the sql statements of the queue for a table
+type queries struct {
+	create    string
+	enqueue   string
+	expire    string
+	dequeue   string
+	heartbeat string
+	finish    string
+	retry     string
+	info      string
+	cancel    string
+
+	// lockID is the advisory lock taken while dequeuing a job when the jobs of a
+	// requester are capped, so workers don't go over the cap together
+	lockID int64
+}
+
+var tableNameRx = regexp.MustCompile(`^[[:alpha:]_][[:alnum:]_]*(\.[[:alpha:]_][[:alnum:]_]*)?$`)
+
+// newQueries returns the sql statements for the queue stored in the table; the
+// table name may be schema qualified
+func newQueries(table string) (queries, error) {
+	if !tableNameRx.MatchString(table) {
+		return queries{}, ErrInvalidTableName(table)
+	}
+	ident := pgx.Identifier(strings.Split(table, "."))
+	tbl := ident.Sanitize()
+	idx := pgx.Identifier{ident[len(ident)-1] + "_ready_idx"}.Sanitize()
+	requesterIdx := pgx.Identifier{ident[len(ident)-1] + "_requester_idx"}.Sanitize()
+	lockID := fnv.New64a()
+	lockID.Write([]byte(table))
+
+	return queries{
+		create: fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %[1]s (
+	id bigserial PRIMARY KEY,
+	queue_key text NOT NULL,
+	job_data text NOT NULL,
+	status text NOT NULL DEFAULT '%[3]s',
+	attempts integer NOT NULL DEFAULT 0,
+	worker text,
+	locked_until timestamp WITH time zone,
+	run_after timestamp WITH time zone NOT NULL DEFAULT NOW(),
+	priority integer NOT NULL DEFAULT 0,
+	requester text NOT NULL DEFAULT '',
+	started timestamp WITH time zone,
+	error text,
+	created timestamp WITH time zone NOT NULL DEFAULT NOW(),
+	updated timestamp WITH time zone NOT NULL DEFAULT NOW()
+);
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS run_after timestamp WITH time zone NOT NULL DEFAULT NOW();
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS priority integer NOT NULL DEFAULT 0;
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS requester text NOT NULL DEFAULT '';
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS started timestamp WITH time zone;
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (id) WHERE status IN ('%[3]s', '%[4]s');
+CREATE INDEX IF NOT EXISTS %[5]s ON %[1]s (requester, started);
+`, tbl, idx, StatusEnqueued, StatusRunning, requesterIdx),
+
+		// $1 is the queue key, $2 the job data, $3 the priority, $4 the requester
+		enqueue: fmt.Sprintf(`
+INSERT INTO %s(queue_key, job_data, priority, requester)
+VALUES($1, $2, $3, $4)
+RETURNING id;
+`, tbl),
+
+		// jobs whose worker died after they were delivered max attempts times are failed
+		// instead of being delivered again.
+		// $1 is the max attempts
+		expire: fmt.Sprintf(`
+UPDATE %s
+SET status = '%s', error = 'worker lost the job after ' || attempts || ' attempts', worker = NULL, locked_until = NULL, updated = NOW()
+WHERE status = '%s' AND locked_until < NOW() AND attempts >= $1
+RETURNING id, job_data, error;
+`, tbl, StatusFailed, StatusRunning),
+
+		// jobs with a higher priority are delivered first; jobs with the same priority
+		// are taken in turn from each requester, starting with the requester whose job
+		// was started least recently. Requesters with max per requester running jobs
+		// are skipped.
+		// $1 is the worker, $2 the visibility timeout in seconds, $3 the max attempts,
+		// $4 the max per requester, 0 for no limit
+		dequeue: fmt.Sprintf(`
+UPDATE %[1]s
+SET status = '%[3]s', worker = $1, attempts = attempts + 1, locked_until = NOW() + $2 * INTERVAL '1 second', started = NOW(), updated = NOW()
+WHERE id = (
+	SELECT q.id FROM %[1]s q
+	WHERE ((q.status = '%[2]s' AND q.run_after <= NOW()) OR (q.status = '%[3]s' AND q.locked_until < NOW() AND q.attempts < $3))
+	AND ($4 <= 0 OR (
+		SELECT count(*) FROM %[1]s r
+		WHERE r.requester = q.requester AND r.status = '%[3]s' AND r.locked_until >= NOW()
+	) < $4)
+	ORDER BY q.priority DESC, (
+		SELECT max(s.started) FROM %[1]s s WHERE s.requester = q.requester
+	) ASC NULLS FIRST, q.id
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id, queue_key, job_data, attempts;
+`, tbl, StatusEnqueued, StatusRunning),
+
+		// $1 is the id, $2 the worker, $3 the visibility timeout in seconds
+		heartbeat: fmt.Sprintf(`
+UPDATE %s
+SET locked_until = NOW() + $3 * INTERVAL '1 second', updated = NOW()
+WHERE id = $1 AND worker = $2 AND status = '%s';
+`, tbl, StatusRunning),
+
+		// $1 is the id, $2 the worker, $3 the new status, $4 the error
+		finish: fmt.Sprintf(`
+UPDATE %s
+SET status = $3, error = $4, locked_until = NULL, updated = NOW()
+WHERE id = $1 AND worker = $2 AND status = '%s';
+`, tbl, StatusRunning),
+
+		// the job is waiting again, but is not delivered till run_after
+		// $1 is the id, $2 the worker, $3 the seconds to wait, $4 the error
+		retry: fmt.Sprintf(`
+UPDATE %s
+SET status = '%s', error = $4, worker = NULL, locked_until = NULL, run_after = NOW() + $3 * INTERVAL '1 second', updated = NOW()
+WHERE id = $1 AND worker = $2 AND status = '%s';
+`, tbl, StatusEnqueued, StatusRunning),
+
+		// $1 is the id
+		info: fmt.Sprintf(`
+SELECT status FROM %s WHERE id = $1;
+`, tbl),
+
+		// the worker of a running job is told on its next heartbeat
+		// $1 is the id
+		cancel: fmt.Sprintf(`
+UPDATE %s
+SET status = '%s', locked_until = NULL, updated = NOW()
+WHERE id = $1 AND status IN ('%s', '%s');
+`, tbl, StatusCancelled, StatusEnqueued, StatusRunning),
+
+		lockID: int64(lockID.Sum64()),
+	}, nil
+}
+
+// initFunc returns a new provider based on the postgresql database, creating the
+// table of the queue if it does not exist
+func initFunc(cfg queuer.Config, a *atlante.Atlante) (queuer.Provider, error) {
+
+	host, err := cfg.String(ConfigKeyHost, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := cfg.String(ConfigKeyDB, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := cfg.String(ConfigKeyUser, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := cfg.String(ConfigKeyPassword, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sslmode := DefaultSSLMode
+	if sslmode, err = cfg.String(ConfigKeySSLMode, &sslmode); err != nil {
+		return nil, err
+	}
+
+	sslkey := DefaultSSLKey
+	if sslkey, err = cfg.String(ConfigKeySSLKey, &sslkey); err != nil {
+		return nil, err
+	}
+
+	sslcert := DefaultSSLCert
+	if sslcert, err = cfg.String(ConfigKeySSLCert, &sslcert); err != nil {
+		return nil, err
+	}
+
+	sslrootcert := DefaultSSLCert
+	if sslrootcert, err = cfg.String(ConfigKeySSLRootCert, &sslrootcert); err != nil {
+		return nil, err
+	}
+
+	port := DefaultPort
+	if port, err = cfg.Int(ConfigKeyPort, &port); err != nil {
+		return nil, err
+	}
+
+	maxcon := DefaultMaxConn
+	if maxcon, err = cfg.Int(ConfigKeyMaxConn, &maxcon); err != nil {
+		return nil, err
+	}
+
+	table := DefaultTable
+	if table, err = cfg.String(ConfigKeyTable, &table); err != nil {
+		return nil, err
+	}
+
+	timeout := DefaultVisibilityTimeout
+	if timeout, err = cfg.Int(ConfigKeyVisibilityTimeout, &timeout); err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		return nil, fmt.Errorf("postgresql queue: %v must be greater than zero, got %v", ConfigKeyVisibilityTimeout, timeout)
+	}
+
+	attempts := DefaultMaxAttempts
+	if attempts, err = cfg.Int(ConfigKeyMaxAttempts, &attempts); err != nil {
+		return nil, err
+	}
+	if attempts <= 0 {
+		return nil, fmt.Errorf("postgresql queue: %v must be greater than zero, got %v", ConfigKeyMaxAttempts, attempts)
+	}
+
+	perRequester := 0
+	if perRequester, err = cfg.Int(ConfigKeyMaxPerRequester, &perRequester); err != nil {
+		return nil, err
+	}
+	if perRequester < 0 {
+		return nil, fmt.Errorf("postgresql queue: %v must not be negative, got %v", ConfigKeyMaxPerRequester, perRequester)
+	}
+
+	// the workers retry the failed jobs; check the policy so a bad policy is found
+	// when the server starts
+	if _, err = queuer.RetryPolicyFromConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	connConfig := pgx.ConnConfig{
+		Host:     host,
+		Port:     uint16(port),
+		Database: db,
+		User:     user,
+		Password: password,
+		LogLevel: pgx.LogLevelWarn,
+		RuntimeParams: map[string]string{
+			"application_name": AppName,
+		},
+	}
+
+	if err = ConfigTLS(sslmode, sslkey, sslcert, sslrootcert, &connConfig); err != nil {
+		return nil, err
+	}
+
+	p := Provider{
+		config: pgx.ConnPoolConfig{
+			ConnConfig:     connConfig,
+			MaxConnections: maxcon,
+		},
+		VisibilityTimeout: timeout,
+		MaxAttempts:       attempts,
+		MaxPerRequester:   perRequester,
+		atlante:           a,
+	}
+	if p.queries, err = newQueries(table); err != nil {
+		return nil, err
+	}
+	if p.pool, err = pgx.NewConnPool(p.config); err != nil {
+		return nil, fmt.Errorf("Failed while creating connection pool: %v", err)
+	}
+	if _, err = p.pool.Exec(p.queries.create); err != nil {
+		p.pool.Close()
+		return nil, fmt.Errorf("Failed while creating queue table %v: %v", table, err)
+	}
+
+	// track the provider so we can clean it up later
+	pLock.Lock()
+	providers = append(providers, &p)
+	pLock.Unlock()
+	return &p, nil
+}
+
+// ConfigTLS is used to configure TLS
+// derived from github.com/jackc/pgx configTLS (https://github.com/jackc/pgx/blob/master/conn.go)
+func ConfigTLS(sslMode string, sslKey string, sslCert string, sslRootCert string, cc *pgx.ConnConfig) error {
+
+	switch sslMode {
+	case "disable":
+		cc.UseFallbackTLS = false
+		cc.TLSConfig = nil
+		cc.FallbackTLSConfig = nil
+		return nil
+	case "allow":
+		cc.UseFallbackTLS = true
+		cc.FallbackTLSConfig = &tls.Config{InsecureSkipVerify: true}
+	case "prefer":
+		cc.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+		cc.UseFallbackTLS = true
+		cc.FallbackTLSConfig = nil
+	case "require":
+		cc.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	case "verify-ca", "verify-full":
+		cc.TLSConfig = &tls.Config{
+			ServerName: cc.Host,
+		}
+	default:
+		return ErrInvalidSSLMode(sslMode)
+	}
+
+	if sslRootCert != "" {
+		caCertPool := x509.NewCertPool()
+
+		caCert, err := ioutil.ReadFile(sslRootCert)
+		if err != nil {
+			return fmt.Errorf("unable to read CA file (%q): %v", sslRootCert, err)
+		}
+
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return fmt.Errorf("unable to add CA to cert pool")
+		}
+
+		cc.TLSConfig.RootCAs = caCertPool
+		cc.TLSConfig.ClientCAs = caCertPool
+	}
+
+	if (sslCert == "") != (sslKey == "") {
+		return fmt.Errorf("both 'sslcert' and 'sslkey' are required")
+	} else if sslCert != "" { // we must have both now
+		cert, err := tls.LoadX509KeyPair(sslCert, sslKey)
+		if err != nil {
+			return fmt.Errorf("unable to read cert: %v", err)
+		}
+
+		cc.TLSConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return nil
+}
+
+// parseJobID returns the row id of the job id
+func parseJobID(jobid string) (int64, error) {
+	id, err := strconv.ParseInt(jobid, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidJobID(jobid)
+	}
+	return id, nil
+}
+
+// Enqueue stores the job in the queue table, the job id is the id of the row
+func (p *Provider) Enqueue(key string, job *atlante.Job) (jobid string, err error) {
+	if p == nil || p.pool == nil {
+		return "", fmt.Errorf("nil provider")
+	}
+	jobstr, err := job.Base64Marshal()
+	if err != nil {
+		return "", err
+	}
+	var id int64
+	priority, requester := job.Priority(), job.Requester()
+	if err = p.pool.QueryRow(p.queries.enqueue, key, jobstr, priority, requester).Scan(&id); err != nil {
+		return "", err
+	}
+	jobid = strconv.FormatInt(id, 10)
+	log.Infof("enqueued job(%v) for %v with priority %v for %q", jobid, key, priority, requester)
+	return jobid, nil
+}
+
+// dequeue runs the dequeue query; when the jobs of a requester are capped the
+// workers take turns, so two workers can not both start the last job allowed
+// for a requester
+func (p *Provider) dequeue(ctx context.Context, worker string, dest ...interface{}) error {
+	args := []interface{}{worker, p.VisibilityTimeout, p.MaxAttempts, p.MaxPerRequester}
+	if p.MaxPerRequester <= 0 {
+		return p.pool.QueryRowEx(ctx, p.queries.dequeue, nil, args...).Scan(dest...)
+	}
+	tx, err := p.pool.BeginEx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err = tx.ExecEx(ctx, "SELECT pg_advisory_xact_lock($1);", nil, p.queries.lockID); err != nil {
+		return err
+	}
+	if err = tx.QueryRowEx(ctx, p.queries.dequeue, nil, args...).Scan(dest...); err != nil {
+		return err
+	}
+	return tx.CommitEx(ctx)
+}
+
+// expire fails the jobs whose worker was lost after they were delivered max attempts
+// times, and emits the failed status of the jobs, as no worker will
+func (p *Provider) expire(ctx context.Context) error {
+	type expired struct {
+		id     int64
+		jobstr string
+		reason string
+	}
+	var jobs []expired
+	rows, err := p.pool.QueryEx(ctx, p.queries.expire, nil, p.MaxAttempts)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var ex expired
+		if err = rows.Scan(&ex.id, &ex.jobstr, &ex.reason); err != nil {
+			rows.Close()
+			return err
+		}
+		jobs = append(jobs, ex)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, ex := range jobs {
+		log.Warnf("failed job(%v): %v", ex.id, ex.reason)
+		if p.atlante == nil || p.atlante.Notifier == nil {
+			continue
+		}
+		job, err := atlante.Base64UnmarshalJob(ex.jobstr)
+		if err != nil {
+			log.Warnf("failed to read job(%v) data: %v", ex.id, err)
+			continue
+		}
+		jobID := job.MetaData["job_id"]
+		if jobID == "" {
+			continue
+		}
+		emitter, err := p.atlante.Notifier.NewEmitter(jobID)
+		if err != nil {
+			log.Warnf("Failed to init emitter: %v", err)
+			continue
+		}
+		if err = emitter.Emit(field.Failed{Error: errors.String(ex.reason)}); err != nil {
+			log.Warnf("failed to emit failed status for job(%v): %v", jobID, err)
+		}
+	}
+	return nil
+}
+
+// Dequeue locks the next job that is waiting, or whose worker stopped sending
+// heartbeats, to the worker for the visibility timeout. Jobs are delivered by
+// priority, then in turn for each requester. Rows locked by other workers
+// dequeuing at the same time are skipped.
+func (p *Provider) Dequeue(ctx context.Context, worker string) (*queuer.Delivery, error) {
+	if err := p.expire(ctx); err != nil {
+		return nil, err
+	}
+	var (
+		id      int64
+		key     string
+		jobstr  string
+		attempt int
+	)
+	err := p.dequeue(ctx, worker, &id, &key, &jobstr, &attempt)
+	if err == pgx.ErrNoRows {
+		return nil, queuer.ErrNoJobs
+	}
+	if err != nil {
+		return nil, err
+	}
+	jobid := strconv.FormatInt(id, 10)
+	job, err := atlante.Base64UnmarshalJob(jobstr)
+	if err != nil {
+		// the job data will not get better; fail it so it's not delivered again
+		if ferr := p.Fail(ctx, jobid, worker, err); ferr != nil {
+			log.Warnf("failed to fail job(%v): %v", jobid, ferr)
+		}
+		return nil, fmt.Errorf("postgresql queue: job(%v) has bad job data: %v", jobid, err)
+	}
+	return &queuer.Delivery{
+		JobID:   jobid,
+		Key:     key,
+		Job:     job,
+		Attempt: attempt,
+	}, nil
+}
+
+// Heartbeat extends the lock the worker has on the job by the visibility timeout; the
+// worker of a cancelled job gets ErrJobCancelled
+func (p *Provider) Heartbeat(ctx context.Context, jobid, worker string) error {
+	id, err := parseJobID(jobid)
+	if err != nil {
+		return err
+	}
+	tag, err := p.pool.ExecEx(ctx, p.queries.heartbeat, nil, id, worker, p.VisibilityTimeout)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		if p.Info(jobid) == queuer.Cancelled {
+			return queuer.ErrJobCancelled
+		}
+		return queuer.ErrJobLost
+	}
+	return nil
+}
+
+// finish sets the final status of the job held by the worker
+func (p *Provider) finish(ctx context.Context, jobid, worker, status string, reason *string) error {
+	id, err := parseJobID(jobid)
+	if err != nil {
+		return err
+	}
+	tag, err := p.pool.ExecEx(ctx, p.queries.finish, nil, id, worker, status, reason)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return queuer.ErrJobLost
+	}
+	return nil
+}
+
+// Complete marks the job held by the worker as completed
+func (p *Provider) Complete(ctx context.Context, jobid, worker string) error {
+	return p.finish(ctx, jobid, worker, StatusCompleted, nil)
+}
+
+// Fail marks the job held by the worker as failed
+func (p *Provider) Fail(ctx context.Context, jobid, worker string, reason error) error {
+	var msg *string
+	if reason != nil {
+		str := reason.Error()
+		msg = &str
+	}
+	return p.finish(ctx, jobid, worker, StatusFailed, msg)
+}
+
+// Retry puts the job held by the worker back in the queue, it is not delivered
+// till after has passed. The attempts of the job are kept, so a job whose worker
+// dies is still failed after max attempts.
+func (p *Provider) Retry(ctx context.Context, jobid, worker string, after time.Duration, reason error) error {
+	id, err := parseJobID(jobid)
+	if err != nil {
+		return err
+	}
+	var msg *string
+	if reason != nil {
+		str := reason.Error()
+		msg = &str
+	}
+	tag, err := p.pool.ExecEx(ctx, p.queries.retry, nil, id, worker, after.Seconds(), msg)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return queuer.ErrJobLost
+	}
+	return nil
+}
+
+// Info returns the status of the job, Unknown if the job is not in the queue
+func (p *Provider) Info(jobid string) queuer.Status {
+	id, err := parseJobID(jobid)
+	if err != nil {
+		return queuer.Unknown
+	}
+	var status string
+	err = p.pool.QueryRow(p.queries.info, id).Scan(&status)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Warnf("failed to get status of job(%v): %v", jobid, err)
+		}
+		return queuer.Unknown
+	}
+	switch status {
+	case StatusEnqueued:
+		return queuer.Enqueded
+	case StatusRunning:
+		return queuer.Processing
+	case StatusCompleted:
+		return queuer.Compleated
+	case StatusFailed:
+		return queuer.Failed
+	case StatusCancelled:
+		return queuer.Cancelled
+	default:
+		return queuer.Unknown
+	}
+}
+
+// Cancel cancels the job; a waiting job is not delivered, and the worker running
+// the job cancels it on its next heartbeat
+func (p *Provider) Cancel(jobid string) error {
+	id, err := parseJobID(jobid)
+	if err != nil {
+		return queuer.ErrUnknownJob
+	}
+	tag, err := p.pool.Exec(p.queries.cancel, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		if p.Info(jobid) == queuer.Unknown {
+			return queuer.ErrUnknownJob
+		}
+		return queuer.ErrJobFinished
+	}
+	log.Infof("cancelled job(%v)", jobid)
+	return nil
+}
+
+// Close will close the provider's database connection
+func (p *Provider) Close() { p.pool.Close() }
+
+var pLock sync.Mutex
+
+// reference to all instantiated providers
+var providers []*Provider
+
+// cleanup will close all database connections and destroy all previously instantiated Provider instances
+func cleanup() {
+	pLock.Lock()
+	for i := range providers {
+		providers[i].Close()
+	}
+	providers = nil
+	pLock.Unlock()
+}
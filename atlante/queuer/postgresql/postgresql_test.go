@@ -0,0 +1,391 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/notifiers"
+	"github.com/go-spatial/atlante/atlante/queuer"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/go-spatial/tegola/dict"
+	"github.com/jackc/pgx"
+)
+
+func TestNewQueries(t *testing.T) {
+	type tcase struct {
+		table string
+		ident string
+		err   error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.table, func(t *testing.T) {
+			q, err := newQueries(tc.table)
+			if tc.err != nil {
+				if err != tc.err {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			for name, query := range map[string]string{
+				"create":    q.create,
+				"enqueue":   q.enqueue,
+				"expire":    q.expire,
+				"dequeue":   q.dequeue,
+				"heartbeat": q.heartbeat,
+				"finish":    q.finish,
+				"retry":     q.retry,
+				"info":      q.info,
+				"cancel":    q.cancel,
+			} {
+				if !strings.Contains(query, tc.ident) {
+					t.Errorf("%v query, expected table %v got %v", name, tc.ident, query)
+				}
+			}
+			if !strings.Contains(q.dequeue, "FOR UPDATE SKIP LOCKED") {
+				t.Errorf("dequeue query, expected to skip locked rows got %v", q.dequeue)
+			}
+			if !strings.Contains(q.dequeue, "ORDER BY q.priority DESC") {
+				t.Errorf("dequeue query, expected to order by priority got %v", q.dequeue)
+			}
+			if q.lockID == 0 {
+				t.Errorf("lock id, expected non zero")
+			}
+		}
+	}
+
+	tests := []tcase{
+		{table: "atlante_queue", ident: `"atlante_queue"`},
+		{table: "jobs.queue", ident: `"jobs"."queue"`},
+		{table: "queue; DROP TABLE jobs", err: ErrInvalidTableName("queue; DROP TABLE jobs")},
+		{table: `"queue"`, err: ErrInvalidTableName(`"queue"`)},
+		{table: "a.b.c", err: ErrInvalidTableName("a.b.c")},
+		{table: "", err: ErrInvalidTableName("")},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+// TESTENV is the environment variable that must be set to "yes" to run the tests against
+// a database; the database is configured with the PGHOST, PGPORT, PGDATABASE, PGUSER,
+// PGPASSWORD and PGSSLMODE environment variables
+const TESTENV = "RUN_POSTGIS_TESTS"
+
+// recordingNotifier records the statuses emitted for each job id
+type recordingNotifier struct {
+	lock     sync.Mutex
+	statuses map[string][]field.StatusEnum
+}
+
+func (rn *recordingNotifier) NewEmitter(jobid string) (notifiers.Emitter, error) {
+	return emitterFunc(func(status field.StatusEnum) error {
+		rn.lock.Lock()
+		defer rn.lock.Unlock()
+		if rn.statuses == nil {
+			rn.statuses = make(map[string][]field.StatusEnum)
+		}
+		rn.statuses[jobid] = append(rn.statuses[jobid], status)
+		return nil
+	}), nil
+}
+
+func (rn *recordingNotifier) For(jobid string) []field.StatusEnum {
+	rn.lock.Lock()
+	defer rn.lock.Unlock()
+	return rn.statuses[jobid]
+}
+
+type emitterFunc func(field.StatusEnum) error
+
+func (fn emitterFunc) Emit(status field.StatusEnum) error { return fn(status) }
+
+// newTestProvider returns a queue in a new table, with the config overrides, the name of
+// the table and a func to drop the table; the test is skipped if TESTENV is not set
+func newTestProvider(t *testing.T, a *atlante.Atlante, overrides dict.Dict) (*Provider, string, func()) {
+	if os.Getenv(TESTENV) != "yes" {
+		t.Skipf("%v is not set to yes", TESTENV)
+	}
+	port := DefaultPort
+	if p := os.Getenv("PGPORT"); p != "" {
+		var err error
+		if port, err = strconv.Atoi(p); err != nil {
+			t.Skipf("err parsing PGPORT: %v", err)
+		}
+	}
+	sslmode := os.Getenv("PGSSLMODE")
+	if sslmode == "" {
+		sslmode = DefaultSSLMode
+	}
+	cfg := dict.Dict{
+		ConfigKeyHost:     os.Getenv("PGHOST"),
+		ConfigKeyPort:     port,
+		ConfigKeyDB:       os.Getenv("PGDATABASE"),
+		ConfigKeyUser:     os.Getenv("PGUSER"),
+		ConfigKeyPassword: os.Getenv("PGPASSWORD"),
+		ConfigKeySSLMode:  sslmode,
+		ConfigKeyTable:    fmt.Sprintf("atlante_queue_test_%d", time.Now().UnixNano()),
+	}
+	for k, v := range overrides {
+		cfg[k] = v
+	}
+	prv, err := initFunc(cfg, a)
+	if err != nil {
+		t.Fatalf("init, expected nil got %v", err)
+	}
+	p := prv.(*Provider)
+	table := cfg[ConfigKeyTable].(string)
+	return p, table, func() {
+		if _, err := p.pool.Exec("DROP TABLE IF EXISTS " + pgx.Identifier{table}.Sanitize()); err != nil {
+			t.Errorf("drop table, expected nil got %v", err)
+		}
+		pLock.Lock()
+		for i := range providers {
+			if providers[i] == p {
+				providers = append(providers[:i], providers[i+1:]...)
+				break
+			}
+		}
+		pLock.Unlock()
+		p.Close()
+	}
+}
+
+func testJob(jobID string, priority int) *atlante.Job {
+	return atlante.NewJob("50k", nil, map[string]string{
+		"job_id":                     jobID,
+		atlante.MetaDataKeyPriority:  strconv.Itoa(priority),
+		atlante.MetaDataKeyRequester: "tester",
+	})
+}
+
+func TestDBEnqueueDequeue(t *testing.T) {
+	p, _, drop := newTestProvider(t, nil, nil)
+	defer drop()
+	ctx := context.Background()
+
+	low, err := p.Enqueue("low", testJob("job-low", 0))
+	if err != nil {
+		t.Fatalf("enqueue, expected nil got %v", err)
+	}
+	high, err := p.Enqueue("high", testJob("job-high", 5))
+	if err != nil {
+		t.Fatalf("enqueue, expected nil got %v", err)
+	}
+	if status := p.Info(low); status != queuer.Enqueded {
+		t.Errorf("info, expected %v got %v", queuer.Enqueded, status)
+	}
+
+	for _, expected := range []struct{ id, key, jobID string }{
+		{high, "high", "job-high"},
+		{low, "low", "job-low"},
+	} {
+		d, err := p.Dequeue(ctx, "worker")
+		if err != nil {
+			t.Fatalf("dequeue, expected nil got %v", err)
+		}
+		if d.JobID != expected.id || d.Key != expected.key || d.Attempt != 1 {
+			t.Errorf("delivery, expected %v %v attempt 1 got %v %v attempt %v", expected.id, expected.key, d.JobID, d.Key, d.Attempt)
+		}
+		if d.Job.SheetName != "50k" || d.Job.MetaData["job_id"] != expected.jobID {
+			t.Errorf("job, expected 50k %v got %v %v", expected.jobID, d.Job.SheetName, d.Job.MetaData["job_id"])
+		}
+		if status := p.Info(d.JobID); status != queuer.Processing {
+			t.Errorf("info, expected %v got %v", queuer.Processing, status)
+		}
+		if err = p.Complete(ctx, d.JobID, "worker"); err != nil {
+			t.Errorf("complete, expected nil got %v", err)
+		}
+		if status := p.Info(d.JobID); status != queuer.Compleated {
+			t.Errorf("info, expected %v got %v", queuer.Compleated, status)
+		}
+	}
+	if _, err = p.Dequeue(ctx, "worker"); err != queuer.ErrNoJobs {
+		t.Errorf("dequeue, expected %v got %v", queuer.ErrNoJobs, err)
+	}
+}
+
+func TestDBDequeueSkipLocked(t *testing.T) {
+	p, table, drop := newTestProvider(t, nil, nil)
+	defer drop()
+	ctx := context.Background()
+
+	const count = 20
+	enqueued := make(map[string]bool, count)
+	for i := 0; i < count; i++ {
+		id, err := p.Enqueue("key", testJob(fmt.Sprintf("job%v", i), 0))
+		if err != nil {
+			t.Fatalf("enqueue, expected nil got %v", err)
+		}
+		enqueued[id] = true
+	}
+
+	// a consumer holding the lock on the first row does not block the other consumer
+	var first string
+	for id := range enqueued {
+		if first == "" || len(id) < len(first) || (len(id) == len(first) && id < first) {
+			first = id
+		}
+	}
+	tx, err := p.pool.Begin()
+	if err != nil {
+		t.Fatalf("begin, expected nil got %v", err)
+	}
+	if _, err = tx.Exec("SELECT id FROM "+pgx.Identifier{table}.Sanitize()+" WHERE id = $1 FOR UPDATE", first); err != nil {
+		t.Fatalf("lock row, expected nil got %v", err)
+	}
+	dctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	d, err := p.Dequeue(dctx, "other")
+	cancel()
+	if err != nil {
+		t.Fatalf("dequeue, expected nil got %v", err)
+	}
+	if d.JobID == first {
+		t.Errorf("dequeue, expected a job other than the locked job %v", first)
+	}
+	tx.Rollback()
+
+	// two consumers dequeuing at once get each job once
+	var (
+		lock      sync.Mutex
+		delivered = map[string]int{d.JobID: 1}
+		wg        sync.WaitGroup
+	)
+	for _, worker := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			for {
+				d, err := p.Dequeue(ctx, worker)
+				if err == queuer.ErrNoJobs {
+					return
+				}
+				if err != nil {
+					t.Errorf("dequeue %v, expected nil got %v", worker, err)
+					return
+				}
+				lock.Lock()
+				delivered[d.JobID]++
+				lock.Unlock()
+			}
+		}(worker)
+	}
+	wg.Wait()
+	if len(delivered) != count {
+		t.Errorf("delivered, expected %v jobs got %v", count, len(delivered))
+	}
+	for id, n := range delivered {
+		if !enqueued[id] || n != 1 {
+			t.Errorf("job %v, expected to be delivered once got %v", id, n)
+		}
+	}
+}
+
+func TestDBHeartbeatExpiry(t *testing.T) {
+	notifier := new(recordingNotifier)
+	p, _, drop := newTestProvider(t, &atlante.Atlante{Notifier: notifier}, dict.Dict{
+		ConfigKeyVisibilityTimeout: 1,
+		ConfigKeyMaxAttempts:       2,
+	})
+	defer drop()
+	ctx := context.Background()
+	wait := func() { time.Sleep(1500 * time.Millisecond) }
+
+	id, err := p.Enqueue("key", testJob("job-lost", 0))
+	if err != nil {
+		t.Fatalf("enqueue, expected nil got %v", err)
+	}
+	d, err := p.Dequeue(ctx, "a")
+	if err != nil || d.JobID != id || d.Attempt != 1 {
+		t.Fatalf("dequeue, expected %v attempt 1 got %+v %v", id, d, err)
+	}
+	if err = p.Heartbeat(ctx, id, "a"); err != nil {
+		t.Errorf("heartbeat, expected nil got %v", err)
+	}
+	if _, err = p.Dequeue(ctx, "b"); err != queuer.ErrNoJobs {
+		t.Errorf("dequeue while locked, expected %v got %v", queuer.ErrNoJobs, err)
+	}
+
+	// worker a stops sending heartbeats, the job is redelivered to worker b
+	wait()
+	d, err = p.Dequeue(ctx, "b")
+	if err != nil || d.JobID != id || d.Attempt != 2 {
+		t.Fatalf("redeliver, expected %v attempt 2 got %+v %v", id, d, err)
+	}
+	if err = p.Heartbeat(ctx, id, "a"); err != queuer.ErrJobLost {
+		t.Errorf("heartbeat lost job, expected %v got %v", queuer.ErrJobLost, err)
+	}
+	if err = p.Complete(ctx, id, "a"); err != queuer.ErrJobLost {
+		t.Errorf("complete lost job, expected %v got %v", queuer.ErrJobLost, err)
+	}
+
+	// worker b is lost as well, after max attempts the job is failed
+	wait()
+	if _, err = p.Dequeue(ctx, "c"); err != queuer.ErrNoJobs {
+		t.Errorf("dequeue expired, expected %v got %v", queuer.ErrNoJobs, err)
+	}
+	if status := p.Info(id); status != queuer.Failed {
+		t.Errorf("info, expected %v got %v", queuer.Failed, status)
+	}
+	statuses := notifier.For("job-lost")
+	if len(statuses) != 1 {
+		t.Fatalf("statuses, expected 1 got %v", statuses)
+	}
+	if failed, ok := statuses[0].(field.Failed); !ok || !strings.Contains(failed.Error.Error(), "worker lost the job after 2 attempts") {
+		t.Errorf("status, expected failed got %#v", statuses[0])
+	}
+}
+
+func TestDBCancel(t *testing.T) {
+	p, _, drop := newTestProvider(t, nil, nil)
+	defer drop()
+	ctx := context.Background()
+
+	waiting, err := p.Enqueue("key", testJob("job-waiting", 0))
+	if err != nil {
+		t.Fatalf("enqueue, expected nil got %v", err)
+	}
+	if err = p.Cancel(waiting); err != nil {
+		t.Errorf("cancel, expected nil got %v", err)
+	}
+	if status := p.Info(waiting); status != queuer.Cancelled {
+		t.Errorf("info, expected %v got %v", queuer.Cancelled, status)
+	}
+	if _, err = p.Dequeue(ctx, "a"); err != queuer.ErrNoJobs {
+		t.Errorf("dequeue cancelled, expected %v got %v", queuer.ErrNoJobs, err)
+	}
+	if err = p.Cancel(waiting); err != queuer.ErrJobFinished {
+		t.Errorf("cancel again, expected %v got %v", queuer.ErrJobFinished, err)
+	}
+
+	// the worker of a running job is told on its next heartbeat
+	running, err := p.Enqueue("key", testJob("job-running", 0))
+	if err != nil {
+		t.Fatalf("enqueue, expected nil got %v", err)
+	}
+	if _, err = p.Dequeue(ctx, "a"); err != nil {
+		t.Fatalf("dequeue, expected nil got %v", err)
+	}
+	if err = p.Cancel(running); err != nil {
+		t.Errorf("cancel running, expected nil got %v", err)
+	}
+	if err = p.Heartbeat(ctx, running, "a"); err != queuer.ErrJobCancelled {
+		t.Errorf("heartbeat, expected %v got %v", queuer.ErrJobCancelled, err)
+	}
+
+	for _, id := range []string{"999999999", "bogus"} {
+		if err = p.Cancel(id); err != queuer.ErrUnknownJob {
+			t.Errorf("cancel %v, expected %v got %v", id, queuer.ErrUnknownJob, err)
+		}
+	}
+}
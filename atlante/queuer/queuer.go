@@ -1,6 +1,10 @@
 package queuer
 
 import (
+	"context"
+	"time"
+
+	"github.com/gdey/errors"
 	"github.com/go-spatial/atlante/atlante"
 )
 
@@ -10,6 +14,22 @@ type Status uint8
 const (
 	// ConfigKeyType is the config key for the type of the provider
 	ConfigKeyType = "type"
+
+	// ErrNoJobs is returned by a Puller when there are no jobs ready in the queue
+	ErrNoJobs = errors.String("no jobs in the queue")
+
+	// ErrJobLost is returned by a Puller when the worker no longer holds the job;
+	// its visibility timeout passed and the job was given to another worker
+	ErrJobLost = errors.String("job is no longer held by the worker")
+
+	// ErrJobCancelled is returned by a Puller when the job held by the worker was cancelled
+	ErrJobCancelled = errors.String("job was cancelled")
+
+	// ErrUnknownJob is returned by a Canceller when the job is not in the queue
+	ErrUnknownJob = errors.String("unknown job")
+
+	// ErrJobFinished is returned by a Canceller when the job has already finished
+	ErrJobFinished = errors.String("job already finished")
 )
 
 const (
@@ -25,6 +45,8 @@ const (
 	Cancelled
 	// Unknown the job is not in the system
 	Unknown
+	// Failed the job finished with an error
+	Failed
 )
 
 // Provider allows things to enqueue jobs to be done
@@ -39,3 +61,43 @@ type InfoProvider interface {
 	Provider
 	Info(jobid string) Status
 }
+
+// Canceller can cancel a job, the jobid is the one that is provided by
+// the Enqueue function. A job that has not started is not run; a running
+// job is stopped.
+type Canceller interface {
+	Provider
+	Cancel(jobid string) error
+}
+
+// Delivery is a job handed to a worker by a Puller
+type Delivery struct {
+	// JobID is the id returned by Enqueue
+	JobID string
+	// Key is the key the job was enqueued with
+	Key string
+	Job *atlante.Job
+	// Attempt is the number of times the job has been delivered, starting at 1
+	Attempt int
+}
+
+// Puller is a queue provider that workers pull jobs from, instead of the queue
+// pushing jobs to runners. A delivered job is locked to the worker until the
+// visibility timeout of the queue passes; a worker must call Heartbeat before
+// then to keep the job, otherwise it is delivered to another worker.
+type Puller interface {
+	Provider
+	// Dequeue locks the next job in the queue to the worker, returns ErrNoJobs
+	// if there are no jobs ready
+	Dequeue(ctx context.Context, worker string) (*Delivery, error)
+	// Heartbeat extends the lock the worker has on the job, returns ErrJobLost if
+	// the worker no longer holds the job, or ErrJobCancelled if the job was cancelled
+	Heartbeat(ctx context.Context, jobid, worker string) error
+	// Complete marks the job as done
+	Complete(ctx context.Context, jobid, worker string) error
+	// Fail marks the job as failed with the given reason
+	Fail(ctx context.Context, jobid, worker string, reason error) error
+	// Retry puts the job, that failed with the given reason, back in the queue
+	// to be delivered again once after has passed
+	Retry(ctx context.Context, jobid, worker string, after time.Duration, reason error) error
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gdey/errors"
 	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/config"
 	"github.com/go-spatial/tegola/dict"
 	"github.com/prometheus/common/log"
 )
@@ -51,6 +52,7 @@ type funcs struct {
 
 var providerLock sync.RWMutex
 var providers map[string]funcs
+var schemas map[string]config.Schema
 
 // Register is called by the init functions of each of the providers
 func Register(providerType string, init InitFunc, cleanup CleanupFunc) error {
@@ -71,6 +73,36 @@ func Register(providerType string, init InitFunc, cleanup CleanupFunc) error {
 	return nil
 }
 
+// RegisterSchema is called by the init functions of the providers to describe the config keys
+// of the queue provider; it is used to validate the config.
+func RegisterSchema(providerType string, schema config.Schema) {
+	providerLock.Lock()
+	defer providerLock.Unlock()
+	if schemas == nil {
+		schemas = make(map[string]config.Schema)
+	}
+	schemas[providerType] = schema
+}
+
+// SchemaFor returns the config schema of the queue provider type, nil if the type did not register
+// one, and weather the type is registered
+func SchemaFor(providerType string) (*config.Schema, bool) {
+	providerLock.RLock()
+	defer providerLock.RUnlock()
+	if _, ok := providers[providerType]; !ok {
+		return nil, false
+	}
+	schema, ok := schemas[providerType]
+	if !ok {
+		return nil, true
+	}
+	return &schema, true
+}
+
+func init() {
+	config.RegisterSchemas(config.SectionQueue, SchemaFor)
+}
+
 // Unregister will remove a provider and call it's cleanup function
 func Unregister(providerType string) {
 	providerLock.Lock()
@@ -0,0 +1,71 @@
+package queuer
+
+import (
+	"time"
+
+	"github.com/go-spatial/atlante/atlante"
+)
+
+const (
+	// ConfigKeyRetryMaxAttempts is the config key for the number of times a job is run,
+	// including the first run
+	ConfigKeyRetryMaxAttempts = "retry_max_attempts"
+	// ConfigKeyRetryBackoff is the config key for the number of seconds to wait before
+	// the first retry
+	ConfigKeyRetryBackoff = "retry_backoff"
+	// ConfigKeyRetryMaxBackoff is the config key for the most seconds to wait between retries
+	ConfigKeyRetryMaxBackoff = "retry_max_backoff"
+	// ConfigKeyRetryMultiplier is the config key for how much the wait grows after each retry
+	ConfigKeyRetryMultiplier = "retry_multiplier"
+	// ConfigKeyRetryOn is the config key for the classes of errors that are retried
+	ConfigKeyRetryOn = "retry_on"
+)
+
+// RetryConfigKeys are the config keys of the retry policy, providers that retry failed
+// jobs add them to the optional keys of their schema
+var RetryConfigKeys = []string{
+	ConfigKeyRetryMaxAttempts,
+	ConfigKeyRetryBackoff,
+	ConfigKeyRetryMaxBackoff,
+	ConfigKeyRetryMultiplier,
+	ConfigKeyRetryOn,
+}
+
+// RetryPolicyFromConfig returns the retry policy of the queue config
+func RetryPolicyFromConfig(cfg Config) (*atlante.RetryPolicy, error) {
+	var (
+		zero       int
+		zeroFloat  float64
+		maxAttempt int
+		backoff    int
+		maxBackoff int
+		multiplier float64
+		retryOn    []string
+		err        error
+	)
+	if cfg == nil {
+		return atlante.NewRetryPolicy(0, 0, 0, 0, nil)
+	}
+	if maxAttempt, err = cfg.Int(ConfigKeyRetryMaxAttempts, &zero); err != nil {
+		return nil, err
+	}
+	if backoff, err = cfg.Int(ConfigKeyRetryBackoff, &zero); err != nil {
+		return nil, err
+	}
+	if maxBackoff, err = cfg.Int(ConfigKeyRetryMaxBackoff, &zero); err != nil {
+		return nil, err
+	}
+	if multiplier, err = cfg.Float(ConfigKeyRetryMultiplier, &zeroFloat); err != nil {
+		return nil, err
+	}
+	if retryOn, err = cfg.StringSlice(ConfigKeyRetryOn); err != nil {
+		return nil, err
+	}
+	return atlante.NewRetryPolicy(
+		maxAttempt,
+		time.Duration(backoff)*time.Second,
+		time.Duration(maxBackoff)*time.Second,
+		multiplier,
+		retryOn,
+	)
+}
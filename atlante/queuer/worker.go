@@ -0,0 +1,223 @@
+package queuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/prometheus/common/log"
+)
+
+const (
+	// DefaultHeartbeatInterval is how often a worker extends the lock on the jobs it holds
+	DefaultHeartbeatInterval = 30 * time.Second
+
+	// DefaultPollInterval is how long a worker waits before asking again for a job
+	// when the queue is empty
+	DefaultPollInterval = 5 * time.Second
+
+	// finishTimeout is how long a worker waits to mark a job as completed or failed
+	finishTimeout = 30 * time.Second
+)
+
+// RunFunc runs the delivered job; the context is cancelled if the job times out,
+// the worker loses the job, or the worker is killed. If the queue cancelled the job
+// the context is cancelled with atlante.ErrJobCancelled, see atlante.CancelCause.
+type RunFunc func(ctx context.Context, delivery *Delivery) error
+
+// RetryFunc returns weather the delivered job, that failed with err, should be run
+// again, and how long to wait before doing so.
+type RetryFunc func(delivery *Delivery, err error) (time.Duration, bool)
+
+// Worker pulls jobs from a Puller and runs them
+type Worker struct {
+	// Queue to pull the jobs from
+	Queue Puller
+	// Name identifies the worker to the queue; it should be unique across processes
+	Name string
+	// Concurrency is the number of jobs to run at once, at least 1
+	Concurrency int
+	// JobTimeout is how long a job may run before it is cancelled, 0 for no timeout
+	JobTimeout time.Duration
+	// HeartbeatInterval is how often to extend the lock on a running job; it must be
+	// shorter than the visibility timeout of the queue
+	HeartbeatInterval time.Duration
+	// PollInterval is how long to wait before asking for a job when the queue is empty
+	PollInterval time.Duration
+	// Run runs the job
+	Run RunFunc
+	// Retry decides if a failed job is run again; if nil failed jobs are not retried
+	Retry RetryFunc
+}
+
+// Start pulls and runs jobs until ctx is done, then waits for the jobs that are
+// running to finish. The jobs are run with a context derived from jobsCtx;
+// cancelling it cancels the running jobs.
+func (w *Worker) Start(ctx, jobsCtx context.Context) error {
+	if w == nil || w.Queue == nil {
+		return fmt.Errorf("worker has no queue")
+	}
+	if w.Run == nil {
+		return fmt.Errorf("worker has no run function")
+	}
+	concurrency := w.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	pollInterval := w.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, concurrency)
+	)
+	defer wg.Wait()
+
+	log.Infof("worker %v started with %v runners", w.Name, concurrency)
+	for {
+		// wait for a free runner
+		select {
+		case <-ctx.Done():
+			log.Infof("worker %v stopping, waiting for running jobs", w.Name)
+			return nil
+		case slots <- struct{}{}:
+		}
+
+		delivery, err := w.Queue.Dequeue(ctx, w.Name)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				continue
+			}
+			if err != ErrNoJobs {
+				log.Warnf("worker %v failed to get a job: %v", w.Name, err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			w.process(jobsCtx, delivery)
+		}()
+	}
+}
+
+// process runs the delivered job, sending heartbeats while it runs, and then marks it
+// as completed or failed
+func (w *Worker) process(ctx context.Context, delivery *Delivery) {
+	var (
+		cancel func(cause error)
+		lost   int32
+		done   = make(chan struct{})
+		beats  sync.WaitGroup
+	)
+	// the job is only reported as cancelled if the queue cancelled it
+	ctx, cancel = atlante.WithJobCancel(ctx)
+	defer cancel(nil)
+	if w.JobTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, w.JobTimeout)
+		defer cancelTimeout()
+	}
+
+	interval := w.HeartbeatInterval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	beats.Add(1)
+	go func() {
+		defer beats.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := w.Queue.Heartbeat(ctx, delivery.JobID, w.Name)
+				switch {
+				case err == nil:
+				case err == ErrJobLost:
+					log.Warnf("worker %v lost job(%v), cancelling it", w.Name, delivery.JobID)
+					atomic.StoreInt32(&lost, 1)
+					cancel(nil)
+					return
+				case err == ErrJobCancelled:
+					log.Infof("worker %v job(%v) was cancelled", w.Name, delivery.JobID)
+					atomic.StoreInt32(&lost, 1)
+					cancel(atlante.ErrJobCancelled)
+					return
+				case ctx.Err() != nil:
+					return
+				default:
+					log.Warnf("worker %v failed to send heartbeat for job(%v): %v", w.Name, delivery.JobID, err)
+				}
+			}
+		}
+	}()
+
+	log.Infof("worker %v starting job(%v) attempt %v", w.Name, delivery.JobID, delivery.Attempt)
+	err := w.Run(ctx, delivery)
+	ctxErr := ctx.Err()
+	close(done)
+	beats.Wait()
+
+	switch {
+	case atomic.LoadInt32(&lost) == 1:
+		// the job was cancelled or given to another worker, it's not ours to finish
+		return
+	case ctxErr == context.Canceled:
+		// the worker was killed; the job will be given to another worker once
+		// its lock expires
+		log.Warnf("worker %v job(%v) killed", w.Name, delivery.JobID)
+		return
+	case ctxErr == context.DeadlineExceeded:
+		// the attempt may have already been marked as retrying, keep the retry
+		var retry atlante.ErrRetryJob
+		if errors.As(err, &retry) {
+			retry.Err = fmt.Errorf("job timed out after %v: %w", w.JobTimeout, retry.Err)
+			err = retry
+		} else {
+			err = fmt.Errorf("job timed out after %v: %w", w.JobTimeout, ctxErr)
+		}
+	}
+
+	// the job is done, even if the worker is shutting down it should be marked as such
+	fctx, fcancel := context.WithTimeout(context.Background(), finishTimeout)
+	defer fcancel()
+	if err != nil {
+		log.Warnf("worker %v job(%v) failed: %v", w.Name, delivery.JobID, err)
+		if after, ok := w.retry(delivery, err); ok {
+			log.Infof("worker %v job(%v) will be retried in %v", w.Name, delivery.JobID, after)
+			err = w.Queue.Retry(fctx, delivery.JobID, w.Name, after, err)
+		} else {
+			err = w.Queue.Fail(fctx, delivery.JobID, w.Name, err)
+		}
+	} else {
+		log.Infof("worker %v job(%v) completed", w.Name, delivery.JobID)
+		err = w.Queue.Complete(fctx, delivery.JobID, w.Name)
+	}
+	if err != nil {
+		log.Warnf("worker %v failed to finish job(%v): %v", w.Name, delivery.JobID, err)
+	}
+}
+
+// retry returns weather the failed job should be run again, and when
+func (w *Worker) retry(delivery *Delivery, err error) (time.Duration, bool) {
+	if w.Retry == nil {
+		return 0, false
+	}
+	return w.Retry(delivery, err)
+}
@@ -0,0 +1,337 @@
+package queuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante"
+)
+
+// fakePuller is an in memory Puller
+type fakePuller struct {
+	lock      sync.Mutex
+	jobs      []string
+	lost      map[string]bool
+	cancelled map[string]bool
+	completed []string
+	failed    map[string]string
+	retried   map[string]time.Duration
+}
+
+func (p *fakePuller) Enqueue(key string, _ *atlante.Job) (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.jobs = append(p.jobs, key)
+	return key, nil
+}
+
+func (p *fakePuller) Dequeue(_ context.Context, _ string) (*Delivery, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.jobs) == 0 {
+		return nil, ErrNoJobs
+	}
+	jobid := p.jobs[0]
+	p.jobs = p.jobs[1:]
+	return &Delivery{JobID: jobid, Key: jobid, Job: new(atlante.Job), Attempt: 1}, nil
+}
+
+func (p *fakePuller) Heartbeat(_ context.Context, jobid, _ string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.lost[jobid] {
+		return ErrJobLost
+	}
+	if p.cancelled[jobid] {
+		return ErrJobCancelled
+	}
+	return nil
+}
+
+func (p *fakePuller) Complete(_ context.Context, jobid, _ string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.completed = append(p.completed, jobid)
+	return nil
+}
+
+func (p *fakePuller) Fail(_ context.Context, jobid, _ string, reason error) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.failed == nil {
+		p.failed = make(map[string]string)
+	}
+	p.failed[jobid] = reason.Error()
+	return nil
+}
+
+func (p *fakePuller) Retry(_ context.Context, jobid, _ string, after time.Duration, _ error) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.retried == nil {
+		p.retried = make(map[string]time.Duration)
+	}
+	p.retried[jobid] = after
+	return nil
+}
+
+func TestWorker(t *testing.T) {
+	type tcase struct {
+		name       string
+		jobs       []string
+		lost       map[string]bool
+		timeout    time.Duration
+		completed  int
+		failed     map[string]string
+		retry      RetryFunc
+		retried    map[string]time.Duration
+		concurrent int
+	}
+
+	// run job "fail" fails, "slow" and "lost" run until cancelled, "slow-retry" runs
+	// until cancelled and is then retried, other jobs take 20ms to run
+	run := func(running, max *int, lock *sync.Mutex) RunFunc {
+		return func(ctx context.Context, d *Delivery) error {
+			lock.Lock()
+			*running++
+			if *running > *max {
+				*max = *running
+			}
+			lock.Unlock()
+			defer func() {
+				lock.Lock()
+				*running--
+				lock.Unlock()
+			}()
+			switch d.JobID {
+			case "fail":
+				return errors.New("render failed")
+			case "slow", "lost":
+				<-ctx.Done()
+				return ctx.Err()
+			case "slow-retry":
+				<-ctx.Done()
+				return atlante.ErrRetryJob{Attempt: d.Attempt + 1, After: time.Second, Err: ctx.Err()}
+			default:
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(20 * time.Millisecond):
+					return nil
+				}
+			}
+		}
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			var (
+				running, max int
+				lock         sync.Mutex
+			)
+			queue := &fakePuller{lost: tc.lost}
+			for _, job := range tc.jobs {
+				queue.Enqueue(job, nil)
+			}
+			w := Worker{
+				Queue:             queue,
+				Name:              "test",
+				Concurrency:       2,
+				JobTimeout:        tc.timeout,
+				HeartbeatInterval: 5 * time.Millisecond,
+				PollInterval:      5 * time.Millisecond,
+				Run:               run(&running, &max, &lock),
+				Retry:             tc.retry,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			if err := w.Start(ctx, context.Background()); err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+
+			if len(queue.completed) != tc.completed {
+				t.Errorf("completed, expected %v got %v", tc.completed, queue.completed)
+			}
+			if len(queue.failed) != len(tc.failed) {
+				t.Errorf("failed, expected %v got %v", tc.failed, queue.failed)
+			}
+			for job, reason := range tc.failed {
+				if !strings.Contains(queue.failed[job], reason) {
+					t.Errorf("failed %v, expected %v got %v", job, reason, queue.failed[job])
+				}
+			}
+			if len(queue.retried) != len(tc.retried) {
+				t.Errorf("retried, expected %v got %v", tc.retried, queue.retried)
+			}
+			for job, after := range tc.retried {
+				if queue.retried[job] != after {
+					t.Errorf("retried %v, expected %v got %v", job, after, queue.retried[job])
+				}
+			}
+			if max != tc.concurrent {
+				t.Errorf("concurrent jobs, expected %v got %v", tc.concurrent, max)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:       "complete",
+			jobs:       []string{"a", "b", "c"},
+			completed:  3,
+			concurrent: 2,
+		},
+		{
+			name:       "fail",
+			jobs:       []string{"fail"},
+			failed:     map[string]string{"fail": "render failed"},
+			concurrent: 1,
+		},
+		{
+			name: "retry",
+			jobs: []string{"fail"},
+			retry: func(d *Delivery, err error) (time.Duration, bool) {
+				return time.Duration(d.Attempt) * time.Second, true
+			},
+			retried:    map[string]time.Duration{"fail": time.Second},
+			concurrent: 1,
+		},
+		{
+			name: "retry exhausted",
+			jobs: []string{"fail"},
+			retry: func(d *Delivery, err error) (time.Duration, bool) {
+				return 0, false
+			},
+			failed:     map[string]string{"fail": "render failed"},
+			concurrent: 1,
+		},
+		{
+			name:       "timeout",
+			jobs:       []string{"slow", "a"},
+			timeout:    50 * time.Millisecond,
+			failed:     map[string]string{"slow": "timed out after 50ms"},
+			completed:  1,
+			concurrent: 2,
+		},
+		{
+			name:    "timeout retrying",
+			jobs:    []string{"slow-retry"},
+			timeout: 50 * time.Millisecond,
+			retry: func(d *Delivery, err error) (time.Duration, bool) {
+				var retry atlante.ErrRetryJob
+				if !errors.As(err, &retry) || !strings.Contains(retry.Err.Error(), "timed out after 50ms") {
+					return 0, false
+				}
+				return retry.After, true
+			},
+			retried:    map[string]time.Duration{"slow-retry": time.Second},
+			concurrent: 1,
+		},
+		{
+			name:       "lost",
+			jobs:       []string{"lost", "a"},
+			lost:       map[string]bool{"lost": true},
+			completed:  1,
+			concurrent: 2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestWorkerShutdown(t *testing.T) {
+	queue := &fakePuller{}
+	for i := 0; i < 4; i++ {
+		queue.Enqueue(fmt.Sprintf("job%v", i), nil)
+	}
+	started := make(chan struct{}, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := Worker{
+		Queue:       queue,
+		Name:        "test",
+		Concurrency: 2,
+		Run: func(jctx context.Context, d *Delivery) error {
+			started <- struct{}{}
+			select {
+			case <-jctx.Done():
+				return jctx.Err()
+			case <-time.After(20 * time.Millisecond):
+				return nil
+			}
+		},
+	}
+	go func() {
+		// stop pulling jobs once the first jobs are running
+		<-started
+		cancel()
+	}()
+	if err := w.Start(ctx, context.Background()); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	// the jobs that were running are finished, the rest are left in the queue
+	if len(queue.completed)+len(queue.jobs) != 4 || len(queue.completed) == 0 {
+		t.Errorf("completed, expected running jobs to finish got %v, left %v", queue.completed, queue.jobs)
+	}
+}
+
+func TestWorkerCancelCause(t *testing.T) {
+	type tcase struct {
+		name      string
+		lost      map[string]bool
+		cancelled map[string]bool
+		expected  error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			queue := &fakePuller{lost: tc.lost, cancelled: tc.cancelled}
+			queue.Enqueue("job", nil)
+			var cause error
+			w := Worker{
+				Queue:             queue,
+				Name:              "test",
+				HeartbeatInterval: 5 * time.Millisecond,
+				PollInterval:      5 * time.Millisecond,
+				Run: func(ctx context.Context, d *Delivery) error {
+					<-ctx.Done()
+					cause = atlante.CancelCause(ctx)
+					return ctx.Err()
+				},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			if err := w.Start(ctx, context.Background()); err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if cause != tc.expected {
+				t.Errorf("cause, expected %v got %v", tc.expected, cause)
+			}
+			// the job is not the worker's to finish
+			if len(queue.completed) != 0 || len(queue.failed) != 0 || len(queue.retried) != 0 {
+				t.Errorf("finished, expected none got %v %v %v", queue.completed, queue.failed, queue.retried)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:      "cancelled",
+			cancelled: map[string]bool{"job": true},
+			expected:  atlante.ErrJobCancelled,
+		},
+		{
+			name:     "lost",
+			lost:     map[string]bool{"job": true},
+			expected: context.Canceled,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -0,0 +1,108 @@
+package atlante
+
+import (
+	"context"
+	"image"
+	"sort"
+	"sync"
+
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/mbgl/bounds"
+	"github.com/go-spatial/tegola/dict"
+)
+
+const (
+	// ConfigKeyRendererType is the config key for the type of renderer
+	ConfigKeyRendererType = "type"
+	// DefaultRendererType is the type of renderer used if one is not configured
+	DefaultRendererType = "mbgl"
+)
+
+// RenderRequest describes the map image to render
+type RenderRequest struct {
+	// Grid is the grid being rendered
+	Grid       *grids.Cell
+	Projection bounds.AProjection
+	// Width and Height of the image in pixels
+	Width, Height int
+	// Center of the image in pixels at the zoom, see bounds.LatLngToPoint; with a tile size of 2048
+	Center [2]float64
+	Zoom   float64
+	// Style is the location of the style to render the map with
+	Style string
+}
+
+// RenderedImage is a map image from a renderer. The pixels may not be rendered till
+// GenerateImage is called, but the bounds of the image are known.
+type RenderedImage interface {
+	image.Image
+	// GenerateImage renders the pixels of the image; it is safe to call more then once
+	GenerateImage() error
+}
+
+// Renderer renders the map images of the grids
+type Renderer interface {
+	Render(ctx context.Context, req RenderRequest) (RenderedImage, error)
+}
+
+// RendererConfig is the config passed to a renderer to configure it
+type RendererConfig interface {
+	dict.Dicter
+}
+
+// RendererInitFunc initilizes a renderer given the config.
+// RendererInitFunc should validate the config, and report any errors.
+type RendererInitFunc func(RendererConfig) (Renderer, error)
+
+var (
+	renderersLock sync.RWMutex
+	renderers     map[string]RendererInitFunc
+)
+
+// RegisterRenderer is called by the init functions of the renderers
+func RegisterRenderer(rendererType string, init RendererInitFunc) error {
+	renderersLock.Lock()
+	defer renderersLock.Unlock()
+
+	if renderers == nil {
+		renderers = make(map[string]RendererInitFunc)
+	}
+	if _, ok := renderers[rendererType]; ok {
+		return ErrRendererExists(rendererType)
+	}
+	renderers[rendererType] = init
+	return nil
+}
+
+// RegisteredRenderers returns the types of the renderers that have been registered
+func RegisteredRenderers() []string {
+	renderersLock.RLock()
+	r := make([]string, 0, len(renderers))
+	for k := range renderers {
+		r = append(r, k)
+	}
+	renderersLock.RUnlock()
+	sort.Strings(r)
+	return r
+}
+
+// RendererFor returns a configured renderer of the given type
+func RendererFor(rendererType string, config RendererConfig) (Renderer, error) {
+	renderersLock.RLock()
+	defer renderersLock.RUnlock()
+	init, ok := renderers[rendererType]
+	if !ok {
+		return nil, ErrUnknownRenderer(rendererType)
+	}
+	return init(config)
+}
+
+// RendererFrom is like RendererFor but assumes that the config has a ConfigKeyRendererType
+// value informing the type of renderer being configured
+func RendererFrom(config RendererConfig) (Renderer, error) {
+	rType, err := config.String(ConfigKeyRendererType, nil)
+	if err != nil {
+		return nil, err
+	}
+	return RendererFor(rType, config)
+}
@@ -0,0 +1,50 @@
+// Package mbgl renders the map images with mapbox-gl native. The mbgl snapshot
+// manager must be started before images are rendered.
+package mbgl
+
+import (
+	"context"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/mbgl/image"
+)
+
+const (
+	// TYPE of the renderer
+	TYPE = atlante.DefaultRendererType
+)
+
+func initFunc(cfg atlante.RendererConfig) (atlante.Renderer, error) {
+	return Renderer{}, nil
+}
+
+func init() {
+	atlante.RegisterRenderer(TYPE, initFunc)
+}
+
+// Renderer renders the map images with mapbox-gl native
+type Renderer struct{}
+
+// Render implements the atlante.Renderer interface
+func (Renderer) Render(ctx context.Context, req atlante.RenderRequest) (atlante.RenderedImage, error) {
+	img, err := image.New(
+		ctx,
+
+		req.Projection,
+		req.Width, req.Height,
+		req.Center,
+		req.Zoom,
+		// TODO(gdey): Need to remove this hack and figure out how to used the
+		// ppi value as well as set the correct scale on the svg/pdf document
+		// that is produced later on. (https://github.com/go-spatial/atlante/issues/13)
+		1.0, // ppiRatio, (we adjust the zoom)
+		0.0, // Bearing
+		0.0, // Pitch
+		req.Style,
+		"", "",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
@@ -0,0 +1,234 @@
+package atlante
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/gdey/errors"
+)
+
+const (
+	// FakeRendererType is the type of the fake renderer
+	FakeRendererType = "fake"
+
+	// ConfigKeyFakePattern is the config key for the pattern of the fake renderer: solid or checkerboard
+	ConfigKeyFakePattern = "pattern"
+	// ConfigKeyFakeColor is the config key for the colour of the fake renderer, as #rrggbb
+	ConfigKeyFakeColor = "color"
+	// ConfigKeyFakeAltColor is the config key for the second colour of the checkerboard
+	ConfigKeyFakeAltColor = "alt_color"
+	// ConfigKeyFakeCellSize is the config key for the size, in pixels, of the squares of the checkerboard
+	ConfigKeyFakeCellSize = "cell_size"
+	// ConfigKeyFakeAnnotate is the config key to turn off the annotations of the bounds
+	ConfigKeyFakeAnnotate = "annotate"
+
+	// FakePatternSolid fills the image with the colour
+	FakePatternSolid = "solid"
+	// FakePatternCheckerboard fills the image with squares of the colour and the alt colour
+	FakePatternCheckerboard = "checkerboard"
+
+	// ErrInvalidColor is returned when a colour is not in the #rrggbb form
+	ErrInvalidColor = errors.String("invalid color, expected #rrggbb")
+)
+
+var (
+	defaultFakeColor    = color.RGBA{R: 0xd8, G: 0xe8, B: 0xc8, A: 0xff}
+	defaultFakeAltColor = color.RGBA{R: 0xb8, G: 0xd0, B: 0xe8, A: 0xff}
+)
+
+// parseHexColor parses a #rrggbb colour
+func parseHexColor(s string) (color.RGBA, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return color.RGBA{}, ErrInvalidColor
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, ErrInvalidColor
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+func initFakeRenderer(cfg RendererConfig) (Renderer, error) {
+	r := FakeRenderer{
+		Pattern:  FakePatternCheckerboard,
+		Color:    defaultFakeColor,
+		AltColor: defaultFakeAltColor,
+		CellSize: 64,
+		Annotate: true,
+	}
+	pattern, err := cfg.String(ConfigKeyFakePattern, &r.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	switch pattern {
+	case FakePatternSolid, FakePatternCheckerboard:
+		r.Pattern = pattern
+	default:
+		return nil, fmt.Errorf("unknown %v %v, expected %v or %v", ConfigKeyFakePattern, pattern, FakePatternSolid, FakePatternCheckerboard)
+	}
+	for key, c := range map[string]*color.RGBA{
+		ConfigKeyFakeColor:    &r.Color,
+		ConfigKeyFakeAltColor: &r.AltColor,
+	} {
+		var def string
+		s, err := cfg.String(key, &def)
+		if err != nil {
+			return nil, err
+		}
+		if s == "" {
+			continue
+		}
+		if *c, err = parseHexColor(s); err != nil {
+			return nil, fmt.Errorf("%v: %v", key, err)
+		}
+	}
+	if r.CellSize, err = cfg.Int(ConfigKeyFakeCellSize, &r.CellSize); err != nil {
+		return nil, err
+	}
+	if r.CellSize <= 0 {
+		return nil, fmt.Errorf("%v must be greater than zero", ConfigKeyFakeCellSize)
+	}
+	if r.Annotate, err = cfg.Bool(ConfigKeyFakeAnnotate, &r.Annotate); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func init() {
+	RegisterRenderer(FakeRendererType, initFakeRenderer)
+}
+
+// FakeRenderer is a renderer that draws a solid colour or a checkerboard, annotated with
+// the bounds of the grid and the zoom, instead of a map. It does not need the mbgl
+// libraries or the styles, so is used for tests.
+type FakeRenderer struct {
+	Pattern  string
+	Color    color.RGBA
+	AltColor color.RGBA
+	// CellSize is the size, in pixels, of the squares of the checkerboard
+	CellSize int
+	// Annotate draws a border, a cross at the center, and the bounds of the grid and the zoom
+	Annotate bool
+}
+
+// Render implements the Renderer interface
+func (r FakeRenderer) Render(ctx context.Context, req RenderRequest) (RenderedImage, error) {
+	if req.Width <= 0 || req.Height <= 0 {
+		return nil, fmt.Errorf("invalid image size %vx%v", req.Width, req.Height)
+	}
+	img := fakeImage{
+		FakeRenderer: r,
+		width:        req.Width,
+		height:       req.Height,
+	}
+	if r.Annotate {
+		img.scale = req.Width / 300
+		if img.scale < 1 {
+			img.scale = 1
+		}
+		lines := []string{fmt.Sprintf("Z %.2f", req.Zoom)}
+		if req.Grid != nil {
+			sw, ne := req.Grid.SW(), req.Grid.NE()
+			// as lat lng
+			lines = append(lines,
+				fmt.Sprintf("NE %.5f %.5f", ne[1], ne[0]),
+				fmt.Sprintf("SW %.5f %.5f", sw[1], sw[0]),
+			)
+		}
+		img.lines = lines
+	}
+	return img, nil
+}
+
+// fakeFont is a 3x5 pixel font for the annotations; each row is 3 bits, the left most pixel is the high bit
+var fakeFont = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7}, '3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7}, '6': {7, 4, 7, 5, 7}, '7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7}, '9': {7, 5, 7, 1, 7}, '-': {0, 0, 7, 0, 0}, '.': {0, 0, 0, 0, 2},
+	'N': {5, 7, 7, 7, 5}, 'E': {7, 4, 7, 4, 7}, 'S': {7, 4, 7, 1, 7}, 'W': {5, 5, 7, 7, 5},
+	'Z': {7, 1, 2, 4, 7},
+}
+
+const (
+	// fakeBorder is the width, in pixels, of the border of an annotated image
+	fakeBorder = 2
+	// fakeMargin is the margin, in font pixels, around the annotations
+	fakeMargin = 2
+)
+
+// fakeImage is the image of the fake renderer, the pixels are computed as they are asked for
+type fakeImage struct {
+	FakeRenderer
+	width, height int
+	// scale of the font
+	scale int
+	lines []string
+}
+
+func (img fakeImage) ColorModel() color.Model { return color.RGBAModel }
+
+func (img fakeImage) Bounds() image.Rectangle { return image.Rect(0, 0, img.width, img.height) }
+
+// GenerateImage implements the RenderedImage interface, the pixels are computed by At
+func (fakeImage) GenerateImage() error { return nil }
+
+// annotation returns the colour of the annotations at x, y, if there is an annotation there
+func (img fakeImage) annotation(x, y int) (color.RGBA, bool) {
+	var (
+		black = color.RGBA{A: 0xff}
+		white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	)
+	if x < fakeBorder || y < fakeBorder || x >= img.width-fakeBorder || y >= img.height-fakeBorder {
+		return black, true
+	}
+	cx, cy, arm := img.width/2, img.height/2, 10*img.scale
+	if (x == cx && y > cy-arm && y < cy+arm) || (y == cy && x > cx-arm && x < cx+arm) {
+		return black, true
+	}
+
+	// the lines of text are in a white box at the top left, in font pixels each glyph
+	// is 3 wide with a space, and each line is 5 high with a space
+	var maxLen int
+	for _, line := range img.lines {
+		if len(line) > maxLen {
+			maxLen = len(line)
+		}
+	}
+	fx, fy := (x-fakeBorder)/img.scale, (y-fakeBorder)/img.scale
+	if len(img.lines) == 0 || fx >= maxLen*4-1+2*fakeMargin || fy >= len(img.lines)*6-1+2*fakeMargin {
+		return color.RGBA{}, false
+	}
+	tx, ty := fx-fakeMargin, fy-fakeMargin
+	if tx < 0 || ty < 0 {
+		return white, true
+	}
+	row, ry, col, rx := ty/6, ty%6, tx/4, tx%4
+	if row >= len(img.lines) || ry == 5 || col >= len(img.lines[row]) || rx == 3 {
+		return white, true
+	}
+	if fakeFont[rune(img.lines[row][col])][ry]&(4>>uint(rx)) != 0 {
+		return black, true
+	}
+	return white, true
+}
+
+// At implements the image.Image interface
+func (img fakeImage) At(x, y int) color.Color {
+	if x < 0 || y < 0 || x >= img.width || y >= img.height {
+		return color.RGBA{}
+	}
+	if img.Annotate {
+		if c, ok := img.annotation(x, y); ok {
+			return c
+		}
+	}
+	if img.Pattern == FakePatternCheckerboard && (x/img.CellSize+y/img.CellSize)%2 == 1 {
+		return img.AltColor
+	}
+	return img.Color
+}
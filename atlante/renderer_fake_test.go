@@ -0,0 +1,292 @@
+package atlante
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/filestore"
+	fsfile "github.com/go-spatial/atlante/atlante/filestore/file"
+	fsmulti "github.com/go-spatial/atlante/atlante/filestore/multi"
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/tegola/dict"
+)
+
+func TestParseHexColor(t *testing.T) {
+	type tcase struct {
+		color    string
+		expected color.RGBA
+		err      error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.color, func(t *testing.T) {
+			got, err := parseHexColor(tc.color)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got != tc.expected {
+				t.Errorf("color, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{color: "#ff8000", expected: color.RGBA{R: 0xff, G: 0x80, A: 0xff}},
+		{color: "0000FF", expected: color.RGBA{B: 0xff, A: 0xff}},
+		{color: "#fff", err: ErrInvalidColor},
+		{color: "#gg0000", err: ErrInvalidColor},
+		{color: "", err: ErrInvalidColor},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestRendererFrom(t *testing.T) {
+	type tcase struct {
+		name     string
+		config   dict.Dict
+		expected FakeRenderer
+		err      string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			r, err := RendererFrom(tc.config)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			got, ok := r.(FakeRenderer)
+			if !ok {
+				t.Fatalf("renderer, expected FakeRenderer got %T", r)
+			}
+			if got != tc.expected {
+				t.Errorf("renderer, expected %+v got %+v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:   "defaults",
+			config: dict.Dict{"type": "fake"},
+			expected: FakeRenderer{
+				Pattern:  FakePatternCheckerboard,
+				Color:    defaultFakeColor,
+				AltColor: defaultFakeAltColor,
+				CellSize: 64,
+				Annotate: true,
+			},
+		},
+		{
+			name: "solid",
+			config: dict.Dict{
+				"type":     "fake",
+				"pattern":  "solid",
+				"color":    "#102030",
+				"annotate": false,
+			},
+			expected: FakeRenderer{
+				Pattern:  FakePatternSolid,
+				Color:    color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff},
+				AltColor: defaultFakeAltColor,
+				CellSize: 64,
+			},
+		},
+		{
+			name:   "bad pattern",
+			config: dict.Dict{"type": "fake", "pattern": "stripes"},
+			err:    "unknown pattern stripes",
+		},
+		{
+			name:   "bad color",
+			config: dict.Dict{"type": "fake", "alt_color": "blue"},
+			err:    "alt_color: " + string(ErrInvalidColor),
+		},
+		{
+			name:   "bad cell size",
+			config: dict.Dict{"type": "fake", "cell_size": 0},
+			err:    "cell_size must be greater than zero",
+		},
+		{
+			name:   "unknown type",
+			config: dict.Dict{"type": "unknown"},
+			err:    ErrUnknownRenderer("unknown").Error(),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestFakeRenderer(t *testing.T) {
+	var (
+		black = color.RGBA{A: 0xff}
+		white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		red   = color.RGBA{R: 0xff, A: 0xff}
+		blue  = color.RGBA{B: 0xff, A: 0xff}
+	)
+	type tcase struct {
+		name     string
+		renderer FakeRenderer
+		// pixels are the expected colours at the points
+		pixels map[[2]int]color.RGBA
+	}
+
+	grid := grids.NewCell("V795G25492", [2]float64{32.5, -117.25}, [2]float64{32.75, -117}, "", "", nil, nil, time.Time{}, "", "", "V795", [2]string{}, [2]string{}, nil)
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			img, err := tc.renderer.Render(context.Background(), RenderRequest{
+				Grid:   grid,
+				Width:  400,
+				Height: 300,
+				Zoom:   11.5,
+			})
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if err = img.GenerateImage(); err != nil {
+				t.Fatalf("generate image, expected nil got %v", err)
+			}
+			if got := img.Bounds(); got.Dx() != 400 || got.Dy() != 300 {
+				t.Errorf("bounds, expected 400x300 got %v", got)
+			}
+			for pt, expected := range tc.pixels {
+				if got := img.At(pt[0], pt[1]); got != expected {
+					t.Errorf("pixel %v, expected %v got %v", pt, expected, got)
+				}
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:     "solid",
+			renderer: FakeRenderer{Pattern: FakePatternSolid, Color: red, AltColor: blue, CellSize: 10},
+			pixels: map[[2]int]color.RGBA{
+				{0, 0}:     red,
+				{15, 5}:    red,
+				{399, 299}: red,
+			},
+		},
+		{
+			name:     "checkerboard",
+			renderer: FakeRenderer{Pattern: FakePatternCheckerboard, Color: red, AltColor: blue, CellSize: 10},
+			pixels: map[[2]int]color.RGBA{
+				{0, 0}:   red,
+				{15, 5}:  blue,
+				{15, 15}: red,
+				{5, 15}:  blue,
+			},
+		},
+		{
+			name:     "annotated",
+			renderer: FakeRenderer{Pattern: FakePatternSolid, Color: red, AltColor: blue, CellSize: 10, Annotate: true},
+			pixels: map[[2]int]color.RGBA{
+				// border
+				{0, 0}:     black,
+				{399, 150}: black,
+				// cross at the center
+				{200, 150}: black,
+				{205, 150}: black,
+				{200, 145}: black,
+				{210, 160}: red,
+				// margin of the text box
+				{2, 2}: white,
+				// the top of the Z
+				{4, 4}: black,
+				// past the text box
+				{300, 4}:   red,
+				{4, 100}:   red,
+				{396, 296}: red,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+// TestRenderSVGFake runs a sheet through the template and the filestore with the fake renderer
+func TestRenderSVGFake(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atlante_render")
+	if err != nil {
+		t.Fatalf("temp dir, expected nil got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sheet := &Sheet{
+		Name:   "50k",
+		DPI:    144,
+		Scale:  50000,
+		Width:  200,
+		Height: 150,
+		Renderer: FakeRenderer{
+			Pattern:  FakePatternSolid,
+			Color:    color.RGBA{R: 0xff, A: 0xff},
+			CellSize: 64,
+		},
+	}
+	sheet.svgTemplate = template.Must(
+		template.New("test").Funcs(sheet.AddTemplateFuncs(funcMap)).Parse(
+			`<svg width="{{.Width}}" height="{{.Height}}">` +
+				`{{ .Image.Place 0 0 .Width .Height }}` +
+				`<image xlink:href="{{ .Image.Filename }}" data-zoom="{{ printf "%.2f" .Zoom }}"/></svg>`,
+		),
+	)
+	grid := grids.NewCell("V795G25492", [2]float64{32.5, -117.25}, [2]float64{32.75, -117}, "", "", nil, nil, time.Time{}, "", "", "V795", [2]string{}, [2]string{}, nil)
+	grid.MetaData = map[string]string{"styleLocation": "fake"}
+
+	filenames := &GeneratedFiles{
+		IMG: filepath.Join(dir, "grid.png"),
+		SVG: filepath.Join(dir, "grid.svg"),
+	}
+	multiWriter := fsmulti.FileWriter{
+		Writers: []filestore.FileWriter{fsfile.Writer{Intermediate: true}},
+	}
+	gtc, err := renderSVG(context.Background(), sheet, grid, filenames, multiWriter, false, 0, 0)
+	if err != nil {
+		t.Fatalf("render svg, expected nil got %v", err)
+	}
+
+	svg, err := ioutil.ReadFile(filenames.SVG)
+	if err != nil {
+		t.Fatalf("read svg, expected nil got %v", err)
+	}
+	expected := fmt.Sprintf(`xlink:href="%v" data-zoom="%.2f"`, filenames.IMG, gtc.Zoom())
+	if !strings.Contains(string(svg), expected) {
+		t.Errorf("svg, expected %q in %s", expected, svg)
+	}
+
+	f, err := os.Open(filenames.IMG)
+	if err != nil {
+		t.Fatalf("open image, expected nil got %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decode image, expected nil got %v", err)
+	}
+	if got, expected := img.Bounds().Dx(), gtc.Image.Width(); got != expected {
+		t.Errorf("image width, expected %v got %v", expected, got)
+	}
+	if r, g, b, _ := img.At(img.Bounds().Dx()/2, img.Bounds().Dy()/2).RGBA(); r != 0xffff || g != 0 || b != 0 {
+		t.Errorf("image center, expected red got %v %v %v", r, g, b)
+	}
+}
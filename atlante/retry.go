@@ -0,0 +1,268 @@
+package atlante
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/filestore"
+	"github.com/go-spatial/atlante/atlante/notifiers"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/prometheus/common/log"
+)
+
+// ErrorClass is the kind of error a job failed with; the retry policy uses it to decide
+// if a failed job should be run again.
+type ErrorClass string
+
+const (
+	// ErrorClassRender the renderer failed to render the map image
+	ErrorClassRender = ErrorClass("render")
+	// ErrorClassFilestore a file could not be written to a file store
+	ErrorClassFilestore = ErrorClass("filestore")
+	// ErrorClassTimeout the job, or something it was waiting on, timed out
+	ErrorClassTimeout = ErrorClass("timeout")
+	// ErrorClassNetwork a network request failed
+	ErrorClassNetwork = ErrorClass("network")
+	// ErrorClassAny matches every error
+	ErrorClassAny = ErrorClass("any")
+)
+
+const (
+	// DefaultRetryMaxAttempts is the number of times a job is run; by default failed jobs
+	// are not retried
+	DefaultRetryMaxAttempts = 1
+	// DefaultRetryBackoff is how long to wait before the first retry
+	DefaultRetryBackoff = 10 * time.Second
+	// DefaultRetryMaxBackoff is the longest to wait between retries
+	DefaultRetryMaxBackoff = 10 * time.Minute
+	// DefaultRetryMultiplier is how much the wait grows after each retry
+	DefaultRetryMultiplier = 2.0
+)
+
+// DefaultRetryOn are the error classes that are retried if none are configured
+var DefaultRetryOn = []ErrorClass{ErrorClassFilestore, ErrorClassTimeout, ErrorClassNetwork}
+
+// ErrUnknownErrorClass is returned when an error class is not one of the known classes
+type ErrUnknownErrorClass string
+
+func (err ErrUnknownErrorClass) Error() string {
+	return fmt.Sprintf("unknown error class %v, expected one of render, filestore, timeout, network or any", string(err))
+}
+
+// ParseErrorClass returns the error class for the name
+func ParseErrorClass(name string) (ErrorClass, error) {
+	class := ErrorClass(strings.ToLower(strings.TrimSpace(name)))
+	switch class {
+	case ErrorClassRender, ErrorClassFilestore, ErrorClassTimeout, ErrorClassNetwork, ErrorClassAny:
+		return class, nil
+	default:
+		return "", ErrUnknownErrorClass(name)
+	}
+}
+
+// ErrorClasses returns the classes of the error; an error may belong to more than
+// one class, e.g. a file store write that timed out is both a filestore and a timeout
+// error. Cancelled jobs do not belong to any class.
+func ErrorClasses(err error) (classes []ErrorClass) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	if errors.As(err, new(ErrRender)) {
+		classes = append(classes, ErrorClassRender)
+	}
+	if errors.As(err, new(filestore.ErrPath)) {
+		classes = append(classes, ErrorClassFilestore)
+	}
+	var tErr interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &tErr) && tErr.Timeout()) {
+		classes = append(classes, ErrorClassTimeout)
+	}
+	// context.DeadlineExceeded is also a net.Error, so look for the errors of the net
+	// and http clients instead
+	if errors.As(err, new(*net.OpError)) || errors.As(err, new(*net.DNSError)) || errors.As(err, new(*url.Error)) {
+		classes = append(classes, ErrorClassNetwork)
+	}
+	return classes
+}
+
+// RetryPolicy decides if, and when, a failed job is run again
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a job is run, including the first run
+	MaxAttempts int
+	// Backoff is how long to wait before the first retry
+	Backoff time.Duration
+	// MaxBackoff is the longest to wait between retries
+	MaxBackoff time.Duration
+	// Multiplier is how much the wait grows after each retry
+	Multiplier float64
+	// RetryOn are the classes of errors that are retried
+	RetryOn []ErrorClass
+}
+
+// NewRetryPolicy returns a retry policy with the defaults for the values that are zero
+func NewRetryPolicy(maxAttempts int, backoff, maxBackoff time.Duration, multiplier float64, retryOn []string) (*RetryPolicy, error) {
+	policy := RetryPolicy{
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		MaxBackoff:  maxBackoff,
+		Multiplier:  multiplier,
+	}
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if policy.Backoff == 0 {
+		policy.Backoff = DefaultRetryBackoff
+	}
+	if policy.MaxBackoff == 0 {
+		// a backoff longer than the default max backoff is the max backoff
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+		if policy.Backoff > policy.MaxBackoff {
+			policy.MaxBackoff = policy.Backoff
+		}
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = DefaultRetryMultiplier
+	}
+	switch {
+	case policy.MaxAttempts < 0:
+		return nil, fmt.Errorf("retry max attempts must be greater than zero, got %v", maxAttempts)
+	case policy.Backoff < 0:
+		return nil, fmt.Errorf("retry backoff must be greater than zero, got %v", backoff)
+	case policy.MaxBackoff < policy.Backoff:
+		return nil, fmt.Errorf("retry max backoff (%v) must not be less than the backoff (%v)", policy.MaxBackoff, policy.Backoff)
+	case policy.Multiplier < 1:
+		return nil, fmt.Errorf("retry multiplier must be at least 1, got %v", multiplier)
+	}
+	if len(retryOn) == 0 {
+		policy.RetryOn = append(policy.RetryOn, DefaultRetryOn...)
+	}
+	for _, name := range retryOn {
+		class, err := ParseErrorClass(name)
+		if err != nil {
+			return nil, err
+		}
+		policy.RetryOn = append(policy.RetryOn, class)
+	}
+	return &policy, nil
+}
+
+// Retryable returns weather the error belongs to one of the classes that are retried
+func (policy *RetryPolicy) Retryable(err error) bool {
+	if policy == nil || err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	classes := ErrorClasses(err)
+	for _, retry := range policy.RetryOn {
+		if retry == ErrorClassAny {
+			return true
+		}
+		for _, class := range classes {
+			if class == retry {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// BackoffFor returns how long to wait before running the job again after the
+// given attempt failed; attempts start at 1.
+func (policy *RetryPolicy) BackoffFor(attempt int) time.Duration {
+	if policy == nil {
+		return 0
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	backoff := float64(policy.Backoff) * math.Pow(policy.Multiplier, float64(attempt-1))
+	if backoff > float64(policy.MaxBackoff) {
+		return policy.MaxBackoff
+	}
+	return time.Duration(backoff)
+}
+
+// ShouldRetry returns weather the job should be run again after the given attempt
+// failed with err, and how long to wait before doing so.
+func (policy *RetryPolicy) ShouldRetry(attempt int, err error) (time.Duration, bool) {
+	if policy == nil || attempt >= policy.MaxAttempts || !policy.Retryable(err) {
+		return 0, false
+	}
+	return policy.BackoffFor(attempt), true
+}
+
+// RunJob runs the attempt, starting at 1, of the job. If the attempt fails the retry
+// policy of the sheet of the job, or queuePolicy if the sheet does not have one, decides
+// if the job is run again before the failure is emitted; a job that will be run again
+// only emits the retrying status, with the next attempt, and ErrRetryJob is returned.
+// The statuses are emitted to the emitter of ctx, or a new emitter for the job if ctx
+// does not carry one.
+func (a *Atlante) RunJob(ctx context.Context, job Job, attempt int, queuePolicy *RetryPolicy, filenameTemplate string) (*GeneratedFiles, error) {
+	if a == nil {
+		return nil, ErrNilAtlanteObject
+	}
+	emitter := EmitterFrom(a.jobContext(ctx, job))
+	held := &heldEmitter{emitter: emitter}
+	files, err := a.generatePDFJob(WithEmitter(ctx, held), job, filenameTemplate)
+	if err == nil {
+		held.flush()
+		return files, nil
+	}
+
+	policy := queuePolicy
+	if sheet, serr := a.SheetFor(job.SheetName); serr == nil && sheet.Retry != nil {
+		policy = sheet.Retry
+	}
+	backoff, ok := policy.ShouldRetry(attempt, err)
+	if !ok {
+		held.flush()
+		return files, err
+	}
+	if emitter != nil {
+		if eerr := emitter.Emit(field.Retrying{Attempt: attempt + 1, Error: err}); eerr != nil {
+			log.Warnf("failed to emit retrying status for job(%v): %v", job.MetaData["job_id"], eerr)
+		}
+	}
+	return files, ErrRetryJob{Attempt: attempt + 1, After: backoff, Err: err}
+}
+
+// heldEmitter passes the statuses of an attempt of a job to the emitter, except for the
+// failed statuses, which are held until it is known if the job will be retried
+type heldEmitter struct {
+	emitter notifiers.Emitter
+
+	lock   sync.Mutex
+	failed []field.StatusEnum
+}
+
+// Emit implements the notifiers.Emitter interface
+func (he *heldEmitter) Emit(status field.StatusEnum) error {
+	if he.emitter == nil {
+		return nil
+	}
+	if _, ok := status.(field.Failed); ok {
+		he.lock.Lock()
+		he.failed = append(he.failed, status)
+		he.lock.Unlock()
+		return nil
+	}
+	return he.emitter.Emit(status)
+}
+
+// flush emits the held failed statuses
+func (he *heldEmitter) flush() {
+	he.lock.Lock()
+	defer he.lock.Unlock()
+	for _, status := range he.failed {
+		if err := he.emitter.Emit(status); err != nil {
+			log.Warnf("failed to emit status: %v", err)
+		}
+	}
+	he.failed = nil
+}
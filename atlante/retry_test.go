@@ -0,0 +1,315 @@
+package atlante
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/filestore"
+	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+	"github.com/go-spatial/atlante/svg2pdf"
+)
+
+func TestErrorClasses(t *testing.T) {
+	type tcase struct {
+		name     string
+		err      error
+		expected []ErrorClass
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			classes := ErrorClasses(tc.err)
+			if !reflect.DeepEqual(classes, tc.expected) {
+				t.Errorf("classes, expected %v got %v", tc.expected, classes)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{name: "nil"},
+		{name: "other", err: errors.New("bad template")},
+		{name: "cancelled", err: fmt.Errorf("job: %w", context.Canceled)},
+		{
+			name:     "render",
+			err:      fmt.Errorf("template: %w", ErrRender{Err: errors.New("mbgl failed")}),
+			expected: []ErrorClass{ErrorClassRender},
+		},
+		{
+			name:     "filestore",
+			err:      filestore.ErrPath{FilestoreType: "s3", Err: errors.New("access denied")},
+			expected: []ErrorClass{ErrorClassFilestore},
+		},
+		{
+			name:     "deadline",
+			err:      fmt.Errorf("job timed out: %w", context.DeadlineExceeded),
+			expected: []ErrorClass{ErrorClassTimeout},
+		},
+		{
+			name: "filestore network timeout",
+			err: filestore.ErrPath{
+				FilestoreType: "s3",
+				Err:           &net.OpError{Op: "dial", Err: &net.DNSError{IsTimeout: true}},
+			},
+			expected: []ErrorClass{ErrorClassFilestore, ErrorClassTimeout, ErrorClassNetwork},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	type tcase struct {
+		name        string
+		maxAttempts int
+		backoff     time.Duration
+		maxBackoff  time.Duration
+		multiplier  float64
+		retryOn     []string
+		expected    *RetryPolicy
+		err         error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			policy, err := NewRetryPolicy(tc.maxAttempts, tc.backoff, tc.maxBackoff, tc.multiplier, tc.retryOn)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(policy, tc.expected) {
+				t.Errorf("policy, expected %+v got %+v", tc.expected, policy)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name: "defaults",
+			expected: &RetryPolicy{
+				MaxAttempts: DefaultRetryMaxAttempts,
+				Backoff:     DefaultRetryBackoff,
+				MaxBackoff:  DefaultRetryMaxBackoff,
+				Multiplier:  DefaultRetryMultiplier,
+				RetryOn:     DefaultRetryOn,
+			},
+		},
+		{
+			name:        "set",
+			maxAttempts: 5,
+			backoff:     time.Second,
+			maxBackoff:  time.Minute,
+			multiplier:  3,
+			retryOn:     []string{"Render", " any "},
+			expected: &RetryPolicy{
+				MaxAttempts: 5,
+				Backoff:     time.Second,
+				MaxBackoff:  time.Minute,
+				Multiplier:  3,
+				RetryOn:     []ErrorClass{ErrorClassRender, ErrorClassAny},
+			},
+		},
+		{
+			name:    "unknown class",
+			retryOn: []string{"disk"},
+			err:     ErrUnknownErrorClass("disk"),
+		},
+		{
+			name:    "backoff over default max backoff",
+			backoff: 15 * time.Minute,
+			expected: &RetryPolicy{
+				MaxAttempts: DefaultRetryMaxAttempts,
+				Backoff:     15 * time.Minute,
+				MaxBackoff:  15 * time.Minute,
+				Multiplier:  DefaultRetryMultiplier,
+				RetryOn:     DefaultRetryOn,
+			},
+		},
+		{
+			name:       "max backoff less than backoff",
+			backoff:    time.Minute,
+			maxBackoff: time.Second,
+			err:        errors.New("retry max backoff (1s) must not be less than the backoff (1m0s)"),
+		},
+		{
+			name:       "multiplier",
+			multiplier: 0.5,
+			err:        errors.New("retry multiplier must be at least 1, got 0.5"),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	type tcase struct {
+		name    string
+		policy  *RetryPolicy
+		attempt int
+		err     error
+		backoff time.Duration
+		retry   bool
+	}
+
+	policy := &RetryPolicy{
+		MaxAttempts: 4,
+		Backoff:     10 * time.Second,
+		MaxBackoff:  30 * time.Second,
+		Multiplier:  2,
+		RetryOn:     []ErrorClass{ErrorClassFilestore},
+	}
+	fsErr := filestore.ErrPath{FilestoreType: "s3", Err: errors.New("put failed")}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			backoff, retry := tc.policy.ShouldRetry(tc.attempt, tc.err)
+			if retry != tc.retry {
+				t.Fatalf("retry, expected %v got %v", tc.retry, retry)
+			}
+			if backoff != tc.backoff {
+				t.Errorf("backoff, expected %v got %v", tc.backoff, backoff)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{name: "first", policy: policy, attempt: 1, err: fsErr, backoff: 10 * time.Second, retry: true},
+		{name: "second", policy: policy, attempt: 2, err: fsErr, backoff: 20 * time.Second, retry: true},
+		{name: "max backoff", policy: policy, attempt: 3, err: fsErr, backoff: 30 * time.Second, retry: true},
+		{name: "max attempts", policy: policy, attempt: 4, err: fsErr},
+		{name: "not retryable", policy: policy, attempt: 1, err: ErrRender{Err: errors.New("bad style")}},
+		{name: "nil policy", attempt: 1, err: fsErr},
+		{
+			name:    "cancelled",
+			policy:  &RetryPolicy{MaxAttempts: 2, RetryOn: []ErrorClass{ErrorClassAny}},
+			attempt: 1,
+			err:     context.Canceled,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+// failingPDFConverter fails to convert the svgs
+type failingPDFConverter struct{}
+
+func (failingPDFConverter) GeneratePDF(string, string, float64, float64) error {
+	return errors.New("convert failed")
+}
+func (failingPDFConverter) GenerateMultiPagePDF([]svg2pdf.Page, string) error {
+	return errors.New("convert failed")
+}
+
+func TestRunJobRetry(t *testing.T) {
+	type tcase struct {
+		name    string
+		attempt int
+		// retry is if the job is to be run again
+		retry bool
+		// ctxEmitter is if the context of the job already carries an emitter
+		ctxEmitter bool
+	}
+
+	dir, err := ioutil.TempDir("", "atlante_retry")
+	if err != nil {
+		t.Fatalf("temp dir, expected nil got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sheet := &Sheet{
+		Name:         "50k",
+		DPI:          72,
+		Scale:        50000,
+		Width:        200,
+		Height:       150,
+		Renderer:     FakeRenderer{Pattern: FakePatternSolid, Color: color.RGBA{R: 0xff, A: 0xff}, CellSize: 64},
+		PDFConverter: failingPDFConverter{},
+		Retry:        &RetryPolicy{MaxAttempts: 2, Backoff: time.Second, MaxBackoff: time.Second, Multiplier: 1, RetryOn: []ErrorClass{ErrorClassAny}},
+	}
+	sheet.svgTemplate = template.Must(template.New("test").Funcs(funcMap).Parse(
+		`<svg width="{{.Width}}" height="{{.Height}}">{{ .Image.Place 0 0 .Width .Height }}</svg>`,
+	))
+	notifier := new(jobNotifier)
+	a := &Atlante{Notifier: notifier}
+	if err = a.AddSheet(sheet); err != nil {
+		t.Fatalf("add sheet, expected nil got %v", err)
+	}
+	filenameTemplate := filepath.Join(dir, "{{.Grid.MetaData.filename}}.{{.Ext}}")
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			cell := grids.NewCell("V795G25492", [2]float64{32.5, -117.25}, [2]float64{32.75, -117}, "", "", nil, nil, time.Time{}, "", "", "V795", [2]string{}, [2]string{}, nil)
+			cell.MetaData = map[string]string{"styleLocation": "fake"}
+			job := NewJob(sheet.Name, cell, map[string]string{"job_id": tc.name, "filename": tc.name})
+
+			ctx := context.Background()
+			emitter := new(recordingEmitter)
+			if tc.ctxEmitter {
+				ctx = WithEmitter(ctx, emitter)
+			}
+			_, err := a.RunJob(ctx, *job, tc.attempt, nil, filenameTemplate)
+			var rerr ErrRetryJob
+			if retry := errors.As(err, &rerr); retry != tc.retry {
+				t.Fatalf("retry, expected %v got %v: %v", tc.retry, retry, err)
+			}
+			if err == nil {
+				t.Fatalf("error, expected an error got nil")
+			}
+			if tc.retry && (rerr.Attempt != tc.attempt+1 || rerr.After != time.Second) {
+				t.Errorf("retry, expected attempt %v after 1s got attempt %v after %v", tc.attempt+1, rerr.Attempt, rerr.After)
+			}
+
+			if _, ok := notifier.emitters[tc.name]; ok == tc.ctxEmitter {
+				t.Fatalf("new emitter, expected %v got %v", !tc.ctxEmitter, ok)
+			}
+			if !tc.ctxEmitter {
+				emitter = notifier.emitters[tc.name]
+			}
+			var failed, retrying int
+			for _, status := range emitter.statuses {
+				switch s := status.(type) {
+				case field.Failed:
+					failed++
+				case field.Retrying:
+					retrying++
+					if s.Attempt != tc.attempt+1 {
+						t.Errorf("retrying attempt, expected %v got %v", tc.attempt+1, s.Attempt)
+					}
+				}
+			}
+			if tc.retry && (failed != 0 || retrying != 1) {
+				t.Errorf("statuses, expected only retrying got %v failed %v retrying", failed, retrying)
+			}
+			if !tc.retry && (failed == 0 || retrying != 0) {
+				t.Errorf("statuses, expected only failed got %v failed %v retrying", failed, retrying)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{name: "retried", attempt: 1, retry: true},
+		{name: "last attempt", attempt: 2},
+		{name: "context emitter", attempt: 1, retry: true, ctxEmitter: true},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -26,9 +26,12 @@ type Job struct {
 	Status        field.Status `json:"status"`
 	EnqueuedAt    time.Time    `json:"enqueued_at"`
 	UpdatedAt     time.Time    `json:"updated_at"`
-	AJob          *atlante.Job `json:"-"`
-	PDF           string       `json:"pdf_url"`
-	LastGen       string       `json:"last_generated"` // RFC 3339 format
+	// Attempt is the attempt of the job being run, starting at 1; failed jobs
+	// may be retried
+	Attempt int          `json:"attempt"`
+	AJob    *atlante.Job `json:"-"`
+	PDF     string       `json:"pdf_url"`
+	LastGen string       `json:"last_generated"` // RFC 3339 format
 }
 
 type Provider interface {
@@ -80,6 +83,7 @@ func NewJob(jobID string, ajob *atlante.Job) *Job {
 		Status:        field.Status{field.Requested{}},
 		EnqueuedAt:    t,
 		UpdatedAt:     t,
+		Attempt:       1,
 		AJob:          ajob,
 	}
 }
@@ -13,10 +13,13 @@ const (
 	started    = "started"
 	processing = "processing"
 	failed     = "failed"
+	cancelled  = "cancelled"
+	retrying   = "retrying"
 
 	errorKey       = "error"
 	descriptionKey = "description"
 	statusKey      = "status"
+	attemptKey     = "attempt"
 )
 
 type (
@@ -51,6 +54,15 @@ type (
 	}
 	// Completed is the status of a successful completed job
 	Completed struct{}
+	// Cancelled is the status of a job that was cancelled
+	Cancelled struct{}
+	// Retrying is the status of a failed job that is waiting to be run again
+	Retrying struct {
+		// Attempt is the attempt that will be run next, starting at 1
+		Attempt int `json:"attempt"`
+		// Error the last attempt failed with
+		Error error `json:"error"`
+	}
 )
 
 func (s Status) String() string { return s.Status.String() }
@@ -65,6 +77,8 @@ func (s Status) MarshalJSON() ([]byte, error) {
 		stageProcessing = 2
 		stageFailed     = 3
 		stageCompleted  = 3
+		stageCancelled  = 3
+		stageRetrying   = 0
 		totalStages     = 3
 	)
 
@@ -90,6 +104,13 @@ func (s Status) MarshalJSON() ([]byte, error) {
 		Description string `json:"description"`
 		Error       string `json:"error"`
 	}
+	type retryingEnum struct {
+		Type    string `json:"status"`
+		Stage   int    `json:"stage"`
+		Total   int    `json:"total"`
+		Attempt int    `json:"attempt"`
+		Error   string `json:"error"`
+	}
 
 	var jsonval interface{}
 	switch senum := s.Status.(type) {
@@ -125,6 +146,24 @@ func (s Status) MarshalJSON() ([]byte, error) {
 			Stage: stageCompleted,
 			Total: totalStages,
 		}
+	case Cancelled:
+		jsonval = sentinalEnum{
+			Type:  cancelled,
+			Stage: stageCancelled,
+			Total: totalStages,
+		}
+	case Retrying:
+		var errStr string
+		if senum.Error != nil {
+			errStr = senum.Error.Error()
+		}
+		jsonval = retryingEnum{
+			Type:    retrying,
+			Stage:   stageRetrying,
+			Total:   totalStages,
+			Attempt: senum.Attempt,
+			Error:   errStr,
+		}
 	default:
 		return []byte{}, fmt.Errorf("Unknown type %t", s.Status)
 
@@ -167,6 +206,21 @@ func (s *Status) UnmarshalJSON(b []byte) error {
 	case completed:
 		s.Status = Completed{}
 
+	case cancelled:
+		s.Status = Cancelled{}
+
+	case retrying:
+		var r Retrying
+		if err := json.Unmarshal(obj[attemptKey], &r.Attempt); err != nil {
+			return nil
+		}
+		var errStr string
+		if err := json.Unmarshal(obj[errorKey], &errStr); err != nil {
+			return nil
+		}
+		r.Error = errors.New(errStr)
+		s.Status = r
+
 	default:
 		return fmt.Errorf("Unknown status type: %v", typ)
 
@@ -182,10 +236,14 @@ func NewStatusFor(status, desc string) (StatusEnum, error) {
 		return Requested{}, nil
 	case completed:
 		return Completed{}, nil
+	case cancelled:
+		return Cancelled{}, nil
 	case processing:
 		return Processing{Description: desc}, nil
 	case failed:
 		return Failed{Error: errors.New(desc)}, nil
+	case retrying:
+		return Retrying{Error: errors.New(desc)}, nil
 	default:
 		return nil, fmt.Errorf("Unknown status type: %v", status)
 	}
@@ -205,3 +263,14 @@ func (f Failed) String() string { return failed + ":" + f.Error.Error() }
 
 func (Completed) statusenum()    {}
 func (Completed) String() string { return completed }
+
+func (Cancelled) statusenum()    {}
+func (Cancelled) String() string { return cancelled }
+
+func (r Retrying) statusenum() {}
+func (r Retrying) String() string {
+	if r.Error == nil {
+		return retrying
+	}
+	return retrying + ":" + r.Error.Error()
+}
@@ -81,6 +81,10 @@ func (p *Provider) UpdateField(job *coordinator.Job, fields ...field.Value) erro
 				log.Infof("update status to processing %v", status.Description)
 			case field.Failed:
 				log.Infof("update status to failed - reason %v", status.Error)
+			case field.Cancelled:
+				log.Infof("update status to cancelled")
+			case field.Retrying:
+				log.Infof("update status to retrying attempt %v - reason %v", status.Attempt, status.Error)
 			default:
 				log.Infof("unknown status: %t", status)
 			}
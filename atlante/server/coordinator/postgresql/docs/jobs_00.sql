@@ -10,6 +10,7 @@ CREATE TABLE jobs (
     bounds geometry(polygon, 4326) NOT NULL,
     style_name text DEFAULT '',
     style_location text DEFAULT '',
+    attempt integer DEFAULT 1,
     created timestamp WITH time zone DEFAULT NOW()
 );
 
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS jobs
+    ADD COLUMN attempt integer DEFAULT 1;
@@ -36,11 +36,30 @@ type Provider struct {
 	QueryUpdateQueueJobID     string
 	QueryUpdateJobData        string
 	QueryInsertStatus         string
+	QueryUpdateAttempt        string
 	QuerySelectMDGIDSheetName string
 	QuerySelectJobID          string
 	QuerySelectAllJobs        string
+
+	// hasAttempt is true if the jobs table has the attempt column, added by
+	// docs/jobs_03.sql; the default queries only use the column if it's there
+	hasAttempt bool
 }
 
+// attemptColumn is the column added to the default select queries when the jobs
+// table has the attempt column
+const attemptColumn = `,
+    job.attempt`
+
+// hasAttemptQuery checks if the jobs table has the attempt column
+const hasAttemptQuery = `
+SELECT EXISTS (
+	SELECT 1
+	FROM information_schema.columns
+	WHERE table_schema = ANY(current_schemas(false)) AND table_name = 'jobs' AND column_name = 'attempt'
+);
+`
+
 const (
 	// DefaultSRID is the assumed srid of data unless specified
 	DefaultSRID = tegola.WebMercator
@@ -128,6 +147,11 @@ type rowScanner interface {
 	Scan(dest ...interface{}) error
 }
 
+// fieldDescriber reports the columns of the rows being scanned
+type fieldDescriber interface {
+	FieldDescriptions() []pgx.FieldDescription
+}
+
 // initFunc returns a new provider based on the postgresql database
 func initFunc(config coordinator.Config) (coordinator.Provider, error) {
 	var emptystr string
@@ -216,12 +240,20 @@ func initFunc(config coordinator.Config) (coordinator.Provider, error) {
 	if p.pool, err = pgx.NewConnPool(p.config); err != nil {
 		return nil, fmt.Errorf("Failed while creating connection pool: %v", err)
 	}
+	if err = p.pool.QueryRow(hasAttemptQuery).Scan(&p.hasAttempt); err != nil {
+		p.pool.Close()
+		return nil, fmt.Errorf("Failed while checking for the jobs attempt column: %v", err)
+	}
+	if !p.hasAttempt {
+		log.Warnf("postgresql coordinator: the jobs table does not have the attempt column, the attempts of retried jobs are not recorded; add it with docs/jobs_03.sql")
+	}
 
 	// Check and step up all the queries.
 	p.QueryNewJob, _ = config.String("query_new_job", &emptystr)
 	p.QueryUpdateQueueJobID, _ = config.String("query_update_queue_job_id", &emptystr)
 	p.QueryUpdateJobData, _ = config.String("query_update_job_data", &emptystr)
 	p.QueryInsertStatus, _ = config.String("query_insert_status", &emptystr)
+	p.QueryUpdateAttempt, _ = config.String("query_update_attempt", &emptystr)
 	p.QuerySelectMDGIDSheetName, _ = config.String("query_select_mdgid_sheetname", &emptystr)
 	p.QuerySelectJobID, _ = config.String("query_select_job_id", &emptystr)
 	p.QuerySelectAllJobs, _ = config.String("query_select_all_jobs", &emptystr)
@@ -352,6 +384,11 @@ WHERE id=$1
 	const updateJobDataQuery = `
 UPDATE jobs 
 SET job_data=$2
+WHERE id=$1
+	`
+	const updateAttemptQuery = `
+UPDATE jobs 
+SET attempt=$2
 WHERE id=$1
 	`
 	const insertStatusQuery = `
@@ -387,7 +424,7 @@ VALUES($1,$2,$3);
 				query = p.QueryInsertStatus
 			}
 			switch status := fld.Status.(type) {
-			case field.Requested, field.Started, field.Completed:
+			case field.Requested, field.Started, field.Completed, field.Cancelled:
 				_, err = p.pool.Exec(
 					query,
 					job.JobID,
@@ -408,6 +445,27 @@ VALUES($1,$2,$3);
 					"failed",
 					status.Description,
 				)
+			case field.Retrying:
+				var desc string
+				if status.Error != nil {
+					desc = status.Error.Error()
+				}
+				_, err = p.pool.Exec(
+					query,
+					job.JobID,
+					"retrying",
+					desc,
+				)
+				if err != nil || status.Attempt == 0 {
+					break
+				}
+				attemptQuery := updateAttemptQuery
+				if p.QueryUpdateAttempt != "" {
+					attemptQuery = p.QueryUpdateAttempt
+				} else if !p.hasAttempt {
+					break
+				}
+				_, err = p.pool.Exec(attemptQuery, job.JobID, status.Attempt)
 			}
 		}
 		if err != nil {
@@ -448,10 +506,11 @@ func scanRow(row rowScanner) (*coordinator.Job, error) {
 		status        *string
 		desc          *string
 		updated       *time.Time
+		attempt       *int32
 		ajob          *atlante.Job
 	)
 
-	if err := row.Scan(
+	dest := []interface{}{
 		&jobid,
 		&mdgid,
 		&sheetNumber,
@@ -464,7 +523,12 @@ func scanRow(row rowScanner) (*coordinator.Job, error) {
 		&status,
 		&desc,
 		&updated,
-	); err != nil {
+	}
+	// the attempt column is optional, so queries written before it was added still work
+	if fd, ok := row.(fieldDescriber); ok && len(fd.FieldDescriptions()) > len(dest) {
+		dest = append(dest, &attempt)
+	}
+	if err := row.Scan(dest...); err != nil {
 		return nil, err
 	}
 	if queueIDp == nil || *queueIDp == "" {
@@ -494,6 +558,14 @@ func scanRow(row rowScanner) (*coordinator.Job, error) {
 	if jobdata != "" {
 		ajob, _ = atlante.Base64UnmarshalJob(jobdata)
 	}
+	jobAttempt := 1
+	if attempt != nil && *attempt > 0 {
+		jobAttempt = int(*attempt)
+	}
+	if r, ok := s.(field.Retrying); ok {
+		r.Attempt = jobAttempt
+		s = r
+	}
 
 	return &coordinator.Job{
 		JobID:         fmt.Sprintf("%v", jobid),
@@ -505,11 +577,21 @@ func scanRow(row rowScanner) (*coordinator.Job, error) {
 		Status:        field.Status{s},
 		EnqueuedAt:    enqueued,
 		UpdatedAt:     *updated,
+		Attempt:       jobAttempt,
 		AJob:          ajob,
 	}, nil
 
 }
 
+// attemptColumn returns the attempt column for the default select queries, empty if
+// the jobs table does not have it
+func (p *Provider) attemptColumn() string {
+	if !p.hasAttempt {
+		return ""
+	}
+	return attemptColumn
+}
+
 // FindByJob will find the lastest 2 jobs as described by the atlante job descriptions
 // TODO(gdey): remove defaultStyleLocation once this version of atlante as supplented older versions without
 // styleLists support.
@@ -528,7 +610,7 @@ SELECT
     job.created as enqueued,
     jobstatus.status,
     jobstatus.description,
-    jobstatus.created as updated
+    jobstatus.created as updated%s
 FROM jobs AS job
 LEFT JOIN
 ( -- find the most recent job status if it exists
@@ -543,7 +625,7 @@ WHERE job.mdgid = $1 AND job.sheet_number = $2 AND job.sheet_name = $3
 ORDER BY jobstatus.id desc 
 ;
 	`
-	query := selectQuery
+	query := fmt.Sprintf(selectQuery, p.attemptColumn())
 	if p.QuerySelectMDGIDSheetName != "" {
 		query = p.QuerySelectMDGIDSheetName
 	}
@@ -608,13 +690,13 @@ SELECT
     job.created as enqueued,
     jobstatus.status,
     jobstatus.description,
-    jobstatus.created as updated
+    jobstatus.created as updated%s
 FROM jobs AS job
 JOIN job_statuses AS jobstatus ON job.id = jobstatus.job_id
 WHERE job.id = $1
 ORDER BY jobstatus.id desc limit 1;
 	`
-	query := selectQuery
+	query := fmt.Sprintf(selectQuery, p.attemptColumn())
 	if p.QuerySelectJobID != "" {
 		query = p.QuerySelectJobID
 	}
@@ -623,8 +705,21 @@ ORDER BY jobstatus.id desc limit 1;
 		return nil, false
 	}
 
-	row := p.pool.QueryRow(query, id)
-	jb, err = scanRow(row)
+	// rows, instead of a row, so the columns of the query are known to scanRow
+	rows, err := p.pool.Query(query, id)
+	if err != nil {
+		logScanError(err, query)
+		return nil, false
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err == nil {
+			err = pgx.ErrNoRows
+		}
+		logScanError(err, query)
+		return nil, false
+	}
+	jb, err = scanRow(rows)
 	if err != nil {
 		logScanError(err, query)
 		return nil, false
@@ -680,7 +775,7 @@ SELECT
     job.created as enqueued,
     jobstatus.status,
     jobstatus.description,
-    jobstatus.created as updated
+    jobstatus.created as updated%s
 FROM jobs AS job
 LEFT JOIN
 ( -- find the most recent job status if it exists
@@ -696,7 +791,7 @@ ORDER BY jobstatus.id desc
 ;
 `
 
-	query, err := genAllSQL(p.QuerySelectAllJobs, selectQuery, limit)
+	query, err := genAllSQL(p.QuerySelectAllJobs, fmt.Sprintf(selectQuery, p.attemptColumn()), limit)
 	if err != nil {
 		return nil, err
 	}
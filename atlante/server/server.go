@@ -7,6 +7,7 @@ import (
 	"hash/adler32"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -26,8 +27,10 @@ import (
 	"github.com/go-spatial/atlante/atlante/queuer"
 
 	"github.com/dimfeld/httptreemux"
+	"github.com/gdey/errors"
 	"github.com/go-spatial/atlante/atlante"
 	"github.com/go-spatial/atlante/atlante/grids"
+	"github.com/go-spatial/atlante/atlante/grids/cache"
 	"github.com/prometheus/common/log"
 )
 
@@ -63,6 +66,12 @@ const (
 	// MaxJobs returns the max number of jobs to return
 	MaxJobs = 100
 
+	// MaxCells is the max number of cells to return for a bounds
+	MaxCells = 1000
+
+	// MaxAtlasPages is the max number of pages allowed in an atlas
+	MaxAtlasPages = 250
+
 	GratingNumRowsKey  = "grating-number-of-rows"
 	GratingNumColsKey  = "grating-number-of-columns"
 	GratingSquarishKey = "grating-not-squarish"
@@ -126,9 +135,38 @@ type (
 
 		// DisableNotificationEP will disable the job notification end points from being registered.
 		DisableNotificationEP bool
+
+		// Reload reloads the sheets of Atlante from the config; if set, the
+		// reload end point is registered
+		Reload func() error
+
+		// RequesterHeader is the request header with the api key that identifies who
+		// requested a job; the queues share the runners fairly between requesters.
+		RequesterHeader string
+
+		// APIKeys are the known api keys, mapped to the name of the requester. The
+		// address of the client is the requester if the api key is not known.
+		APIKeys map[string]string
+
+		// MaxPriority is the highest priority, and the negative of the lowest, a job
+		// may be given; DefaultMaxPriority is used if it is 0
+		MaxPriority int
 	}
 )
 
+const (
+	// DefaultRequesterHeader is the header that identifies who requested a job, if one is
+	// not configured
+	DefaultRequesterHeader = "X-API-Key"
+
+	// DefaultMaxPriority is the highest priority a job may be given, if one is not configured
+	DefaultMaxPriority = 10
+)
+
+// ErrQueueFailedJob is the error of a job the queue reports as failed, when the failure
+// was not reported to the coordinator, e.g. the worker running the job died
+const ErrQueueFailedJob = errors.String("the queue reports the job failed")
+
 var (
 	// Version is the version of the software, this should be set by the main program, before starting up.
 	// It is used by various Middleware to determine the version.
@@ -175,6 +213,13 @@ func badRequest(w http.ResponseWriter, reasonFmt string, data ...interface{}) {
 	w.WriteHeader(http.StatusBadRequest)
 }
 
+func conflict(w http.ResponseWriter, reasonFmt string, data ...interface{}) {
+	setHeaders(map[string]string{
+		HTTPErrorHeader: fmt.Sprintf(reasonFmt, data...),
+	}, w)
+	w.WriteHeader(http.StatusConflict)
+}
+
 func serverError(w http.ResponseWriter, reasonFmt string, data ...interface{}) {
 	setHeaders(map[string]string{
 		HTTPErrorHeader: fmt.Sprintf(reasonFmt, data...),
@@ -234,18 +279,27 @@ func encodeCellAsJSON(w io.Writer, cell *grids.Cell, defaultStyle string, pdf fi
 	}
 
 	mdgidStr := mdgid.AsString()
-	sw := cell.GetSw()
-	ne := cell.GetNe()
-	jsonCell.GeoJSON = json.RawMessage(
-		fmt.Sprintf(geoJSONFmt,
-			adler32.Checksum([]byte(mdgidStr)), // Just need a stable unique number, a checksum will do.
-			sw.GetLng(), sw.GetLat(),
-			sw.GetLng(), ne.GetLat(),
-			ne.GetLng(), ne.GetLat(),
-			ne.GetLng(), sw.GetLat(),
-			sw.GetLng(), sw.GetLat(),
-		),
-	)
+	if cell.HasFootprint() {
+		if footprint, err := footprintGeoJSON(cell); err == nil {
+			jsonCell.GeoJSON = footprint
+		} else {
+			log.Warnf("failed to encode footprint for %v: %v", mdgidStr, err)
+		}
+	}
+	if jsonCell.GeoJSON == nil {
+		sw := cell.GetSw()
+		ne := cell.GetNe()
+		jsonCell.GeoJSON = json.RawMessage(
+			fmt.Sprintf(geoJSONFmt,
+				adler32.Checksum([]byte(mdgidStr)), // Just need a stable unique number, a checksum will do.
+				sw.GetLng(), sw.GetLat(),
+				sw.GetLng(), ne.GetLat(),
+				ne.GetLng(), ne.GetLat(),
+				ne.GetLng(), sw.GetLat(),
+				sw.GetLng(), sw.GetLat(),
+			),
+		)
+	}
 	// Encoding the cell into the json
 	err := json.NewEncoder(w).Encode(jsonCell)
 	if err != nil {
@@ -253,6 +307,27 @@ func encodeCellAsJSON(w io.Writer, cell *grids.Cell, defaultStyle string, pdf fi
 	}
 }
 
+// footprintGeoJSON returns a geojson FeatureCollection of the footprint of the cell
+func footprintGeoJSON(cell *grids.Cell) (json.RawMessage, error) {
+	footprint, err := cell.Footprint()
+	if err != nil {
+		return nil, err
+	}
+	mdgidStr := cell.GetMdgid().AsString()
+	features := geojson.FeatureCollection{
+		Features: []geojson.Feature{
+			{
+				Geometry: geojson.Geometry{Geometry: footprint},
+				Properties: map[string]interface{}{
+					// Just need a stable unique number, a checksum will do.
+					"objectid": fmt.Sprintf("%v", adler32.Checksum([]byte(mdgidStr))),
+				},
+			},
+		},
+	}
+	return json.Marshal(features)
+}
+
 // GetHostName returns determines the hostname:port to return based on the following hierarchy
 // Hostname/Port in the server object.
 // the host/port in the request object.
@@ -499,6 +574,75 @@ func (s *Server) BoundsGeojsonHandler(w http.ResponseWriter, request *http.Reque
 	return
 }
 
+// CellsGeojsonHandler will write out a geojson FeatureCollection of all the cells of the
+// sheet that intersect the given bounds. The grid provider of the sheet must support
+// enumerating cells.
+func (s *Server) CellsGeojsonHandler(w http.ResponseWriter, request *http.Request, urlParams map[string]string) {
+	ji, sheet, didErr := s.retriveSheetAndJob(w, request, urlParams)
+	if didErr {
+		return
+	}
+	if ji.Bounds == nil {
+		badRequest(w, "bounds must be given")
+		return
+	}
+
+	iter, err := grids.CellsForBounds(sheet.Provider, *ji.Bounds, ji.Srid)
+	if err != nil {
+		if err == grids.ErrEnumerationNotSupported {
+			badRequest(w, "sheet (%v) does not support listing cells", sheet.Name)
+			return
+		}
+		serverError(w, "error getting cells for %v: %v", *ji.Bounds, err)
+		return
+	}
+	defer iter.Close()
+
+	features := geojson.FeatureCollection{
+		Features: []geojson.Feature{},
+	}
+	for iter.Next() {
+		if len(features.Features) >= MaxCells {
+			badRequest(w, "bounds contains more then %v cells", MaxCells)
+			return
+		}
+		cell := iter.Cell()
+		mdgid := cell.GetMdgid().AsString()
+		id := uint64(adler32.Checksum([]byte(mdgid)))
+		footprint, err := cell.Footprint()
+		if err != nil {
+			serverError(w, "error decoding footprint for %v: %v", mdgid, err)
+			return
+		}
+		features.Features = append(features.Features, geojson.Feature{
+			ID:       &id,
+			Geometry: geojson.Geometry{Geometry: footprint},
+			Properties: map[string]interface{}{
+				"mdgid":        mdgid,
+				"sheet_number": cell.SheetNumber(),
+				"sheet_name":   cell.GetSheet(),
+				"series":       cell.GetSeries(),
+			},
+		})
+	}
+	if err = iter.Err(); err != nil {
+		serverError(w, "error getting cells for %v: %v", *ji.Bounds, err)
+		return
+	}
+
+	setHeaders(map[string]string{
+		"Content-Type":  "application/geo+json",
+		"Cache-Control": "no-cache, no-store, must-revalidate",
+		"Pragma":        "no-cache",
+		"Expires":       "0",
+	},
+		w)
+
+	if err = json.NewEncoder(w).Encode(features); err != nil {
+		log.Errorf("Failed to write to connection: %v", err)
+	}
+}
+
 type QueueJob struct {
 	MdgID     *string      `json:"mdgid,omitempty"`
 	MdgIDPart uint32       `json:"sheet_number,omitempty"`
@@ -508,6 +652,15 @@ type QueueJob struct {
 	Rectangle bool         `json:"rectangle,omitempty"`
 	Srid      uint         `json:"srid,omitempty"`
 	StyleName string       `json:"style_name,omitempty"`
+
+	PaperSize   string `json:"paper_size,omitempty"`
+	Orientation string `json:"orientation,omitempty"`
+
+	PosterPaperSize   string `json:"poster_paper_size,omitempty"`
+	PosterOrientation string `json:"poster_orientation,omitempty"`
+
+	// Priority of the job, jobs with a higher priority are run first
+	Priority *int `json:"priority,omitempty"`
 }
 
 func (s *Server) retriveSheetAndJob(w http.ResponseWriter, request *http.Request, urlParams map[string]string) (ji QueueJob, sheet *atlante.Sheet, didErr bool) {
@@ -541,24 +694,123 @@ func (s *Server) retriveSheetAndJob(w http.ResponseWriter, request *http.Request
 		return ji, nil, true
 	}
 
+	if err = validatePaper(ji.PaperSize, ji.Orientation); err != nil {
+		badRequest(w, "%v", err)
+		return ji, nil, true
+	}
+	if err = validatePoster(ji.PosterPaperSize, ji.PosterOrientation); err != nil {
+		badRequest(w, "poster: %v", err)
+		return ji, nil, true
+	}
+	if err = s.validatePriority(ji.Priority); err != nil {
+		badRequest(w, "%v", err)
+		return ji, nil, true
+	}
+
+	sheet, didErr = s.sheetForParams(w, urlParams)
+	if didErr {
+		return ji, nil, true
+	}
+	if ji.Srid == 0 {
+		ji.Srid = 4326
+	}
+	return ji, sheet, false
+}
+
+// sheetForParams returns the sheet named in the url params, writing out a bad request
+// if there isn't one
+func (s *Server) sheetForParams(w http.ResponseWriter, urlParams map[string]string) (sheet *atlante.Sheet, didErr bool) {
 	sheetName, ok := urlParams[string(ParamsKeySheetname)]
 	if !ok {
 		// We need a sheetnumber.
 		badRequest(w, "missing sheet name)")
-		return ji, nil, true
+		return nil, true
 	}
 
 	sheetName = s.Atlante.NormalizeSheetName(sheetName, false)
 
-	sheet, err = s.Atlante.SheetFor(sheetName)
+	sheet, err := s.Atlante.SheetFor(sheetName)
 	if err != nil {
 		badRequest(w, "error getting sheet(%v):%v", sheetName, err)
-		return ji, nil, true
+		return nil, true
 	}
-	if ji.Srid == 0 {
-		ji.Srid = 4326
+	return sheet, false
+}
+
+// validatePaper returns an error if the paper size or orientation of a job is not known
+func validatePaper(paperSize, orientation string) error {
+	if err := atlante.ValidatePaperSize(paperSize); err != nil {
+		return err
 	}
-	return ji, sheet, false
+	_, err := atlante.ParseOrientation(orientation)
+	return err
+}
+
+// paperMetaData adds the paper size and orientation, if given, to the job metadata
+func paperMetaData(md map[string]string, paperSize, orientation string) map[string]string {
+	if paperSize != "" {
+		md[atlante.MetaDataKeyPaperSize] = paperSize
+	}
+	if orientation != "" {
+		md[atlante.MetaDataKeyOrientation] = orientation
+	}
+	return md
+}
+
+// requester returns who made the request; the name of the requester of a known api key
+// in the requester header, or the address of the client. The header is set by the
+// client, so unknown values are not trusted.
+func (s *Server) requester(request *http.Request) string {
+	header := s.RequesterHeader
+	if header == "" {
+		header = DefaultRequesterHeader
+	}
+	if key := strings.TrimSpace(request.Header.Get(header)); key != "" {
+		if name, ok := s.APIKeys[key]; ok {
+			return name
+		}
+	}
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+	return host
+}
+
+// validatePriority returns an error if the priority of a job is out of range
+func (s *Server) validatePriority(priority *int) error {
+	if priority == nil {
+		return nil
+	}
+	max := s.MaxPriority
+	if max <= 0 {
+		max = DefaultMaxPriority
+	}
+	if *priority < -max || *priority > max {
+		return fmt.Errorf("priority needs to be between %v and %v", -max, max)
+	}
+	return nil
+}
+
+// schedulingMetaData adds the priority, if given, and the requester of the job to the
+// job metadata
+func (s *Server) schedulingMetaData(md map[string]string, request *http.Request, priority *int) map[string]string {
+	if priority != nil {
+		md[atlante.MetaDataKeyPriority] = strconv.Itoa(*priority)
+	}
+	md[atlante.MetaDataKeyRequester] = s.requester(request)
+	return md
+}
+
+// validatePoster returns an error if the poster paper size or orientation of a job is not known
+func validatePoster(paperSize, orientation string) error {
+	if paperSize != "" {
+		if _, ok := atlante.PaperSizeFor(paperSize); !ok {
+			return atlante.ErrUnknownPaperSize(paperSize)
+		}
+	}
+	_, err := atlante.ParseOrientation(orientation)
+	return err
 }
 
 func cellForQueueJob(ji QueueJob, sheet *atlante.Sheet) (*grids.Cell, bool, error) {
@@ -614,10 +866,20 @@ func (s *Server) QueueHandler(w http.ResponseWriter, request *http.Request, urlP
 
 	qjob := atlante.Job{
 		SheetName: sheet.Name,
-		MetaData: map[string]string{
-			"styleLocation": requestedStyle.Location,
-			"styleName":     requestedStyle.Name,
-		},
+		MetaData: paperMetaData(
+			map[string]string{
+				"styleLocation": requestedStyle.Location,
+				"styleName":     requestedStyle.Name,
+			},
+			ji.PaperSize,
+			ji.Orientation,
+		),
+	}
+	if ji.PosterPaperSize != "" {
+		qjob.MetaData[atlante.MetaDataKeyPosterPaperSize] = ji.PosterPaperSize
+	}
+	if ji.PosterOrientation != "" {
+		qjob.MetaData[atlante.MetaDataKeyPosterOrientation] = ji.PosterOrientation
 	}
 
 	var isBoundsBased bool
@@ -639,7 +901,7 @@ func (s *Server) QueueHandler(w http.ResponseWriter, request *http.Request, urlP
 		default:
 			// do nothing we should enqueue a
 			// new job.
-		case field.Requested, field.Started:
+		case field.Requested, field.Started, field.Retrying:
 			// Job is already there just return
 			// info about the old job.
 			setHeaders(nil, w)
@@ -658,6 +920,7 @@ func (s *Server) QueueHandler(w http.ResponseWriter, request *http.Request, urlP
 	// Fill out the Metadata with JobID
 	qjob.MetaData["job_id"] = jb.JobID
 	qjob.MetaData[GratingSquarishKey] = strconv.FormatBool(ji.Rectangle)
+	s.schedulingMetaData(qjob.MetaData, request, ji.Priority)
 
 	if ji.NumRows != nil {
 		// Add row to Metadata
@@ -672,7 +935,13 @@ func (s *Server) QueueHandler(w http.ResponseWriter, request *http.Request, urlP
 		}
 	}
 
-	qjobid, err := s.Queue.Enqueue(jb.JobID, &qjob)
+	s.enqueue(w, jb, &qjob)
+}
+
+// enqueue puts the job on the queue, and records the queue job id into the coordinator, writing
+// out the coordinator job.
+func (s *Server) enqueue(w http.ResponseWriter, jb *coordinator.Job, qjob *atlante.Job) {
+	qjobid, err := s.Queue.Enqueue(jb.JobID, qjob)
 	if err != nil {
 		s.Coordinator.UpdateField(jb,
 			field.Status{
@@ -699,6 +968,149 @@ func (s *Server) QueueHandler(w http.ResponseWriter, request *http.Request, urlP
 	}
 }
 
+// AtlasJob is the body of an atlas request. Either the mdgids of the pages, or
+// a bounds to enumerate the pages from must be given.
+type AtlasJob struct {
+	MdgIDs      []string     `json:"mdgids,omitempty"`
+	Bounds      *geom.Extent `json:"bounds,omitempty"`
+	Srid        uint         `json:"srid,omitempty"`
+	StyleName   string       `json:"style_name,omitempty"`
+	Title       string       `json:"title,omitempty"`
+	Cover       bool         `json:"cover,omitempty"`
+	Index       bool         `json:"index,omitempty"`
+	PageNumbers bool         `json:"page_numbers,omitempty"`
+	PaperSize   string       `json:"paper_size,omitempty"`
+	Orientation string       `json:"orientation,omitempty"`
+	// Priority of the job, jobs with a higher priority are run first
+	Priority *int `json:"priority,omitempty"`
+}
+
+// cellsForAtlasJob returns the cells for the pages of the atlas, in the order given, or
+// the order of the enumeration of the bounds
+func cellsForAtlasJob(ji AtlasJob, sheet *atlante.Sheet) ([]*grids.Cell, error) {
+	if ji.Bounds != nil {
+		iter, err := grids.CellsForBounds(sheet.Provider, *ji.Bounds, ji.Srid)
+		if err != nil {
+			if err == grids.ErrEnumerationNotSupported {
+				return nil, fmt.Errorf("sheet (%v) does not support listing cells", sheet.Name)
+			}
+			return nil, fmt.Errorf("error getting cells for %v: %w", *ji.Bounds, err)
+		}
+		defer iter.Close()
+		var cells []*grids.Cell
+		for iter.Next() {
+			if len(cells) >= MaxAtlasPages {
+				return nil, fmt.Errorf("bounds contains more then %v cells", MaxAtlasPages)
+			}
+			cells = append(cells, iter.Cell())
+		}
+		if err = iter.Err(); err != nil {
+			return nil, fmt.Errorf("error getting cells for %v: %w", *ji.Bounds, err)
+		}
+		return cells, nil
+	}
+
+	if len(ji.MdgIDs) > MaxAtlasPages {
+		return nil, fmt.Errorf("atlas can have at most %v pages", MaxAtlasPages)
+	}
+	cells := make([]*grids.Cell, 0, len(ji.MdgIDs))
+	for _, id := range ji.MdgIDs {
+		mdgid := grids.NewMDGID(id)
+		cell, err := sheet.CellForMDGID(mdgid)
+		if err != nil {
+			return nil, fmt.Errorf("error getting grid(%v):%w", mdgid.AsString(), err)
+		}
+		cells = append(cells, cell)
+	}
+	return cells, nil
+}
+
+// AtlasHandler takes a list of mdgids or a bounds and queues a job to render all the cells
+// as pages of a single pdf.
+func (s *Server) AtlasHandler(w http.ResponseWriter, request *http.Request, urlParams map[string]string) {
+	if s.Coordinator == nil {
+		s.Coordinator = &null.Provider{}
+	}
+
+	var ji AtlasJob
+
+	bdy, err := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		badRequest(w, "error reading body")
+		return
+	}
+	if err = json.Unmarshal(bdy, &ji); err != nil {
+		badRequest(w, "unable to unmarshal json: %v", err)
+		return
+	}
+	if ji.Bounds == nil && len(ji.MdgIDs) == 0 {
+		badRequest(w, "mdgids or bounds must be given")
+		return
+	}
+	if ji.Srid == 0 {
+		ji.Srid = 4326
+	}
+	if err = validatePaper(ji.PaperSize, ji.Orientation); err != nil {
+		badRequest(w, "%v", err)
+		return
+	}
+	if err = s.validatePriority(ji.Priority); err != nil {
+		badRequest(w, "%v", err)
+		return
+	}
+
+	sheet, didErr := s.sheetForParams(w, urlParams)
+	if didErr {
+		return
+	}
+
+	requestedStyle, found := sheet.Styles.For(ji.StyleName)
+	if !found {
+		badRequest(w, "style %v is unknown", ji.StyleName)
+		return
+	}
+
+	cells, err := cellsForAtlasJob(ji, sheet)
+	if err != nil {
+		badRequest(w, "%v", err)
+		return
+	}
+	if len(cells) == 0 {
+		badRequest(w, "no cells found for atlas")
+		return
+	}
+
+	qjob := atlante.NewAtlasJob(
+		sheet.Name,
+		cells,
+		atlante.AtlasOptions{
+			Title:       ji.Title,
+			Cover:       ji.Cover,
+			Index:       ji.Index,
+			PageNumbers: ji.PageNumbers,
+		},
+		paperMetaData(
+			map[string]string{
+				"styleLocation": requestedStyle.Location,
+				"styleName":     requestedStyle.Name,
+			},
+			ji.PaperSize,
+			ji.Orientation,
+		),
+	)
+
+	jb, err := s.Coordinator.NewJob(qjob)
+	if err != nil {
+		serverError(w, "failed to get new job from coordinator: %v", err)
+		return
+	}
+	qjob.MetaData["job_id"] = jb.JobID
+	s.schedulingMetaData(qjob.MetaData, request, ji.Priority)
+
+	s.enqueue(w, jb, qjob)
+}
+
 // SheetInfoHandler takes a job from a post and enqueue it on the configured queue
 // if the job has not be submitted before
 func (s *Server) SheetInfoHandler(w http.ResponseWriter, request *http.Request, urlParams map[string]string) {
@@ -744,10 +1156,34 @@ func (s *Server) SheetInfoHandler(w http.ResponseWriter, request *http.Request,
 	}
 }
 
+// GridStatsHandler returns the counters of the grid provider caches, by sheet
+func (s *Server) GridStatsHandler(w http.ResponseWriter, request *http.Request, urlParams map[string]string) {
+	type cacheStats struct {
+		cache.Stats
+		HitRatio float64 `json:"hit_ratio"`
+	}
+	stats := make(map[string]cacheStats)
+	for _, sh := range s.Atlante.Sheets() {
+		prv, ok := sh.Provider.(*cache.Provider)
+		if !ok {
+			continue
+		}
+		st := prv.Stats()
+		stats[sh.Name] = cacheStats{Stats: st, HitRatio: st.HitRatio()}
+	}
+
+	setHeaders(nil, w)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"caches": stats}); err != nil {
+		serverError(w, "failed to marshal json: %v", err)
+	}
+}
+
 // InfoJob is the struct for the json return by info end point
 type InfoJob struct {
 	*coordinator.Job
 	StyleName string `json:"style_name"`
+	// PosterPDF is the url of the poster pdf, if one was generated
+	PosterPDF string `json:"poster_pdf_url,omitempty"`
 }
 
 // JobInfoHandler is a http handler for information about a job.
@@ -764,7 +1200,11 @@ func (s *Server) JobInfoHandler(w http.ResponseWriter, request *http.Request, ur
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
-	var styleIdx map[string]string
+	s.queueStatus(job)
+	var (
+		styleIdx  map[string]string
+		posterPDF string
+	)
 	if job.AJob != nil {
 		sheetName := job.SheetName
 		sheetName = s.Atlante.NormalizeSheetName(sheetName, false)
@@ -777,6 +1217,9 @@ func (s *Server) JobInfoHandler(w http.ResponseWriter, request *http.Request, ur
 				job.PDF = pdfURL.String()
 				job.LastGen = pdfURL.TimeString()
 			}
+			if posterURL, ok := sheet.GetURL(mdgid, gf.Poster, false); ok {
+				posterPDF = posterURL.String()
+			}
 		}
 		// Make sure the styleLocation is always set.
 		if job.StyleLocation == "" {
@@ -786,7 +1229,8 @@ func (s *Server) JobInfoHandler(w http.ResponseWriter, request *http.Request, ur
 		styleIdx = style.Location2Style(sheet.Styles)
 	}
 	iJob := InfoJob{
-		Job: job,
+		Job:       job,
+		PosterPDF: posterPDF,
 	}
 	iJob.StyleName = styleIdx[job.StyleLocation]
 
@@ -797,6 +1241,38 @@ func (s *Server) JobInfoHandler(w http.ResponseWriter, request *http.Request, ur
 
 }
 
+// queueStatus asks the queue for the status of a job the coordinator does not have as
+// finished. If the queue has finished the job, but the status never reached the
+// coordinator, the status of the queue is recorded for the job.
+func (s *Server) queueStatus(job *coordinator.Job) {
+	switch job.Status.Status.(type) {
+	case field.Completed, field.Failed, field.Cancelled:
+		return
+	}
+	infoProvider, ok := s.Queue.(queuer.InfoProvider)
+	if !ok || job.QJobID == "" {
+		return
+	}
+	var status field.StatusEnum
+	switch qstatus := infoProvider.Info(job.QJobID); qstatus {
+	case queuer.Compleated:
+		status = field.Completed{}
+	case queuer.Failed:
+		status = field.Failed{Error: ErrQueueFailedJob}
+	case queuer.Cancelled:
+		status = field.Cancelled{}
+	default:
+		// the job is still in the queue, or the queue does not know about it; the
+		// status of the coordinator is more detailed
+		return
+	}
+	log.Infof("queue reports job(%v) as %v, coordinator has %v", job.JobID, status, job.Status)
+	job.Status = field.Status{Status: status}
+	if err := s.Coordinator.UpdateField(job, job.Status); err != nil {
+		log.Warnf("failed to update job(%v) status: %v", job.JobID, err)
+	}
+}
+
 // JobsHandler is a http handler for the jobs end-point
 func (s *Server) JobsHandler(w http.ResponseWriter, request *http.Request, urlParams map[string]string) {
 	// Hardcode 100 limit for now.
@@ -892,6 +1368,81 @@ func (s *Server) NotificationHandler(w http.ResponseWriter, request *http.Reques
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// CancelHandler is an http handler that cancels a job. A job that has not started is
+// not run, and a running job is stopped. 409 is returned if the job already finished.
+func (s *Server) CancelHandler(w http.ResponseWriter, request *http.Request, urlParams map[string]string) {
+
+	jobid, ok := urlParams[string(ParamsKeyJobID)]
+	if !ok {
+		badRequest(w, "missing job_id")
+		return
+	}
+	canceller, ok := s.Queue.(queuer.Canceller)
+	if !ok {
+		serverError(w, "queue does not support cancelling jobs")
+		return
+	}
+	job, ok := s.Coordinator.FindByJobID(jobid)
+	if !ok {
+		setHeaders(nil, w)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch job.Status.Status.(type) {
+	case field.Completed, field.Failed, field.Cancelled:
+		conflict(w, "job %v already finished: %v", jobid, job.Status)
+		return
+	}
+
+	switch err := canceller.Cancel(job.QJobID); err {
+	case nil:
+	case queuer.ErrJobFinished:
+		conflict(w, "job %v already finished", jobid)
+		return
+	case queuer.ErrUnknownJob:
+		setHeaders(map[string]string{HTTPErrorHeader: fmt.Sprintf("job %v is not in the queue", jobid)}, w)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	default:
+		serverError(w, "failed to cancel job %v: %v", jobid, err)
+		return
+	}
+
+	job.Status = field.Status{Status: field.Cancelled{}}
+	if err := s.Coordinator.UpdateField(job, job.Status); err != nil {
+		serverError(w, "failed to update job %v: %v", jobid, err)
+		return
+	}
+	setHeaders(nil, w)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		serverError(w, "failed to marshal json: %v", err)
+	}
+}
+
+// ReloadHandler is an http handler that reloads the config. On error the server
+// keeps the config it had, and the error is returned.
+func (s *Server) ReloadHandler(w http.ResponseWriter, request *http.Request, urlParams map[string]string) {
+	type reloadInfo struct {
+		Sheets []string `json:"sheets,omitempty"`
+		Error  string   `json:"error,omitempty"`
+	}
+	var info reloadInfo
+	status := http.StatusOK
+	if err := s.Reload(); err != nil {
+		log.Errorf("failed to reload config: %v", err)
+		info.Error = err.Error()
+		status = http.StatusInternalServerError
+	} else {
+		info.Sheets = s.Atlante.SheetNames()
+	}
+	setHeaders(nil, w)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		log.Warnf("failed to marshal json: %v", err)
+	}
+}
+
 // HealthCheckHandler is an http handler use to indicate the health of the server.
 // TODO(gdey): make the handler more intelligent as we understand more about the environment.
 func (*Server) HealthCheckHandler(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
@@ -926,10 +1477,17 @@ func (s *Server) RegisterRoutes(r *httptreemux.TreeMux) {
 		group.POST("/mdgid", s.QueueHandler)
 		log.Infof("registering: POST /sheets/:sheetname/bounds")
 		group.POST("/bounds", s.QueueHandler)
+		log.Infof("registering: POST /sheets/:sheetname/atlas")
+		group.POST("/atlas", s.AtlasHandler)
 	}
 
 	log.Infof("registering: POST /sheets/:sheetname/bounds/grid")
 	group.POST("/bounds/grid", s.BoundsGeojsonHandler)
+	log.Infof("registering: POST /sheets/:sheetname/bounds/cells")
+	group.POST("/bounds/cells", s.CellsGeojsonHandler)
+
+	log.Infof("registering: GET  /grids/stats")
+	r.GET("/grids/stats", s.GridStatsHandler)
 
 	log.Infof("registering: GET  /jobs")
 	r.GET("/jobs", s.JobsHandler)
@@ -940,4 +1498,12 @@ func (s *Server) RegisterRoutes(r *httptreemux.TreeMux) {
 		log.Infof("registering: POST  /jobs/:jobid/status")
 		jobsGroup.POST("/status", s.NotificationHandler)
 	}
+	if _, ok := s.Queue.(queuer.Canceller); ok {
+		log.Infof("registering: DELETE /jobs/:jobid")
+		r.DELETE(GenPath("jobs", ParamsKeyJobID), s.CancelHandler)
+	}
+	if s.Reload != nil {
+		log.Infof("registering: POST /admin/reload")
+		r.POST("/admin/reload", s.ReloadHandler)
+	}
 }
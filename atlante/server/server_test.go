@@ -0,0 +1,265 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/queuer"
+	"github.com/go-spatial/atlante/atlante/server/coordinator"
+	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
+)
+
+// jobsCoordinator is a coordinator with a fixed set of jobs, that records the updates
+type jobsCoordinator struct {
+	jobs    map[string]*coordinator.Job
+	updates []field.Value
+}
+
+func (jc *jobsCoordinator) NewJob(*atlante.Job) (*coordinator.Job, error) {
+	return nil, coordinator.ErrNilJob
+}
+func (jc *jobsCoordinator) FindByJob(*atlante.Job, string) []*coordinator.Job { return nil }
+func (jc *jobsCoordinator) FindByJobID(jobid string) (*coordinator.Job, bool) {
+	job, ok := jc.jobs[jobid]
+	return job, ok
+}
+func (jc *jobsCoordinator) UpdateField(_ *coordinator.Job, fields ...field.Value) error {
+	jc.updates = append(jc.updates, fields...)
+	return nil
+}
+func (jc *jobsCoordinator) Jobs(uint) ([]*coordinator.Job, error) { return nil, nil }
+
+// statusQueue is a queue that reports a fixed status for its jobs
+type statusQueue map[string]queuer.Status
+
+func (statusQueue) Enqueue(string, *atlante.Job) (string, error) { return "", nil }
+func (sq statusQueue) Info(jobid string) queuer.Status {
+	status, ok := sq[jobid]
+	if !ok {
+		return queuer.Unknown
+	}
+	return status
+}
+
+// enqueueOnly is a queue that can not report on its jobs
+type enqueueOnly struct{}
+
+func (enqueueOnly) Enqueue(string, *atlante.Job) (string, error) { return "", nil }
+
+func TestJobInfoHandlerQueueStatus(t *testing.T) {
+	type tcase struct {
+		name   string
+		status field.StatusEnum
+		qjobid string
+		queue  queuer.Provider
+		// expected is the status returned for the job
+		expected string
+		// updated is if the status is recorded with the coordinator
+		updated bool
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			coord := &jobsCoordinator{jobs: map[string]*coordinator.Job{
+				"job1": {
+					JobID:  "job1",
+					QJobID: tc.qjobid,
+					Status: field.Status{Status: tc.status},
+				},
+			}}
+			s := &Server{
+				Atlante:     new(atlante.Atlante),
+				Queue:       tc.queue,
+				Coordinator: coord,
+			}
+			w := httptest.NewRecorder()
+			s.JobInfoHandler(w, httptest.NewRequest(http.MethodGet, "/jobs/job1/status", nil), map[string]string{
+				string(ParamsKeyJobID): "job1",
+			})
+			if w.Code != http.StatusOK {
+				t.Fatalf("code, expected %v got %v", http.StatusOK, w.Code)
+			}
+			var got struct {
+				Status struct {
+					Status string `json:"status"`
+					Error  string `json:"error"`
+				} `json:"status"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decode, expected nil got %v", err)
+			}
+			if got.Status.Status != tc.expected {
+				t.Errorf("status, expected %v got %v", tc.expected, got.Status.Status)
+			}
+			if tc.expected == "failed" && tc.updated && got.Status.Error != ErrQueueFailedJob.Error() {
+				t.Errorf("error, expected %q got %q", ErrQueueFailedJob, got.Status.Error)
+			}
+			if updated := len(coord.updates) != 0; updated != tc.updated {
+				t.Errorf("updated, expected %v got %v", tc.updated, updated)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:     "queue failed",
+			status:   field.Started{},
+			qjobid:   "q1",
+			queue:    statusQueue{"q1": queuer.Failed},
+			expected: "failed",
+			updated:  true,
+		},
+		{
+			name:     "queue completed",
+			status:   field.Processing{Description: "page 1"},
+			qjobid:   "q1",
+			queue:    statusQueue{"q1": queuer.Compleated},
+			expected: "completed",
+			updated:  true,
+		},
+		{
+			name:     "queue cancelled",
+			status:   field.Requested{},
+			qjobid:   "q1",
+			queue:    statusQueue{"q1": queuer.Cancelled},
+			expected: "cancelled",
+			updated:  true,
+		},
+		{
+			name:     "queue processing",
+			status:   field.Processing{Description: "page 1"},
+			qjobid:   "q1",
+			queue:    statusQueue{"q1": queuer.Processing},
+			expected: "processing",
+		},
+		{
+			name:     "queue unknown",
+			status:   field.Started{},
+			qjobid:   "q1",
+			queue:    statusQueue{},
+			expected: "started",
+		},
+		{
+			name:     "coordinator finished",
+			status:   field.Completed{},
+			qjobid:   "q1",
+			queue:    statusQueue{"q1": queuer.Failed},
+			expected: "completed",
+		},
+		{
+			name:     "not enqueued",
+			status:   field.Requested{},
+			queue:    statusQueue{"": queuer.Failed},
+			expected: "requested",
+		},
+		{
+			name:     "no info provider",
+			status:   field.Started{},
+			qjobid:   "q1",
+			queue:    enqueueOnly{},
+			expected: "started",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestValidatePriority(t *testing.T) {
+	type tcase struct {
+		name        string
+		maxPriority int
+		priority    *int
+		err         bool
+	}
+
+	p := func(i int) *int { return &i }
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			s := &Server{MaxPriority: tc.maxPriority}
+			err := s.validatePriority(tc.priority)
+			if (err != nil) != tc.err {
+				t.Errorf("error, expected error %v got %v", tc.err, err)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{name: "not given"},
+		{name: "default max", priority: p(DefaultMaxPriority)},
+		{name: "default min", priority: p(-DefaultMaxPriority)},
+		{name: "over default max", priority: p(DefaultMaxPriority + 1), err: true},
+		{name: "under default min", priority: p(-DefaultMaxPriority - 1), err: true},
+		{name: "configured max", maxPriority: 3, priority: p(3)},
+		{name: "over configured max", maxPriority: 3, priority: p(4), err: true},
+		{name: "under configured min", maxPriority: 3, priority: p(-4), err: true},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestQueueHandlerPriority(t *testing.T) {
+	s := &Server{Atlante: new(atlante.Atlante), MaxPriority: 5}
+	for _, path := range []string{"/sheets/50k/mdgid", "/sheets/50k/atlas"} {
+		w := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"mdgid":"V795G25492","mdgids":["V795G25492"],"priority":6}`))
+		params := map[string]string{string(ParamsKeySheetname): "50k"}
+		if strings.HasSuffix(path, "atlas") {
+			s.AtlasHandler(w, request, params)
+		} else {
+			s.QueueHandler(w, request, params)
+		}
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%v code, expected %v got %v", path, http.StatusBadRequest, w.Code)
+		}
+		if desc := w.Header().Get(HTTPErrorHeader); !strings.Contains(desc, "priority") {
+			t.Errorf("%v error, expected the priority got %q", path, desc)
+		}
+	}
+}
+
+func TestRequester(t *testing.T) {
+	type tcase struct {
+		name     string
+		header   string
+		apiKeys  map[string]string
+		key      string
+		expected string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			s := &Server{RequesterHeader: tc.header, APIKeys: tc.apiKeys}
+			request := httptest.NewRequest(http.MethodPost, "/sheets/50k/mdgid", nil)
+			request.RemoteAddr = "192.0.2.1:1234"
+			header := tc.header
+			if header == "" {
+				header = DefaultRequesterHeader
+			}
+			if tc.key != "" {
+				request.Header.Set(header, tc.key)
+			}
+			if got := s.requester(request); got != tc.expected {
+				t.Errorf("requester, expected %q got %q", tc.expected, got)
+			}
+		}
+	}
+
+	keys := map[string]string{"secret": "mapping"}
+	tests := []tcase{
+		{name: "known key", apiKeys: keys, key: "secret", expected: "mapping"},
+		{name: "unknown key", apiKeys: keys, key: "made-up", expected: "192.0.2.1"},
+		{name: "no keys configured", key: "secret", expected: "192.0.2.1"},
+		{name: "no header", apiKeys: keys, expected: "192.0.2.1"},
+		{name: "configured header", header: "X-Token", apiKeys: keys, key: "secret", expected: "mapping"},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -1,6 +1,7 @@
 package atlante
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	math "math"
@@ -9,13 +10,12 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/go-spatial/atlante/atlante/filestore"
 	"github.com/go-spatial/atlante/atlante/grids"
 	"github.com/go-spatial/atlante/atlante/internal/urlutil"
-	"github.com/go-spatial/atlante/atlante/notifiers"
-	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
 	"github.com/go-spatial/atlante/atlante/style"
 	"github.com/prometheus/common/log"
 )
@@ -55,7 +55,14 @@ type Sheet struct {
 	// Description of the sheet
 	Desc string
 
-	Emitter notifiers.Emitter
+	// Renderer renders the map image of the grids; if nil the renderer of the
+	// DefaultRendererType is used
+	Renderer Renderer
+
+	// PDFConverter converts the generated svgs to pdfs; if nil the pdf converter
+	// of the DefaultPDFConverterType is used
+	PDFConverter PDFConverter
+
 	// Width of the canvas in mm
 	Width float64
 	// Height of the canvas in mm
@@ -66,6 +73,44 @@ type Sheet struct {
 
 	// UseCached tells remote file providers to use cached versions
 	UseCached bool
+
+	// GeoPDF will georeference the map frame of the generated pdf, the
+	// template must place the image with .Image.Place
+	GeoPDF bool
+
+	// GeoTIFF will write the rendered map image as a GeoTIFF to the filestore
+	GeoTIFF bool
+
+	// PaperSize is the name of the paper size (a0, letter, arch-d, ...) to use, or auto to
+	// pick the smallest paper size that fits the grid. If empty the Width and Height are used.
+	// Use SetPaperSize to change it.
+	PaperSize string
+	// Orientation of the paper
+	Orientation Orientation
+	// PaperMargin is the margin in mm, on each side, to leave around the map when picking a paper size
+	PaperMargin float64
+
+	// Bleed is the margin in mm, on each side, the page is extended past the trim (the paper
+	// size) so backgrounds can be printed to the edge of the paper once cut
+	Bleed float64
+	// CropMarks adds crop and registration marks outside of the bleed
+	CropMarks bool
+	// LiveMargin is the margin in mm, inside of the trim, content that must not be cut should be kept in
+	LiveMargin float64
+
+	// PDFMetadata is the document metadata, and PDF/A mode, of the generated pdfs
+	PDFMetadata PDFMetadata
+
+	// Poster will split the sheet into pages of a smaller paper size, as a second pdf, if
+	// the paper size of the poster is set
+	Poster PosterOptions
+
+	// Retry is the retry policy for the failed jobs of the sheet; if nil the retry
+	// policy of the queue is used
+	Retry *RetryPolicy
+
+	// jobs are the jobs running on the sheet
+	jobs sync.WaitGroup
 }
 
 // loadTemplateDir will load additional tempalates if the location is local and there is
@@ -110,6 +155,24 @@ func loadTemplateDir(t *template.Template, location *url.URL) (*template.Templat
 	return t.ParseFiles(subtemplates...)
 }
 
+// parseTemplate parses the svg template at location, after the sub-templates in the
+// templates directory next to it, with the given template functions
+func parseTemplate(location *url.URL, funcs template.FuncMap) (*template.Template, error) {
+	tpl, err := urlutil.ReadAll(location)
+	if err != nil {
+		return nil, err
+	}
+
+	t := template.New(location.String()).
+		Funcs(funcs).
+		Option("missingkey=error")
+	t, err = loadTemplateDir(t, location)
+	if err != nil {
+		return nil, err
+	}
+	return t.Parse(string(tpl))
+}
+
 // NewSheet returns a new sheet
 func NewSheet(name string, provider grids.Provider, dpi uint, desc string, stylelist style.Provider, svgTemplateFilename *url.URL, fs filestore.Provider) (*Sheet, error) {
 	var (
@@ -134,20 +197,7 @@ func NewSheet(name string, provider grids.Provider, dpi uint, desc string, style
 	}
 
 	log.Infof("Sheet %v processing template: %v", name, svgTemplateFilename)
-	tpl, err := urlutil.ReadAll(svgTemplateFilename)
-	if err != nil {
-		return nil, err
-	}
-
-	t = template.New(svgTemplateFilename.String()).
-		Funcs(sheet.AddTemplateFuncs(funcMap)).
-		Option("missingkey=error")
-	t, err = loadTemplateDir(t, svgTemplateFilename)
-	if err != nil {
-		return nil, err
-	}
-
-	t, err = t.Parse(string(tpl))
+	t, err = parseTemplate(svgTemplateFilename, sheet.AddTemplateFuncs(funcMap))
 	if err != nil {
 		return nil, err
 	}
@@ -164,8 +214,20 @@ func NewSheet(name string, provider grids.Provider, dpi uint, desc string, style
 }
 
 // Execute the sheets template
-func (sheet *Sheet) Execute(wr io.Writer, tplContext *GridTemplateContext) error {
-	return sheet.svgTemplate.Execute(wr, tplContext)
+func (sheet *Sheet) Execute(ctx context.Context, wr io.Writer, tplContext *GridTemplateContext) error {
+	return sheet.execute(ctx, wr, tplContext, sheet.FuncFilestoreWriter, sheet.UseCached)
+}
+
+// execute runs the sheets template for a job; the remote files of the template are written
+// with fswriter and emitted to the emitter of ctx, so jobs running on the sheet at the same
+// time don't share them
+func (sheet *Sheet) execute(ctx context.Context, wr io.Writer, tplContext *GridTemplateContext, fswriter filestore.FileWriter, useCached bool) error {
+	t, err := sheet.svgTemplate.Clone()
+	if err != nil {
+		return err
+	}
+	t.Funcs(template.FuncMap{"remote": remoteFunc(ctx, fswriter, useCached)})
+	return t.Execute(wr, tplContext)
 }
 
 func mmToPoint(mm float64, dpi uint) uint64 {
@@ -192,23 +254,17 @@ func (sheet *Sheet) WidthInPoints(dpi uint) uint64 {
 	return mmToPoint(sheet.Width, dpi)
 }
 
-// Emit will emit an notifier event if the notifier is not nil.
-func (sheet *Sheet) Emit(status field.StatusEnum) error {
-	if sheet == nil || sheet.Emitter == nil {
-		return nil
+// pdfConverter returns the pdf converter of the sheet, or the default pdf converter
+func (sheet *Sheet) pdfConverter() (PDFConverter, error) {
+	if sheet.PDFConverter != nil {
+		return sheet.PDFConverter, nil
 	}
-	return sheet.Emitter.Emit(status)
-}
-
-// EmitError will emit a notifier event for a failed processing job
-func (sheet *Sheet) EmitError(desc string, err error) error {
-	if sheet == nil || sheet.Emitter == nil || err == nil {
-		return nil
+	converter, err := PDFConverterFor(DefaultPDFConverterType)
+	if err != nil {
+		log.Warnf("default pdf converter: %v", err)
+		return nil, ErrNoPDFConverter
 	}
-	return sheet.Emitter.Emit(field.Failed{
-		Description: desc,
-		Error:       err,
-	})
+	return converter, nil
 }
 
 func (sheet *Sheet) GetURL(mdgid string, filename string, intermediate bool) (filestore.URLInfo, bool) {
@@ -259,17 +315,18 @@ func (a *Atlante) SheetFor(sheetName string) (*Sheet, error) {
 	if a == nil {
 		return nil, ErrNilAtlanteObject
 	}
+	sheetnm := a.NormalizeSheetName(sheetName, false)
+
+	a.sLock.RLock()
 	if len(a.sheets) == 0 {
+		a.sLock.RUnlock()
 		return nil, ErrNoSheets
 	}
-	sheetnm := a.NormalizeSheetName(sheetName, false)
+	sheet := a.sheets[sheetnm]
+	a.sLock.RUnlock()
 	if sheetnm == "" {
 		return nil, ErrBlankSheetName
 	}
-
-	a.sLock.RLock()
-	sheet := a.sheets[sheetnm]
-	a.sLock.RUnlock()
 	if sheet == nil {
 		return nil, ErrUnknownSheetName(sheetnm)
 	}
@@ -278,11 +335,15 @@ func (a *Atlante) SheetFor(sheetName string) (*Sheet, error) {
 
 // SheetNames returns the currently configured sheet names.
 func (a *Atlante) SheetNames() (sheets []string) {
-	if a == nil || len(a.sheets) == 0 {
+	if a == nil {
 		return sheets
 	}
-	sheets = make([]string, len(a.sheets))
 	a.sLock.RLock()
+	if len(a.sheets) == 0 {
+		a.sLock.RUnlock()
+		return sheets
+	}
+	sheets = make([]string, len(a.sheets))
 	i := 0
 	for k := range a.sheets {
 		sheets[i] = k
@@ -295,14 +356,17 @@ func (a *Atlante) SheetNames() (sheets []string) {
 
 // Sheets returns the currently configured sheets
 func (a *Atlante) Sheets() (sheets []*Sheet) {
-	if a == nil || len(a.sheets) == 0 {
+	sheetnames := a.SheetNames()
+	if len(sheetnames) == 0 {
 		return sheets
 	}
-	sheetnames := a.SheetNames()
-	sheets = make([]*Sheet, len(sheetnames))
+	sheets = make([]*Sheet, 0, len(sheetnames))
 	a.sLock.RLock()
-	for i, k := range sheetnames {
-		sheets[i] = a.sheets[k]
+	for _, k := range sheetnames {
+		// the sheets may have been replaced since we got the names
+		if sheet, ok := a.sheets[k]; ok {
+			sheets = append(sheets, sheet)
+		}
 	}
 	a.sLock.RUnlock()
 	return sheets
@@ -332,3 +396,59 @@ func (a *Atlante) AddSheet(s *Sheet) error {
 	a.sheets[name] = s
 	return nil
 }
+
+// ReplaceSheets replaces all of the sheets of atlante with the given sheets. The sheets
+// are added, with AddSheet, to a new set of sheets which is swapped in under the sheet
+// lock, so the sheets are replaced all at once or, on error, not at all. Jobs that are
+// running keep the sheets they started with; the replaced sheets are returned, use
+// Wait on them to wait for those jobs.
+func (a *Atlante) ReplaceSheets(sheets ...*Sheet) (replaced []*Sheet, err error) {
+	if a == nil {
+		return nil, ErrNilAtlanteObject
+	}
+	var staged Atlante
+	for _, s := range sheets {
+		if err := staged.AddSheet(s); err != nil {
+			return nil, err
+		}
+	}
+	a.sLock.Lock()
+	for _, s := range a.sheets {
+		replaced = append(replaced, s)
+	}
+	a.sheets = staged.sheets
+	a.sLock.Unlock()
+	return replaced, nil
+}
+
+// acquireSheet is like SheetFor, but counts the caller as a job running on the sheet till
+// it calls release on the sheet
+func (a *Atlante) acquireSheet(sheetName string) (*Sheet, error) {
+	if a == nil {
+		return nil, ErrNilAtlanteObject
+	}
+	sheetnm := a.NormalizeSheetName(sheetName, false)
+	if sheetnm == "" {
+		return nil, ErrBlankSheetName
+	}
+
+	// the sheet is counted under the lock so it can not be replaced, and waited on,
+	// before the job is counted
+	a.sLock.RLock()
+	defer a.sLock.RUnlock()
+	if len(a.sheets) == 0 {
+		return nil, ErrNoSheets
+	}
+	sheet := a.sheets[sheetnm]
+	if sheet == nil {
+		return nil, ErrUnknownSheetName(sheetnm)
+	}
+	sheet.jobs.Add(1)
+	return sheet, nil
+}
+
+// release records that a job acquired with acquireSheet is done with the sheet
+func (sheet *Sheet) release() { sheet.jobs.Done() }
+
+// Wait waits for the jobs running on the sheet to finish
+func (sheet *Sheet) Wait() { sheet.jobs.Wait() }
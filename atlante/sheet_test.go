@@ -4,6 +4,7 @@ import (
 	"fmt"
 	math "math"
 	"testing"
+	"time"
 )
 
 // TOLERANCE is the epsilon value used in comparing floats.
@@ -107,3 +108,101 @@ func Test_MMConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceSheets(t *testing.T) {
+	type tcase struct {
+		name   string
+		sheets []*Sheet
+		names  []string
+		err    error
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			var a Atlante
+			old := &Sheet{Name: "50k"}
+			if err := a.AddSheet(old); err != nil {
+				t.Fatalf("add sheet, expected nil got %v", err)
+			}
+			replaced, err := a.ReplaceSheets(tc.sheets...)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err == nil && (len(replaced) != 1 || replaced[0] != old) {
+				t.Errorf("replaced, expected the old sheet got %v", replaced)
+			}
+			names := a.SheetNames()
+			if fmt.Sprint(names) != fmt.Sprint(tc.names) {
+				t.Errorf("names, expected %v got %v", tc.names, names)
+			}
+			if tc.err != nil {
+				// the sheets should not have changed
+				if sheet, _ := a.SheetFor("50k"); sheet != old {
+					t.Errorf("sheet, expected the old sheet got %v", sheet)
+				}
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:   "replace",
+			sheets: []*Sheet{{Name: "50K"}, {Name: "25k"}},
+			names:  []string{"25k", "50k"},
+		},
+		{
+			name:   "duplicate",
+			sheets: []*Sheet{{Name: "25k"}, {Name: " 25k "}},
+			names:  []string{"50k"},
+			err:    ErrDuplicateSheetName,
+		},
+		{
+			name:   "blank",
+			sheets: []*Sheet{{Name: "25k"}, {Name: " "}},
+			names:  []string{"50k"},
+			err:    ErrBlankSheetName,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestReplaceSheetsWait(t *testing.T) {
+	var a Atlante
+	if err := a.AddSheet(&Sheet{Name: "50k"}); err != nil {
+		t.Fatalf("add sheet, expected nil got %v", err)
+	}
+	sheet, err := a.acquireSheet("50K")
+	if err != nil {
+		t.Fatalf("acquire sheet, expected nil got %v", err)
+	}
+	replaced, err := a.ReplaceSheets(&Sheet{Name: "50k"})
+	if err != nil {
+		t.Fatalf("replace sheets, expected nil got %v", err)
+	}
+	if len(replaced) != 1 || replaced[0] != sheet {
+		t.Fatalf("replaced, expected the acquired sheet got %v", replaced)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		replaced[0].Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatalf("wait, expected to wait for the job on the sheet")
+	case <-time.After(20 * time.Millisecond):
+	}
+	sheet.release()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("wait, expected to return once the job released the sheet")
+	}
+
+	if _, err = a.acquireSheet("none"); err != ErrUnknownSheetName("none") {
+		t.Errorf("acquire sheet, expected %v got %v", ErrUnknownSheetName("none"), err)
+	}
+}
@@ -0,0 +1,188 @@
+package atlante
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante/filestore"
+	fsfile "github.com/go-spatial/atlante/atlante/filestore/file"
+	fsmulti "github.com/go-spatial/atlante/atlante/filestore/multi"
+	"github.com/go-spatial/atlante/atlante/grids"
+)
+
+const (
+	// IssueParse is a template that does not parse
+	IssueParse = "parse"
+	// IssueFunction is a call to a function that is not defined
+	IssueFunction = "function"
+	// IssueArgs is a required args key that is missing
+	IssueArgs = "args"
+	// IssueExecute is a template that failed to execute
+	IssueExecute = "execute"
+	// IssueSVG is a template that does not produce a valid svg
+	IssueSVG = "svg"
+
+	// maxUnknownFunctions is the number of unknown functions after which we stop
+	// looking for more
+	maxUnknownFunctions = 100
+)
+
+var (
+	// unknownFunctionRe matches the parse error of a function that is not defined
+	unknownFunctionRe = regexp.MustCompile(`function "([^"]+)" not defined`)
+	// missingArgsRe matches the errors of check_args, and of the tplArgs getters
+	missingArgsRe = regexp.MustCompile(`Missing required keys|Unknown key`)
+)
+
+// TemplateIssue is a problem found checking a sheet template
+type TemplateIssue struct {
+	// Kind is one of the Issue constants
+	Kind string
+	Msg  string
+}
+
+func (issue TemplateIssue) String() string {
+	return fmt.Sprintf("[%v] %v", issue.Kind, issue.Msg)
+}
+
+// CheckCell returns a cell that can be used to check a template; it is a 15 minute cell
+// near San Diego with all of the information a provider would fill in.
+func CheckCell() *grids.Cell {
+	return grids.NewCell(
+		"V795G25492",
+		[2]float64{32.5, -117.25},
+		[2]float64{32.75, -117},
+		"United States",
+		"San Diego",
+		nil,
+		grids.NewEditInfo("atlante", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		"NRN",
+		"2492",
+		"V795",
+		[2]string{},
+		[2]string{},
+		nil,
+	)
+}
+
+// parseTemplateChecked parses the template, replacing any function that is not defined with
+// a function that returns an empty string so the rest of the template can be checked. It
+// returns the parse errors of the functions that were not defined.
+func parseTemplateChecked(location *url.URL, funcs template.FuncMap) (*template.Template, []error, error) {
+	var (
+		unknown []error
+		stub    = func(...interface{}) string { return "" }
+	)
+	for {
+		t, err := parseTemplate(location, funcs)
+		if err == nil {
+			return t, unknown, nil
+		}
+		match := unknownFunctionRe.FindStringSubmatch(err.Error())
+		if match == nil || len(unknown) >= maxUnknownFunctions {
+			return nil, unknown, err
+		}
+		if _, ok := funcs[match[1]]; ok {
+			// should not happen, but don't loop forever
+			return nil, unknown, err
+		}
+		unknown = append(unknown, err)
+		funcs[match[1]] = stub
+	}
+}
+
+// checkSVG checks that the document is well formed xml with a svg root element
+func checkSVG(r io.Reader) error {
+	var (
+		decoder = xml.NewDecoder(r)
+		root    string
+	)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if el, ok := tok.(xml.StartElement); ok && root == "" {
+			root = el.Name.Local
+		}
+	}
+	if root != "svg" {
+		if root == "" {
+			return ErrInvalidSVG
+		}
+		return fmt.Errorf("root element is %v, expected svg", root)
+	}
+	return nil
+}
+
+// CheckTemplate parses the svg template at location, and the templates directory next to
+// it, and executes it for the sheet against the grid, returning the problems found: functions
+// that are not defined, missing args keys, other parse and execute errors, and output that is
+// not a valid svg. The files generated by the template are written to dir. The sheet
+// should have a renderer that does not need the styles, such as the FakeRenderer.
+func CheckTemplate(ctx context.Context, sheet *Sheet, location *url.URL, grid *grids.Cell, dir string) ([]TemplateIssue, error) {
+	if sheet == nil {
+		return nil, ErrNilSheet
+	}
+	if grid == nil {
+		return nil, ErrNilGrid
+	}
+	var issues []TemplateIssue
+
+	funcs := make(template.FuncMap, len(funcMap)+1)
+	for k, v := range sheet.AddTemplateFuncs(funcMap) {
+		funcs[k] = v
+	}
+	t, unknown, err := parseTemplateChecked(location, funcs)
+	for _, e := range unknown {
+		issues = append(issues, TemplateIssue{Kind: IssueFunction, Msg: e.Error()})
+	}
+	if err != nil {
+		issues = append(issues, TemplateIssue{Kind: IssueParse, Msg: err.Error()})
+		return issues, nil
+	}
+	sheet.svgTemplate = t
+
+	multiWriter := fsmulti.FileWriter{
+		Writers: []filestore.FileWriter{fsfile.Writer{Base: dir, Intermediate: true}},
+	}
+	filenames := &GeneratedFiles{
+		IMG: "check.png",
+		SVG: "check.svg",
+		TIF: "check.tif",
+	}
+	if _, err = renderSVG(ctx, sheet, grid, filenames, multiWriter, false, 0, 0); err != nil {
+		kind := IssueExecute
+		if missingArgsRe.MatchString(err.Error()) {
+			kind = IssueArgs
+		}
+		issues = append(issues, TemplateIssue{Kind: kind, Msg: err.Error()})
+		return issues, nil
+	}
+
+	svg, err := ioutil.ReadFile(filepath.Join(dir, filenames.SVG))
+	if err != nil {
+		return issues, err
+	}
+	if err = checkSVG(bytes.NewReader(svg)); err != nil {
+		issues = append(issues, TemplateIssue{
+			Kind: IssueSVG,
+			Msg:  strings.TrimSpace(err.Error()),
+		})
+	}
+	return issues, nil
+}
@@ -0,0 +1,151 @@
+package atlante
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/atlante/atlante/style"
+)
+
+func TestCheckSVG(t *testing.T) {
+	type tcase struct {
+		svg string
+		err string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.svg, func(t *testing.T) {
+			err := checkSVG(strings.NewReader(tc.svg))
+			if tc.err == "" {
+				if err != nil {
+					t.Errorf("error, expected nil got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{svg: `<?xml version="1.0"?><svg width="10" height="10"><rect/></svg>`},
+		{svg: `<svg><g></svg>`, err: "element <g> closed by </svg>"},
+		{svg: `<html></html>`, err: "root element is html"},
+		{svg: ``, err: string(ErrInvalidSVG)},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestCheckTemplate(t *testing.T) {
+	type tcase struct {
+		name     string
+		template string
+		partial  string
+		metadata map[string]string
+		kinds    []string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "atlante_check")
+			if err != nil {
+				t.Fatalf("temp dir, expected nil got %v", err)
+			}
+			defer os.RemoveAll(dir)
+			tplfn := filepath.Join(dir, "sheet.svg")
+			if err = ioutil.WriteFile(tplfn, []byte(tc.template), 0644); err != nil {
+				t.Fatalf("write template, expected nil got %v", err)
+			}
+			if tc.partial != "" {
+				if err = os.Mkdir(filepath.Join(dir, "templates"), 0755); err != nil {
+					t.Fatalf("mkdir, expected nil got %v", err)
+				}
+				if err = ioutil.WriteFile(filepath.Join(dir, "templates", "partial.tpl"), []byte(tc.partial), 0644); err != nil {
+					t.Fatalf("write partial, expected nil got %v", err)
+				}
+			}
+			outdir := filepath.Join(dir, "out")
+
+			sheet := &Sheet{
+				Name:     "50k",
+				DPI:      144,
+				Scale:    50000,
+				Width:    200,
+				Height:   150,
+				Styles:   (*style.List)(nil),
+				Renderer: FakeRenderer{Pattern: FakePatternSolid, CellSize: 64},
+			}
+			grid := CheckCell()
+			for k, v := range tc.metadata {
+				grid.MetaData[k] = v
+			}
+			issues, err := CheckTemplate(context.Background(), sheet, &url.URL{Path: tplfn}, grid, outdir)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(issues) != len(tc.kinds) {
+				t.Fatalf("issues, expected %v got %v", tc.kinds, issues)
+			}
+			for i := range issues {
+				if issues[i].Kind != tc.kinds[i] {
+					t.Errorf("issue %v kind, expected %v got %v", i, tc.kinds[i], issues[i])
+				}
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:     "good",
+			template: `<svg width="{{ .Width }}" height="{{ .Height }}">{{ template "title" . }}<image xlink:href="{{ .Image.Filename }}"/></svg>`,
+			partial:  `{{ define "title" }}<text>{{ .Grid.GetMdgid.AsString }}</text>{{ end }}`,
+		},
+		{
+			name:     "unknown functions",
+			template: `<svg>{{ frobnicate 1 }}{{ template "title" . }}</svg>`,
+			partial:  `{{ define "title" }}<text>{{ bogus .Grid }}</text>{{ end }}`,
+			kinds:    []string{IssueFunction, IssueFunction},
+		},
+		{
+			name:     "parse error",
+			template: `<svg>{{ if .Width }}</svg>`,
+			kinds:    []string{IssueParse},
+		},
+		{
+			name:     "missing args",
+			template: `{{ $args := args "x" 1 }}<svg>{{ check_args $args "x" "y" }}</svg>`,
+			kinds:    []string{IssueArgs},
+		},
+		{
+			name:     "missing args metadata",
+			template: `<svg>{{ .Args.Get "series" }}</svg>`,
+			kinds:    []string{IssueArgs},
+		},
+		{
+			name:     "metadata args",
+			template: `<svg>{{ .Args.Get "series" }}</svg>`,
+			metadata: map[string]string{"series": "V795"},
+		},
+		{
+			name:     "execute error",
+			template: `<svg>{{ div 1 0 }}</svg>`,
+			kinds:    []string{IssueExecute},
+		},
+		{
+			name:     "invalid svg",
+			template: `<svg><text>{{ .Width }}</svg>`,
+			kinds:    []string{IssueSVG},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -2,6 +2,7 @@ package atlante
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -14,10 +15,12 @@ import (
 	"github.com/gdey/as"
 	"github.com/prometheus/common/log"
 
+	"github.com/go-spatial/atlante/atlante/filestore"
 	"github.com/go-spatial/atlante/atlante/server/coordinator/field"
 	"github.com/go-spatial/atlante/atlante/template/grating"
 	"github.com/go-spatial/atlante/atlante/template/remote"
 	"github.com/go-spatial/atlante/atlante/template/trellis"
+	"github.com/go-spatial/atlante/mbgl/bounds"
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/planar/coord"
 )
@@ -164,8 +167,16 @@ func (sheet *Sheet) AddTemplateFuncs(funcMap template.FuncMap) template.FuncMap
 }
 
 func (sheet *Sheet) templateFuncRemote(loc string) (string, error) {
-	sheet.Emit(field.Processing{Description: fmt.Sprintf("remote file: %v", loc)})
-	return remote.Remote(loc, sheet.FuncFilestoreWriter, sheet.UseCached)
+	return remoteFunc(context.Background(), sheet.FuncFilestoreWriter, sheet.UseCached)(loc)
+}
+
+// remoteFunc returns the remote template function of a job; the remote files are written
+// with fswriter, and emitted to the emitter of ctx
+func remoteFunc(ctx context.Context, fswriter filestore.FileWriter, useCached bool) func(string) (string, error) {
+	return func(loc string) (string, error) {
+		Emit(ctx, field.Processing{Description: fmt.Sprintf("remote file: %v", loc)})
+		return remote.Remote(loc, fswriter, useCached)
+	}
 }
 
 //tplFormat is a helper function for templates that will format the given
@@ -494,6 +505,46 @@ func LngLatCoord(lngArg, latArg interface{}) (lnglat coord.LngLat, err error) {
 	return lnglat, nil
 }
 
+// tplSVGPath returns the svg path data for the polygons of the geometry; the projected
+// extent is scaled to the pixel box x1,y1 (top left) to x2,y2 (bottom right).
+func tplSVGPath(g geom.Geometry, extent *geom.Extent, prj bounds.AProjection, x1, y1, x2, y2 float64) string {
+	var polygons [][][][2]float64
+	switch geo := g.(type) {
+	case geom.Polygoner:
+		polygons = append(polygons, geo.LinearRings())
+	case geom.MultiPolygoner:
+		polygons = geo.Polygons()
+	default:
+		return ""
+	}
+	// projected min and max
+	min := prj.Project([2]float64{extent.MinY(), extent.MinX()})
+	max := prj.Project([2]float64{extent.MaxY(), extent.MaxX()})
+	dx, dy := max[0]-min[0], max[1]-min[1]
+	if dx == 0 || dy == 0 {
+		return ""
+	}
+
+	var path strings.Builder
+	for _, plyg := range polygons {
+		for _, ring := range plyg {
+			for i, pt := range ring {
+				xy := prj.Project([2]float64{pt[1], pt[0]})
+				cmd := "L"
+				if i == 0 {
+					cmd = "M"
+				}
+				fmt.Fprintf(&path, "%s%.3f,%.3f ", cmd,
+					x1+(xy[0]-min[0])/dx*(x2-x1),
+					y1+(max[1]-xy[1])/dy*(y2-y1),
+				)
+			}
+			path.WriteString("Z ")
+		}
+	}
+	return strings.TrimSpace(path.String())
+}
+
 type PixelBox struct {
 	Starting     [2]float64
 	Ending       [2]float64
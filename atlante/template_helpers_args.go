@@ -269,5 +269,5 @@ func (a *tplArgs) GetAsArgs(key string) (*tplArgs, error) {
 	case tplArgs:
 		return &newArgs, nil
 	}
-	return nil, fmt.Errorf("%k does not contains args")
+	return nil, fmt.Errorf("%v does not contains args", key)
 }
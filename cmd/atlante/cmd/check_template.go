@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/config"
+	"github.com/go-spatial/atlante/atlante/style"
+	cmdconfig "github.com/go-spatial/atlante/cmd/atlante/config"
+	"github.com/go-spatial/tegola/dict"
+	"github.com/spf13/cobra"
+)
+
+// DefaultCheckScale is the scale of the cell used to check a template
+const DefaultCheckScale = 50000
+
+var (
+	// CheckTemplate is the command to lint the template of a sheet
+	CheckTemplate = &cobra.Command{
+		Use:   "check-template",
+		Short: "Check the template of a sheet for errors",
+		Long: `Parse the svg template of the sheet, and the templates directory next to it, and
+execute it against a mock cell with the fake renderer. Functions that are not defined,
+missing args keys, other template errors and invalid svg output are reported, and the
+command exits non-zero if any are found. The grid provider and file stores of the
+sheet are not used.`,
+		RunE: checkTemplateCmdRunE,
+	}
+
+	checkScale    uint
+	checkMetadata map[string]string
+	checkKeep     bool
+)
+
+func init() {
+	CheckTemplate.Flags().StringVar(&sheetName, "sheet", "", "the sheet whose template to check; all sheets if not given")
+	CheckTemplate.Flags().UintVar(&checkScale, "scale", DefaultCheckScale, "the scale of the mock cell")
+	CheckTemplate.Flags().StringToStringVar(&checkMetadata, "metadata", nil, "metadata of the mock job, as key=value")
+	CheckTemplate.Flags().BoolVar(&checkKeep, "keep", false, "keep the generated svg and images")
+}
+
+// checkSheetTemplate checks the template of the sheet config, and returns the issues found
+func checkSheetTemplate(ctx context.Context, sheet config.Sheet, renderer atlante.Renderer) ([]atlante.TemplateIssue, error) {
+	templateURL, err := url.Parse(string(sheet.Template))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template url (%v): %v", string(sheet.Template), err)
+	}
+	odpi := uint(sheet.DPI)
+	if odpi == 0 {
+		odpi = uint(dpi)
+	}
+	sht := &atlante.Sheet{
+		Name:                strings.TrimSpace(strings.ToLower(string(sheet.Name))),
+		DPI:                 odpi,
+		Scale:               checkScale,
+		Styles:              style.Provider((*style.List)(nil)),
+		SvgTemplateFilename: templateURL.String(),
+		Desc:                string(sheet.Description),
+		Width:               atlante.DefaultWidthMM,
+		Height:              atlante.DefaultHeightMM,
+		Renderer:            renderer,
+	}
+	if err = cmdconfig.ConfigureSheet(sht, sheet); err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "atlante_check_"+sht.Name)
+	if err != nil {
+		return nil, err
+	}
+	if checkKeep {
+		fmt.Fprintf(os.Stderr, "[check] sheet %v files: %v\n", sht.Name, dir)
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	cell := atlante.CheckCell()
+	for k, v := range checkMetadata {
+		cell.MetaData[k] = v
+	}
+	return atlante.CheckTemplate(ctx, sht, templateURL, cell, dir)
+}
+
+func checkTemplateCmdRunE(cmd *cobra.Command, args []string) error {
+	aURL, overlayURLs, err := cmdconfig.ParseLocations(configFile, overlayFiles...)
+	if err != nil {
+		return err
+	}
+	conf, err := config.LoadAndValidate(aURL, overlayURLs...)
+	if err != nil {
+		return ErrExitWith{
+			ShowUsage: true,
+			Msg:       fmt.Sprintf("[error] loading config: %v\n", err),
+			Err:       err,
+			ExitCode:  1,
+		}
+	}
+
+	var sheets []config.Sheet
+	name := strings.TrimSpace(strings.ToLower(sheetName))
+	for _, sheet := range conf.Sheets {
+		if name == "" || strings.ToLower(string(sheet.Name)) == name {
+			sheets = append(sheets, sheet)
+		}
+	}
+	if len(sheets) == 0 {
+		var strwriter strings.Builder
+		fmt.Fprintf(&strwriter, "\t[error] unknown sheet name `%v`\n", name)
+		fmt.Fprintf(&strwriter, "\tknown sheets\n")
+		for _, sheet := range conf.Sheets {
+			fmt.Fprintf(&strwriter, "\t\t%v\n", strings.ToLower(string(sheet.Name)))
+		}
+		return ErrExitWith{
+			Msg:      strwriter.String(),
+			Err:      atlante.ErrUnknownSheetName(name),
+			ExitCode: 1,
+		}
+	}
+
+	// The fake renderer does not need the styles, or the mbgl libraries
+	renderer, err := atlante.RendererFor(atlante.FakeRendererType, dict.Dict{})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	failed := 0
+	for _, sheet := range sheets {
+		issues, err := checkSheetTemplate(ctx, sheet, renderer)
+		if err != nil {
+			issues = append(issues, atlante.TemplateIssue{Kind: "config", Msg: err.Error()})
+		}
+		if len(issues) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "ok    %v (%v)\n", sheet.Name, sheet.Template)
+			continue
+		}
+		failed++
+		fmt.Fprintf(cmd.OutOrStdout(), "FAIL  %v (%v)\n", sheet.Name, sheet.Template)
+		for _, issue := range issues {
+			fmt.Fprintf(cmd.OutOrStdout(), "\t%v\n", issue)
+		}
+	}
+	if failed > 0 {
+		return ErrExitWith{
+			Msg:      fmt.Sprintf("[error] %v of %v sheet templates have errors\n", failed, len(sheets)),
+			ExitCode: 2,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/config"
+	cmdconfig "github.com/go-spatial/atlante/cmd/atlante/config"
+	"github.com/prometheus/common/log"
+)
+
+// DefaultReloadInterval is how often the config file is checked for changes
+const DefaultReloadInterval = 5 * time.Second
+
+// configReloader reloads the sheets, styles and file stores of atlante from the config
+type configReloader struct {
+	// lock makes sure only one reload runs at a time
+	lock        sync.Mutex
+	location    *url.URL
+	overlays    []*url.URL
+	a           *atlante.Atlante
+	dpi         int
+	overrideDPI bool
+}
+
+// Reload loads and validates the config, and replaces the sheets of atlante with the ones
+// it describes. If the config fails to load the current sheets are kept. The grid providers
+// of the replaced sheets are closed once the jobs running on them are done. The webserver,
+// queue, coordinator and notifier are not reloaded.
+func (r *configReloader) Reload() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	conf, err := config.LoadAndValidate(r.location, r.overlays...)
+	if err != nil {
+		return err
+	}
+	providers, fileStores := cmdconfig.Providers, cmdconfig.FileStores
+	na, err := cmdconfig.LoadConfig(conf, r.dpi, r.overrideDPI)
+	if err != nil {
+		return err
+	}
+	replaced, err := r.a.ReplaceSheets(na.Sheets()...)
+	if err != nil {
+		cmdconfig.CloseProviders(cmdconfig.Providers)
+		cmdconfig.Providers, cmdconfig.FileStores = providers, fileStores
+		return err
+	}
+	go func() {
+		for _, sheet := range replaced {
+			sheet.Wait()
+		}
+		cmdconfig.CloseProviders(providers)
+	}()
+	log.Infof("reloaded config %v: sheets %v", r.location, r.a.SheetNames())
+	return nil
+}
+
+// reload reloads the config, logging the error if there is one
+func (r *configReloader) reload(reason string) {
+	log.Infof("reloading config (%v)", reason)
+	if err := r.Reload(); err != nil {
+		log.Errorf("failed to reload config, keeping the current config: %v", err)
+	}
+}
+
+// modTime returns the latest modification time of the config and overlay files, false if
+// none of them are local files. Changes to included files are not seen.
+func (r *configReloader) modTime() (latest time.Time, ok bool) {
+	for _, location := range append([]*url.URL{r.location}, r.overlays...) {
+		if scheme := strings.ToLower(location.Scheme); scheme != "" && scheme != "file" {
+			continue
+		}
+		fi, err := os.Stat(location.Path)
+		if err != nil {
+			continue
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+		ok = true
+	}
+	return latest, ok
+}
+
+// Watch reloads the config on SIGHUP, and when the config file is modified; it is
+// checked every interval, if the interval is not zero and the config file is local.
+func (r *configReloader) Watch(ctx context.Context, interval time.Duration) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	var tick <-chan time.Time
+	lastMod, ok := r.modTime()
+	if interval > 0 && ok {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			r.reload("SIGHUP")
+		case <-tick:
+			mod, ok := r.modTime()
+			if !ok || mod.Equal(lastMod) {
+				// the file may be in the middle of being replaced
+				continue
+			}
+			lastMod = mod
+			r.reload("config file modified")
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -53,6 +54,7 @@ var (
 	jobid      string
 	showJob    bool
 	listStyles bool
+	listCells  bool
 	workDir    string
 	timeout    uint8
 	srid       int
@@ -62,9 +64,13 @@ var (
 	styleName  string
 )
 
+// overlayFiles are the config files merged on top of the config file
+var overlayFiles []string
+
 func init() {
 
 	Root.PersistentFlags().StringVar(&configFile, "config", "config.toml", "config file to use")
+	Root.PersistentFlags().StringSliceVar(&overlayFiles, "overlay", nil, "config files to merge on top of the config file, in order")
 	Root.PersistentFlags().IntVar(&dpi, "dpi", DefaultDPI, "dpi to use")
 	Root.Flags().StringVar(&mdgid, "mdgid", "", "mdgid of the grid")
 	Root.Flags().StringVar(&sheetName, "sheet", "", "the sheet to use")
@@ -77,9 +83,15 @@ func init() {
 	Root.Flags().StringVar(&boundsStr, "bounds", "", "the bounds to use to generate the map")
 	Root.Flags().StringVar(&styleName, "style", "", "The name of the style to use; will use the default for sheet if not given")
 	Root.Flags().BoolVar(&listStyles, "list-styles", false, "list out the styles, if sheet is defined, then just list the styles for that sheet.")
+	Root.Flags().BoolVar(&listCells, "list-cells", false, "list out the cells of the sheet that intersect the bounds.")
 
 	// Add server command
 	Root.AddCommand(Server)
+	// Add template test commands
+	Root.AddCommand(TestTemplates)
+	Root.AddCommand(CheckTemplate)
+	// Add worker command
+	Root.AddCommand(Worker)
 }
 
 // Root is the main cobra command
@@ -97,11 +109,24 @@ func generatePDFForJob(ctx context.Context, a *atlante.Atlante, jobstr string) (
 	if err != nil {
 		return nil, fmt.Errorf("%v : jobstr '%v' ", err, jobstr)
 	}
-	return a.GeneratePDFJob(ctx, *job, "")
+	// failed jobs are retried according to the retry policy of the sheet
+	for attempt := 1; ; attempt++ {
+		files, err := a.RunJob(ctx, *job, attempt, nil, "")
+		var retry atlante.ErrRetryJob
+		if !errors.As(err, &retry) {
+			return files, err
+		}
+		log.Printf("attempt %v failed: %v, retrying in %v", attempt, retry.Err, retry.After)
+		select {
+		case <-ctx.Done():
+			return files, retry.Err
+		case <-time.After(retry.After):
+		}
+	}
 }
 
 func rootCmdParseArgs(ctx context.Context, a *atlante.Atlante) (*atlante.GeneratedFiles, error) {
-	a.JobID = jobid
+	ctx = a.JobContext(ctx, jobid)
 	switch {
 
 	case listStyles:
@@ -133,6 +158,33 @@ func rootCmdParseArgs(ctx context.Context, a *atlante.Atlante) (*atlante.Generat
 		}
 		return nil, nil
 
+	case listCells:
+		if !haveBounds {
+			return nil, fmt.Errorf("bounds must be given to list cells")
+		}
+		sname := a.NormalizeSheetName(sheetName, true)
+		sheet, err := a.SheetFor(sname)
+		if err != nil {
+			return nil, err
+		}
+		ext := geom.Extent{float64(bounds[0]), float64(bounds[1]), float64(bounds[2]), float64(bounds[3])}
+		iter, err := grids.CellsForBounds(sheet.Provider, ext, uint(srid))
+		if err != nil {
+			return nil, err
+		}
+		defer iter.Close()
+		count := 0
+		for iter.Next() {
+			cell := iter.Cell()
+			count++
+			fmt.Fprintf(os.Stdout, "  % 5d: %s -- %s\n", count, cell.GetMdgid().AsString(), cell.GetSheet())
+		}
+		if err = iter.Err(); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stdout, "sheet '%v' has %v cells in %v\n", sheet.Name, count, ext)
+		return nil, nil
+
 	case showJob:
 		switch {
 		case job != "":
@@ -233,7 +285,7 @@ func rootCmdRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	a, err := config.Load(configFile, dpi, cmd.Flag("dpi").Changed)
+	a, err := config.Load(configFile, dpi, cmd.Flag("dpi").Changed, overlayFiles...)
 	if err != nil {
 		return ErrExitWith{
 			ShowUsage: true,
@@ -1,9 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
-	"net/url"
+	"time"
 
 	"github.com/go-spatial/atlante/atlante/server/coordinator"
 	crdnull "github.com/go-spatial/atlante/atlante/server/coordinator/null"
@@ -31,19 +32,23 @@ var (
 
 	// port that server should start up on, but default we will use :8080
 	port = ":8080"
+
+	// reloadInterval is how often to check the config file for changes
+	reloadInterval time.Duration
 )
 
 func init() {
 	Server.Flags().StringVar(&port, "port", ":8080", "port to start the server on")
+	Server.Flags().DurationVar(&reloadInterval, "reload-interval", DefaultReloadInterval, "how often to check the config file for changes to reload; 0 to only reload on SIGHUP")
 }
 
 func serverCmdRunE(cmd *cobra.Command, args []string) error {
 
-	aURL, err := url.Parse(configFile)
+	aURL, overlayURLs, err := cmdconfig.ParseLocations(configFile, overlayFiles...)
 	if err != nil {
 		return err
 	}
-	conf, err := config.LoadAndValidate(aURL)
+	conf, err := config.LoadAndValidate(aURL, overlayURLs...)
 	if err != nil {
 		return err
 	}
@@ -74,6 +79,16 @@ func serverCmdRunE(cmd *cobra.Command, args []string) error {
 		Atlante:               a,
 		Coordinator:           coordinator.Provider(crdnull.Provider{}),
 		DisableNotificationEP: conf.Webserver.DisableNotificationEP,
+		RequesterHeader:       string(conf.Webserver.RequesterHeader),
+		APIKeys:               make(map[string]string, len(conf.Webserver.APIKeys)),
+		MaxPriority:           int(conf.Webserver.MaxPriority),
+	}
+	for name := range conf.Webserver.APIKeys {
+		key, err := conf.Webserver.APIKeys.String(name, nil)
+		if err != nil {
+			return fmt.Errorf("webserver api key %v: %v", name, err)
+		}
+		srv.APIKeys[key] = name
 	}
 
 	// Setup Coordinator
@@ -112,6 +127,22 @@ func serverCmdRunE(cmd *cobra.Command, args []string) error {
 		log.Infof("configured queue %v", qType)
 	}
 
+	// Reload the sheets when the config changes, without restarting
+	// the server or losing the jobs in the queue
+	reloader := &configReloader{
+		location:    aURL,
+		overlays:    overlayURLs,
+		a:           a,
+		dpi:         dpi,
+		overrideDPI: cmd.Flag("dpi").Changed,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, reloadInterval)
+	if conf.Webserver.EnableReloadEP {
+		srv.Reload = reloader.Reload
+	}
+
 	for name, value := range conf.Webserver.Headers {
 		// cast to string
 		val := fmt.Sprintf("%v", value)
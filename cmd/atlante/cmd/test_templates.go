@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/golden"
+	"github.com/go-spatial/atlante/cmd/atlante/config"
+	"github.com/go-spatial/atlante/svg2pdf/cairo"
+	"github.com/go-spatial/tegola/dict"
+	"github.com/gogo/protobuf/proto"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// fixtureExt is the extension of the fixture jobs, the golden image
+	// of a fixture has the same name with a .png extension
+	fixtureExt = ".job"
+	// reportFilename is the name of the html report in the output directory
+	reportFilename = "report.html"
+)
+
+var (
+	// TestTemplates is the command to check the sheet templates against golden images
+	TestTemplates = &cobra.Command{
+		Use:   "test-templates",
+		Short: "Render fixture jobs and compare them against golden images",
+		Long: `Render each of the fixture jobs in the fixtures directory with the fake renderer,
+rasterize the svg, and compare it against the golden image of the fixture. A fixture
+is a job in protobuf text format (see --show-job) in a file with a .job extension;
+the golden image is a png with the same name. An html report of the differences
+is written to the output directory.`,
+		RunE: testTemplatesCmdRunE,
+	}
+
+	fixturesDir    string
+	reportDir      string
+	diffThreshold  float64
+	maxDiff        float64
+	rasterScale    float64
+	updateFixtures bool
+)
+
+func init() {
+	TestTemplates.Flags().StringVar(&fixturesDir, "fixtures", "fixtures", "directory of the fixture jobs and golden images")
+	TestTemplates.Flags().StringVar(&reportDir, "out", "template-report", "directory to write the rendered images and the report to")
+	TestTemplates.Flags().Float64Var(&diffThreshold, "threshold", golden.DefaultThreshold, "perceptual difference, from 0 to 1, above which pixels are different")
+	TestTemplates.Flags().Float64Var(&maxDiff, "max-diff", golden.DefaultMaxDiff, "fraction of the pixels that may be different")
+	TestTemplates.Flags().Float64Var(&rasterScale, "scale", 1, "pixels per point to rasterize the svg at")
+	TestTemplates.Flags().BoolVar(&updateFixtures, "update", false, "write the rendered images as the golden images")
+}
+
+// fixture is a job to render for a template test
+type fixture struct {
+	Name string
+	Job  *atlante.Job
+}
+
+// loadFixtures reads the fixture jobs in the directory, ordered by name
+func loadFixtures(dir string) ([]fixture, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+fixtureExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	fixtures := make([]fixture, 0, len(files))
+	for _, fn := range files {
+		data, err := ioutil.ReadFile(fn)
+		if err != nil {
+			return nil, err
+		}
+		var job atlante.Job
+		if err = proto.UnmarshalText(string(data), &job); err != nil {
+			return nil, fmt.Errorf("fixture %v: %v", fn, err)
+		}
+		if job.Cell == nil {
+			return nil, fmt.Errorf("fixture %v: no cell", fn)
+		}
+		fixtures = append(fixtures, fixture{
+			Name: strings.TrimSuffix(filepath.Base(fn), fixtureExt),
+			Job:  &job,
+		})
+	}
+	return fixtures, nil
+}
+
+// renderFixture renders the fixture into the output directory, and returns the filename of
+// the rasterized svg
+func renderFixture(ctx context.Context, a *atlante.Atlante, fx fixture, dir string) (string, error) {
+	if fx.Job.IsAtlas() {
+		return "", fmt.Errorf("atlas fixtures are not supported")
+	}
+	sheet, err := a.SheetFor(fx.Job.SheetName)
+	if err != nil {
+		return "", err
+	}
+	cell := fx.Job.Cell
+	if cell.GetSw() == nil && cell.GetMdgid() != nil {
+		// only the mdgid was given, look up the cell
+		if cell, err = sheet.CellForMDGID(cell.GetMdgid()); err != nil {
+			return "", err
+		}
+	}
+	if cell.MetaData == nil {
+		cell.MetaData = make(map[string]string, len(fx.Job.MetaData))
+	}
+	for k, v := range fx.Job.MetaData {
+		cell.MetaData[k] = v
+	}
+	filenames := &atlante.GeneratedFiles{
+		IMG: fx.Name + ".map.png",
+		SVG: fx.Name + ".svg",
+	}
+	gtc, err := atlante.GenerateSVG(ctx, sheet, cell, filenames, dir)
+	if err != nil {
+		return "", err
+	}
+	actual := filepath.Join(dir, fx.Name+".actual.png")
+	if err = cairo.GeneratePNG(filepath.Join(dir, filenames.SVG), actual, gtc.Width, gtc.Height, rasterScale); err != nil {
+		return "", fmt.Errorf("rasterizing svg: %v", err)
+	}
+	return actual, nil
+}
+
+// testFixture renders the fixture and compares, or updates, the golden image
+func testFixture(ctx context.Context, a *atlante.Atlante, fx fixture) golden.Case {
+	c := golden.Case{
+		Name:  fx.Name,
+		Sheet: fx.Job.SheetName,
+	}
+	actual, err := renderFixture(ctx, a, fx, reportDir)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	c.Actual = filepath.Base(actual)
+
+	goldenFn := filepath.Join(fixturesDir, fx.Name+".png")
+	actualImg, err := golden.ReadPNG(actual)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	if updateFixtures {
+		c.Err = golden.WritePNG(goldenFn, actualImg)
+		c.Updated = c.Err == nil
+		return c
+	}
+	goldenImg, err := golden.ReadPNG(goldenFn)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = fmt.Errorf("no golden image %v, use --update to create it", goldenFn)
+		}
+		c.Err = err
+		return c
+	}
+	// the images in the report are relative to the report
+	if c.Golden, err = relPath(reportDir, goldenFn); err != nil {
+		c.Err = err
+		return c
+	}
+	c.Result = golden.Compare(goldenImg, actualImg, diffThreshold)
+	c.Passed = c.Result.Passed(maxDiff)
+	diffFn := filepath.Join(reportDir, fx.Name+".diff.png")
+	if err = golden.WritePNG(diffFn, c.Result.Diff); err != nil {
+		c.Err = err
+		return c
+	}
+	c.Diff = filepath.Base(diffFn)
+	return c
+}
+
+// relPath returns the path of target relative to the base directory
+func relPath(base, target string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absBase, absTarget)
+	return filepath.ToSlash(rel), err
+}
+
+func testTemplatesCmdRunE(cmd *cobra.Command, args []string) error {
+	if diffThreshold < 0 || diffThreshold > 1 {
+		return ErrExitWith{
+			ShowUsage: true,
+			Msg:       fmt.Sprintf("[error] threshold must be from 0 to 1, got %v\n", diffThreshold),
+			ExitCode:  1,
+		}
+	}
+	if rasterScale <= 0 {
+		return ErrExitWith{
+			ShowUsage: true,
+			Msg:       fmt.Sprintf("[error] scale must be greater than zero, got %v\n", rasterScale),
+			ExitCode:  1,
+		}
+	}
+
+	a, err := config.Load(configFile, dpi, cmd.Flag("dpi").Changed, overlayFiles...)
+	if err != nil {
+		return ErrExitWith{
+			ShowUsage: true,
+			Msg:       fmt.Sprintf("[error] loading config: %v\n", err),
+			Err:       err,
+			ExitCode:  1,
+		}
+	}
+
+	fixtures, err := loadFixtures(fixturesDir)
+	if err != nil {
+		return ErrExitWith{
+			Msg:      fmt.Sprintf("[error] loading fixtures: %v\n", err),
+			Err:      err,
+			ExitCode: 1,
+		}
+	}
+	if len(fixtures) == 0 {
+		return ErrExitWith{
+			Msg:      fmt.Sprintf("[error] no fixtures (*%v) found in %v\n", fixtureExt, fixturesDir),
+			ExitCode: 1,
+		}
+	}
+	if err = os.MkdirAll(reportDir, os.ModePerm); err != nil {
+		return ErrExitWith{
+			Msg:      fmt.Sprintf("[error] creating output dir %v: %v\n", reportDir, err),
+			Err:      err,
+			ExitCode: 1,
+		}
+	}
+
+	// The fake renderer draws the same map image every time, so the templates
+	// are the only thing being tested
+	renderer, err := atlante.RendererFor(atlante.FakeRendererType, dict.Dict{})
+	if err != nil {
+		return err
+	}
+	for _, sheet := range a.Sheets() {
+		sheet.Renderer = renderer
+	}
+
+	ctx := context.Background()
+	rpt := golden.Report{
+		Threshold: diffThreshold,
+		MaxDiff:   maxDiff,
+		Generated: time.Now(),
+	}
+	for _, fx := range fixtures {
+		c := testFixture(ctx, a, fx)
+		fmt.Fprintf(cmd.OutOrStdout(), "%-8v %v (%v)", c.Status(), c.Name, c.Sheet)
+		switch {
+		case c.Err != nil:
+			fmt.Fprintf(cmd.OutOrStdout(), ": %v", c.Err)
+		case c.Diff != "":
+			fmt.Fprintf(cmd.OutOrStdout(), ": %.3f%% different", c.Result.Ratio()*100)
+		}
+		fmt.Fprintln(cmd.OutOrStdout())
+		rpt.Cases = append(rpt.Cases, c)
+	}
+
+	reportFn := filepath.Join(reportDir, reportFilename)
+	f, err := os.Create(reportFn)
+	if err != nil {
+		return err
+	}
+	if err = rpt.WriteHTML(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "report: %v\n", reportFn)
+
+	if failed := rpt.Failed(); failed > 0 {
+		return ErrExitWith{
+			Msg:      fmt.Sprintf("[error] %v of %v fixtures failed\n", failed, len(rpt.Cases)),
+			ExitCode: 4,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-spatial/atlante/atlante"
+	"github.com/go-spatial/atlante/atlante/config"
+	"github.com/go-spatial/atlante/atlante/queuer"
+	cmdconfig "github.com/go-spatial/atlante/cmd/atlante/config"
+	"github.com/go-spatial/atlante/mbgl"
+	"github.com/prometheus/common/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Worker is the command to run jobs pulled from the queue
+	Worker = &cobra.Command{
+		Use:   "worker",
+		Short: "Run jobs pulled from the queue",
+		Long: `Pull jobs from the queue configured in webserver.queue, and generate them. The
+queue must be one that workers pull jobs from, like postgresql; any number of workers,
+on any number of machines, can pull from the same queue.
+
+On SIGTERM or SIGINT the worker stops pulling jobs, and exits once the jobs it is running
+finish; a second signal cancels the running jobs, which are given to another worker once
+their lock expires.`,
+		RunE: workerCmdRunE,
+	}
+
+	workerName        string
+	workerConcurrency int
+	workerJobTimeout  time.Duration
+	workerHeartbeat   time.Duration
+	workerPoll        time.Duration
+)
+
+func init() {
+	Worker.Flags().StringVar(&workerName, "name", "", "name of the worker, unique across workers; defaults to hostname-pid")
+	Worker.Flags().IntVar(&workerConcurrency, "concurrency", 1, "number of jobs to run at once")
+	Worker.Flags().DurationVar(&workerJobTimeout, "job-timeout", 0, "how long a job may run before it is cancelled; 0 means no timeout")
+	Worker.Flags().DurationVar(&workerHeartbeat, "heartbeat", queuer.DefaultHeartbeatInterval, "how often to extend the lock on running jobs; must be shorter than the visibility timeout of the queue")
+	Worker.Flags().DurationVar(&workerPoll, "poll-interval", queuer.DefaultPollInterval, "how long to wait before asking for a job when the queue is empty")
+}
+
+func workerCmdRunE(cmd *cobra.Command, args []string) error {
+
+	aURL, overlayURLs, err := cmdconfig.ParseLocations(configFile, overlayFiles...)
+	if err != nil {
+		return err
+	}
+	conf, err := config.LoadAndValidate(aURL, overlayURLs...)
+	if err != nil {
+		return err
+	}
+
+	a, err := cmdconfig.LoadConfig(conf, dpi, cmd.Flag("dpi").Changed)
+	if err != nil {
+		return ErrExitWith{
+			Err:       err,
+			Msg:       "error loading config",
+			ExitCode:  1,
+			ShowUsage: true,
+		}
+	}
+
+	var qType string
+	if conf.Webserver.Queue != nil {
+		qType, _ = conf.Webserver.Queue.String(queuer.ConfigKeyType, nil)
+	}
+	if qType == "" || qType == "none" {
+		return ErrExitWith{
+			Msg:      "no queue configured",
+			Err:      fmt.Errorf("webserver.queue must be configured for workers"),
+			ExitCode: 1,
+		}
+	}
+	queue, err := queuer.For(qType, conf.Webserver.Queue, a)
+	if err != nil {
+		if _, ok := err.(queuer.ErrUnknownProvider); ok {
+			log.Infoln("known queue providers:")
+			for _, p := range queuer.Registered() {
+				log.Infoln("\t", p)
+			}
+		}
+		return err
+	}
+	puller, ok := queue.(queuer.Puller)
+	if !ok {
+		return ErrExitWith{
+			Msg:      "queue does not support workers",
+			Err:      fmt.Errorf("workers can not pull jobs from queue type %v", qType),
+			ExitCode: 1,
+		}
+	}
+
+	retry, err := queuer.RetryPolicyFromConfig(conf.Webserver.Queue)
+	if err != nil {
+		return ErrExitWith{
+			Msg:      "invalid retry policy for the queue",
+			Err:      err,
+			ExitCode: 1,
+		}
+	}
+
+	name := workerName
+	if name == "" {
+		hostname, _ := os.Hostname()
+		name = fmt.Sprintf("%v-%v", hostname, os.Getpid())
+	}
+
+	// ctx stops the pulling of jobs, jobsCtx cancels the running jobs
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	jobsCtx, kill := context.WithCancel(context.Background())
+	defer kill()
+	mbgl.StartSnapshotManager(jobsCtx)
+
+	go func() {
+		sigch := make(chan os.Signal, 2)
+		signal.Notify(sigch, syscall.SIGTERM, syscall.SIGINT)
+		defer signal.Stop(sigch)
+		var sig os.Signal
+		select {
+		case <-jobsCtx.Done():
+			return
+		case sig = <-sigch:
+		}
+		log.Infof("got signal %v, finishing running jobs", sig)
+		stop()
+		select {
+		case <-jobsCtx.Done():
+		case sig = <-sigch:
+			log.Infof("got signal %v, cancelling running jobs", sig)
+			kill()
+		}
+	}()
+
+	w := queuer.Worker{
+		Queue:             puller,
+		Name:              name,
+		Concurrency:       workerConcurrency,
+		JobTimeout:        workerJobTimeout,
+		HeartbeatInterval: workerHeartbeat,
+		PollInterval:      workerPoll,
+		// the retry is decided by RunJob, before the status of the failure is emitted
+		Run: func(ctx context.Context, delivery *queuer.Delivery) error {
+			_, err := a.RunJob(ctx, *delivery.Job, delivery.Attempt, retry, "")
+			return err
+		},
+		Retry: func(delivery *queuer.Delivery, err error) (time.Duration, bool) {
+			var rerr atlante.ErrRetryJob
+			if !errors.As(err, &rerr) {
+				return 0, false
+			}
+			return rerr.After, true
+		},
+	}
+	fmt.Fprintf(cmd.OutOrStderr(), "starting worker %v on queue %v\n", name, qType)
+	return w.Start(ctx, jobsCtx)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-spatial/atlante/atlante/style"
 
@@ -29,13 +30,19 @@ var (
 // Provider is a config structure for Grid Providers
 type Provider struct {
 	dict.Dicter
+	// providers are the providers being configured, if nil Providers is used
+	providers map[string]grids.Provider
 }
 
 // NameGridProvider implements grids.Config interface
 func (pcfg Provider) NameGridProvider(name string) (grids.Provider, error) {
 
+	providers := pcfg.providers
+	if providers == nil {
+		providers = Providers
+	}
 	name = strings.ToLower(name)
-	p, ok := Providers[name]
+	p, ok := providers[name]
 	if !ok {
 		return nil, grids.ErrProviderNotRegistered(name)
 	}
@@ -46,23 +53,136 @@ func (pcfg Provider) NameGridProvider(name string) (grids.Provider, error) {
 // Filestore is a config for file stores
 type Filestore struct {
 	dict.Dicter
+	// fileStores are the file stores being configured, if nil FileStores is used
+	fileStores map[string]filestore.Provider
 }
 
 // FileStoreFor implements the filestore.Config interface
 func (fscfg Filestore) FileStoreFor(name string) (filestore.Provider, error) {
+	fileStores := fscfg.fileStores
+	if fileStores == nil {
+		fileStores = FileStores
+	}
 	name = strings.ToLower(name)
-	p, ok := FileStores[name]
+	p, ok := fileStores[name]
 	if !ok {
 		return nil, filestore.ErrUnknownProvider(name)
 	}
 	return p, nil
 }
 
-// LoadConfig will create a new Atlante object based on the config provided
+// ConfigureSheet sets the page, print and pdf options of the sheet from the sheet config
+func ConfigureSheet(sht *atlante.Sheet, sheet config.Sheet) error {
+	if sheet.Height != 0 {
+		sht.Height = float64(sheet.Height)
+	}
+	if sheet.Width != 0 {
+		sht.Width = float64(sheet.Width)
+	}
+	sht.GeoPDF = bool(sheet.GeoPDF)
+	sht.GeoTIFF = bool(sheet.GeoTIFF)
+	orientation, err := atlante.ParseOrientation(string(sheet.Orientation))
+	if err != nil {
+		return err
+	}
+	// a named paper size overrides the height and width
+	if err = sht.SetPaperSize(string(sheet.PaperSize), orientation); err != nil {
+		return err
+	}
+	sht.PaperMargin = float64(sheet.PaperMargin)
+	if sheet.Bleed < 0 || sheet.LiveMargin < 0 {
+		return fmt.Errorf("bleed and live_margin can not be negative")
+	}
+	sht.Bleed = float64(sheet.Bleed)
+	sht.CropMarks = bool(sheet.CropMarks)
+	sht.LiveMargin = float64(sheet.LiveMargin)
+
+	sht.PDFMetadata = atlante.PDFMetadata{
+		Title:    string(sheet.PDFTitle),
+		Author:   string(sheet.PDFAuthor),
+		Subject:  string(sheet.PDFSubject),
+		Keywords: string(sheet.PDFKeywords),
+		Series:   string(sheet.Series),
+		Edition:  string(sheet.Edition),
+		PDFA:     bool(sheet.PDFA),
+	}
+	if err = sht.PDFMetadata.Validate(); err != nil {
+		return err
+	}
+
+	if sheet.PosterPaperSize != "" {
+		if _, ok := atlante.PaperSizeFor(string(sheet.PosterPaperSize)); !ok {
+			return fmt.Errorf("poster: %v", atlante.ErrUnknownPaperSize(string(sheet.PosterPaperSize)))
+		}
+	}
+	posterOrientation, err := atlante.ParseOrientation(string(sheet.PosterOrientation))
+	if err != nil {
+		return fmt.Errorf("poster: %v", err)
+	}
+	sht.Poster = atlante.PosterOptions{
+		PaperSize:   string(sheet.PosterPaperSize),
+		Orientation: posterOrientation,
+		Overlap:     atlante.DefaultPosterOverlap,
+		Margin:      atlante.DefaultPosterMargin,
+	}
+	if sheet.PosterOverlap != 0 {
+		sht.Poster.Overlap = float64(sheet.PosterOverlap)
+	}
+	if sheet.PosterMargin != 0 {
+		sht.Poster.Margin = float64(sheet.PosterMargin)
+	}
+
+	// the retry policy of the queue is used, unless the sheet has its own
+	sht.Retry = nil
+	if sheet.RetryMaxAttempts != 0 || sheet.RetryBackoff != 0 || sheet.RetryMaxBackoff != 0 ||
+		sheet.RetryMultiplier != 0 || len(sheet.RetryOn) != 0 {
+		sht.Retry, err = atlante.NewRetryPolicy(
+			int(sheet.RetryMaxAttempts),
+			time.Duration(sheet.RetryBackoff)*time.Second,
+			time.Duration(sheet.RetryMaxBackoff)*time.Second,
+			float64(sheet.RetryMultiplier),
+			[]string(sheet.RetryOn),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CloseProviders closes the grid providers that hold resources, like database connections
+func CloseProviders(providers map[string]grids.Provider) {
+	for name, prv := range providers {
+		if closer, ok := prv.(grids.Closer); ok {
+			log.Infof("closing grid provider: %v", name)
+			closer.Close()
+		}
+	}
+}
+
+// LoadConfig will create a new Atlante object based on the config provided.
+// The providers, file stores and styles are created anew each time, so
+// LoadConfig can be called again to reload the config; Providers and FileStores
+// are only replaced if the config loads. The providers created for a config
+// that fails to load are closed.
 func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante, error) {
+	providers := make(map[string]grids.Provider)
+	a, err := loadConfig(conf, dpi, overrideDPI, providers)
+	if err != nil {
+		CloseProviders(providers)
+		return nil, err
+	}
+	return a, nil
+}
+
+func loadConfig(conf config.Config, dpi int, overrideDPI bool, providers map[string]grids.Provider) (*atlante.Atlante, error) {
 
 	var ok bool
 	var a atlante.Atlante
+	var (
+		fileStores = make(map[string]filestore.Provider)
+		styleList  = new(style.List)
+	)
 
 	// Notifier
 	if conf.Notifier != nil {
@@ -73,6 +193,20 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 		a.Notifier = note
 	}
 
+	// Renderer
+	var (
+		renderer atlante.Renderer
+		err      error
+	)
+	if conf.Renderer != nil {
+		renderer, err = atlante.RendererFrom(conf.Renderer)
+	} else {
+		renderer, err = atlante.RendererFor(atlante.DefaultRendererType, conf.Renderer)
+	}
+	if err != nil {
+		return nil, errors.String(fmt.Sprintf("renderer: %v", err))
+	}
+
 	// Loop through and load up any global styles
 	styles := make([]style.Style, len(conf.Styles))
 	for i, s := range conf.Styles {
@@ -80,7 +214,7 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 		styles[i].Description = string(s.Desc)
 		styles[i].Location = string(s.Loc)
 	}
-	if err := style.Append(styles...); err != nil {
+	if err := styleList.Append(styles...); err != nil {
 		return nil, fmt.Errorf("error global styles %w", err)
 	}
 
@@ -96,15 +230,15 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 			return nil, fmt.Errorf("error provider( %v) missing name : %w", i, err)
 		}
 		name = strings.ToLower(name)
-		if _, ok := Providers[name]; ok {
+		if _, ok := providers[name]; ok {
 			return nil, fmt.Errorf("error provider with name (%v) is already registered", name)
 		}
-		prv, err := grids.For(typ, Provider{p})
+		prv, err := grids.For(typ, Provider{Dicter: p, providers: providers})
 		if err != nil {
 			return nil, fmt.Errorf("error registering provider (%v -- %v)(#%v): %w", typ, name, i, err)
 		}
 
-		Providers[name] = prv
+		providers[name] = prv
 		log.Infof("configured grid provider: %v (%v)", name, typ)
 	}
 
@@ -120,14 +254,14 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 			return nil, fmt.Errorf("error filestore (%v) missing name: %v", i, err)
 		}
 		name = strings.ToLower(name)
-		if _, ok = FileStores[name]; ok {
+		if _, ok = fileStores[name]; ok {
 			return nil, fmt.Errorf("error provider(%v) with name (%v) is already registered", i, name)
 		}
-		prv, err := filestore.For(typ, Filestore{fstore})
+		prv, err := filestore.For(typ, Filestore{Dicter: fstore, fileStores: fileStores})
 		if err != nil {
 			return nil, fmt.Errorf("error registering filestore %v:%v", i, err)
 		}
-		FileStores[name] = prv
+		fileStores[name] = prv
 	}
 
 	if len(conf.Sheets) == 0 {
@@ -138,7 +272,7 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 
 		providerName := strings.ToLower(string(sheet.ProviderGrid))
 
-		prv, ok := Providers[providerName]
+		prv, ok := providers[providerName]
 		if providerName != "" && !ok {
 			return nil, fmt.Errorf("error locating provider (%v) for sheet %v (#%v)", providerName, sheet.Name, i)
 		}
@@ -158,10 +292,10 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 			if filestoreName == "" {
 				continue
 			}
-			fsprv, ok = FileStores[filestoreName]
+			fsprv, ok = fileStores[filestoreName]
 			if !ok {
 				log.Warnln("Known file stores are:")
-				for k := range FileStores {
+				for k := range fileStores {
 					log.Warnln("\t", k)
 				}
 				return nil, filestore.ErrUnknownProvider(filestoreName)
@@ -182,7 +316,7 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 		if overrideDPI || odpi == 0 {
 			odpi = uint(dpi)
 		}
-		var stylelist = style.Provider((*style.List)(nil))
+		var stylelist = style.Provider(styleList)
 
 		{
 			// Here we need to check to see if
@@ -195,18 +329,18 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 
 			case len(stylesEntry) > 0:
 				// we have a styles entry. Always use that
-				stylelist = style.SubList(stylesEntry...)
+				stylelist = styleList.SubList(stylesEntry...)
 
 			case styleEntry != "":
 				// Old style config. We need to build a new style
 				// name and entry and issue a warning.
 				styleName := fmt.Sprintf("%s_style", name)
-				style.Append(style.Style{
+				styleList.Append(style.Style{
 					Name:        styleName,
 					Description: fmt.Sprintf("Style for sheet %v:\n%v", name, sheet.Description),
 					Location:    styleEntry,
 				})
-				stylelist = style.SubList(styleName)
+				stylelist = styleList.SubList(styleName)
 				log.Warnf("For sheet %v, style is deprecated, please use styles instead", name)
 
 			default:
@@ -228,30 +362,47 @@ func LoadConfig(conf config.Config, dpi int, overrideDPI bool) (*atlante.Atlante
 		if err != nil {
 			return nil, fmt.Errorf("error trying to create sheet %v: %v", i, err)
 		}
-		if sheet.Height != 0 {
-			sht.Height = float64(sheet.Height)
-		}
-		if sheet.Width != 0 {
-			sht.Width = float64(sheet.Width)
+		if err = ConfigureSheet(sht, sheet); err != nil {
+			return nil, fmt.Errorf("error for sheet %v: %v", i, err)
 		}
 
+		sht.Renderer = renderer
+
 		err = a.AddSheet(sht)
 		if err != nil {
 			return nil, fmt.Errorf("error trying to add sheet %v: %v", i, err)
 		}
 	}
+	Providers = providers
+	FileStores = fileStores
 	return &a, nil
 
 }
 
-// Load will attempt to load and validate a config at the given location
-func Load(location string, dpi int, overrideDPI bool) (*atlante.Atlante, error) {
-
+// ParseLocations parses the locations of the config file and the overlay files
+func ParseLocations(location string, overlays ...string) (*url.URL, []*url.URL, error) {
 	aURL, err := url.Parse(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	overlayURLs := make([]*url.URL, len(overlays))
+	for i := range overlays {
+		if overlayURLs[i], err = url.Parse(overlays[i]); err != nil {
+			return nil, nil, fmt.Errorf("overlay %v: %v", overlays[i], err)
+		}
+	}
+	return aURL, overlayURLs, nil
+}
+
+// Load will attempt to load and validate a config at the given location, with the
+// overlays merged on top of it
+func Load(location string, dpi int, overrideDPI bool, overlays ...string) (*atlante.Atlante, error) {
+
+	aURL, overlayURLs, err := ParseLocations(location, overlays...)
 	if err != nil {
 		return nil, err
 	}
-	conf, err := config.LoadAndValidate(aURL)
+	conf, err := config.LoadAndValidate(aURL, overlayURLs...)
 	if err != nil {
 		return nil, err
 	}
@@ -3,8 +3,12 @@ package main
 import (
 	// Import various grid providers
 	"github.com/go-spatial/atlante/atlante/grids"
+	_ "github.com/go-spatial/atlante/atlante/grids/cache"
+	_ "github.com/go-spatial/atlante/atlante/grids/geojson"
 	_ "github.com/go-spatial/atlante/atlante/grids/grid5k"
 	_ "github.com/go-spatial/atlante/atlante/grids/postgresql"
+	_ "github.com/go-spatial/atlante/atlante/grids/subdivide"
+	_ "github.com/go-spatial/atlante/atlante/grids/utm"
 )
 
 func init() {
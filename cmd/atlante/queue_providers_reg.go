@@ -4,6 +4,7 @@ import (
 	"github.com/go-spatial/atlante/atlante/queuer"
 	_ "github.com/go-spatial/atlante/atlante/queuer/awsbatch"
 	_ "github.com/go-spatial/atlante/atlante/queuer/local"
+	_ "github.com/go-spatial/atlante/atlante/queuer/postgresql"
 )
 
 func init() {
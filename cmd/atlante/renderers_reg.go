@@ -0,0 +1,6 @@
+package main
+
+import (
+	_ "github.com/go-spatial/atlante/atlante/pdfconverter/cairo"
+	_ "github.com/go-spatial/atlante/atlante/renderer/mbgl"
+)
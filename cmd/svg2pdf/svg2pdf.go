@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/go-spatial/atlante/svg2pdf"
+	"github.com/go-spatial/atlante/svg2pdf/cairo"
 )
 
 var height, width float64
@@ -23,7 +23,7 @@ func main() {
 	}
 
 	fmt.Println(flag.Args())
-	err := svg2pdf.GeneratePDF(flag.Arg(0), flag.Arg(1), height, width)
+	err := cairo.GeneratePDF(flag.Arg(0), flag.Arg(1), height, width)
 	if err != nil {
 		panic(err)
 	}
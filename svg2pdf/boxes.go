@@ -0,0 +1,79 @@
+package svg2pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/go-spatial/atlante/svg2pdf/internal/pdf"
+)
+
+// Box is a region of the page in pdf user space (points with the origin at the
+// bottom left of the page): llx, lly, urx, ury
+type Box [4]float64
+
+// IsZero returns weather the box is empty
+func (b Box) IsZero() bool { return b == Box{} }
+
+func (b Box) String() string {
+	return fmt.Sprintf("[%v %v %v %v]", pdf.Num(b[0]), pdf.Num(b[1]), pdf.Num(b[2]), pdf.Num(b[3]))
+}
+
+// PageBoxes are the boxes of a page used when printing commercially; zero boxes are not set
+type PageBoxes struct {
+	// Bleed is the region the page is clipped to in production, backgrounds
+	// should extend to it
+	Bleed Box
+	// Trim is the intended size of the finished page after trimming
+	Trim Box
+}
+
+// WritePageBoxes writes out the pdf with the boxes set on the pages; boxes[i] are the
+// boxes for the i-th page. The pdf is not rewritten, the pages are updated with an
+// incremental update.
+func WritePageBoxes(w io.Writer, data []byte, boxes []PageBoxes) error {
+	f, err := pdf.Parse(data)
+	if err != nil {
+		return err
+	}
+	pages, err := f.Pages()
+	if err != nil {
+		return err
+	}
+	if len(boxes) > len(pages) {
+		return fmt.Errorf("boxes given for %d pages, pdf only has %d pages", len(boxes), len(pages))
+	}
+	update := f.Update()
+	for i, pb := range boxes {
+		page := pages[i]
+		if !pb.Bleed.IsZero() {
+			page.Body = pdf.SetEntry(page.Body, "/BleedBox", pb.Bleed.String())
+		}
+		if !pb.Trim.IsZero() {
+			page.Body = pdf.SetEntry(page.Body, "/TrimBox", pb.Trim.String())
+		}
+		if !bytes.Equal(page.Body, pages[i].Body) {
+			update.Set(page)
+		}
+	}
+	return update.Write(w)
+}
+
+// SetPageBoxes sets the boxes on the pages of the pdf file, see WritePageBoxes
+func SetPageBoxes(filename string, boxes []PageBoxes) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var buff bytes.Buffer
+	if err = WritePageBoxes(&buff, data, boxes); err != nil {
+		return err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, buff.Bytes(), info.Mode())
+}
@@ -0,0 +1,91 @@
+package svg2pdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/go-spatial/atlante/svg2pdf/internal/pdf"
+)
+
+func TestWritePageBoxes(t *testing.T) {
+	var buff bytes.Buffer
+	buff.WriteString("%PDF-1.5\n")
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [ 3 0 R 4 0 R ] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 660 840 ] >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 660 840 ] /TrimBox [ 0 0 660 840 ] >>",
+	}
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buff.Len()
+		fmt.Fprintf(&buff, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buff.Len()
+	fmt.Fprintf(&buff, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buff, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buff, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	data := buff.Bytes()
+
+	type tcase struct {
+		name  string
+		boxes []PageBoxes
+		// expected boxes of each page, "" if not set
+		bleed, trim []string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := WritePageBoxes(&out, data, tc.boxes); err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			f, err := pdf.Parse(out.Bytes())
+			if err != nil {
+				t.Fatalf("parse, expected nil got %v", err)
+			}
+			pages, err := f.Pages()
+			if err != nil {
+				t.Fatalf("pages, expected nil got %v", err)
+			}
+			for i, page := range pages {
+				if got := string(pdf.Entry(page.Body, "/BleedBox")); got != tc.bleed[i] {
+					t.Errorf("page %v bleed box, expected %q got %q", i+1, tc.bleed[i], got)
+				}
+				if got := string(pdf.Entry(page.Body, "/TrimBox")); got != tc.trim[i] {
+					t.Errorf("page %v trim box, expected %q got %q", i+1, tc.trim[i], got)
+				}
+			}
+		}
+	}
+
+	bleedTrim := PageBoxes{
+		Bleed: Box{15, 15, 645, 825},
+		Trim:  Box{24.5, 24.5, 635.5, 815.5},
+	}
+	tests := []tcase{
+		{
+			name:  "none",
+			bleed: []string{"", ""},
+			trim:  []string{"", "[ 0 0 660 840 ]"},
+		},
+		{
+			name:  "first page",
+			boxes: []PageBoxes{bleedTrim},
+			bleed: []string{"[15 15 645 825]", ""},
+			trim:  []string{"[24.5 24.5 635.5 815.5]", "[ 0 0 660 840 ]"},
+		},
+		{
+			name:  "replace trim",
+			boxes: []PageBoxes{{}, {Trim: bleedTrim.Trim}},
+			bleed: []string{"", ""},
+			trim:  []string{"", "[24.5 24.5 635.5 815.5]"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
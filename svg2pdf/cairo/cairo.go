@@ -0,0 +1,90 @@
+// Package cairo converts svg files to pdf and png with cairo and librsvg (cgo).
+package cairo
+
+/*
+
+#cgo pkg-config: librsvg-2.0 cairo-pdf cairo-ft libxml-2.0
+#cgo pkg-config: gio-2.0
+#cgo pkg-config: libcroco-0.6 libpcre libpng
+#cgo pkg-config: pango pangocairo pangoft2 fontconfig freetype2
+
+#include <stdlib.h>
+#include "svg2pdf.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/go-spatial/atlante/svg2pdf"
+)
+
+// GeneratePDF renders the svg file as a pdf
+func GeneratePDF(fileIn, fileOut string, height, width float64) error {
+	e := C.svg2pdf_file(C.CString(fileIn), C.CString(fileOut),
+		C.double(height), C.double(width))
+
+	if e != 0 {
+		return fmt.Errorf("error %d", e)
+	}
+	return nil
+}
+
+// GenerateMultiPagePDF renders each of the svg pages into a single pdf
+func GenerateMultiPagePDF(pages []svg2pdf.Page, fileOut string) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("error no pages")
+	}
+	var (
+		count   = len(pages)
+		files   = C.malloc(C.size_t(count) * C.size_t(unsafe.Sizeof(uintptr(0))))
+		widths  = C.malloc(C.size_t(count) * C.size_t(unsafe.Sizeof(C.double(0))))
+		heights = C.malloc(C.size_t(count) * C.size_t(unsafe.Sizeof(C.double(0))))
+		cOut    = C.CString(fileOut)
+	)
+	defer C.free(files)
+	defer C.free(widths)
+	defer C.free(heights)
+	defer C.free(unsafe.Pointer(cOut))
+
+	cFiles := (*[1 << 28]*C.char)(files)[:count:count]
+	cWidths := (*[1 << 28]C.double)(widths)[:count:count]
+	cHeights := (*[1 << 28]C.double)(heights)[:count:count]
+	for i := range pages {
+		cFiles[i] = C.CString(pages[i].Filename)
+		defer C.free(unsafe.Pointer(cFiles[i]))
+		cWidths[i] = C.double(pages[i].Width)
+		cHeights[i] = C.double(pages[i].Height)
+	}
+
+	e := C.svg2pdf_files(
+		(**C.char)(files),
+		(*C.double)(widths),
+		(*C.double)(heights),
+		C.int(count),
+		cOut,
+	)
+	// errors for a page are 2 + the index of the page
+	switch idx := int(e) - 2; {
+	case e == 0:
+		return nil
+	case idx >= 0 && idx < count:
+		return fmt.Errorf("error %d rendering page %d (%v)", e, idx+1, pages[idx].Filename)
+	default:
+		return fmt.Errorf("error %d", e)
+	}
+}
+
+// GeneratePNG rasterizes the svg file onto a white background as a png. The width and height
+// are the size of the page in points, and scale is the number of pixels per point.
+func GeneratePNG(fileIn, fileOut string, width, height, scale float64) error {
+	cIn, cOut := C.CString(fileIn), C.CString(fileOut)
+	defer C.free(unsafe.Pointer(cIn))
+	defer C.free(unsafe.Pointer(cOut))
+
+	e := C.svg2png_file(cIn, cOut, C.double(width), C.double(height), C.double(scale))
+	if e != 0 {
+		return fmt.Errorf("error %d", e)
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-package atlante
+package cairo
 
 import (
 	"bufio"
@@ -10,8 +10,6 @@ import (
 	"regexp"
 	"strconv"
 	"testing"
-
-	"github.com/go-spatial/atlante/svg2pdf"
 )
 
 var mediabox = regexp.MustCompile(`/MediaBox\s+\[(.+)\]`)
@@ -83,7 +81,7 @@ func TestWidthHeightSVG2PDF(t *testing.T) {
 			// let's first create a tmp pdf file name to use.
 			pdfFilename := filepath.Join(dir, tname+".pdf")
 			t.Logf("test pdf: %v", pdfFilename)
-			err := svg2pdf.GeneratePDF(svgFilename, pdfFilename, tc.Width, tc.Height)
+			err := GeneratePDF(svgFilename, pdfFilename, tc.Width, tc.Height)
 			if err != nil {
 				t.Errorf("error generating pdf, expected nil got %v", err)
 				return
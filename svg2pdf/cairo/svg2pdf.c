@@ -0,0 +1,279 @@
+#include <stdlib.h>
+#include <glib/gstdio.h>
+#include <librsvg/rsvg.h>
+#include <cairo-pdf.h>
+#include <cairo-ps.h>
+
+int svg2pdf_file(const char * inFile, const char * outFile,
+		double width, double height) {
+	cairo_t * cr;
+	cairo_surface_t * surface;
+	RsvgHandle * handle;
+	GError * error = NULL;
+	GFile * file;
+	GInputStream * stream;
+	RsvgHandleFlags flags;
+
+
+#if DEBUG
+	printf("%s\n", "start");
+
+	printf("%p\n", cairo_pdf_surface_create);
+	printf("%d\n", CAIRO_HAS_PDF_SURFACE);
+
+	printf("creating handle to %s\n", inFile);
+#endif
+
+	file = g_file_new_for_path(inFile);
+	stream = (GInputStream *) g_file_read(file, NULL, &error);
+	if (stream == NULL){
+
+
+#if DEBUG
+		printf("%s\n", "could not create read stream");
+#endif
+
+		return 1;
+	}
+
+	flags |= RSVG_HANDLE_FLAG_UNLIMITED;
+
+	handle = rsvg_handle_new_from_stream_sync(stream, file, flags, NULL,
+		&error);
+	if (handle == NULL) {
+
+		printf("error (%d) %s\n", error->code, error->message);
+
+		return 1;
+	}
+
+#if DEBUG
+	printf("%p\n", handle);
+#endif
+
+	// set handle options
+
+	surface = cairo_pdf_surface_create(outFile, width, height);
+	cairo_status_t status = cairo_surface_status(surface);
+	if (status != CAIRO_STATUS_SUCCESS) {
+#if DEBUG
+		printf("%s\n", cairo_status_to_string(status));
+#endif
+		return 1;
+	}
+#if DEBUG
+	printf("surface created %p\n", ((void *) surface));
+#endif
+
+	cr = cairo_create(surface);
+	status = cairo_status(cr);
+	if (status != CAIRO_STATUS_SUCCESS) {
+#if DEBUG
+		printf("%s\n", cairo_status_to_string(status));
+#endif
+		return 1;
+	}
+#if DEBUG
+	printf("context created %p\n", ((void *) cr));
+#endif
+
+	gboolean ok;
+	ok = rsvg_handle_render_cairo(handle, cr);
+#if DEBUG
+	printf("render returned\n");
+#endif
+	if (!ok) {
+#if DEBUG
+		printf("error\n");
+#endif
+		return 1;
+	}
+
+	cairo_surface_destroy(surface);
+	cairo_destroy(cr);
+	rsvg_handle_close(handle, &error);
+#if DEBUG
+	printf("Done!\n");
+#endif
+	return 0;
+}
+
+// release_svg frees the handle, stream and file of a svg, and the error, and
+// clears them so it can be called again
+static void release_svg(RsvgHandle ** handle, GInputStream ** stream,
+		GFile ** file, GError ** error) {
+	if (*error != NULL) {
+#if DEBUG
+		printf("error (%d) %s\n", (*error)->code, (*error)->message);
+#endif
+		g_clear_error(error);
+	}
+	if (*handle != NULL) {
+		rsvg_handle_close(*handle, NULL);
+		g_object_unref(*handle);
+		*handle = NULL;
+	}
+	if (*stream != NULL) {
+		g_object_unref(*stream);
+		*stream = NULL;
+	}
+	if (*file != NULL) {
+		g_object_unref(*file);
+		*file = NULL;
+	}
+}
+
+// svg2pdf_files renders each of the svg files as a page of a single pdf,
+// each page is sized to the matching width and height.
+int svg2pdf_files(char ** inFiles, double * widths, double * heights,
+		int count, const char * outFile) {
+	cairo_t * cr = NULL;
+	cairo_surface_t * surface = NULL;
+	RsvgHandle * handle = NULL;
+	GError * error = NULL;
+	GFile * file = NULL;
+	GInputStream * stream = NULL;
+	RsvgHandleFlags flags = RSVG_HANDLE_FLAG_UNLIMITED;
+	cairo_status_t status;
+	int ret = 0;
+	int i;
+
+	if (count <= 0) {
+		return 1;
+	}
+
+	surface = cairo_pdf_surface_create(outFile, widths[0], heights[0]);
+	status = cairo_surface_status(surface);
+	if (status != CAIRO_STATUS_SUCCESS) {
+#if DEBUG
+		printf("%s\n", cairo_status_to_string(status));
+#endif
+		ret = 1;
+		goto cleanup;
+	}
+
+	cr = cairo_create(surface);
+	status = cairo_status(cr);
+	if (status != CAIRO_STATUS_SUCCESS) {
+#if DEBUG
+		printf("%s\n", cairo_status_to_string(status));
+#endif
+		ret = 1;
+		goto cleanup;
+	}
+
+	for (i = 0; i < count; i++) {
+		file = g_file_new_for_path(inFiles[i]);
+		stream = (GInputStream *) g_file_read(file, NULL, &error);
+		if (stream == NULL) {
+			ret = 2 + i;
+			goto cleanup;
+		}
+
+		handle = rsvg_handle_new_from_stream_sync(stream, file, flags, NULL,
+			&error);
+		if (handle == NULL) {
+			ret = 2 + i;
+			goto cleanup;
+		}
+
+		// the size has to be set before anything is drawn on the page
+		cairo_pdf_surface_set_size(surface, widths[i], heights[i]);
+		if (!rsvg_handle_render_cairo(handle, cr)) {
+			ret = 2 + i;
+			goto cleanup;
+		}
+		cairo_show_page(cr);
+		release_svg(&handle, &stream, &file, &error);
+	}
+
+cleanup:
+	release_svg(&handle, &stream, &file, &error);
+	if (cr != NULL) {
+		cairo_destroy(cr);
+	}
+	if (surface != NULL) {
+		cairo_surface_destroy(surface);
+	}
+	return ret;
+}
+
+// svg2png_file rasterizes the svg file onto a white background and writes it
+// as a png; width and height are the size of the page in points, and scale
+// is the number of pixels per point.
+int svg2png_file(const char * inFile, const char * outFile,
+		double width, double height, double scale) {
+	cairo_t * cr = NULL;
+	cairo_surface_t * surface = NULL;
+	RsvgHandle * handle = NULL;
+	GError * error = NULL;
+	GFile * file = NULL;
+	GInputStream * stream = NULL;
+	RsvgHandleFlags flags = RSVG_HANDLE_FLAG_UNLIMITED;
+	cairo_status_t status;
+	int ret = 0;
+	int w, h;
+
+	w = (int)(width * scale + 0.5);
+	h = (int)(height * scale + 0.5);
+	if (w <= 0 || h <= 0) {
+		return 1;
+	}
+
+	file = g_file_new_for_path(inFile);
+	stream = (GInputStream *) g_file_read(file, NULL, &error);
+	if (stream == NULL) {
+		ret = 1;
+		goto cleanup;
+	}
+
+	handle = rsvg_handle_new_from_stream_sync(stream, file, flags, NULL,
+		&error);
+	if (handle == NULL) {
+		ret = 1;
+		goto cleanup;
+	}
+
+	surface = cairo_image_surface_create(CAIRO_FORMAT_ARGB32, w, h);
+	status = cairo_surface_status(surface);
+	if (status != CAIRO_STATUS_SUCCESS) {
+#if DEBUG
+		printf("%s\n", cairo_status_to_string(status));
+#endif
+		ret = 1;
+		goto cleanup;
+	}
+
+	cr = cairo_create(surface);
+	status = cairo_status(cr);
+	if (status != CAIRO_STATUS_SUCCESS) {
+#if DEBUG
+		printf("%s\n", cairo_status_to_string(status));
+#endif
+		ret = 1;
+		goto cleanup;
+	}
+
+	// the pdfs are printed on white paper
+	cairo_set_source_rgb(cr, 1, 1, 1);
+	cairo_paint(cr);
+	cairo_scale(cr, scale, scale);
+
+	if (!rsvg_handle_render_cairo(handle, cr)) {
+		ret = 2;
+		goto cleanup;
+	}
+	if (cairo_surface_write_to_png(surface, outFile) != CAIRO_STATUS_SUCCESS) {
+		ret = 3;
+	}
+
+cleanup:
+	release_svg(&handle, &stream, &file, &error);
+	if (cr != NULL) {
+		cairo_destroy(cr);
+	}
+	if (surface != NULL) {
+		cairo_surface_destroy(surface);
+	}
+	return ret;
+}
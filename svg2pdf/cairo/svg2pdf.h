@@ -4,5 +4,7 @@
 #define DEBUG 0
 
 int svg2pdf_file(const char *, const char *, double, double);
+int svg2pdf_files(char **, double *, double *, int, const char *);
+int svg2png_file(const char *, const char *, double, double, double);
 
 #endif // SVG2PDF_H
@@ -0,0 +1,10 @@
+package svg2pdf
+
+import "strings"
+
+// ErrNotPDFA is returned when a pdf does not meet the requirements of PDF/A; it lists the problems found
+type ErrNotPDFA []string
+
+func (err ErrNotPDFA) Error() string {
+	return "not PDF/A: " + strings.Join(err, "; ")
+}
@@ -0,0 +1,31 @@
+package geopdf
+
+import (
+	"fmt"
+
+	"github.com/go-spatial/atlante/svg2pdf/internal/pdf"
+)
+
+const (
+	// ErrMalformed is returned when the pdf could not be parsed
+	ErrMalformed = pdf.ErrMalformed
+	// ErrUnsupportedFilter is returned when an object stream is compressed with something other than flate
+	ErrUnsupportedFilter = pdf.ErrUnsupportedFilter
+)
+
+// ErrHasViewports is returned when the page already has viewports
+type ErrHasViewports int
+
+func (err ErrHasViewports) Error() string {
+	return fmt.Sprintf("page %d already has viewports", int(err))
+}
+
+// ErrTooManyPages is returned when there are more pages of viewports then pages in the pdf
+type ErrTooManyPages struct {
+	Pages     int
+	Viewports int
+}
+
+func (err ErrTooManyPages) Error() string {
+	return fmt.Sprintf("viewports given for %d pages, pdf only has %d pages", err.Viewports, err.Pages)
+}
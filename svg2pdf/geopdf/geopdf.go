@@ -0,0 +1,138 @@
+// Package geopdf adds ISO 32000 geospatial measure dictionaries to the pages
+// of an existing pdf, turning it into a georeferenced GeoPDF.
+//
+// The pdf is not rewritten; the modified page objects are appended to the
+// file as an incremental update.
+package geopdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-spatial/atlante/svg2pdf/internal/pdf"
+)
+
+// Viewport is a georeferenced region of a page
+type Viewport struct {
+	// Name of the viewport
+	Name string
+	// BBox is the region of the page in pdf user space (points with the origin
+	// at the bottom left of the page): llx, lly, urx, ury
+	BBox [4]float64
+	// SW and NE corners of the region as lng, lat
+	SW, NE [2]float64
+	// EPSG code of the projection the region was drawn in. The region is assumed
+	// to be linear in this projection. Defaults to 4326.
+	EPSG uint
+}
+
+// wkt for the well known projections, readers will use the EPSG code for the rest
+var wkt = map[uint]string{
+	4326: `GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]]`,
+	3857: `PROJCS["WGS 84 / Pseudo-Mercator",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]],PROJECTION["Mercator_1SP"],PARAMETER["central_meridian",0],PARAMETER["scale_factor",1],PARAMETER["false_easting",0],PARAMETER["false_northing",0],UNIT["metre",1],EXTENSION["PROJ4","+proj=merc +a=6378137 +b=6378137 +lat_ts=0 +lon_0=0 +x_0=0 +y_0=0 +k=1 +units=m +nadgrids=@null +wktext +no_defs"]]`,
+}
+
+// Dict returns the viewport dictionary for the viewport
+func (vp Viewport) Dict() string {
+	epsg := vp.EPSG
+	if epsg == 0 {
+		epsg = 4326
+	}
+	csType := "/PROJCS"
+	if epsg == 4326 {
+		csType = "/GEOGCS"
+	}
+	var gcs strings.Builder
+	fmt.Fprintf(&gcs, "<< /Type %v /EPSG %d", csType, epsg)
+	if w, ok := wkt[epsg]; ok {
+		fmt.Fprintf(&gcs, " /WKT %v", pdf.String(w))
+	}
+	gcs.WriteString(" >>")
+
+	sw, ne := vp.SW, vp.NE
+	// The points of the unit square of the bbox, and the lat, lng for each of them
+	// lower left, upper left, upper right, lower right
+	gpts := []float64{
+		sw[1], sw[0],
+		ne[1], sw[0],
+		ne[1], ne[0],
+		sw[1], ne[0],
+	}
+	gptsStr := make([]string, len(gpts))
+	for i := range gpts {
+		gptsStr[i] = pdf.Num(gpts[i])
+	}
+
+	name := vp.Name
+	if name == "" {
+		name = "map"
+	}
+	return fmt.Sprintf(
+		"<< /Type /Viewport /Name %v /BBox [%v %v %v %v] /Measure << /Type /Measure /Subtype /GEO /Bounds [0 0 0 1 1 1 1 0] /GPTS [%v] /LPTS [0 0 0 1 1 1 1 0] /GCS %v /PDU [/M /SQM /DEG] >> >>",
+		pdf.String(name),
+		pdf.Num(vp.BBox[0]), pdf.Num(vp.BBox[1]), pdf.Num(vp.BBox[2]), pdf.Num(vp.BBox[3]),
+		strings.Join(gptsStr, " "),
+		gcs.String(),
+	)
+}
+
+var reVP = regexp.MustCompile(`/VP\b`)
+
+// Write writes out the pdf with the viewports added to the pages; viewports[i] are
+// the viewports for the i-th page. Pages without viewports are left untouched.
+func Write(w io.Writer, data []byte, viewports [][]Viewport) error {
+	f, err := pdf.Parse(data)
+	if err != nil {
+		return err
+	}
+	pages, err := f.Pages()
+	if err != nil {
+		return err
+	}
+	if len(viewports) > len(pages) {
+		return ErrTooManyPages{Pages: len(pages), Viewports: len(viewports)}
+	}
+
+	update := f.Update()
+	for i, vps := range viewports {
+		if len(vps) == 0 {
+			continue
+		}
+		page := pages[i]
+		if reVP.Match(page.Body) {
+			return ErrHasViewports(i + 1)
+		}
+		if !bytes.HasPrefix(page.Body, []byte("<<")) {
+			return ErrMalformed
+		}
+		dicts := make([]string, len(vps))
+		for j := range vps {
+			dicts[j] = vps[j].Dict()
+		}
+		page.Body = pdf.SetEntry(page.Body, "/VP", "["+strings.Join(dicts, " ")+"]")
+		update.Set(page)
+	}
+	return update.Write(w)
+}
+
+// Georeference adds the viewports to the pages of the pdf file, see Write
+func Georeference(filename string, viewports [][]Viewport) error {
+	pdf, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var buff bytes.Buffer
+	if err = Write(&buff, pdf, viewports); err != nil {
+		return err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, buff.Bytes(), info.Mode())
+}
@@ -0,0 +1,192 @@
+package geopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/atlante/svg2pdf/internal/pdf"
+)
+
+// buildPDF returns a pdf with the given objects, numbered from 1, and a classic xref table
+func buildPDF(objs ...string) []byte {
+	var buff bytes.Buffer
+	buff.WriteString("%PDF-1.5\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buff.Len()
+		fmt.Fprintf(&buff, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buff.Len()
+	fmt.Fprintf(&buff, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buff, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buff, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return buff.Bytes()
+}
+
+// objStm returns an object stream containing the given objects
+func objStm(objs map[int]string, order ...int) string {
+	var header, body strings.Builder
+	for _, num := range order {
+		fmt.Fprintf(&header, "%d %d ", num, body.Len())
+		body.WriteString(objs[num])
+		body.WriteString("\n")
+	}
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write([]byte(header.String() + body.String()))
+	zw.Close()
+	return fmt.Sprintf("<< /Type /ObjStm /N %d /First %d /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+		len(order), header.Len(), z.Len(), z.String(),
+	)
+}
+
+func TestWrite(t *testing.T) {
+	type tcase struct {
+		name      string
+		pdf       []byte
+		viewports [][]Viewport
+		// page object numbers expected to be updated
+		updated []int
+		err     error
+	}
+
+	vp := Viewport{
+		BBox: [4]float64{36, 36, 576, 756},
+		SW:   [2]float64{-117.25, 32.5},
+		NE:   [2]float64{-117, 32.75},
+		EPSG: 3857,
+	}
+
+	simple := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [ 3 0 R 4 0 R ] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 612 792 ] /Contents 5 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 612 792 ] /Contents 5 0 R >>",
+		"<< /Length 12 >>\nstream\nendobj 0 0 m\nendstream",
+	)
+
+	compressed := buildPDF(
+		"<< /Type /Catalog /Pages 3 0 R >>",
+		objStm(map[int]string{
+			3: "<< /Type /Pages /Kids [ 4 0 R ] /Count 1 >>",
+			4: "<< /Type /Page /Parent 3 0 R /MediaBox [ 0 0 612 792 ] >>",
+		}, 3, 4),
+	)
+
+	withVP := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [ 3 0 R ] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /VP [ ] >>",
+	)
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			var buff bytes.Buffer
+			err := Write(&buff, tc.pdf, tc.viewports)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			out := buff.Bytes()
+			if !bytes.HasPrefix(out, tc.pdf) {
+				t.Fatalf("prefix, expected original pdf to be unchanged")
+			}
+			if len(tc.updated) == 0 {
+				if len(out) != len(tc.pdf) {
+					t.Errorf("len, expected %v got %v", len(tc.pdf), len(out))
+				}
+				return
+			}
+
+			f, err := pdf.Parse(out)
+			if err != nil {
+				t.Fatalf("parse error, expected nil got %v", err)
+			}
+			for _, num := range tc.updated {
+				body := string(f.Objects[num].Body)
+				if !strings.Contains(body, "/VP [<< /Type /Viewport") {
+					t.Errorf("page %v, expected viewport got %v", num, body)
+				}
+				if !strings.Contains(body, "/GPTS [32.5 -117.25 32.75 -117.25 32.75 -117 32.5 -117]") {
+					t.Errorf("page %v, expected gpts got %v", num, body)
+				}
+				if !strings.Contains(body, "/Type /Page ") {
+					t.Errorf("page %v, expected original entries got %v", num, body)
+				}
+				// check the xref entry points to the object
+				entry := fmt.Sprintf("%d 1\n", num)
+				idx := bytes.LastIndex(out, []byte(entry))
+				if idx == -1 {
+					t.Fatalf("xref, expected entry for %v", num)
+				}
+				var off int
+				fmt.Sscanf(string(out[idx+len(entry):]), "%d", &off)
+				if !bytes.HasPrefix(out[off:], []byte(fmt.Sprintf("%d 0 obj", num))) {
+					t.Errorf("xref offset for %v, expected object got %q", num, out[off:off+10])
+				}
+			}
+			if !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+				t.Errorf("eof, expected %%%%EOF")
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:      "first page",
+			pdf:       simple,
+			viewports: [][]Viewport{{vp}},
+			updated:   []int{3},
+		},
+		{
+			name:      "second page",
+			pdf:       simple,
+			viewports: [][]Viewport{nil, {vp}},
+			updated:   []int{4},
+		},
+		{
+			name:      "all pages",
+			pdf:       simple,
+			viewports: [][]Viewport{{vp}, {vp}},
+			updated:   []int{3, 4},
+		},
+		{
+			name: "no viewports",
+			pdf:  simple,
+		},
+		{
+			name:      "object stream",
+			pdf:       compressed,
+			viewports: [][]Viewport{{vp}},
+			updated:   []int{4},
+		},
+		{
+			name:      "too many pages",
+			pdf:       simple,
+			viewports: [][]Viewport{{vp}, {vp}, {vp}},
+			err:       ErrTooManyPages{Pages: 2, Viewports: 3},
+		},
+		{
+			name:      "existing viewports",
+			pdf:       withVP,
+			viewports: [][]Viewport{{vp}},
+			err:       ErrHasViewports(1),
+		},
+		{
+			name:      "not a pdf",
+			pdf:       []byte("hello world"),
+			viewports: [][]Viewport{{vp}},
+			err:       ErrMalformed,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
@@ -0,0 +1,117 @@
+package svg2pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// sRGBIdentifier is the output condition identifier of the sRGB output intent
+const sRGBIdentifier = "sRGB IEC61966-2.1"
+
+// iccTag is a tag of an icc profile
+type iccTag struct {
+	sig  string
+	data []byte
+}
+
+// s15Fixed16 encodes the number as an icc signed 15.16 fixed point number
+func s15Fixed16(f float64) uint32 { return uint32(int32(math.Round(f * 65536))) }
+
+// iccXYZ returns an icc XYZType
+func iccXYZ(x, y, z float64) []byte {
+	var buff bytes.Buffer
+	buff.WriteString("XYZ \x00\x00\x00\x00")
+	for _, v := range []float64{x, y, z} {
+		binary.Write(&buff, binary.BigEndian, s15Fixed16(v))
+	}
+	return buff.Bytes()
+}
+
+// iccText returns an icc textType
+func iccText(s string) []byte {
+	return append([]byte("text\x00\x00\x00\x00"+s), 0)
+}
+
+// iccDesc returns an icc textDescriptionType with only the ascii description
+func iccDesc(s string) []byte {
+	var buff bytes.Buffer
+	buff.WriteString("desc\x00\x00\x00\x00")
+	binary.Write(&buff, binary.BigEndian, uint32(len(s)+1))
+	buff.WriteString(s)
+	buff.WriteByte(0)
+	// empty unicode (language code and count) and scriptcode (code, count and 67 bytes) descriptions
+	buff.Write(make([]byte, 4+4+2+1+67))
+	return buff.Bytes()
+}
+
+// iccGamma returns an icc curveType with a single gamma value
+func iccGamma(gamma float64) []byte {
+	var buff bytes.Buffer
+	buff.WriteString("curv\x00\x00\x00\x00")
+	binary.Write(&buff, binary.BigEndian, uint32(1))
+	binary.Write(&buff, binary.BigEndian, uint16(math.Round(gamma*256)))
+	return buff.Bytes()
+}
+
+// sRGBProfile returns a small version 2 icc profile of the sRGB colour space, used as the
+// output intent of PDF/A documents. The tone curves are approximated with a gamma of 2.2.
+func sRGBProfile() []byte {
+	trc := iccGamma(2.2)
+	tags := []iccTag{
+		{"desc", iccDesc(sRGBIdentifier)},
+		{"cprt", iccText("No copyright, use freely")},
+		{"wtpt", iccXYZ(0.9505, 1, 1.0891)},
+		// primaries adapted to the D50 profile connection space
+		{"rXYZ", iccXYZ(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", iccXYZ(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", iccXYZ(0.1431, 0.0606, 0.7141)},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+
+	// tag data is 4 byte aligned, and follows the header and the tag table; tags with
+	// the same data share it
+	var (
+		data    bytes.Buffer
+		table   bytes.Buffer
+		offset  = 128 + 4 + 12*len(tags)
+		offsets = make(map[string]int, len(tags))
+	)
+	binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	for _, tag := range tags {
+		off, ok := offsets[string(tag.data)]
+		if !ok {
+			off = offset + data.Len()
+			offsets[string(tag.data)] = off
+			data.Write(tag.data)
+			for data.Len()%4 != 0 {
+				data.WriteByte(0)
+			}
+		}
+		table.WriteString(tag.sig)
+		binary.Write(&table, binary.BigEndian, uint32(off))
+		binary.Write(&table, binary.BigEndian, uint32(len(tag.data)))
+	}
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(offset+data.Len()))
+	// version 2.1
+	binary.BigEndian.PutUint32(header[8:], 0x02100000)
+	copy(header[12:], "mntr")
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+	// creation date: 2000-01-01
+	binary.BigEndian.PutUint16(header[24:], 2000)
+	binary.BigEndian.PutUint16(header[26:], 1)
+	binary.BigEndian.PutUint16(header[28:], 1)
+	copy(header[36:], "acsp")
+	// D50 illuminant of the profile connection space
+	binary.BigEndian.PutUint32(header[68:], s15Fixed16(0.9642))
+	binary.BigEndian.PutUint32(header[72:], s15Fixed16(1))
+	binary.BigEndian.PutUint32(header[76:], s15Fixed16(0.8249))
+
+	profile := append(header, table.Bytes()...)
+	return append(profile, data.Bytes()...)
+}
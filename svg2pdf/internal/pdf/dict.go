@@ -0,0 +1,192 @@
+package pdf
+
+import (
+	"bytes"
+)
+
+// entry is a key, value pair of a dictionary; the offsets are into the dictionary
+type entry struct {
+	key                  string
+	keyStart             int
+	valueStart, valueEnd int
+}
+
+func isWhite(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0:
+		return true
+	}
+	return false
+}
+
+func isDelim(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return isWhite(c)
+}
+
+func skipWhite(data []byte, i int) int {
+	for i < len(data) && isWhite(data[i]) {
+		i++
+	}
+	return i
+}
+
+// skipToken returns the index after the token starting at i
+func skipToken(data []byte, i int) int {
+	for i < len(data) && !isDelim(data[i]) {
+		i++
+	}
+	return i
+}
+
+// skipValue returns the index after the value starting at i
+func skipValue(data []byte, i int) (int, error) {
+	if i >= len(data) {
+		return i, ErrMalformed
+	}
+	switch c := data[i]; {
+	case c == '/':
+		return skipToken(data, i+1), nil
+	case c == '(':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return i + 1, nil
+				}
+			}
+		}
+		return i, ErrMalformed
+	case c == '[' || (c == '<' && i+1 < len(data) && data[i+1] == '<'):
+		open, close := "[", "]"
+		if c == '<' {
+			open, close = "<<", ">>"
+		}
+		i += len(open)
+		for {
+			i = skipWhite(data, i)
+			if i >= len(data) {
+				return i, ErrMalformed
+			}
+			if bytes.HasPrefix(data[i:], []byte(close)) {
+				return i + len(close), nil
+			}
+			var err error
+			if i, err = skipValue(data, i); err != nil {
+				return i, err
+			}
+		}
+	case c == '<':
+		end := bytes.IndexByte(data[i:], '>')
+		if end == -1 {
+			return i, ErrMalformed
+		}
+		return i + end + 1, nil
+	default:
+		end := skipToken(data, i)
+		if end == i {
+			return i, ErrMalformed
+		}
+		// an indirect reference is three tokens: num gen R
+		if j := skipWhite(data, end); j < len(data) && data[j] >= '0' && data[j] <= '9' {
+			k := skipToken(data, j)
+			if l := skipWhite(data, k); l < len(data) && data[l] == 'R' && skipToken(data, l) == l+1 {
+				return l + 1, nil
+			}
+		}
+		return end, nil
+	}
+}
+
+// entries returns the top level entries of the dictionary
+func entries(dict []byte) ([]entry, error) {
+	i := skipWhite(dict, 0)
+	if !bytes.HasPrefix(dict[i:], []byte("<<")) {
+		return nil, ErrMalformed
+	}
+	i += 2
+	var list []entry
+	for {
+		i = skipWhite(dict, i)
+		if i >= len(dict) {
+			return nil, ErrMalformed
+		}
+		if bytes.HasPrefix(dict[i:], []byte(">>")) {
+			return list, nil
+		}
+		if dict[i] != '/' {
+			return nil, ErrMalformed
+		}
+		e := entry{keyStart: i}
+		i = skipToken(dict, i+1)
+		e.key = string(dict[e.keyStart:i])
+		e.valueStart = skipWhite(dict, i)
+		var err error
+		if i, err = skipValue(dict, e.valueStart); err != nil {
+			return nil, err
+		}
+		e.valueEnd = i
+		list = append(list, e)
+	}
+}
+
+// Entry returns the value of the key, e.g. "/Type", in the dictionary; nil if it is not there
+func Entry(dict []byte, key string) []byte {
+	list, err := entries(dict)
+	if err != nil {
+		return nil
+	}
+	for _, e := range list {
+		if e.key == key {
+			return dict[e.valueStart:e.valueEnd]
+		}
+	}
+	return nil
+}
+
+// SetEntry returns a copy of the dictionary with the key set to the value. If the key is
+// already in the dictionary its value is replaced, otherwise the key is added at the start.
+func SetEntry(dict []byte, key string, value string) []byte {
+	var out bytes.Buffer
+	list, err := entries(dict)
+	if err == nil {
+		for _, e := range list {
+			if e.key != key {
+				continue
+			}
+			out.Write(dict[:e.valueStart])
+			out.WriteString(value)
+			out.Write(dict[e.valueEnd:])
+			return out.Bytes()
+		}
+	}
+	i := bytes.Index(dict, []byte("<<"))
+	if i == -1 {
+		return dict
+	}
+	out.Write(dict[:i+2])
+	out.WriteString(" " + key + " " + value)
+	if i+2 < len(dict) && !isWhite(dict[i+2]) {
+		out.WriteString(" ")
+	}
+	out.Write(dict[i+2:])
+	return out.Bytes()
+}
+
+// Ref returns the object number of an indirect reference, e.g. 12 0 R
+func Ref(value []byte) (int, bool) {
+	m := reRef.FindSubmatch(value)
+	if m == nil || len(bytes.TrimSpace(value)) != len(m[0]) {
+		return 0, false
+	}
+	return atoi(m[1]), true
+}
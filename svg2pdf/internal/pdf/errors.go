@@ -0,0 +1,10 @@
+package pdf
+
+import "github.com/gdey/errors"
+
+const (
+	// ErrMalformed is returned when the pdf could not be parsed
+	ErrMalformed = errors.String("malformed or unsupported pdf")
+	// ErrUnsupportedFilter is returned when an object stream is compressed with something other than flate
+	ErrUnsupportedFilter = errors.String("unsupported object stream filter")
+)
@@ -0,0 +1,396 @@
+// Package pdf reads the objects of an existing pdf, and appends incremental
+// updates to it. It only understands enough of the format to find and replace
+// objects; the pdf is never rewritten.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Object is an indirect object of the pdf
+type Object struct {
+	Num, Gen int
+	// Body is the text of the object without the obj, endobj keywords and stream data
+	Body []byte
+	// Stream is the, still encoded, data of a stream object; nil if the object is not a stream
+	Stream []byte
+}
+
+var (
+	reObj        = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	reRoot       = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	reInfo       = regexp.MustCompile(`/Info\s+(\d+)\s+\d+\s+R`)
+	reID         = regexp.MustCompile(`/ID\s*(\[[^\]]*\])`)
+	reSize       = regexp.MustCompile(`/Size\s+(\d+)`)
+	reStartXref  = regexp.MustCompile(`startxref\s+(\d+)`)
+	rePages      = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R`)
+	reKids       = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+	reRef        = regexp.MustCompile(`(\d+)\s+\d+\s+R`)
+	reTypePages  = regexp.MustCompile(`/Type\s*/Pages\b`)
+	reTypePage   = regexp.MustCompile(`/Type\s*/Page\b`)
+	reTypeObjStm = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	reFirst      = regexp.MustCompile(`/First\s+(\d+)`)
+	reN          = regexp.MustCompile(`/N\s+(\d+)`)
+	reLength     = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	reVersion    = regexp.MustCompile(`^%PDF-(\d\.\d)`)
+)
+
+func lastSubmatch(re *regexp.Regexp, data []byte) []byte {
+	all := re.FindAllSubmatch(data, -1)
+	if len(all) == 0 {
+		return nil
+	}
+	m := all[len(all)-1]
+	if len(m) > 1 {
+		return m[1]
+	}
+	return m[0]
+}
+
+func atoi(b []byte) int {
+	i, _ := strconv.Atoi(string(b))
+	return i
+}
+
+// Num formats the number for a pdf
+func Num(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+// String escapes the string as a pdf literal string
+func String(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+	return "(" + r.Replace(s) + ")"
+}
+
+// File is a parsed pdf
+type File struct {
+	data []byte
+	// Objects is the latest definition of every object in the pdf
+	Objects map[int]Object
+	// Root is the object number of the catalog
+	Root int
+	// Info is the object number of the document information dictionary, 0 if there isn't one
+	Info int
+	size int
+	prev []byte
+}
+
+// Parse parses the objects of the pdf, including the ones in object streams
+func Parse(data []byte) (*File, error) {
+	objs, err := parseObjects(data)
+	if err != nil {
+		return nil, err
+	}
+	root := lastSubmatch(reRoot, data)
+	prev := lastSubmatch(reStartXref, data)
+	if root == nil || prev == nil {
+		return nil, ErrMalformed
+	}
+	f := File{
+		data:    data,
+		Objects: objs,
+		Root:    atoi(root),
+		Info:    atoi(lastSubmatch(reInfo, data)),
+		size:    atoi(lastSubmatch(reSize, data)),
+		prev:    prev,
+	}
+	for num := range objs {
+		if num >= f.size {
+			f.size = num + 1
+		}
+	}
+	return &f, nil
+}
+
+// ID returns the file identifier array of the trailer, nil if there isn't one
+func (f *File) ID() []byte { return lastSubmatch(reID, f.data) }
+
+// Version returns the version of the pdf from the header, e.g. 1.5
+func (f *File) Version() string {
+	if m := reVersion.FindSubmatch(f.data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// Catalog returns the document catalog
+func (f *File) Catalog() (Object, error) {
+	catalog, ok := f.Objects[f.Root]
+	if !ok {
+		return Object{}, ErrMalformed
+	}
+	return catalog, nil
+}
+
+// parseObjects returns the latest definition of every object in the pdf,
+// including the ones in object streams
+func parseObjects(data []byte) (map[int]Object, error) {
+	objs := make(map[int]Object)
+	pos := 0
+	for {
+		loc := reObj.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		obj := Object{
+			Num: atoi(data[pos+loc[2] : pos+loc[3]]),
+			Gen: atoi(data[pos+loc[4] : pos+loc[5]]),
+		}
+		start := pos + loc[1]
+		end := bytes.Index(data[start:], []byte("endobj"))
+		if end == -1 {
+			return nil, ErrMalformed
+		}
+		end += start
+		body := data[start:end]
+		pos = end + len("endobj")
+
+		if sidx := bytes.Index(body, []byte("stream")); sidx != -1 {
+			// stream data can contain anything, make sure we skip past the end of it
+			if eidx := bytes.Index(data[start+sidx:], []byte("endstream")); eidx != -1 {
+				eidx += start + sidx
+				if eidx > end {
+					end = eidx + bytes.Index(data[eidx:], []byte("endobj"))
+					if end < eidx {
+						return nil, ErrMalformed
+					}
+					pos = end + len("endobj")
+				}
+				stream := streamData(body[:sidx], data[start+sidx:eidx])
+				obj.Stream = stream
+				if reTypeObjStm.Match(body[:sidx]) {
+					if err := parseObjStm(objs, body[:sidx], stream); err != nil {
+						return nil, err
+					}
+				}
+			}
+			body = body[:sidx]
+		}
+		obj.Body = bytes.TrimSpace(body)
+		objs[obj.Num] = obj
+	}
+	if len(objs) == 0 {
+		return nil, ErrMalformed
+	}
+	return objs, nil
+}
+
+// streamData returns the data of the stream, given the text from the stream keyword to the endstream keyword
+func streamData(dict []byte, stream []byte) []byte {
+	stream = bytes.TrimPrefix(stream, []byte("stream"))
+	stream = bytes.TrimPrefix(stream, []byte("\r"))
+	stream = bytes.TrimPrefix(stream, []byte("\n"))
+	if m := reLength.FindSubmatch(dict); m != nil && len(m[2]) == 0 {
+		if l := atoi(m[1]); l <= len(stream) {
+			return stream[:l]
+		}
+	}
+	// the length is an indirect reference, drop the end of line before endstream
+	stream = bytes.TrimSuffix(stream, []byte("\n"))
+	return bytes.TrimSuffix(stream, []byte("\r"))
+}
+
+// parseObjStm adds the objects in the object stream to objs
+func parseObjStm(objs map[int]Object, dict []byte, stream []byte) error {
+	if !bytes.Contains(dict, []byte("/FlateDecode")) {
+		// we only support flate compressed object streams
+		return ErrUnsupportedFilter
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(zr)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	n, first := atoi(lastSubmatch(reN, dict)), atoi(lastSubmatch(reFirst, dict))
+	if first > len(data) {
+		return ErrMalformed
+	}
+	header := strings.Fields(string(data[:first]))
+	if len(header) < 2*n {
+		return ErrMalformed
+	}
+	for i := 0; i < n; i++ {
+		num, _ := strconv.Atoi(header[2*i])
+		off, _ := strconv.Atoi(header[2*i+1])
+		end := len(data)
+		if i+1 < n {
+			next, _ := strconv.Atoi(header[2*i+3])
+			end = first + next
+		}
+		if first+off > end || end > len(data) {
+			return ErrMalformed
+		}
+		objs[num] = Object{
+			Num:  num,
+			Body: bytes.TrimSpace(data[first+off : end]),
+		}
+	}
+	return nil
+}
+
+// Pages returns the page objects in page order
+func (f *File) Pages() ([]Object, error) {
+	catalog, err := f.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	m := rePages.FindSubmatch(catalog.Body)
+	if m == nil {
+		return nil, ErrMalformed
+	}
+	var (
+		pages []Object
+		seen  = make(map[int]bool)
+		walk  func(num int) error
+	)
+	walk = func(num int) error {
+		if seen[num] {
+			return ErrMalformed
+		}
+		seen[num] = true
+		node, ok := f.Objects[num]
+		if !ok {
+			return ErrMalformed
+		}
+		if !reTypePages.Match(node.Body) {
+			if reTypePage.Match(node.Body) {
+				pages = append(pages, node)
+			}
+			return nil
+		}
+		kids := reKids.FindSubmatch(node.Body)
+		if kids == nil {
+			return nil
+		}
+		for _, ref := range reRef.FindAllSubmatch(kids[1], -1) {
+			if err := walk(atoi(ref[1])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(atoi(m[1])); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// Update is an incremental update of a pdf
+type Update struct {
+	f    *File
+	objs map[int]Object
+	info int
+	id   []byte
+}
+
+// Update starts an incremental update of the file
+func (f *File) Update() *Update {
+	return &Update{
+		f:    f,
+		objs: make(map[int]Object),
+		info: f.Info,
+	}
+}
+
+// Set replaces the object, or adds it if the object number is new. The stream
+// data of the object is written as is, so the dictionary should not change
+// the /Length or /Filter of a stream.
+func (u *Update) Set(obj Object) { u.objs[obj.Num] = obj }
+
+// Add adds a new object with the given body, returning its object number
+func (u *Update) Add(body []byte) int {
+	num := u.f.size
+	u.f.size++
+	u.objs[num] = Object{Num: num, Body: body}
+	return num
+}
+
+// AddStream adds a new stream object, the /Length is added to the dictionary
+func (u *Update) AddStream(dict []byte, data []byte) int {
+	num := u.Add(SetEntry(dict, "/Length", strconv.Itoa(len(data))))
+	obj := u.objs[num]
+	obj.Stream = data
+	u.objs[num] = obj
+	return num
+}
+
+// SetInfo sets the object number of the document information dictionary in the trailer
+func (u *Update) SetInfo(num int) { u.info = num }
+
+// SetID sets the file identifier array of the trailer, e.g. [<...> <...>]; by default
+// the identifier of the pdf is kept
+func (u *Update) SetID(id []byte) { u.id = id }
+
+// Len returns the number of objects in the update
+func (u *Update) Len() int { return len(u.objs) }
+
+// Write writes out the pdf followed by the update. If there is nothing in the update
+// only the pdf is written.
+func (u *Update) Write(w io.Writer) error {
+	pdf := u.f.data
+	if len(u.objs) == 0 && u.info == u.f.Info && u.id == nil {
+		_, err := w.Write(pdf)
+		return err
+	}
+
+	nums := make([]int, 0, len(u.objs))
+	for num := range u.objs {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	var (
+		buff    bytes.Buffer
+		offsets = make(map[int]int, len(nums))
+		base    = len(pdf)
+	)
+	if !bytes.HasSuffix(pdf, []byte("\n")) {
+		buff.WriteString("\n")
+	}
+	for _, num := range nums {
+		obj := u.objs[num]
+		offsets[num] = base + buff.Len()
+		fmt.Fprintf(&buff, "%d %d obj\n", obj.Num, obj.Gen)
+		buff.Write(obj.Body)
+		if obj.Stream != nil {
+			buff.WriteString("\nstream\n")
+			buff.Write(obj.Stream)
+			buff.WriteString("\nendstream")
+		}
+		buff.WriteString("\nendobj\n")
+	}
+
+	xref := base + buff.Len()
+	buff.WriteString("xref\n")
+	for _, num := range nums {
+		fmt.Fprintf(&buff, "%d 1\n%010d %05d n \n", num, offsets[num], u.objs[num].Gen)
+	}
+	fmt.Fprintf(&buff, "trailer\n<< /Size %d /Root %d 0 R /Prev %s", u.f.size, u.f.Root, u.f.prev)
+	if u.info != 0 {
+		fmt.Fprintf(&buff, " /Info %d 0 R", u.info)
+	}
+	id := u.id
+	if id == nil {
+		id = u.f.ID()
+	}
+	if id != nil {
+		fmt.Fprintf(&buff, " /ID %s", id)
+	}
+	fmt.Fprintf(&buff, " >>\nstartxref\n%d\n%%%%EOF\n", xref)
+
+	if _, err := w.Write(pdf); err != nil {
+		return err
+	}
+	_, err := w.Write(buff.Bytes())
+	return err
+}
@@ -0,0 +1,182 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSetEntry(t *testing.T) {
+	type tcase struct {
+		name     string
+		dict     string
+		key      string
+		value    string
+		expected string
+	}
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			got := string(SetEntry([]byte(tc.dict), tc.key, tc.value))
+			if got != tc.expected {
+				t.Errorf("dict, expected %q got %q", tc.expected, got)
+			}
+			if v := string(Entry([]byte(got), tc.key)); v != tc.value {
+				t.Errorf("entry, expected %q got %q", tc.value, v)
+			}
+		}
+	}
+
+	tests := []tcase{
+		{
+			name:     "add",
+			dict:     "<< /Type /Page /Parent 2 0 R >>",
+			key:      "/TrimBox",
+			value:    "[0 0 10 10]",
+			expected: "<< /TrimBox [0 0 10 10] /Type /Page /Parent 2 0 R >>",
+		},
+		{
+			name:     "add no space",
+			dict:     "<</Type/Page>>",
+			key:      "/Rotate",
+			value:    "0",
+			expected: "<< /Rotate 0 /Type/Page>>",
+		},
+		{
+			name:     "replace",
+			dict:     "<< /Type /Page /TrimBox [ 0 0 1 1 ] /Parent 2 0 R >>",
+			key:      "/TrimBox",
+			value:    "[0 0 10 10]",
+			expected: "<< /Type /Page /TrimBox [0 0 10 10] /Parent 2 0 R >>",
+		},
+		{
+			name:     "replace reference",
+			dict:     "<< /Type /Catalog /Metadata 7 0 R /Pages 2 0 R >>",
+			key:      "/Metadata",
+			value:    "9 0 R",
+			expected: "<< /Type /Catalog /Metadata 9 0 R /Pages 2 0 R >>",
+		},
+		{
+			name:     "nested keys are not replaced",
+			dict:     "<< /Resources << /Title (a (nested) \\) title) /Font << /F1 4 0 R >> >> /Contents <ab01> >>",
+			key:      "/Title",
+			value:    "(map)",
+			expected: "<< /Title (map) /Resources << /Title (a (nested) \\) title) /Font << /F1 4 0 R >> >> /Contents <ab01> >>",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+// buildPDF returns a pdf with the given objects, numbered from 1, and a classic xref table
+func buildPDF(objs ...string) []byte {
+	var buff bytes.Buffer
+	buff.WriteString("%PDF-1.5\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buff.Len()
+		fmt.Fprintf(&buff, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buff.Len()
+	fmt.Fprintf(&buff, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buff, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buff, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return buff.Bytes()
+}
+
+func TestUpdate(t *testing.T) {
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [ 3 0 R ] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 612 792 ] >>",
+		"<< /Producer (cairo) >>",
+	)
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("parse, expected nil got %v", err)
+	}
+	if f.Root != 1 || f.Info != 4 {
+		t.Fatalf("trailer, expected root 1 info 4 got root %v info %v", f.Root, f.Info)
+	}
+
+	var buff bytes.Buffer
+	if err = f.Update().Write(&buff); err != nil {
+		t.Fatalf("empty update, expected nil got %v", err)
+	}
+	if !bytes.Equal(buff.Bytes(), data) {
+		t.Errorf("empty update, expected pdf to be unchanged")
+	}
+
+	pages, err := f.Pages()
+	if err != nil || len(pages) != 1 {
+		t.Fatalf("pages, expected 1 got %v (%v)", len(pages), err)
+	}
+	update := f.Update()
+	page := pages[0]
+	page.Body = SetEntry(page.Body, "/TrimBox", "[9 9 603 783]")
+	update.Set(page)
+	info := update.Add([]byte("<< /Title (map) >>"))
+	stream := update.AddStream([]byte("<< /Type /Metadata >>"), []byte("<x:xmpmeta/>"))
+	update.SetInfo(info)
+	if info != 5 || stream != 6 {
+		t.Errorf("object numbers, expected 5, 6 got %v, %v", info, stream)
+	}
+
+	buff.Reset()
+	if err = update.Write(&buff); err != nil {
+		t.Fatalf("update, expected nil got %v", err)
+	}
+	out := buff.Bytes()
+	if !bytes.HasPrefix(out, data) {
+		t.Fatalf("prefix, expected original pdf to be unchanged")
+	}
+	tail := string(out[len(data):])
+	for _, expected := range []string{
+		"3 0 obj\n<< /TrimBox [9 9 603 783] /Type /Page",
+		"5 0 obj\n<< /Title (map) >>\nendobj",
+		"6 0 obj\n<< /Length 12 /Type /Metadata >>\nstream\n<x:xmpmeta/>\nendstream\nendobj",
+		"/Size 7 /Root 1 0 R /Prev",
+		"/Info 5 0 R",
+	} {
+		if !strings.Contains(tail, expected) {
+			t.Errorf("update, expected %q in\n%s", expected, tail)
+		}
+	}
+
+	// the update should be readable
+	f, err = Parse(out)
+	if err != nil {
+		t.Fatalf("parse update, expected nil got %v", err)
+	}
+	if got := string(Entry(f.Objects[3].Body, "/TrimBox")); got != "[9 9 603 783]" {
+		t.Errorf("trim box, expected [9 9 603 783] got %v", got)
+	}
+	if f.Info != 5 {
+		t.Errorf("info, expected 5 got %v", f.Info)
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := map[string]string{
+		"(map \\(1\\))":        "map (1)",
+		"(line\\nbreak \\101)": "line\nbreak A",
+		"(caf\\351)":           "café",
+		"<FEFF004D00E9>":       "Mé",
+		"<4d 61 7>":            "Map",
+	}
+	for value, expected := range tests {
+		got, err := Text([]byte(value))
+		if err != nil || got != expected {
+			t.Errorf("%v, expected %q got %q (%v)", value, expected, got, err)
+		}
+	}
+	for _, s := range []string{"50k (V795)", "Carte de Montréal"} {
+		if got, _ := Text([]byte(TextString(s))); got != s {
+			t.Errorf("round trip, expected %q got %q", s, got)
+		}
+	}
+}
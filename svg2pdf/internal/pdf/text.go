@@ -0,0 +1,114 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// TextString returns the string as a pdf text string; a literal string if it is
+// printable ascii, otherwise a hex string in UTF-16BE
+func TextString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r < ' ' || r > '~' {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return String(s)
+	}
+	var buff strings.Builder
+	buff.WriteString("<FEFF")
+	for _, c := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&buff, "%04X", c)
+	}
+	buff.WriteString(">")
+	return buff.String()
+}
+
+// literalEscapes are the single character escapes of a literal string
+var literalEscapes = map[byte]byte{
+	'n': '\n', 'r': '\r', 't': '\t', 'b': '\b', 'f': '\f',
+	'(': '(', ')': ')', '\\': '\\',
+}
+
+// decodeString returns the bytes of a literal or hex string
+func decodeString(value []byte) ([]byte, error) {
+	value = bytes.TrimSpace(value)
+	switch {
+	case len(value) >= 2 && value[0] == '<' && value[len(value)-1] == '>':
+		digits := bytes.Map(func(r rune) rune {
+			if isWhite(byte(r)) {
+				return -1
+			}
+			return r
+		}, value[1:len(value)-1])
+		if len(digits)%2 == 1 {
+			digits = append(digits, '0')
+		}
+		return hex.DecodeString(string(digits))
+	case len(value) >= 2 && value[0] == '(' && value[len(value)-1] == ')':
+		var (
+			out  []byte
+			data = value[1 : len(value)-1]
+		)
+		for i := 0; i < len(data); i++ {
+			if data[i] != '\\' || i+1 == len(data) {
+				out = append(out, data[i])
+				continue
+			}
+			i++
+			if c, ok := literalEscapes[data[i]]; ok {
+				out = append(out, c)
+				continue
+			}
+			switch {
+			case data[i] >= '0' && data[i] <= '7':
+				j := i
+				for j < len(data) && j < i+3 && data[j] >= '0' && data[j] <= '7' {
+					j++
+				}
+				c, _ := strconv.ParseUint(string(data[i:j]), 8, 8)
+				out = append(out, byte(c))
+				i = j - 1
+			case data[i] == '\r':
+				// a line continuation
+				if i+1 < len(data) && data[i+1] == '\n' {
+					i++
+				}
+			case data[i] == '\n':
+			default:
+				out = append(out, data[i])
+			}
+		}
+		return out, nil
+	}
+	return nil, ErrMalformed
+}
+
+// Text returns the value of a text string, e.g. the /Title of the document information
+// dictionary; strings that are not UTF-16BE are taken to be latin-1.
+func Text(value []byte) (string, error) {
+	data, err := decodeString(value)
+	if err != nil {
+		return "", err
+	}
+	if len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF {
+		data = data[2:]
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		}
+		return string(utf16.Decode(units)), nil
+	}
+	runes := make([]rune, len(data))
+	for i, c := range data {
+		runes[i] = rune(c)
+	}
+	return string(runes), nil
+}
@@ -0,0 +1,251 @@
+package svg2pdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-spatial/atlante/svg2pdf/internal/pdf"
+)
+
+// Metadata is the document information of a pdf
+type Metadata struct {
+	Title    string
+	Author   string
+	Subject  string
+	Keywords []string
+	// Creator is the application the document was created with
+	Creator string
+	// Producer is the application that produced the pdf, if empty the producer
+	// of the pdf (cairo) is kept
+	Producer string
+	// Created is the creation and modification date of the document, if zero the current time is used
+	Created time.Time
+	// Custom are additional entries of the document; the keys should only have letters
+	// and digits, other characters are removed.
+	Custom map[string]string
+}
+
+// customKeyRegex matches the characters not allowed in custom keys
+var customKeyRegex = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// customEntry is an entry of Metadata.Custom
+type customEntry struct {
+	Key, Value string
+}
+
+// customEntries returns the custom entries sorted by key, with empty keys and values dropped
+func (md Metadata) customEntries() []customEntry {
+	entries := make([]customEntry, 0, len(md.Custom))
+	for k, v := range md.Custom {
+		k = customKeyRegex.ReplaceAllString(k, "")
+		if k == "" || v == "" {
+			continue
+		}
+		entries = append(entries, customEntry{Key: k, Value: v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// pdfDate formats the time as a pdf date
+func pdfDate(t time.Time) string { return t.UTC().Format("D:20060102150405Z") }
+
+// infoDict returns the document information dictionary for the metadata
+func (md Metadata) infoDict() []byte {
+	var buff bytes.Buffer
+	entry := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&buff, " /%s %s", key, pdf.TextString(value))
+		}
+	}
+	buff.WriteString("<<")
+	entry("Title", md.Title)
+	entry("Author", md.Author)
+	entry("Subject", md.Subject)
+	entry("Keywords", strings.Join(md.Keywords, ", "))
+	entry("Creator", md.Creator)
+	entry("Producer", md.Producer)
+	entry("CreationDate", pdfDate(md.Created))
+	entry("ModDate", pdfDate(md.Created))
+	for _, e := range md.customEntries() {
+		entry(e.Key, e.Value)
+	}
+	buff.WriteString(" >>")
+	return buff.Bytes()
+}
+
+// xmpNamespace is the namespace of the custom entries in the xmp metadata
+const xmpNamespace = "http://github.com/go-spatial/atlante/ns/pdf/1.0/"
+
+// xmpTemplate is the xmp metadata of the document; it has the same values as the
+// document information dictionary as required by PDF/A. The custom entries are
+// described by a PDF/A extension schema.
+var xmpTemplate = template.Must(template.New("xmp").Funcs(template.FuncMap{
+	"xml":  template.HTMLEscapeString,
+	"date": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+	"join": strings.Join,
+}).Parse(`<?xpacket begin="` + "\ufeff" + `" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about=""
+  xmlns:dc="http://purl.org/dc/elements/1.1/"
+  xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+  xmlns:pdf="http://ns.adobe.com/pdf/1.3/"
+  xmlns:atlante="{{.Namespace}}"
+{{- if .PDFA }}
+  xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/"
+{{- end }}>
+  <dc:format>application/pdf</dc:format>
+{{- with .Title }}
+  <dc:title><rdf:Alt><rdf:li xml:lang="x-default">{{xml .}}</rdf:li></rdf:Alt></dc:title>
+{{- end }}
+{{- with .Author }}
+  <dc:creator><rdf:Seq><rdf:li>{{xml .}}</rdf:li></rdf:Seq></dc:creator>
+{{- end }}
+{{- with .Subject }}
+  <dc:description><rdf:Alt><rdf:li xml:lang="x-default">{{xml .}}</rdf:li></rdf:Alt></dc:description>
+{{- end }}
+{{- with .Keywords }}
+  <dc:subject><rdf:Bag>{{range .}}<rdf:li>{{xml .}}</rdf:li>{{end}}</rdf:Bag></dc:subject>
+  <pdf:Keywords>{{xml (join . ", ")}}</pdf:Keywords>
+{{- end }}
+{{- with .Creator }}
+  <xmp:CreatorTool>{{xml .}}</xmp:CreatorTool>
+{{- end }}
+{{- with .Producer }}
+  <pdf:Producer>{{xml .}}</pdf:Producer>
+{{- end }}
+  <xmp:CreateDate>{{date .Created}}</xmp:CreateDate>
+  <xmp:ModifyDate>{{date .Created}}</xmp:ModifyDate>
+  <xmp:MetadataDate>{{date .Created}}</xmp:MetadataDate>
+{{- range .CustomEntries }}
+  <atlante:{{.Key}}>{{xml .Value}}</atlante:{{.Key}}>
+{{- end }}
+{{- if .PDFA }}
+  <pdfaid:part>2</pdfaid:part>
+  <pdfaid:conformance>B</pdfaid:conformance>
+{{- end }}
+</rdf:Description>
+{{- if .CustomEntries }}
+<rdf:Description rdf:about=""
+  xmlns:pdfaExtension="http://www.aiim.org/pdfa/ns/extension/"
+  xmlns:pdfaSchema="http://www.aiim.org/pdfa/ns/schema#"
+  xmlns:pdfaProperty="http://www.aiim.org/pdfa/ns/property#">
+  <pdfaExtension:schemas><rdf:Bag><rdf:li rdf:parseType="Resource">
+    <pdfaSchema:schema>Atlante sheet</pdfaSchema:schema>
+    <pdfaSchema:namespaceURI>{{.Namespace}}</pdfaSchema:namespaceURI>
+    <pdfaSchema:prefix>atlante</pdfaSchema:prefix>
+    <pdfaSchema:property><rdf:Seq>
+{{- range .CustomEntries }}
+      <rdf:li rdf:parseType="Resource"><pdfaProperty:name>{{.Key}}</pdfaProperty:name><pdfaProperty:valueType>Text</pdfaProperty:valueType><pdfaProperty:category>external</pdfaProperty:category><pdfaProperty:description>{{.Key}}</pdfaProperty:description></rdf:li>
+{{- end }}
+    </rdf:Seq></pdfaSchema:property>
+  </rdf:li></rdf:Bag></pdfaExtension:schemas>
+</rdf:Description>
+{{- end }}
+</rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`))
+
+// xmp returns the xmp metadata packet for the metadata
+func (md Metadata) xmp(pdfa bool) ([]byte, error) {
+	var buff bytes.Buffer
+	err := xmpTemplate.Execute(&buff, struct {
+		Metadata
+		CustomEntries []customEntry
+		Namespace     string
+		PDFA          bool
+	}{
+		Metadata:      md,
+		CustomEntries: md.customEntries(),
+		Namespace:     xmpNamespace,
+		PDFA:          pdfa,
+	})
+	return buff.Bytes(), err
+}
+
+var reInterpolate = regexp.MustCompile(`/Interpolate\s+true`)
+
+// WriteMetadata writes out the pdf with the metadata set as the document information
+// dictionary and as an xmp metadata stream. If pdfa is true the pdf is also made PDF/A-2b;
+// the metadata marks it as such, an sRGB output intent is added, images are not
+// interpolated and the trailer is given a file identifier. The pdf is not rewritten, the
+// changes are added with an incremental update.
+func WriteMetadata(w io.Writer, data []byte, md Metadata, pdfa bool) error {
+	f, err := pdf.Parse(data)
+	if err != nil {
+		return err
+	}
+	catalog, err := f.Catalog()
+	if err != nil {
+		return err
+	}
+	if md.Producer == "" {
+		if info, ok := f.Objects[f.Info]; ok {
+			if producer := pdf.Entry(info.Body, "/Producer"); producer != nil {
+				md.Producer, _ = pdf.Text(producer)
+			}
+		}
+	}
+	if md.Created.IsZero() {
+		md.Created = time.Now()
+	}
+	// xmp only has a precision of seconds, so make sure the dates match
+	md.Created = md.Created.Truncate(time.Second)
+
+	xmp, err := md.xmp(pdfa)
+	if err != nil {
+		return err
+	}
+
+	update := f.Update()
+	update.SetInfo(update.Add(md.infoDict()))
+	metadata := update.AddStream([]byte("<< /Type /Metadata /Subtype /XML >>"), xmp)
+	catalog.Body = pdf.SetEntry(catalog.Body, "/Metadata", fmt.Sprintf("%d 0 R", metadata))
+
+	if pdfa {
+		profile := update.AddStream([]byte("<< /N 3 >>"), sRGBProfile())
+		catalog.Body = pdf.SetEntry(catalog.Body, "/OutputIntents", fmt.Sprintf(
+			"[<< /Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier %s /Info %s /RegistryName (http://www.color.org) /DestOutputProfile %d 0 R >>]",
+			pdf.String(sRGBIdentifier), pdf.String(sRGBIdentifier), profile,
+		))
+		for _, obj := range f.Objects {
+			if obj.Stream != nil && reInterpolate.Match(obj.Body) {
+				obj.Body = pdf.SetEntry(obj.Body, "/Interpolate", "false")
+				update.Set(obj)
+			}
+		}
+		if f.ID() == nil {
+			sum := fmt.Sprintf("<%x>", md5.Sum(data))
+			update.SetID([]byte("[" + sum + " " + sum + "]"))
+		}
+	}
+	update.Set(catalog)
+	return update.Write(w)
+}
+
+// SetMetadata sets the metadata of the pdf file, see WriteMetadata
+func SetMetadata(filename string, md Metadata, pdfa bool) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var buff bytes.Buffer
+	if err = WriteMetadata(&buff, data, md, pdfa); err != nil {
+		return err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, buff.Bytes(), info.Mode())
+}
@@ -0,0 +1,174 @@
+package svg2pdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/atlante/svg2pdf/internal/pdf"
+)
+
+// cairoPDF returns a small pdf like the ones cairo writes
+func cairoPDF() []byte {
+	var buff bytes.Buffer
+	buff.WriteString("%PDF-1.5\n%\xb5\xed\xae\xfb\n")
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [ 3 0 R ] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 612 792 ] /Resources << /XObject << /x5 5 0 R >> >> >>",
+		"<< /Producer (cairo 1.16.0 \\(https://cairographics.org\\)) /CreationDate (D:20200101000000Z) >>",
+		"<< /Length 3 /Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceRGB /Interpolate true /BitsPerComponent 8 >>\nstream\n\x00\x00\x00\nendstream",
+		"<< /Type /FontDescriptor /FontName /ABCDEF+DejaVuSans /FontFile2 7 0 R >>",
+		"<< /Length 1 >>\nstream\n\x00\nendstream",
+	}
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buff.Len()
+		fmt.Fprintf(&buff, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buff.Len()
+	fmt.Fprintf(&buff, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buff, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buff, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return buff.Bytes()
+}
+
+func TestWriteMetadata(t *testing.T) {
+	type tcase struct {
+		name string
+		md   Metadata
+		pdfa bool
+		// info is the expected document information dictionary entries
+		info map[string]string
+		// xmp is expected in the xmp metadata
+		xmp []string
+		// problems are the expected PDF/A problems
+		problems ErrNotPDFA
+	}
+
+	data := cairoPDF()
+	created := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	fn := func(tc tcase) (string, func(*testing.T)) {
+		return tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := WriteMetadata(&out, data, tc.md, tc.pdfa); err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !bytes.HasPrefix(out.Bytes(), data) {
+				t.Fatalf("prefix, expected the original pdf to be unchanged")
+			}
+			f, err := pdf.Parse(out.Bytes())
+			if err != nil {
+				t.Fatalf("parse, expected nil got %v", err)
+			}
+			info := f.Objects[f.Info].Body
+			for key, expected := range tc.info {
+				got, err := pdf.Text(pdf.Entry(info, key))
+				if err != nil || got != expected {
+					t.Errorf("info %v, expected %q got %q (%v)", key, expected, got, err)
+				}
+			}
+			catalog, _ := f.Catalog()
+			num, ok := pdf.Ref(pdf.Entry(catalog.Body, "/Metadata"))
+			if !ok {
+				t.Fatalf("metadata, expected a reference got %q", pdf.Entry(catalog.Body, "/Metadata"))
+			}
+			xmp := string(f.Objects[num].Stream)
+			for _, expected := range tc.xmp {
+				if !strings.Contains(xmp, expected) {
+					t.Errorf("xmp, expected %q in\n%s", expected, xmp)
+				}
+			}
+
+			err = ValidatePDFA(out.Bytes())
+			if tc.problems == nil {
+				if err != nil {
+					t.Errorf("validate, expected nil got %v", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(err, tc.problems) {
+				t.Errorf("validate, expected %v got %v", tc.problems, err)
+			}
+		}
+	}
+
+	md := Metadata{
+		Title:    "50k V795G25492",
+		Author:   "Mapping Office",
+		Subject:  "Topographic map <sheet>",
+		Keywords: []string{"50k", "V795G25492"},
+		Creator:  "atlante",
+		Created:  created,
+		Custom: map[string]string{
+			"Series": "V795",
+			"Job ID": "1234",
+			"Empty":  "",
+		},
+	}
+	unicode := md
+	unicode.Title = "Carte de Montréal"
+
+	tests := []tcase{
+		{
+			name: "metadata",
+			md:   md,
+			info: map[string]string{
+				"/Title":        "50k V795G25492",
+				"/Keywords":     "50k, V795G25492",
+				"/Producer":     "cairo 1.16.0 (https://cairographics.org)",
+				"/CreationDate": "D:20200304050607Z",
+				"/Series":       "V795",
+				"/JobID":        "1234",
+			},
+			xmp: []string{
+				`<rdf:li xml:lang="x-default">Topographic map &lt;sheet&gt;</rdf:li>`,
+				`<pdf:Producer>cairo 1.16.0 (https://cairographics.org)</pdf:Producer>`,
+				`<xmp:CreateDate>2020-03-04T05:06:07Z</xmp:CreateDate>`,
+				`<atlante:JobID>1234</atlante:JobID>`,
+				`<pdfaProperty:name>Series</pdfaProperty:name>`,
+			},
+			problems: ErrNotPDFA{
+				"xmp metadata does not identify the pdf as PDF/A-2",
+				"no output intent",
+				"no file identifier in the trailer",
+				"image 5 is interpolated",
+			},
+		},
+		{
+			name: "pdfa",
+			md:   unicode,
+			pdfa: true,
+			info: map[string]string{
+				"/Title": "Carte de Montréal",
+			},
+			xmp: []string{
+				`<rdf:li xml:lang="x-default">Carte de Montréal</rdf:li>`,
+				`<pdfaid:part>2</pdfaid:part>`,
+				`<pdfaid:conformance>B</pdfaid:conformance>`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestSRGBProfile(t *testing.T) {
+	profile := sRGBProfile()
+	if size := int(profile[0])<<24 | int(profile[1])<<16 | int(profile[2])<<8 | int(profile[3]); size != len(profile) {
+		t.Errorf("size, expected %v got %v", len(profile), size)
+	}
+	if got := string(profile[36:40]); got != "acsp" {
+		t.Errorf("signature, expected acsp got %v", got)
+	}
+	if len(profile)%4 != 0 {
+		t.Errorf("length, expected to be 4 byte aligned got %v", len(profile))
+	}
+}
@@ -0,0 +1,8 @@
+package svg2pdf
+
+// Page is a svg file to render as a page of a pdf, the width and height are in points
+type Page struct {
+	Filename string
+	Width    float64
+	Height   float64
+}
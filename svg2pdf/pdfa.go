@@ -0,0 +1,87 @@
+package svg2pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+
+	"github.com/go-spatial/atlante/svg2pdf/internal/pdf"
+)
+
+var (
+	reEncrypt        = regexp.MustCompile(`/Encrypt\s+\d+\s+\d+\s+R`)
+	reJavaScript     = regexp.MustCompile(`/(JS|JavaScript)\b`)
+	reLZW            = regexp.MustCompile(`/LZWDecode\b`)
+	reFontDescriptor = regexp.MustCompile(`/Type\s*/FontDescriptor\b`)
+	reFontFile       = regexp.MustCompile(`/FontFile[23]?\b`)
+	rePDFAID         = regexp.MustCompile(`pdfaid:part\s*(=\s*["']|>)2`)
+)
+
+// ValidatePDFA checks the pdf for the requirements of PDF/A-2b that can be checked
+// without rendering it: the PDF/A identification in the xmp metadata, the output intent,
+// the file identifier, embedded fonts, and no encryption, javascript, LZW compression or
+// interpolated images. If any of them are not met an ErrNotPDFA listing them is returned.
+func ValidatePDFA(data []byte) error {
+	f, err := pdf.Parse(data)
+	if err != nil {
+		return err
+	}
+	catalog, err := f.Catalog()
+	if err != nil {
+		return err
+	}
+
+	var problems ErrNotPDFA
+	if v := f.Version(); v == "" || v > "1.7" {
+		problems = append(problems, fmt.Sprintf("pdf version %q is not 1.7 or earlier", v))
+	}
+	if num, ok := pdf.Ref(pdf.Entry(catalog.Body, "/Metadata")); !ok {
+		problems = append(problems, "no xmp metadata")
+	} else if md, ok := f.Objects[num]; !ok || !rePDFAID.Match(md.Stream) {
+		problems = append(problems, "xmp metadata does not identify the pdf as PDF/A-2")
+	}
+	if pdf.Entry(catalog.Body, "/OutputIntents") == nil {
+		problems = append(problems, "no output intent")
+	}
+	if f.ID() == nil {
+		problems = append(problems, "no file identifier in the trailer")
+	}
+	if reEncrypt.Match(data) {
+		problems = append(problems, "pdf is encrypted")
+	}
+
+	nums := make([]int, 0, len(f.Objects))
+	for num := range f.Objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		body := f.Objects[num].Body
+		switch {
+		case reJavaScript.Match(body):
+			problems = append(problems, fmt.Sprintf("object %d has javascript", num))
+		case reLZW.Match(body):
+			problems = append(problems, fmt.Sprintf("object %d uses LZW compression", num))
+		case reInterpolate.Match(body):
+			problems = append(problems, fmt.Sprintf("image %d is interpolated", num))
+		case reFontDescriptor.Match(body) && !reFontFile.Match(body):
+			name := bytes.TrimPrefix(pdf.Entry(body, "/FontName"), []byte("/"))
+			problems = append(problems, fmt.Sprintf("font %s is not embedded", name))
+		}
+	}
+	if len(problems) != 0 {
+		return problems
+	}
+	return nil
+}
+
+// ValidatePDFAFile checks the pdf file, see ValidatePDFA
+func ValidatePDFAFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return ValidatePDFA(data)
+}